@@ -0,0 +1,240 @@
+package caldav
+
+import (
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+)
+
+// Alarm actions.
+const (
+	AlarmDisplay = "DISPLAY"
+	AlarmAudio   = "AUDIO"
+	AlarmEmail   = "EMAIL"
+)
+
+// Alarm represents a reminder (VALARM) on an event.
+type Alarm struct {
+	// Action is AlarmDisplay, AlarmAudio or AlarmEmail. Alarms read from the
+	// server may have other actions, such as PROCEDURE; they can be passed
+	// back unchanged but not created.
+	Action string `json:"action"`
+	// Trigger is either a duration relative to the event's start, such as
+	// "-PT15M" or "-P1D", or an absolute time in RFC 3339 format.
+	Trigger string `json:"trigger"`
+	// RelatedEnd makes a relative trigger count from the event's end.
+	RelatedEnd  bool   `json:"relatedEnd,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Summary and Attendees are the subject and recipient email addresses of
+	// an email alarm.
+	Summary   string   `json:"summary,omitempty"`
+	Attendees []string `json:"attendees,omitempty"`
+}
+
+// ValidateAlarm checks that a new alarm has a supported action and a valid
+// trigger, and that an email alarm has recipients.
+func ValidateAlarm(alarm Alarm) error {
+	switch strings.ToUpper(alarm.Action) {
+	case AlarmDisplay, AlarmAudio:
+	case AlarmEmail:
+		if len(alarm.Attendees) == 0 {
+			return fmt.Errorf("email alarm requires at least one attendee")
+		}
+		for _, addr := range alarm.Attendees {
+			if _, err := mail.ParseAddress(addr); err != nil {
+				return fmt.Errorf("invalid alarm attendee %q: %w", addr, err)
+			}
+		}
+	default:
+		return fmt.Errorf("invalid alarm action %q: use %s, %s or %s", alarm.Action, AlarmDisplay, AlarmAudio, AlarmEmail)
+	}
+
+	if _, _, err := parseTrigger(alarm.Trigger); err != nil {
+		return err
+	}
+	if alarm.RelatedEnd && !isRelativeTrigger(alarm.Trigger) {
+		return fmt.Errorf("relatedEnd requires a relative alarm trigger")
+	}
+	return nil
+}
+
+func isRelativeTrigger(trigger string) bool {
+	trigger = strings.TrimLeft(strings.TrimSpace(trigger), "+-")
+	return strings.HasPrefix(strings.ToUpper(trigger), "P")
+}
+
+// parseTrigger reads a Trigger: a relative one as a duration, an absolute
+// one as a time.
+func parseTrigger(trigger string) (time.Duration, time.Time, error) {
+	trigger = strings.TrimSpace(trigger)
+	if trigger == "" {
+		return 0, time.Time{}, fmt.Errorf("alarm trigger is required")
+	}
+	if isRelativeTrigger(trigger) {
+		d, err := parseDuration(trigger)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("invalid alarm trigger %q: %w", trigger, err)
+		}
+		return d, time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, trigger)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("invalid alarm trigger %q: use a duration like -PT15M or an RFC 3339 time", trigger)
+	}
+	return 0, t, nil
+}
+
+// newAlarm builds the VALARM component for alarm. title is used as the
+// description and summary when the alarm has none. Audio alarms play the
+// client's default sound and carry no description (RFC 5545 section
+// 3.6.6).
+func newAlarm(alarm Alarm, title string) (*ical.Component, error) {
+	if err := ValidateAlarm(alarm); err != nil {
+		return nil, err
+	}
+
+	comp := ical.NewComponent(ical.CompAlarm)
+	action := strings.ToUpper(alarm.Action)
+	comp.Props.SetText(ical.PropAction, action)
+
+	trigger := ical.NewProp(ical.PropTrigger)
+	if _, at, _ := parseTrigger(alarm.Trigger); !at.IsZero() {
+		trigger.SetDateTime(at.UTC())
+	} else {
+		trigger.Value = strings.ToUpper(strings.TrimSpace(alarm.Trigger))
+		if alarm.RelatedEnd {
+			trigger.Params.Set(ical.ParamRelated, "END")
+		}
+	}
+	comp.Props.Set(trigger)
+	if action == AlarmAudio {
+		return comp, nil
+	}
+
+	description := alarm.Description
+	if description == "" {
+		description = title
+	}
+	if description == "" {
+		description = "Reminder"
+	}
+	comp.Props.SetText(ical.PropDescription, description)
+
+	if action == AlarmEmail {
+		summary := alarm.Summary
+		if summary == "" {
+			summary = description
+		}
+		comp.Props.SetText(ical.PropSummary, summary)
+		for _, addr := range alarm.Attendees {
+			comp.Props.Add(&ical.Prop{Name: ical.PropAttendee, Value: "mailto:" + addr, Params: ical.Params{}})
+		}
+	}
+	return comp, nil
+}
+
+// setAlarms replaces the VALARMs of vevent with alarms. Existing VALARMs
+// that already describe one of alarms are kept as they are, with any
+// properties Alarm does not map, whatever their action; only the others
+// are validated as new alarms.
+func setAlarms(vevent *ical.Component, alarms []Alarm) error {
+	title, _ := vevent.Props.Text(ical.PropSummary)
+	var existing []*ical.Component
+	children := make([]*ical.Component, 0, len(vevent.Children)+len(alarms))
+	for _, child := range vevent.Children {
+		if child.Name == ical.CompAlarm {
+			existing = append(existing, child)
+		} else {
+			children = append(children, child)
+		}
+	}
+	for _, alarm := range alarms {
+		if i := findAlarm(existing, alarm); i >= 0 {
+			children = append(children, existing[i])
+			existing = append(existing[:i], existing[i+1:]...)
+			continue
+		}
+		comp, err := newAlarm(alarm, title)
+		if err != nil {
+			return err
+		}
+		children = append(children, comp)
+	}
+	vevent.Children = children
+	return nil
+}
+
+// findAlarm returns the index of the VALARM in comps that reads as alarm,
+// or -1.
+func findAlarm(comps []*ical.Component, alarm Alarm) int {
+	for i, comp := range comps {
+		if a, ok := parseAlarm(comp); ok && sameAlarm(a, alarm) {
+			return i
+		}
+	}
+	return -1
+}
+
+// sameAlarm reports whether a and b describe the same reminder.
+func sameAlarm(a, b Alarm) bool {
+	return strings.EqualFold(a.Action, b.Action) &&
+		strings.EqualFold(strings.TrimSpace(a.Trigger), strings.TrimSpace(b.Trigger)) &&
+		a.RelatedEnd == b.RelatedEnd &&
+		a.Description == b.Description &&
+		a.Summary == b.Summary &&
+		slices.Equal(a.Attendees, b.Attendees)
+}
+
+// parseAlarms reads the VALARMs of vevent.
+func parseAlarms(vevent *ical.Component) []Alarm {
+	var alarms []Alarm
+	for _, child := range vevent.Children {
+		if child.Name != ical.CompAlarm {
+			continue
+		}
+		if alarm, ok := parseAlarm(child); ok {
+			alarms = append(alarms, alarm)
+		}
+	}
+	return alarms
+}
+
+// parseAlarm reads a VALARM. It reports false if the trigger is missing or
+// cannot be read.
+func parseAlarm(comp *ical.Component) (Alarm, bool) {
+	trigger := comp.Props.Get(ical.PropTrigger)
+	if trigger == nil {
+		return Alarm{}, false
+	}
+
+	alarm := Alarm{}
+	if action := comp.Props.Get(ical.PropAction); action != nil {
+		alarm.Action = strings.ToUpper(action.Value)
+	}
+	if trigger.ValueType() == ical.ValueDateTime {
+		t, err := trigger.DateTime(time.UTC)
+		if err != nil {
+			return Alarm{}, false
+		}
+		alarm.Trigger = t.UTC().Format(time.RFC3339)
+	} else {
+		alarm.Trigger = trigger.Value
+		alarm.RelatedEnd = strings.EqualFold(trigger.Params.Get(ical.ParamRelated), "END")
+	}
+	if desc := comp.Props.Get(ical.PropDescription); desc != nil {
+		alarm.Description = desc.Value
+	}
+	if summary := comp.Props.Get(ical.PropSummary); summary != nil {
+		alarm.Summary = summary.Value
+	}
+	for _, prop := range comp.Props.Values(ical.PropAttendee) {
+		if addr := strings.TrimPrefix(prop.Value, "mailto:"); addr != prop.Value {
+			alarm.Attendees = append(alarm.Attendees, addr)
+		}
+	}
+	return alarm, true
+}
@@ -0,0 +1,272 @@
+package caldav
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+	extcaldav "github.com/emersion/go-webdav/caldav"
+)
+
+// alarmComponents returns the VALARMs of comp.
+func alarmComponents(comp *ical.Component) []*ical.Component {
+	var alarms []*ical.Component
+	for _, child := range comp.Children {
+		if child.Name == ical.CompAlarm {
+			alarms = append(alarms, child)
+		}
+	}
+	return alarms
+}
+
+func TestValidateAlarm(t *testing.T) {
+	tests := []struct {
+		name    string
+		alarm   Alarm
+		wantErr bool
+	}{
+		{"relative display", Alarm{Action: "DISPLAY", Trigger: "-PT15M"}, false},
+		{"lowercase action", Alarm{Action: "display", Trigger: "-p1d"}, false},
+		{"positive duration", Alarm{Action: "DISPLAY", Trigger: "PT0S"}, false},
+		{"related to end", Alarm{Action: "DISPLAY", Trigger: "-PT5M", RelatedEnd: true}, false},
+		{"absolute", Alarm{Action: "DISPLAY", Trigger: "2025-03-14T08:00:00Z"}, false},
+		{"email", Alarm{Action: "EMAIL", Trigger: "-P1D", Attendees: []string{"a@example.com"}}, false},
+		{"email without attendees", Alarm{Action: "EMAIL", Trigger: "-P1D"}, true},
+		{"email with bad address", Alarm{Action: "EMAIL", Trigger: "-P1D", Attendees: []string{"nope"}}, true},
+		{"audio", Alarm{Action: "AUDIO", Trigger: "-PT15M"}, false},
+		{"procedure", Alarm{Action: "PROCEDURE", Trigger: "-PT15M"}, true},
+		{"missing trigger", Alarm{Action: "DISPLAY"}, true},
+		{"bad duration", Alarm{Action: "DISPLAY", Trigger: "-PT15X"}, true},
+		{"bad time", Alarm{Action: "DISPLAY", Trigger: "tomorrow"}, true},
+		{"absolute related to end", Alarm{Action: "DISPLAY", Trigger: "2025-03-14T08:00:00Z", RelatedEnd: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAlarm(tt.alarm)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAlarm(%+v) error = %v, wantErr %v", tt.alarm, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateEvent_WithAlarms(t *testing.T) {
+	mb := &mockBackend{putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	at := time.Date(2025, 3, 14, 8, 0, 0, 0, time.UTC)
+	_, err := c.CreateEvent(context.Background(), "/cal/work", &Event{
+		Title:     "Dentist",
+		StartTime: time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC),
+		Alarms: []Alarm{
+			{Action: "display", Trigger: "-pt15m"},
+			{Action: AlarmDisplay, Trigger: "-PT5M", RelatedEnd: true},
+			{Action: AlarmDisplay, Trigger: at.Format(time.RFC3339)},
+			{Action: AlarmEmail, Trigger: "-P1D", Attendees: []string{"me@example.com"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	vevent := eventComponents(mb.lastPutCal)[0]
+	comps := alarmComponents(vevent)
+	if len(comps) != 4 {
+		t.Fatalf("expected 4 VALARMs, got %d", len(comps))
+	}
+	if got := comps[0].Props.Get(ical.PropTrigger).Value; got != "-PT15M" {
+		t.Errorf("TRIGGER = %q, want -PT15M", got)
+	}
+	if got := comps[0].Props.Get(ical.PropDescription).Value; got != "Dentist" {
+		t.Errorf("DESCRIPTION = %q, want the event title", got)
+	}
+	if got := comps[1].Props.Get(ical.PropTrigger).Params.Get(ical.ParamRelated); got != "END" {
+		t.Errorf("RELATED = %q, want END", got)
+	}
+	abs := comps[2].Props.Get(ical.PropTrigger)
+	if abs.Value != "20250314T080000Z" || abs.Params.Get(ical.ParamValue) != "DATE-TIME" {
+		t.Errorf("absolute TRIGGER = %q %v", abs.Value, abs.Params)
+	}
+	if got := comps[3].Props.Get(ical.PropAttendee).Value; got != "mailto:me@example.com" {
+		t.Errorf("email ATTENDEE = %q", got)
+	}
+	if comps[3].Props.Get(ical.PropSummary) == nil {
+		t.Error("email alarm needs a SUMMARY")
+	}
+
+	// The alarms read back as they were given
+	event := parseEvent(vevent)
+	want := []Alarm{
+		{Action: AlarmDisplay, Trigger: "-PT15M", Description: "Dentist"},
+		{Action: AlarmDisplay, Trigger: "-PT5M", RelatedEnd: true, Description: "Dentist"},
+		{Action: AlarmDisplay, Trigger: "2025-03-14T08:00:00Z", Description: "Dentist"},
+		{Action: AlarmEmail, Trigger: "-P1D", Description: "Dentist", Summary: "Dentist", Attendees: []string{"me@example.com"}},
+	}
+	if len(event.Alarms) != len(want) {
+		t.Fatalf("parsed %d alarms, want %d", len(event.Alarms), len(want))
+	}
+	for i, got := range event.Alarms {
+		if got.Action != want[i].Action || got.Trigger != want[i].Trigger || got.RelatedEnd != want[i].RelatedEnd ||
+			got.Description != want[i].Description || got.Summary != want[i].Summary || len(got.Attendees) != len(want[i].Attendees) {
+			t.Errorf("alarm %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestCreateEvent_InvalidAlarm(t *testing.T) {
+	mb := &mockBackend{putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	_, err := c.CreateEvent(context.Background(), "/cal/work", &Event{
+		Title:     "Dentist",
+		StartTime: time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC),
+		Alarms:    []Alarm{{Action: AlarmDisplay, Trigger: "soon"}},
+	})
+	if err == nil {
+		t.Fatal("expected error for invalid trigger")
+	}
+	if mb.lastPutCal != nil {
+		t.Error("nothing should be written for an invalid alarm")
+	}
+}
+
+// makeObjectWithAlarm returns an event with one DISPLAY alarm 10 minutes
+// before its start.
+func makeObjectWithAlarm() *extcaldav.CalendarObject {
+	obj := makeExistingObject("etag-1")
+	alarm := ical.NewComponent(ical.CompAlarm)
+	alarm.Props.SetText(ical.PropAction, AlarmDisplay)
+	alarm.Props.Set(&ical.Prop{Name: ical.PropTrigger, Value: "-PT10M", Params: ical.Params{}})
+	alarm.Props.SetText(ical.PropDescription, "Title")
+	obj.Data.Children[0].Children = append(obj.Data.Children[0].Children, alarm)
+	return obj
+}
+
+func TestUpdateEvent_KeepsAlarms(t *testing.T) {
+	mb := &mockBackend{getResult: makeObjectWithAlarm(), putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	title := "New"
+	if _, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Title: &title}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	comps := alarmComponents(eventComponents(mb.lastPutCal)[0])
+	if len(comps) != 1 || comps[0].Props.Get(ical.PropTrigger).Value != "-PT10M" {
+		t.Errorf("alarms not kept: %d VALARMs", len(comps))
+	}
+}
+
+func TestUpdateEvent_ReplacesAlarms(t *testing.T) {
+	mb := &mockBackend{getResult: makeObjectWithAlarm(), putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	alarms := []Alarm{{Action: AlarmDisplay, Trigger: "-PT1H"}}
+	if _, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Alarms: &alarms}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	comps := alarmComponents(eventComponents(mb.lastPutCal)[0])
+	if len(comps) != 1 || comps[0].Props.Get(ical.PropTrigger).Value != "-PT1H" {
+		t.Errorf("alarms not replaced")
+	}
+
+	mb.getResult = makeObjectWithAlarm()
+	alarms = []Alarm{}
+	if _, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Alarms: &alarms}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if comps := alarmComponents(eventComponents(mb.lastPutCal)[0]); len(comps) != 0 {
+		t.Errorf("expected alarms removed, got %d", len(comps))
+	}
+}
+
+func TestUpdateEvent_ReplaceKeepsMatchingAlarms(t *testing.T) {
+	obj := makeObjectWithAlarm()
+	existing := alarmComponents(eventComponents(obj.Data)[0])[0]
+	existing.Props.SetText("X-WR-ALARMUID", "alarm-1")
+	mb := &mockBackend{getResult: obj, putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	// The first alarm reads as the existing VALARM, the second is new
+	alarms := []Alarm{
+		{Action: AlarmDisplay, Trigger: "-PT10M", Description: "Title"},
+		{Action: AlarmDisplay, Trigger: "-PT1H"},
+	}
+	if _, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Alarms: &alarms}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	comps := alarmComponents(eventComponents(mb.lastPutCal)[0])
+	if len(comps) != 2 {
+		t.Fatalf("got %d VALARMs, want 2", len(comps))
+	}
+	if uid, _ := comps[0].Props.Text("X-WR-ALARMUID"); uid != "alarm-1" {
+		t.Errorf("X-WR-ALARMUID = %q, want the existing VALARM kept", uid)
+	}
+	if comps[1].Props.Get(ical.PropTrigger).Value != "-PT1H" {
+		t.Errorf("second trigger = %q, want -PT1H", comps[1].Props.Get(ical.PropTrigger).Value)
+	}
+}
+
+func TestUpdateEvent_ReplaceKeepsUnknownAlarmActions(t *testing.T) {
+	obj := makeObjectWithAlarm()
+	existing := alarmComponents(eventComponents(obj.Data)[0])[0]
+	existing.Props.SetText(ical.PropAction, "X-SPEAK")
+	existing.Props.SetText("X-VOICE", "Samantha")
+	mb := &mockBackend{getResult: obj, putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	// The alarm as search_events reports it, and a new audio alarm
+	alarms := []Alarm{
+		{Action: "X-SPEAK", Trigger: "-PT10M", Description: "Title"},
+		{Action: AlarmAudio, Trigger: "-PT1H"},
+	}
+	if _, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Alarms: &alarms}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	comps := alarmComponents(eventComponents(mb.lastPutCal)[0])
+	if len(comps) != 2 {
+		t.Fatalf("got %d VALARMs, want 2", len(comps))
+	}
+	if voice, _ := comps[0].Props.Text("X-VOICE"); voice != "Samantha" {
+		t.Errorf("X-VOICE = %q, want the existing VALARM kept", voice)
+	}
+	if action, _ := comps[1].Props.Text(ical.PropAction); action != AlarmAudio {
+		t.Errorf("ACTION = %q, want AUDIO", action)
+	}
+	if comps[1].Props.Get(ical.PropDescription) != nil {
+		t.Error("an audio alarm should have no DESCRIPTION")
+	}
+
+	// An unknown action is refused for a new alarm
+	mb.getResult = makeObjectWithAlarm()
+	alarms = []Alarm{{Action: "X-SPEAK", Trigger: "-PT5M"}}
+	if _, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Alarms: &alarms}); err == nil {
+		t.Error("expected an error for a new alarm with an unknown action")
+	}
+}
+
+func TestUpdateEvent_OverrideKeepsSeriesAlarms(t *testing.T) {
+	obj := makeObjectWithAlarm()
+	setRecurrenceRule(obj.Data.Children[0].Props, "FREQ=WEEKLY;COUNT=5")
+	mb := &mockBackend{getResult: obj, putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	rid := time.Date(2024, 1, 22, 14, 0, 0, 0, time.UTC)
+	title := "Moved"
+	update := &EventUpdate{Title: &title, RecurrenceID: &rid, Scope: ScopeThis}
+	if _, err := c.UpdateEvent(context.Background(), "/cal/event.ics", update); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events := eventComponents(mb.lastPutCal)
+	if len(events) != 2 {
+		t.Fatalf("expected master and override, got %d VEVENTs", len(events))
+	}
+	if comps := alarmComponents(events[1]); len(comps) != 1 {
+		t.Errorf("override has %d VALARMs, want the series' alarm", len(comps))
+	}
+}
@@ -1,9 +1,240 @@
 package caldav
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/emersion/go-ical"
+)
+
 // Attendee represents a calendar event attendee.
 type Attendee struct {
 	Email  string `json:"email"`
 	Name   string `json:"name,omitempty"`
 	Role   string `json:"role,omitempty"`
 	Status string `json:"status,omitempty"`
+	// RSVP reports whether the organizer expects a reply (RSVP=TRUE). nil
+	// means the parameter is absent.
+	RSVP *bool `json:"rsvp,omitempty"`
+	// Type is the calendar user type (CUTYPE), such as "INDIVIDUAL",
+	// "GROUP", "RESOURCE" or "ROOM".
+	Type string `json:"type,omitempty"`
+	// DelegatedFrom and DelegatedTo are the email addresses of the
+	// attendees this one was delegated by and has delegated to.
+	DelegatedFrom []string `json:"delegatedFrom,omitempty"`
+	DelegatedTo   []string `json:"delegatedTo,omitempty"`
+	// ScheduleAgent is who delivers scheduling messages to the attendee
+	// (RFC 6638 SCHEDULE-AGENT): ScheduleAgentServer, the default,
+	// ScheduleAgentClient or ScheduleAgentNone.
+	ScheduleAgent string `json:"scheduleAgent,omitempty"`
+	// Params holds the other ATTENDEE parameters, such as SCHEDULE-STATUS
+	// or X- parameters, so that they survive an update.
+	Params map[string][]string `json:"params,omitempty"`
+}
+
+// AttendeeChanges edits the attendee list of an event. Attendees are matched
+// by email address, ignoring case. Parameters of an existing attendee that a
+// change leaves empty, such as RSVP, CUTYPE or DELEGATED-FROM, are kept.
+// Replace is applied first, then Add, then Remove.
+type AttendeeChanges struct {
+	// Replace, if non-nil, becomes the attendee list; attendees missing
+	// from it are removed, except those without an email address (such as
+	// urn:uuid: addresses). An empty slice removes all others.
+	Replace *[]Attendee
+	// Add invites new attendees, or changes the role, status or other
+	// fields of attendees already on the list.
+	Add []Attendee
+	// Remove holds the email addresses of attendees to remove.
+	Remove []string
+}
+
+// mailtoAddress returns the email address of a "mailto:" calendar user
+// address, or "" if value is another kind of address.
+func mailtoAddress(value string) string {
+	if len(value) < len("mailto:") || !strings.EqualFold(value[:len("mailto:")], "mailto:") {
+		return ""
+	}
+	return value[len("mailto:"):]
+}
+
+// parseAttendee converts an ATTENDEE property to an Attendee. It reports
+// false for attendees without a mailto: address.
+func parseAttendee(prop ical.Prop) (Attendee, bool) {
+	a := Attendee{Email: mailtoAddress(prop.Value)}
+	if a.Email == "" {
+		return a, false
+	}
+	for name, values := range prop.Params {
+		if len(values) == 0 {
+			continue
+		}
+		switch strings.ToUpper(name) {
+		case ical.ParamCommonName:
+			a.Name = values[0]
+		case ical.ParamRole:
+			a.Role = values[0]
+		case ical.ParamParticipationStatus:
+			a.Status = values[0]
+		case ical.ParamRSVP:
+			rsvp := strings.EqualFold(values[0], "TRUE")
+			a.RSVP = &rsvp
+		case ical.ParamCalendarUserType:
+			a.Type = values[0]
+		case ical.ParamDelegatedFrom:
+			a.DelegatedFrom = addressEmails(values)
+		case ical.ParamDelegatedTo:
+			a.DelegatedTo = addressEmails(values)
+		case paramScheduleAgent:
+			a.ScheduleAgent = values[0]
+		default:
+			if a.Params == nil {
+				a.Params = make(map[string][]string)
+			}
+			a.Params[name] = append([]string(nil), values...)
+		}
+	}
+	return a, true
+}
+
+// addressEmails returns the email addresses of the mailto: values.
+func addressEmails(values []string) []string {
+	var emails []string
+	for _, v := range values {
+		if email := mailtoAddress(v); email != "" {
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
+
+// newAttendeeProp builds the ATTENDEE property for a newly invited attendee.
+// The role defaults to REQ-PARTICIPANT, and an attendee without a status
+// is asked to reply.
+func newAttendeeProp(a Attendee) ical.Prop {
+	prop := ical.Prop{
+		Name:   ical.PropAttendee,
+		Value:  "mailto:" + a.Email,
+		Params: ical.Params{},
+	}
+	prop.Params.Set(ical.ParamRole, "REQ-PARTICIPANT")
+	prop.Params.Set(ical.ParamParticipationStatus, PartStatNeedsAction)
+	if a.Status == "" && a.RSVP == nil {
+		prop.Params.Set(ical.ParamRSVP, "TRUE")
+	}
+	mergeAttendee(&prop, a)
+	return prop
+}
+
+// mergeAttendee sets the non-empty fields of a as parameters of prop,
+// keeping the parameters a does not mention.
+func mergeAttendee(prop *ical.Prop, a Attendee) {
+	if prop.Params == nil {
+		prop.Params = ical.Params{}
+	}
+	for name, values := range a.Params {
+		prop.Params[strings.ToUpper(name)] = append([]string(nil), values...)
+	}
+	if a.Name != "" {
+		prop.Params.Set(ical.ParamCommonName, a.Name)
+	}
+	if a.Role != "" {
+		prop.Params.Set(ical.ParamRole, strings.ToUpper(a.Role))
+	}
+	if a.Status != "" {
+		prop.Params.Set(ical.ParamParticipationStatus, strings.ToUpper(a.Status))
+	}
+	if a.RSVP != nil {
+		prop.Params.Set(ical.ParamRSVP, strings.ToUpper(fmt.Sprint(*a.RSVP)))
+	}
+	if a.Type != "" {
+		prop.Params.Set(ical.ParamCalendarUserType, strings.ToUpper(a.Type))
+	}
+	if len(a.DelegatedFrom) > 0 {
+		prop.Params[ical.ParamDelegatedFrom] = mailtoAddresses(a.DelegatedFrom)
+	}
+	if len(a.DelegatedTo) > 0 {
+		prop.Params[ical.ParamDelegatedTo] = mailtoAddresses(a.DelegatedTo)
+	}
+	if a.ScheduleAgent != "" {
+		prop.Params.Set(paramScheduleAgent, strings.ToUpper(a.ScheduleAgent))
+	}
+}
+
+// mailtoAddresses returns the emails as mailto: calendar user addresses.
+func mailtoAddresses(emails []string) []string {
+	values := make([]string, len(emails))
+	for i, email := range emails {
+		values[i] = "mailto:" + email
+	}
+	return values
+}
+
+// applyAttendeeChanges edits the ATTENDEE properties of comp. Removing an
+// address that is not on the list is an error, so typos do not go unnoticed.
+func applyAttendeeChanges(comp *ical.Component, changes AttendeeChanges) error {
+	var added []Attendee
+	if changes.Replace != nil {
+		added = append(added, *changes.Replace...)
+	}
+	for _, a := range append(added, changes.Add...) {
+		if a.Email == "" {
+			return fmt.Errorf("attendee email is required")
+		}
+		if err := ValidateScheduleAgent(a.ScheduleAgent); err != nil {
+			return err
+		}
+	}
+
+	props := comp.Props[ical.PropAttendee]
+	if changes.Replace != nil {
+		// Attendees without an email address cannot be named, so they stay
+		var replaced []ical.Prop
+		for _, prop := range props {
+			if mailtoAddress(prop.Value) == "" {
+				replaced = append(replaced, prop)
+			}
+		}
+		for _, a := range *changes.Replace {
+			if i := findAttendee(replaced, a.Email); i >= 0 {
+				mergeAttendee(&replaced[i], a)
+			} else if i := findAttendee(props, a.Email); i >= 0 {
+				mergeAttendee(&props[i], a)
+				replaced = append(replaced, props[i])
+			} else {
+				replaced = append(replaced, newAttendeeProp(a))
+			}
+		}
+		props = replaced
+	}
+	for _, a := range changes.Add {
+		if i := findAttendee(props, a.Email); i >= 0 {
+			mergeAttendee(&props[i], a)
+		} else {
+			props = append(props, newAttendeeProp(a))
+		}
+	}
+	for _, email := range changes.Remove {
+		i := findAttendee(props, email)
+		if i < 0 {
+			return fmt.Errorf("%s is not an attendee of this event", email)
+		}
+		props = append(props[:i], props[i+1:]...)
+	}
+
+	if len(props) == 0 {
+		comp.Props.Del(ical.PropAttendee)
+	} else {
+		comp.Props[ical.PropAttendee] = props
+	}
+	return nil
+}
+
+// findAttendee returns the index of the attendee with email in props, or -1.
+func findAttendee(props []ical.Prop, email string) int {
+	for i := range props {
+		if isUserAddress(props[i].Value, []string{email}) {
+			return i
+		}
+	}
+	return -1
 }
@@ -1,8 +1,13 @@
 package caldav
 
 import (
+	"context"
 	"encoding/json"
+	"strings"
 	"testing"
+
+	"github.com/emersion/go-ical"
+	extcaldav "github.com/emersion/go-webdav/caldav"
 )
 
 func TestAttendee_JSON(t *testing.T) {
@@ -30,3 +35,190 @@ func TestAttendee_JSON(t *testing.T) {
 		t.Errorf("name = %q, want %q", decoded.Name, a.Name)
 	}
 }
+
+func TestParseAttendee_Params(t *testing.T) {
+	prop := ical.Prop{Name: ical.PropAttendee, Value: "MAILTO:room@example.com", Params: ical.Params{}}
+	prop.Params.Set(ical.ParamCommonName, "Room 4")
+	prop.Params.Set(ical.ParamRole, "NON-PARTICIPANT")
+	prop.Params.Set(ical.ParamParticipationStatus, "ACCEPTED")
+	prop.Params.Set(ical.ParamRSVP, "FALSE")
+	prop.Params.Set(ical.ParamCalendarUserType, "ROOM")
+	prop.Params.Set(ical.ParamDelegatedFrom, "mailto:boss@example.com")
+	prop.Params.Set("SCHEDULE-STATUS", "2.0")
+
+	a, ok := parseAttendee(prop)
+	if !ok {
+		t.Fatal("expected attendee to be parsed")
+	}
+	if a.Email != "room@example.com" || a.Name != "Room 4" || a.Role != "NON-PARTICIPANT" || a.Status != "ACCEPTED" {
+		t.Errorf("attendee = %+v", a)
+	}
+	if a.RSVP == nil || *a.RSVP {
+		t.Errorf("RSVP = %v, want false", a.RSVP)
+	}
+	if a.Type != "ROOM" {
+		t.Errorf("Type = %q, want ROOM", a.Type)
+	}
+	if len(a.DelegatedFrom) != 1 || a.DelegatedFrom[0] != "boss@example.com" {
+		t.Errorf("DelegatedFrom = %v", a.DelegatedFrom)
+	}
+	if got := a.Params["SCHEDULE-STATUS"]; len(got) != 1 || got[0] != "2.0" {
+		t.Errorf("Params = %v", a.Params)
+	}
+
+	// Written back as a new attendee, every parameter survives
+	written := newAttendeeProp(a)
+	for _, name := range []string{ical.ParamCommonName, ical.ParamRole, ical.ParamParticipationStatus, ical.ParamRSVP, ical.ParamCalendarUserType, ical.ParamDelegatedFrom, "SCHEDULE-STATUS"} {
+		if got, want := written.Params.Get(name), prop.Params.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+// attendeeEvent returns a calendar object whose event has alice, with
+// parameters a client set, and bob as attendees.
+func attendeeEvent() *extcaldav.CalendarObject {
+	vevent := ical.NewEvent()
+	vevent.Props.SetText(ical.PropUID, "uid-1")
+	alice := ical.Prop{Name: ical.PropAttendee, Value: "mailto:alice@example.com", Params: ical.Params{}}
+	alice.Params.Set(ical.ParamParticipationStatus, "NEEDS-ACTION")
+	alice.Params.Set(ical.ParamRSVP, "TRUE")
+	alice.Params.Set(ical.ParamCalendarUserType, "INDIVIDUAL")
+	alice.Params.Set(ical.ParamDelegatedFrom, "mailto:boss@example.com")
+	alice.Params.Set("X-CLIENT-ID", "42")
+	bob := ical.Prop{Name: ical.PropAttendee, Value: "mailto:bob@example.com", Params: ical.Params{}}
+	vevent.Props[ical.PropAttendee] = []ical.Prop{alice, bob}
+
+	cal := ical.NewCalendar()
+	cal.Children = append(cal.Children, vevent.Component)
+	return &extcaldav.CalendarObject{Path: "/home/work/uid-1.ics", ETag: `"etag-1"`, Data: cal}
+}
+
+func TestUpdateEvent_AddAndRemoveAttendees(t *testing.T) {
+	mb := newSchedulingBackend()
+	mb.getResult = attendeeEvent()
+	c := NewClientWithBackend(mb)
+
+	_, err := c.UpdateEvent(context.Background(), "/home/work/uid-1.ics", &EventUpdate{
+		Attendees: &AttendeeChanges{
+			Add:    []Attendee{{Email: "Alice@Example.com", Status: "accepted"}, {Email: "carol@example.com", Role: "OPT-PARTICIPANT"}},
+			Remove: []string{"bob@example.com"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	vevent := putEvent(t, mb)
+	if n := len(vevent.Props[ical.PropAttendee]); n != 2 {
+		t.Fatalf("got %d attendees, want 2", n)
+	}
+	alice := attendeeProp(t, vevent, "mailto:alice@example.com")
+	for name, want := range map[string]string{
+		ical.ParamParticipationStatus: "ACCEPTED",
+		ical.ParamRSVP:                "TRUE",
+		ical.ParamCalendarUserType:    "INDIVIDUAL",
+		ical.ParamDelegatedFrom:       "mailto:boss@example.com",
+		"X-CLIENT-ID":                 "42",
+	} {
+		if got := alice.Params.Get(name); got != want {
+			t.Errorf("alice %s = %q, want %q", name, got, want)
+		}
+	}
+	carol := attendeeProp(t, vevent, "mailto:carol@example.com")
+	if carol.Params.Get(ical.ParamRole) != "OPT-PARTICIPANT" || carol.Params.Get(ical.ParamParticipationStatus) != "NEEDS-ACTION" || carol.Params.Get(ical.ParamRSVP) != "TRUE" {
+		t.Errorf("carol params = %v", carol.Params)
+	}
+	if organizer := vevent.Props.Get(ical.PropOrganizer); organizer == nil || organizer.Value != "mailto:me@icloud.com" {
+		t.Errorf("ORGANIZER = %v, want mailto:me@icloud.com", organizer)
+	}
+	if mb.lastPutCond.ifMatch != `"etag-1"` {
+		t.Errorf("If-Match = %q, want the fetched ETag", mb.lastPutCond.ifMatch)
+	}
+}
+
+func TestUpdateEvent_Organizer(t *testing.T) {
+	mb := newSchedulingBackend()
+	mb.getResult = attendeeEvent()
+	c := NewClientWithBackend(mb)
+	organizer := "Boss@Example.com"
+
+	if _, err := c.UpdateEvent(context.Background(), "/home/work/uid-1.ics", &EventUpdate{Organizer: &organizer}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prop := putEvent(t, mb).Props.Get(ical.PropOrganizer); prop == nil || prop.Value != "mailto:Boss@Example.com" {
+		t.Errorf("ORGANIZER = %v, want mailto:Boss@Example.com", prop)
+	}
+
+	// An empty organizer makes the account the organizer again
+	mb.getResult = attendeeEvent()
+	mb.getResult.Data.Children[0].Props.SetText(ical.PropOrganizer, "mailto:boss@example.com")
+	organizer = ""
+	if _, err := c.UpdateEvent(context.Background(), "/home/work/uid-1.ics", &EventUpdate{Organizer: &organizer}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prop := putEvent(t, mb).Props.Get(ical.PropOrganizer); prop == nil || prop.Value != "mailto:me@icloud.com" {
+		t.Errorf("ORGANIZER = %v, want mailto:me@icloud.com", prop)
+	}
+
+	organizer = "not an address"
+	mb.lastPutCal = nil
+	if _, err := c.UpdateEvent(context.Background(), "/home/work/uid-1.ics", &EventUpdate{Organizer: &organizer}); err == nil {
+		t.Error("expected an error for an invalid organizer")
+	}
+	if mb.lastPutCal != nil {
+		t.Error("nothing should be written for an invalid organizer")
+	}
+}
+
+func TestUpdateEvent_ReplaceAttendees(t *testing.T) {
+	mb := newSchedulingBackend()
+	mb.getResult = attendeeEvent()
+	c := NewClientWithBackend(mb)
+
+	replace := []Attendee{{Email: "alice@example.com", Name: "Alice"}, {Email: "dave@example.com"}}
+	_, err := c.UpdateEvent(context.Background(), "/home/work/uid-1.ics", &EventUpdate{
+		Attendees: &AttendeeChanges{Replace: &replace},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	vevent := putEvent(t, mb)
+	if n := len(vevent.Props[ical.PropAttendee]); n != 2 {
+		t.Fatalf("got %d attendees, want 2", n)
+	}
+	alice := attendeeProp(t, vevent, "mailto:alice@example.com")
+	if alice.Params.Get(ical.ParamCommonName) != "Alice" || alice.Params.Get(ical.ParamDelegatedFrom) != "mailto:boss@example.com" {
+		t.Errorf("alice params = %v", alice.Params)
+	}
+	attendeeProp(t, vevent, "mailto:dave@example.com")
+
+	// An empty list removes every attendee
+	mb.getResult = attendeeEvent()
+	replace = []Attendee{}
+	if _, err := c.UpdateEvent(context.Background(), "/home/work/uid-1.ics", &EventUpdate{
+		Attendees: &AttendeeChanges{Replace: &replace},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attendees := putEvent(t, mb).Props[ical.PropAttendee]; len(attendees) != 0 {
+		t.Errorf("got %d attendees, want none", len(attendees))
+	}
+}
+
+func TestUpdateEvent_RemoveUnknownAttendee(t *testing.T) {
+	mb := newSchedulingBackend()
+	mb.getResult = attendeeEvent()
+	c := NewClientWithBackend(mb)
+
+	_, err := c.UpdateEvent(context.Background(), "/home/work/uid-1.ics", &EventUpdate{
+		Attendees: &AttendeeChanges{Remove: []string{"mallory@example.com"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "not an attendee") {
+		t.Fatalf("err = %v, want a not-an-attendee error", err)
+	}
+	if mb.lastPutCal != nil {
+		t.Error("nothing should be written")
+	}
+}
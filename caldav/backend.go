@@ -2,6 +2,7 @@ package caldav
 
 import (
 	"context"
+	"time"
 
 	"github.com/emersion/go-ical"
 	extcaldav "github.com/emersion/go-webdav/caldav"
@@ -12,12 +13,20 @@ import (
 type backend interface {
 	FindCurrentUserPrincipal(ctx context.Context) (string, error)
 	FindCalendarHomeSet(ctx context.Context, principal string) (string, error)
-	FindCalendars(ctx context.Context, homeSet string) ([]extcaldav.Calendar, error)
+	FindCalendars(ctx context.Context, homeSet string) ([]Calendar, error)
+	IsCalendar(ctx context.Context, path string) (bool, error)
 	QueryCalendar(ctx context.Context, path string, query *extcaldav.CalendarQuery) ([]extcaldav.CalendarObject, error)
-	PutCalendarObject(ctx context.Context, path string, cal *ical.Calendar) (*extcaldav.CalendarObject, error)
+	MultiGetCalendar(ctx context.Context, path string, multiGet *extcaldav.CalendarMultiGet) ([]extcaldav.CalendarObject, error)
+	SyncCollection(ctx context.Context, path, syncToken string) (*syncResponse, error)
+	CalendarCTag(ctx context.Context, path string) (string, error)
+	PutCalendarObject(ctx context.Context, path string, cal *ical.Calendar, cond precondition) (*extcaldav.CalendarObject, error)
 	GetCalendarObject(ctx context.Context, path string) (*extcaldav.CalendarObject, error)
-	RemoveAll(ctx context.Context, path string) error
+	Remove(ctx context.Context, path string, cond precondition) error
+	MoveCalendarObject(ctx context.Context, path, dest string, cond precondition) error
+	CopyCalendarObject(ctx context.Context, path, dest string) error
+	MakeCalendar(ctx context.Context, path string, props calendarProps) error
+	PatchCalendar(ctx context.Context, path string, props calendarProps) error
+	FreeBusyQuery(ctx context.Context, path string, start, end time.Time) (*ical.Calendar, error)
+	FindSchedulingInfo(ctx context.Context, principal string) (*schedulingInfo, error)
+	PostOutbox(ctx context.Context, path, originator string, recipients []string, cal *ical.Calendar) error
 }
-
-// Compile-time assertion that the real caldav.Client satisfies backend.
-var _ backend = (*extcaldav.Client)(nil)
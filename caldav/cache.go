@@ -0,0 +1,453 @@
+package caldav
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emersion/go-ical"
+)
+
+// CachingClient wraps a CalendarService with a per-calendar event cache.
+// SearchEvents brings a calendar's cache up to date with SyncCalendar, which
+// transfers only the events changed since the last sync, and answers from
+// the cache. Within ttl of the last sync, searches are answered without
+// contacting the server at all; writes made through the client invalidate
+// the calendars they touch, so the next search sees them.
+//
+// With a store, the cache is kept on disk as well: a restart picks up where
+// the last sync left off, and while the server is unreachable SearchEvents
+// and ListCalendars answer from the cache with a *StaleError.
+type CachingClient struct {
+	inner CalendarService
+	ttl   time.Duration
+	now   func() time.Time
+	store *AccountStore
+
+	mu        sync.Mutex
+	calendars map[string]*calendarCache
+	// calendarList is the last calendar list, or nil before the first one.
+	calendarList *storedCalendars
+}
+
+// calendarCache is the cached state of one calendar.
+type calendarCache struct {
+	mu     sync.Mutex // held while syncing
+	loaded bool
+	token  string
+	events map[string]Event // by path
+	// synced is the time of the last sync, or zero if the calendar must be
+	// synced before the cache is used again.
+	synced time.Time
+	// lastSync is the time of the last successful sync, kept across
+	// invalidations and restarts.
+	lastSync time.Time
+}
+
+var _ CalendarService = (*CachingClient)(nil)
+
+// NewCachingClient wraps the given client with an event cache. Searches
+// within ttl of a calendar's last sync are answered from the cache alone;
+// with a ttl of 0 every search checks the server for changes. store may be
+// nil to keep the cache in memory only.
+func NewCachingClient(inner CalendarService, ttl time.Duration, store *AccountStore) *CachingClient {
+	return &CachingClient{
+		inner:     inner,
+		ttl:       ttl,
+		now:       time.Now,
+		store:     store,
+		calendars: make(map[string]*calendarCache),
+	}
+}
+
+// cacheKey returns the key of the calendar at calendarPath, which may be
+// given with or without a trailing slash.
+func cacheKey(calendarPath string) string {
+	return strings.TrimSuffix(calendarPath, "/") + "/"
+}
+
+func (c *CachingClient) calendar(calendarPath string) *calendarCache {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := cacheKey(calendarPath)
+	cc, ok := c.calendars[key]
+	if !ok {
+		cc = &calendarCache{}
+		c.calendars[key] = cc
+	}
+	return cc
+}
+
+// invalidate makes the next search of each calendar sync it first. A sync
+// in progress finishes before the calendar is invalidated, so it cannot
+// hide the change that caused the invalidation.
+func (c *CachingClient) invalidate(calendarPaths ...string) {
+	for _, p := range calendarPaths {
+		c.mu.Lock()
+		cc := c.calendars[cacheKey(p)]
+		c.mu.Unlock()
+		if cc != nil {
+			cc.mu.Lock()
+			cc.synced = time.Time{}
+			cc.mu.Unlock()
+		}
+	}
+}
+
+// restore loads cc from the store, if it holds the calendar. The caller
+// holds cc.mu.
+func (c *CachingClient) restore(calendarPath string, cc *calendarCache) {
+	if c.store == nil || cc.loaded {
+		return
+	}
+	events, state, err := c.store.loadCalendar(cacheKey(calendarPath))
+	if err != nil {
+		slog.Warn("failed to load cached calendar", "path", calendarPath, "error", err)
+		return
+	}
+	if events == nil {
+		return
+	}
+	cc.loaded = true
+	cc.token = state.Token
+	cc.events = events
+	cc.lastSync = state.Synced
+}
+
+// sync brings cc up to date unless it was synced within the ttl. The
+// caller holds cc.mu.
+func (c *CachingClient) sync(ctx context.Context, calendarPath string, cc *calendarCache) error {
+	c.restore(calendarPath, cc)
+	if cc.loaded && !cc.synced.IsZero() && c.now().Sub(cc.synced) < c.ttl {
+		return nil
+	}
+
+	token := cc.token
+	if !cc.loaded {
+		token = ""
+	}
+	result, err := c.inner.SyncCalendar(ctx, calendarPath, token)
+	if err != nil {
+		return err
+	}
+
+	if result.Full || !cc.loaded {
+		cc.events = make(map[string]Event, len(result.Changed))
+	}
+	for _, p := range result.Deleted {
+		delete(cc.events, p)
+	}
+	for _, event := range result.Changed {
+		cc.events[event.Path] = event
+	}
+	cc.loaded = true
+	cc.token = result.Token
+	cc.synced = c.now()
+	cc.lastSync = cc.synced
+
+	if c.store != nil {
+		if err := c.store.saveSync(cacheKey(calendarPath), token, result, cc.lastSync); err != nil {
+			slog.Warn("failed to store calendar", "path", calendarPath, "error", err)
+		}
+	}
+	return nil
+}
+
+// serverUnreachable reports whether err means the server could not be
+// reached or failed, rather than refusing the request.
+func serverUnreachable(err error) bool {
+	status := httpStatus(err)
+	return status == 0 || status >= http.StatusInternalServerError
+}
+
+// SearchEvents answers from the calendar's cache after syncing it. If the
+// server cannot be reached, the cached events are returned with a
+// *StaleError; if the sync fails otherwise, the search is passed on to the
+// server.
+func (c *CachingClient) SearchEvents(ctx context.Context, calendarPath string, startTime, endTime *time.Time, opts ...SearchOptions) ([]Event, error) {
+	var opt SearchOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.Expand && (startTime == nil || endTime == nil) {
+		return nil, fmt.Errorf("expanding recurring events requires both a start and an end time")
+	}
+
+	cc := c.calendar(calendarPath)
+	cc.mu.Lock()
+	if err := c.sync(ctx, calendarPath, cc); err != nil {
+		if cc.loaded && serverUnreachable(err) {
+			events := searchCached(cc.events, startTime, endTime, opt)
+			stale := &StaleError{Since: cc.lastSync, Err: err}
+			cc.mu.Unlock()
+			slog.Warn("calendar sync failed, answering from the cache", "path", calendarPath, "since", stale.Since, "error", err)
+			return events, stale
+		}
+		cc.mu.Unlock()
+		slog.Warn("calendar sync failed, searching the server directly", "path", calendarPath, "error", err)
+		return c.inner.SearchEvents(ctx, calendarPath, startTime, endTime, opts...)
+	}
+	events := searchCached(cc.events, startTime, endTime, opt)
+	cc.mu.Unlock()
+	return events, nil
+}
+
+// searchCached returns the cached events matching a search, as the server
+// would for a calendar-query with the same time range and options.
+func searchCached(cached map[string]Event, startTime, endTime *time.Time, opt SearchOptions) []Event {
+	paths := make([]string, 0, len(cached))
+	for p := range cached {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	events := make([]Event, 0, len(paths))
+	for _, p := range paths {
+		event := cached[p]
+		if !overlaps(event, startTime, endTime) {
+			continue
+		}
+		if opt.Expand {
+			occurrences, err := expandEvent(event, *startTime, *endTime)
+			if err != nil {
+				slog.Warn("skipping calendar object with invalid recurrence", "path", p, "error", err)
+				continue
+			}
+			for _, occurrence := range occurrences {
+				if opt.Filter.matches(occurrence) {
+					events = append(events, occurrence)
+				}
+			}
+			continue
+		}
+		if opt.Filter.matches(event) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// overlaps reports whether the event, or one of its occurrences, overlaps
+// the time range (RFC 4791 section 9.9). Either bound may be nil.
+func overlaps(event Event, startTime, endTime *time.Time) bool {
+	if startTime == nil && endTime == nil {
+		return true
+	}
+	if event.Recurrence == "" && len(event.RecurrenceDates) == 0 {
+		return intervalOverlaps(event.StartTime, event.EndTime, startTime, endTime)
+	}
+
+	// Overrides can move occurrences anywhere
+	for _, o := range event.Overrides {
+		if o.Status != EventCancelled && intervalOverlaps(o.StartTime, o.EndTime, startTime, endTime) {
+			return true
+		}
+	}
+
+	duration := event.EndTime.Sub(event.StartTime)
+	switch {
+	case startTime == nil:
+		// The series starts with its first occurrence
+		return event.StartTime.Before(*endTime)
+	case endTime == nil:
+		set, err := recurrenceSet(event, startTime.Location())
+		if err != nil {
+			return false
+		}
+		return !set.After(startTime.Add(-duration), false).IsZero()
+	}
+
+	// Occurrences starting up to one duration before the range still
+	// overlap it
+	occurrences, err := ExpandRecurrence(event, startTime.Add(-duration), *endTime)
+	if err != nil {
+		return false
+	}
+	for _, o := range occurrences {
+		if intervalOverlaps(o.StartTime, o.EndTime, startTime, endTime) {
+			return true
+		}
+	}
+	return false
+}
+
+// intervalOverlaps reports whether [start, end) overlaps the range. An
+// event without duration overlaps if it starts within the range.
+func intervalOverlaps(start, end time.Time, rangeStart, rangeEnd *time.Time) bool {
+	if rangeEnd != nil && !start.Before(*rangeEnd) {
+		return false
+	}
+	if rangeStart != nil {
+		if end.After(start) {
+			return end.After(*rangeStart)
+		}
+		return !start.Before(*rangeStart)
+	}
+	return true
+}
+
+// SyncCalendar passes through; it does not touch the cache.
+func (c *CachingClient) SyncCalendar(ctx context.Context, calendarPath, token string) (*SyncResult, error) {
+	return c.inner.SyncCalendar(ctx, calendarPath, token)
+}
+
+func (c *CachingClient) DiscoverCalendarHomeSet(ctx context.Context) (string, error) {
+	return c.inner.DiscoverCalendarHomeSet(ctx)
+}
+
+// ListCalendars remembers the calendar list, and returns the last one with
+// a *StaleError if the server cannot be reached.
+func (c *CachingClient) ListCalendars(ctx context.Context) ([]Calendar, error) {
+	calendars, err := c.inner.ListCalendars(ctx)
+	if err != nil {
+		if !serverUnreachable(err) {
+			return nil, err
+		}
+		last := c.lastCalendarList()
+		if last == nil {
+			return nil, err
+		}
+		slog.Warn("listing calendars failed, answering from the cache", "since", last.Listed, "error", err)
+		return last.Calendars, &StaleError{Since: last.Listed, Err: err}
+	}
+
+	list := &storedCalendars{Calendars: calendars, Listed: c.now()}
+	c.mu.Lock()
+	c.calendarList = list
+	c.mu.Unlock()
+	if c.store != nil {
+		if err := c.store.saveCalendars(list); err != nil {
+			slog.Warn("failed to store calendar list", "error", err)
+		}
+	}
+	return calendars, nil
+}
+
+// lastCalendarList returns the last calendar list, from memory or the
+// store, or nil if there is none.
+func (c *CachingClient) lastCalendarList() *storedCalendars {
+	c.mu.Lock()
+	list := c.calendarList
+	c.mu.Unlock()
+	if list != nil || c.store == nil {
+		return list
+	}
+	list, err := c.store.calendars()
+	if err != nil {
+		slog.Warn("failed to load cached calendar list", "error", err)
+		return nil
+	}
+	return list
+}
+
+func (c *CachingClient) CreateCalendar(ctx context.Context, calendar *Calendar) (string, error) {
+	return c.inner.CreateCalendar(ctx, calendar)
+}
+
+func (c *CachingClient) UpdateCalendar(ctx context.Context, calendarPath string, update *CalendarUpdate) error {
+	return c.inner.UpdateCalendar(ctx, calendarPath, update)
+}
+
+// DeleteCalendar drops the calendar's cache.
+func (c *CachingClient) DeleteCalendar(ctx context.Context, calendarPath string) error {
+	err := c.inner.DeleteCalendar(ctx, calendarPath)
+	c.mu.Lock()
+	delete(c.calendars, cacheKey(calendarPath))
+	c.mu.Unlock()
+	if c.store != nil {
+		if err := c.store.deleteCalendar(cacheKey(calendarPath)); err != nil {
+			slog.Warn("failed to remove cached calendar", "path", calendarPath, "error", err)
+		}
+	}
+	return err
+}
+
+func (c *CachingClient) GetEvent(ctx context.Context, eventPath string) (*Event, *EventObject, error) {
+	return c.inner.GetEvent(ctx, eventPath)
+}
+
+// CreateEvent and the other writes invalidate the calendars they touch,
+// even when they fail, since the server may have applied them anyway.
+func (c *CachingClient) CreateEvent(ctx context.Context, calendarPath string, event *Event) (string, error) {
+	defer c.invalidate(calendarPath)
+	return c.inner.CreateEvent(ctx, calendarPath, event)
+}
+
+func (c *CachingClient) UpdateEvent(ctx context.Context, eventPath string, update *EventUpdate) (string, error) {
+	defer c.invalidate(calendarOf(eventPath))
+	return c.inner.UpdateEvent(ctx, eventPath, update)
+}
+
+func (c *CachingClient) DeleteEvent(ctx context.Context, eventPath, etag string) error {
+	defer c.invalidate(calendarOf(eventPath))
+	return c.inner.DeleteEvent(ctx, eventPath, etag)
+}
+
+func (c *CachingClient) DeleteOccurrence(ctx context.Context, eventPath string, recurrenceID time.Time, scope, etag string) error {
+	defer c.invalidate(calendarOf(eventPath))
+	return c.inner.DeleteOccurrence(ctx, eventPath, recurrenceID, scope, etag)
+}
+
+func (c *CachingClient) GetEventPath(calendarPath, eventID string) string {
+	return c.inner.GetEventPath(calendarPath, eventID)
+}
+
+func (c *CachingClient) GetEventObject(ctx context.Context, eventPath string) (*EventObject, error) {
+	return c.inner.GetEventObject(ctx, eventPath)
+}
+
+func (c *CachingClient) PutEventObject(ctx context.Context, eventPath string, data *ical.Calendar) error {
+	defer c.invalidate(calendarOf(eventPath))
+	return c.inner.PutEventObject(ctx, eventPath, data)
+}
+
+func (c *CachingClient) DeleteEventObject(ctx context.Context, eventPath, etag string) error {
+	defer c.invalidate(calendarOf(eventPath))
+	return c.inner.DeleteEventObject(ctx, eventPath, etag)
+}
+
+func (c *CachingClient) MoveEvent(ctx context.Context, eventPath, calendarPath, etag string) (string, error) {
+	defer c.invalidate(calendarOf(eventPath), calendarPath)
+	return c.inner.MoveEvent(ctx, eventPath, calendarPath, etag)
+}
+
+func (c *CachingClient) CopyEvent(ctx context.Context, eventPath, calendarPath string) (string, error) {
+	defer c.invalidate(calendarPath)
+	return c.inner.CopyEvent(ctx, eventPath, calendarPath)
+}
+
+func (c *CachingClient) SearchTasks(ctx context.Context, calendarPath string, includeCompleted bool) ([]Task, error) {
+	return c.inner.SearchTasks(ctx, calendarPath, includeCompleted)
+}
+
+func (c *CachingClient) CreateTask(ctx context.Context, calendarPath string, task *Task) (string, error) {
+	return c.inner.CreateTask(ctx, calendarPath, task)
+}
+
+func (c *CachingClient) UpdateTask(ctx context.Context, taskPath string, update *TaskUpdate) error {
+	return c.inner.UpdateTask(ctx, taskPath, update)
+}
+
+func (c *CachingClient) CompleteTask(ctx context.Context, taskPath, etag string) error {
+	return c.inner.CompleteTask(ctx, taskPath, etag)
+}
+
+func (c *CachingClient) FreeBusy(ctx context.Context, calendarPath string, start, end time.Time) ([]BusyPeriod, error) {
+	return c.inner.FreeBusy(ctx, calendarPath, start, end)
+}
+
+func (c *CachingClient) ListInvitations(ctx context.Context, start, end time.Time) ([]Invitation, error) {
+	return c.inner.ListInvitations(ctx, start, end)
+}
+
+// RespondToInvitation invalidates the calendar holding the invitation,
+// whose event records the reply.
+func (c *CachingClient) RespondToInvitation(ctx context.Context, path string, response InvitationResponse) error {
+	defer c.invalidate(calendarOf(path))
+	return c.inner.RespondToInvitation(ctx, path, response)
+}
@@ -0,0 +1,298 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func cachedEvent(id string, start time.Time) Event {
+	return Event{ID: id, Title: id, Path: "/cal/" + id + ".ics", StartTime: start, EndTime: start.Add(time.Hour)}
+}
+
+func eventIDs(events []Event) []string {
+	ids := make([]string, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+func TestCachingClient_SyncsOnceWithinTTL(t *testing.T) {
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	mock := &MockClient{Events: []Event{cachedEvent("a", start)}}
+	cc := NewCachingClient(mock, time.Minute, nil)
+
+	for i := 0; i < 3; i++ {
+		events, err := cc.SearchEvents(context.Background(), "/cal/", nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(events) != 1 {
+			t.Fatalf("got %d events, want 1", len(events))
+		}
+	}
+	if mock.SyncCallCount != 1 || mock.SearchCallCount != 0 {
+		t.Errorf("syncs = %d, searches = %d, want one sync and no search", mock.SyncCallCount, mock.SearchCallCount)
+	}
+}
+
+func TestCachingClient_AppliesChanges(t *testing.T) {
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	moved := cachedEvent("a", start.Add(24*time.Hour))
+	mock := &MockClient{Syncs: []*SyncResult{
+		{Token: "t1", Full: true, Changed: []Event{cachedEvent("a", start), cachedEvent("b", start)}},
+		{Token: "t2", Changed: []Event{moved, cachedEvent("c", start)}, Deleted: []string{"/cal/b.ics"}},
+		{Token: "t3", Full: true, Changed: []Event{cachedEvent("d", start)}},
+	}}
+	cc := NewCachingClient(mock, 0, nil)
+
+	want := [][]string{{"a", "b"}, {"a", "c"}, {"d"}}
+	for i, ids := range want {
+		events, err := cc.SearchEvents(context.Background(), "/cal", nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fmt.Sprint(eventIDs(events)) != fmt.Sprint(ids) {
+			t.Errorf("search %d = %v, want %v", i+1, eventIDs(events), ids)
+		}
+	}
+	if mock.LastSyncToken != "t2" {
+		t.Errorf("last token = %q, want t2", mock.LastSyncToken)
+	}
+}
+
+func TestCachingClient_WritesInvalidate(t *testing.T) {
+	mock := &MockClient{}
+	cc := NewCachingClient(mock, time.Hour, nil)
+	ctx := context.Background()
+
+	search := func(calendarPath string) {
+		t.Helper()
+		if _, err := cc.SearchEvents(ctx, calendarPath, nil, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	search("/cal/work/")
+	search("/cal/home/")
+	search("/cal/work/")
+	if mock.SyncCallCount != 2 {
+		t.Fatalf("syncs = %d, want 2", mock.SyncCallCount)
+	}
+
+	_, _ = cc.CreateEvent(ctx, "/cal/work", &Event{Title: "New"})
+	search("/cal/work/")
+	search("/cal/home/")
+	if mock.SyncCallCount != 3 {
+		t.Errorf("syncs = %d, want only /cal/work/ synced again after CreateEvent", mock.SyncCallCount)
+	}
+
+	_, _ = cc.MoveEvent(ctx, "/cal/work/a.ics", "/cal/home/", "")
+	search("/cal/work/")
+	search("/cal/home/")
+	if mock.SyncCallCount != 5 {
+		t.Errorf("syncs = %d, want both calendars synced again after MoveEvent", mock.SyncCallCount)
+	}
+
+	// A failed write may still have reached the server
+	mock.DeleteEventErr = fmt.Errorf("timeout")
+	_ = cc.DeleteEvent(ctx, "/cal/home/a.ics", "")
+	search("/cal/home/")
+	if mock.SyncCallCount != 6 {
+		t.Errorf("syncs = %d, want /cal/home/ synced again after a failed DeleteEvent", mock.SyncCallCount)
+	}
+}
+
+func TestCachingClient_SyncErrorSearchesServer(t *testing.T) {
+	mock := &MockClient{
+		SyncErr: fmt.Errorf("connection reset"),
+		Events:  []Event{{ID: "a"}},
+	}
+	cc := NewCachingClient(mock, time.Minute, nil)
+
+	events, err := cc.SearchEvents(context.Background(), "/cal/", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || mock.SearchCallCount != 1 {
+		t.Errorf("events = %d, searches = %d, want the server's answer", len(events), mock.SearchCallCount)
+	}
+}
+
+func TestCachingClient_SearchFilters(t *testing.T) {
+	day := func(d int, hour int) time.Time { return time.Date(2025, 3, d, hour, 0, 0, 0, time.UTC) }
+	weekly := cachedEvent("weekly", day(3, 9))
+	weekly.Recurrence = "FREQ=WEEKLY;COUNT=4"
+	ended := cachedEvent("ended", day(1, 9))
+	ended.Recurrence = "FREQ=DAILY;COUNT=2"
+	dentist := cachedEvent("dentist", day(12, 14))
+	dentist.Title = "Dentist"
+
+	mock := &MockClient{Events: []Event{
+		cachedEvent("before", day(5, 9)),
+		dentist,
+		cachedEvent("after", day(20, 9)),
+		weekly,
+		ended,
+	}}
+	cc := NewCachingClient(mock, time.Minute, nil)
+	ctx := context.Background()
+
+	start, end := day(10, 0), day(17, 0)
+	tests := []struct {
+		name       string
+		start, end *time.Time
+		opt        SearchOptions
+		want       []string
+	}{
+		{"range", &start, &end, SearchOptions{}, []string{"dentist", "weekly"}},
+		{"open end", &start, nil, SearchOptions{}, []string{"after", "dentist", "weekly"}},
+		{"open start", nil, &end, SearchOptions{}, []string{"before", "dentist", "ended", "weekly"}},
+		{"filter", &start, &end, SearchOptions{Filter: EventFilter{Title: "dentist"}}, []string{"dentist"}},
+		{"expand", &start, &end, SearchOptions{Expand: true}, []string{"dentist", "weekly"}},
+	}
+	for _, tt := range tests {
+		events, err := cc.SearchEvents(ctx, "/cal/", tt.start, tt.end, tt.opt)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if fmt.Sprint(eventIDs(events)) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: events = %v, want %v", tt.name, eventIDs(events), tt.want)
+		}
+		if tt.opt.Expand {
+			for _, e := range events {
+				if e.ID == "weekly" && (e.RecurrenceID == nil || !e.StartTime.Equal(day(10, 9))) {
+					t.Errorf("expanded occurrence = %+v, want the one on 10 March", e)
+				}
+			}
+		}
+	}
+
+	if _, err := cc.SearchEvents(ctx, "/cal/", &start, nil, SearchOptions{Expand: true}); err == nil {
+		t.Error("expected error expanding without an end time")
+	}
+}
+
+func TestIntervalOverlaps(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2025, 3, 10, hour, 0, 0, 0, time.UTC) }
+	start, end := at(10), at(12)
+	tests := []struct {
+		s, e time.Time
+		want bool
+	}{
+		{at(9), at(10), false},
+		{at(9), at(11), true},
+		{at(11), at(13), true},
+		{at(12), at(13), false},
+		{at(10), at(10), true},
+		{at(12), at(12), false},
+	}
+	for _, tt := range tests {
+		if got := intervalOverlaps(tt.s, tt.e, &start, &end); got != tt.want {
+			t.Errorf("%v-%v overlaps = %v, want %v", tt.s.Hour(), tt.e.Hour(), got, tt.want)
+		}
+	}
+}
+
+func TestCachingClient_RestoresFromStore(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	store := openTestStore(t, dir)
+	first := &MockClient{Syncs: []*SyncResult{{Token: "t1", Full: true, Changed: []Event{seriesEvent(t)}}}}
+	cc := NewCachingClient(first, time.Minute, openTestAccount(t, store, "work"))
+	if _, err := cc.SearchEvents(ctx, "/cal/", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// After a restart, only the changes since the stored token are fetched
+	second := &MockClient{Syncs: []*SyncResult{{Token: "t2"}}}
+	cc = NewCachingClient(second, time.Minute, openTestAccount(t, openTestStore(t, dir), "work"))
+	events, err := cc.SearchEvents(ctx, "/cal", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.LastSyncToken != "t1" {
+		t.Errorf("sync token = %q, want the stored t1", second.LastSyncToken)
+	}
+	if len(events) != 1 || events[0].ID != "series" || len(events[0].Overrides) != 1 {
+		t.Errorf("events = %+v, want the stored series", events)
+	}
+}
+
+func TestCachingClient_StaleWhenUnreachable(t *testing.T) {
+	ctx := context.Background()
+	synced := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	mock := &MockClient{
+		Events:    []Event{{ID: "a", Path: "/cal/a.ics"}},
+		Calendars: []Calendar{{Path: "/cal/", Name: "Work"}},
+	}
+	cc := NewCachingClient(mock, 0, nil)
+	cc.now = func() time.Time { return synced }
+	if _, err := cc.SearchEvents(ctx, "/cal/", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := cc.ListCalendars(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mock.SyncErr = fmt.Errorf("dial tcp: no such host")
+	mock.ListCalendarsErr = &statusError{code: http.StatusServiceUnavailable}
+	events, err := cc.SearchEvents(ctx, "/cal/", nil, nil)
+	var stale *StaleError
+	if !errors.As(err, &stale) || !stale.Since.Equal(synced) {
+		t.Fatalf("err = %v, want a StaleError as of the last sync", err)
+	}
+	if len(events) != 1 || mock.SearchCallCount != 0 {
+		t.Errorf("events = %d, searches = %d, want the cached event without a search", len(events), mock.SearchCallCount)
+	}
+
+	calendars, err := cc.ListCalendars(ctx)
+	if !errors.As(err, &stale) || len(calendars) != 1 {
+		t.Errorf("calendars = %v, err = %v, want the last list with a StaleError", calendars, err)
+	}
+
+	// Refusals are not answered from the cache
+	mock.SyncErr = &statusError{code: http.StatusUnauthorized}
+	mock.ListCalendarsErr = mock.SyncErr
+	if _, err := cc.SearchEvents(ctx, "/cal/", nil, nil); errors.As(err, &stale) || mock.SearchCallCount != 1 {
+		t.Errorf("err = %v, searches = %d, want the search passed on to the server", err, mock.SearchCallCount)
+	}
+	if _, err := cc.ListCalendars(ctx); err == nil || errors.As(err, &stale) {
+		t.Errorf("err = %v, want the server's refusal", err)
+	}
+}
+
+func TestCachingClient_StaleFromStore(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	store := openTestStore(t, dir)
+	online := &MockClient{
+		Syncs:     []*SyncResult{{Token: "t1", Full: true, Changed: []Event{seriesEvent(t)}}},
+		Calendars: []Calendar{{Path: "/cal/", Name: "Work"}},
+	}
+	cc := NewCachingClient(online, 0, openTestAccount(t, store, "work"))
+	_, _ = cc.SearchEvents(ctx, "/cal/", nil, nil)
+	_, _ = cc.ListCalendars(ctx)
+	_ = store.Close()
+
+	// Started again while offline
+	offline := &MockClient{Err: fmt.Errorf("network is unreachable"), SyncErr: fmt.Errorf("network is unreachable")}
+	cc = NewCachingClient(offline, 0, openTestAccount(t, openTestStore(t, dir), "work"))
+	var stale *StaleError
+	if events, err := cc.SearchEvents(ctx, "/cal/", nil, nil); !errors.As(err, &stale) || len(events) != 1 {
+		t.Errorf("events = %d, err = %v, want the stored event with a StaleError", len(events), err)
+	}
+	if calendars, err := cc.ListCalendars(ctx); !errors.As(err, &stale) || len(calendars) != 1 {
+		t.Errorf("calendars = %d, err = %v, want the stored list with a StaleError", len(calendars), err)
+	}
+	if _, err := cc.SearchEvents(ctx, "/other/", nil, nil); err == nil || errors.As(err, &stale) {
+		t.Errorf("err = %v, want a plain error for a calendar that was never synced", err)
+	}
+}
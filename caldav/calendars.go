@@ -0,0 +1,147 @@
+package caldav
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/emersion/go-ical"
+	"github.com/google/uuid"
+)
+
+// CalendarUpdate represents changes to a calendar's properties.
+// nil pointer = don't change, non-nil empty string = remove the property.
+type CalendarUpdate struct {
+	// Name is the display name, which cannot be removed.
+	Name        *string
+	Description *string
+	// Color is "#RRGGBB" or "#RRGGBBAA", as used by Apple Calendar.
+	Color *string
+	// Order is the calendar's position in Apple Calendar's list.
+	Order *int
+}
+
+var colorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}([0-9A-Fa-f]{2})?$`)
+
+// ValidateCalendarColor checks that color is "#RRGGBB" or "#RRGGBBAA".
+func ValidateCalendarColor(color string) error {
+	if !colorPattern.MatchString(color) {
+		return fmt.Errorf("invalid color %q: use #RRGGBB or #RRGGBBAA, e.g. #FF2968", color)
+	}
+	return nil
+}
+
+// ValidateCalendarComponent checks that component is a component type a
+// calendar can hold: VEVENT for events or VTODO for tasks.
+func ValidateCalendarComponent(component string) error {
+	switch component {
+	case ical.CompEvent, ical.CompToDo:
+		return nil
+	}
+	return fmt.Errorf("invalid component %q: use %s or %s", component, ical.CompEvent, ical.CompToDo)
+}
+
+// CreateCalendar creates a calendar in the account's calendar home with a
+// MKCALENDAR request (RFC 4791 section 5.3.1) and returns its path. The
+// calendar's Name is required; its Description, Color and
+// SupportedComponents are set if given, and a calendar without
+// SupportedComponents accepts whatever the server allows.
+func (c *Client) CreateCalendar(ctx context.Context, calendar *Calendar) (string, error) {
+	if strings.TrimSpace(calendar.Name) == "" {
+		return "", fmt.Errorf("calendar name cannot be empty")
+	}
+	props := calendarProps{name: &calendar.Name}
+	if calendar.Description != "" {
+		props.description = &calendar.Description
+	}
+	if calendar.Color != "" {
+		if err := ValidateCalendarColor(calendar.Color); err != nil {
+			return "", err
+		}
+		props.color = &calendar.Color
+	}
+	for _, comp := range calendar.SupportedComponents {
+		if err := ValidateCalendarComponent(comp); err != nil {
+			return "", err
+		}
+	}
+	props.components = calendar.SupportedComponents
+
+	homeSet, err := c.DiscoverCalendarHomeSet(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// Apple names calendar collections by UUID as well
+	calendarPath := path.Join(homeSet, strings.ToUpper(uuid.New().String())) + "/"
+	if err := c.backend.MakeCalendar(ctx, calendarPath, props); err != nil {
+		return "", fmt.Errorf("failed to create calendar: %w", err)
+	}
+	return calendarPath, nil
+}
+
+// UpdateCalendar changes the properties of the calendar at calendarPath
+// with a PROPPATCH request. The server applies all changes or none.
+func (c *Client) UpdateCalendar(ctx context.Context, calendarPath string, update *CalendarUpdate) error {
+	if update.Name == nil && update.Description == nil && update.Color == nil && update.Order == nil {
+		return fmt.Errorf("no calendar changes given")
+	}
+	if update.Name != nil && strings.TrimSpace(*update.Name) == "" {
+		return fmt.Errorf("calendar name cannot be empty")
+	}
+	if update.Color != nil && *update.Color != "" {
+		if err := ValidateCalendarColor(*update.Color); err != nil {
+			return err
+		}
+	}
+
+	props := calendarProps{
+		name:        update.Name,
+		description: update.Description,
+		color:       update.Color,
+		order:       update.Order,
+	}
+	if err := c.backend.PatchCalendar(ctx, calendarPath, props); err != nil {
+		return fmt.Errorf("failed to update calendar: %w", err)
+	}
+	return nil
+}
+
+// DeleteCalendar deletes the calendar at calendarPath with every event and
+// task in it. Only calendar collections inside the account's calendar home
+// can be deleted: never the home itself, the scheduling inbox or outbox, or
+// any other collection.
+func (c *Client) DeleteCalendar(ctx context.Context, calendarPath string) error {
+	homeSet, err := c.DiscoverCalendarHomeSet(ctx)
+	if err != nil {
+		return err
+	}
+	home := strings.TrimSuffix(homeSet, "/") + "/"
+	p := path.Clean(calendarPath)
+	if p+"/" == home || !strings.HasPrefix(p, home) {
+		return fmt.Errorf("failed to delete calendar: %s is not a calendar in the calendar home %s", calendarPath, homeSet)
+	}
+	// Should the scheduling lookup fail, the resource type check below still
+	// refuses the inbox and outbox
+	if info, err := c.scheduling(ctx); err == nil {
+		for _, box := range []string{info.inbox, info.outbox} {
+			if box != "" && path.Clean(box) == p {
+				return fmt.Errorf("failed to delete calendar: %s is the scheduling inbox or outbox", calendarPath)
+			}
+		}
+	}
+	isCalendar, err := c.backend.IsCalendar(ctx, calendarPath)
+	if err != nil {
+		return fmt.Errorf("failed to delete calendar: %w", err)
+	}
+	if !isCalendar {
+		return fmt.Errorf("failed to delete calendar: %s is not a calendar", calendarPath)
+	}
+
+	if err := c.backend.Remove(ctx, calendarPath, precondition{}); err != nil {
+		return fmt.Errorf("failed to delete calendar: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCreateCalendar(t *testing.T) {
+	mb := &mockBackend{principal: "/principals/user/", homeSet: "/calendars/user/"}
+	c := NewClientWithBackend(mb)
+
+	calendarPath, err := c.CreateCalendar(context.Background(), &Calendar{
+		Name:                "Project X",
+		Color:               "#FF2968",
+		SupportedComponents: []string{"VEVENT"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(calendarPath, "/calendars/user/") || !strings.HasSuffix(calendarPath, "/") {
+		t.Errorf("path = %q, want a collection in the calendar home", calendarPath)
+	}
+	if mb.madeCalendar != calendarPath {
+		t.Errorf("MKCALENDAR path = %q, want %q", mb.madeCalendar, calendarPath)
+	}
+	props := mb.calendarProps
+	if *props.name != "Project X" || *props.color != "#FF2968" || props.description != nil {
+		t.Errorf("props = %+v", props)
+	}
+	if len(props.components) != 1 || props.components[0] != "VEVENT" {
+		t.Errorf("components = %v, want [VEVENT]", props.components)
+	}
+}
+
+func TestCreateCalendar_Invalid(t *testing.T) {
+	tests := map[string]*Calendar{
+		"empty name":  {Name: " "},
+		"bad color":   {Name: "Work", Color: "red"},
+		"bad comp":    {Name: "Work", SupportedComponents: []string{"VJOURNAL"}},
+		"short color": {Name: "Work", Color: "#FFF"},
+	}
+	for name, calendar := range tests {
+		t.Run(name, func(t *testing.T) {
+			mb := &mockBackend{principal: "/principals/user/", homeSet: "/calendars/user/"}
+			c := NewClientWithBackend(mb)
+			if _, err := c.CreateCalendar(context.Background(), calendar); err == nil {
+				t.Fatal("expected error")
+			}
+			if mb.madeCalendar != "" {
+				t.Error("MKCALENDAR sent for an invalid calendar")
+			}
+		})
+	}
+}
+
+func TestUpdateCalendar(t *testing.T) {
+	mb := &mockBackend{}
+	c := NewClientWithBackend(mb)
+
+	name, description, order := "Renamed", "", 3
+	err := c.UpdateCalendar(context.Background(), "/calendars/user/work/", &CalendarUpdate{
+		Name:        &name,
+		Description: &description,
+		Order:       &order,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mb.patchedPath != "/calendars/user/work/" {
+		t.Errorf("PROPPATCH path = %q", mb.patchedPath)
+	}
+	if *mb.calendarProps.name != "Renamed" || *mb.calendarProps.description != "" || *mb.calendarProps.order != 3 || mb.calendarProps.color != nil {
+		t.Errorf("props = %+v", mb.calendarProps)
+	}
+
+	empty := ""
+	for _, update := range []*CalendarUpdate{{}, {Name: &empty}} {
+		mb.patchedPath = ""
+		if err := c.UpdateCalendar(context.Background(), "/calendars/user/work/", update); err == nil {
+			t.Errorf("update %+v: expected error", update)
+		}
+		if mb.patchedPath != "" {
+			t.Errorf("update %+v: PROPPATCH sent", update)
+		}
+	}
+
+	mb.calendarErr = errors.New("403 Forbidden")
+	if err := c.UpdateCalendar(context.Background(), "/calendars/user/work/", &CalendarUpdate{Name: &name}); err == nil {
+		t.Error("expected the server's error")
+	}
+}
+
+func TestDeleteCalendar(t *testing.T) {
+	mb := &mockBackend{principal: "/principals/user/", homeSet: "/calendars/user/"}
+	c := NewClientWithBackend(mb)
+
+	if err := c.DeleteCalendar(context.Background(), "/calendars/user/work/"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mb.lastRemovePath != "/calendars/user/work/" {
+		t.Errorf("removed %q, want /calendars/user/work/", mb.lastRemovePath)
+	}
+
+	for _, p := range []string{"/calendars/user/", "/calendars/user", "/calendars/other/work/", "/calendars/username/"} {
+		mb.lastRemovePath = ""
+		if err := c.DeleteCalendar(context.Background(), p); err == nil {
+			t.Errorf("%s: expected error", p)
+		}
+		if mb.lastRemovePath != "" {
+			t.Errorf("%s: DELETE sent", p)
+		}
+	}
+}
+
+func TestDeleteCalendar_OnlyCalendars(t *testing.T) {
+	mb := &mockBackend{
+		principal:    "/principals/user/",
+		homeSet:      "/calendars/user/",
+		scheduling:   &schedulingInfo{inbox: "/calendars/user/inbox/", outbox: "/calendars/user/outbox/"},
+		notCalendars: map[string]bool{"/calendars/user/dropbox/": true},
+	}
+	c := NewClientWithBackend(mb)
+
+	for _, p := range []string{"/calendars/user/inbox/", "/calendars/user/outbox", "/calendars/user/dropbox/"} {
+		if err := c.DeleteCalendar(context.Background(), p); err == nil {
+			t.Errorf("%s: expected error", p)
+		}
+		if mb.lastRemovePath != "" {
+			t.Errorf("%s: DELETE sent", p)
+		}
+	}
+
+	mb.isCalendarErr = errors.New("503 Service Unavailable")
+	if err := c.DeleteCalendar(context.Background(), "/calendars/user/work/"); err == nil {
+		t.Error("expected the PROPFIND error")
+	}
+	if mb.lastRemovePath != "" {
+		t.Error("DELETE sent although the resource type is unknown")
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
+	"path"
 	"strings"
 	"sync"
 	"time"
@@ -26,9 +28,14 @@ const (
 // Client wraps the CalDAV client with iCloud-specific functionality
 type Client struct {
 	backend         backend
+	email           string
+	principal       string
 	calendarHomeSet string
 	homeSetOnce     sync.Once
 	homeSetErr      error
+	schedulingInfo  *schedulingInfo
+	schedulingOnce  sync.Once
+	schedulingErr   error
 }
 
 // Calendar represents a calendar with its metadata
@@ -36,21 +43,131 @@ type Calendar struct {
 	Path        string
 	Name        string
 	Description string
-	Color       string
+	// Color is Apple's calendar color, "#RRGGBB" or "#RRGGBBAA".
+	Color string
+	// Order is the calendar's position in Apple Calendar's list, or 0 if
+	// the server did not say.
+	Order int
+	// SupportedComponents lists the component types the calendar accepts,
+	// such as "VEVENT" or "VTODO". Empty means the server did not say.
+	SupportedComponents []string
+	// ReadOnly is set when the current user may not add or change events in
+	// the calendar, such as a subscribed or read-only shared calendar.
+	ReadOnly bool
+	// Owner is the path of the principal that owns the calendar, and Shared
+	// is set when that is not the current user.
+	Owner  string
+	Shared bool
+	// CTag changes whenever anything in the calendar changes.
+	CTag string
+}
+
+// Supports reports whether the calendar accepts components of the given
+// type, such as ical.CompEvent. Calendars that do not list their supported
+// components are assumed to accept any.
+func (c Calendar) Supports(component string) bool {
+	if len(c.SupportedComponents) == 0 {
+		return true
+	}
+	for _, comp := range c.SupportedComponents {
+		if strings.EqualFold(comp, component) {
+			return true
+		}
+	}
+	return false
 }
 
 // Event represents a calendar event
 type Event struct {
-	ID          string     `json:"id"`
-	Path        string     `json:"path"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	Location    string     `json:"location"`
-	StartTime   time.Time  `json:"startTime"`
-	EndTime     time.Time  `json:"endTime"`
-	Recurrence  string     `json:"recurrence,omitempty"`
-	Timezone    string     `json:"timezone"`
-	Attendees   []Attendee `json:"attendees,omitempty"`
+	ID          string    `json:"id"`
+	Path        string    `json:"path"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Location    string    `json:"location"`
+	StartTime   time.Time `json:"startTime"`
+	EndTime     time.Time `json:"endTime"`
+	Recurrence  string    `json:"recurrence,omitempty"`
+	// RecurrenceDates (RDATE) adds occurrences to the series and
+	// ExceptionDates (EXDATE) removes them.
+	RecurrenceDates []time.Time `json:"recurrenceDates,omitempty"`
+	ExceptionDates  []time.Time `json:"exceptionDates,omitempty"`
+	Timezone        string      `json:"timezone"` // IANA zone of a timed event; empty means UTC
+	Attendees       []Attendee  `json:"attendees,omitempty"`
+	// Organizer is the email address of the event's organizer. CreateEvent
+	// sets it to the account's address for events with attendees.
+	Organizer string  `json:"organizer,omitempty"`
+	Alarms    []Alarm `json:"alarms,omitempty"`
+	ETag      string  `json:"etag,omitempty"`
+	// AllDay events span whole dates. StartTime and EndTime are midnight UTC
+	// of the first day and of the day after the last day (exclusive end).
+	AllDay bool `json:"allDay,omitempty"`
+	// Floating events happen at the same wall-clock time in every time zone.
+	// Their StartTime and EndTime carry that wall-clock time in UTC. Events
+	// whose TZID is not a known IANA zone are reported as floating too.
+	Floating bool `json:"floating,omitempty"`
+	// Status is the event's STATUS: EventTentative, EventConfirmed or
+	// EventCancelled.
+	Status string `json:"status,omitempty"`
+	// Transparent events (TRANSP:TRANSPARENT) do not block time in
+	// free/busy lookups.
+	Transparent bool `json:"transparent,omitempty"`
+	// Categories are the event's CATEGORIES, such as "Work".
+	Categories []string `json:"categories,omitempty"`
+	// URL links to a page about the event, such as an agenda.
+	URL string `json:"url,omitempty"`
+	// Class is the access classification: ClassPublic, ClassPrivate or
+	// ClassConfidential.
+	Class string `json:"class,omitempty"`
+	// Priority ranges from 1 (highest) to 9 (lowest); 0 is undefined.
+	Priority int `json:"priority,omitempty"`
+	// Sequence is the event's revision (SEQUENCE). UpdateEvent increases it
+	// on significant changes, such as new times or a cancellation.
+	Sequence int `json:"sequence,omitempty"`
+	// Created and LastModified are when the event was created and last
+	// changed in the calendar. They are set by CreateEvent and UpdateEvent.
+	Created      *time.Time `json:"created,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	// RecurrenceID is the original start of an occurrence of a recurring
+	// event. It is set on overrides and on occurrences from ExpandRecurrence,
+	// and identifies the occurrence to update_event and delete_event.
+	RecurrenceID *time.Time `json:"recurrenceId,omitempty"`
+	// OccurrenceID identifies an occurrence by UID and RECURRENCE-ID in one
+	// string; see OccurrenceID.
+	OccurrenceID string `json:"occurrenceId,omitempty"`
+	// Overrides are the occurrences of a recurring event that were moved,
+	// edited or cancelled on their own (VEVENTs with a RECURRENCE-ID).
+	// CreateEvent writes those with a Raw component along with the event.
+	Overrides []Event `json:"overrides,omitempty"`
+	// Raw is the VEVENT the event was read from, with every property and
+	// component, including those the fields above do not map (CATEGORIES,
+	// URL, X-APPLE-* properties, attachments, ...). CreateEvent starts from
+	// a copy of it, so an event copied from another calendar keeps them.
+	Raw *ical.Component `json:"-"`
+}
+
+// eventFieldProps are the VEVENT properties CreateEvent writes from the
+// fields of Event, replacing those in Event.Raw.
+var eventFieldProps = []string{
+	ical.PropUID,
+	ical.PropSummary,
+	ical.PropDescription,
+	ical.PropLocation,
+	ical.PropDateTimeStart,
+	ical.PropDateTimeEnd,
+	ical.PropDuration,
+	ical.PropRecurrenceID,
+	ical.PropRecurrenceRule,
+	ical.PropRecurrenceDates,
+	ical.PropExceptionDates,
+	ical.PropDateTimeStamp,
+	ical.PropStatus,
+	ical.PropTransparency,
+	ical.PropCategories,
+	ical.PropURL,
+	ical.PropClass,
+	ical.PropPriority,
+	ical.PropCreated,
+	ical.PropLastModified,
 }
 
 // EventUpdate represents fields to update on an event.
@@ -61,15 +178,80 @@ type EventUpdate struct {
 	Location    *string
 	StartTime   *time.Time
 	EndTime     *time.Time
+	// Recurrence replaces the RRULE; an empty string makes the event
+	// non-recurring. RecurrenceDates and ExceptionDates replace the RDATE
+	// and EXDATE lists; an empty slice clears them.
+	Recurrence      *string
+	RecurrenceDates *[]time.Time
+	ExceptionDates  *[]time.Time
+	// Timezone, if set, moves the event's times to this IANA zone; "" or
+	// "UTC" stores them in UTC. New times are written in the event's current
+	// zone unless Timezone is set.
+	Timezone *string
+	// AllDay, if set, converts the event to or from an all-day event.
+	// Converting to a timed event requires StartTime and EndTime.
+	AllDay *bool
+	// Alarms replaces the event's alarms; an empty slice removes them. Left
+	// nil, existing alarms are kept as they are.
+	Alarms *[]Alarm
+	// Attendees adds, removes or replaces attendees. Left nil, the
+	// attendee list is kept as it is.
+	Attendees *AttendeeChanges
+	// Status, URL and Class replace the STATUS, URL and CLASS; an empty
+	// string removes them. Categories replaces the CATEGORIES; an empty
+	// slice removes them. Priority is 0 to 9, with 0 clearing it.
+	Status      *string
+	Transparent *bool
+	Categories  *[]string
+	URL         *string
+	Class       *string
+	Priority    *int
+	// Organizer sets the email address of the organizer of an event with
+	// attendees, as Event.Organizer does for CreateEvent. An empty string
+	// makes the account the organizer again, or removes the ORGANIZER of an
+	// event without attendees.
+	Organizer *string
+	// RecurrenceID selects an occurrence of a recurring event by its original
+	// start, and Scope the occurrences the update applies to: ScopeAll (the
+	// default), ScopeThis or ScopeThisAndFollowing.
+	RecurrenceID *time.Time
+	Scope        string
+	// NewSeriesID is the UID to give the series split off by
+	// ScopeThisAndFollowing. One is generated if empty.
+	NewSeriesID string
+	// ETag, if set, makes the update fail with a *ConflictError unless the
+	// event still has this ETag on the server.
+	ETag string
+}
+
+// SearchOptions configures SearchEvents.
+type SearchOptions struct {
+	// Expand returns each occurrence of a recurring event in the range as an
+	// Event of its own, and requires both a start and an end time. The server
+	// is asked to expand the series (RFC 4791 <C:expand>); series it returns
+	// unexpanded are expanded with ExpandRecurrence.
+	Expand bool
+	// Filter restricts the results to events containing the given texts.
+	// It is sent to the server as text-match prop-filters and applied again
+	// to the results, so servers that ignore or refuse text-match still
+	// return only matching events.
+	Filter EventFilter
 }
 
 // ClientOptions configures the CalDAV client.
 type ClientOptions struct {
+	// ServerURL is the CalDAV server to connect to. Empty selects iCloud.
+	// See ResolveServerURL for the accepted forms.
+	ServerURL       string
 	MaxConnsPerHost int
 	Timeout         time.Duration
 	TLSCertFile     string
 	TLSKeyFile      string
 	TLSCAFile       string
+	// Principal and CalendarHomeSet, if both set, are the account's principal
+	// and calendar home set from an earlier run, and skip their discovery.
+	Principal       string
+	CalendarHomeSet string
 }
 
 // DefaultClientOptions returns sensible defaults.
@@ -80,7 +262,8 @@ func DefaultClientOptions() ClientOptions {
 	}
 }
 
-// NewClient creates a new CalDAV client configured for iCloud
+// NewClient creates a new CalDAV client. It connects to iCloud unless
+// opts.ServerURL selects another server.
 func NewClient(email, password string, opts ...ClientOptions) (*Client, error) {
 	opt := DefaultClientOptions()
 	if len(opts) > 0 {
@@ -132,15 +315,36 @@ func NewClient(email, password string, opts ...ClientOptions) (*Client, error) {
 	// Create basic auth HTTP client
 	authClient := webdav.HTTPClientWithBasicAuth(httpClient, email, password)
 
+	// Resolve the server URL, using a client that reports redirects instead of
+	// following them so well-known lookups can see where they point.
+	discoveryClient := *httpClient
+	discoveryClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), opt.Timeout)
+	defer cancel()
+	serverURL, err := ResolveServerURL(ctx, webdav.HTTPClientWithBasicAuth(&discoveryClient, email, password), opt.ServerURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve CalDAV server URL: %w", err)
+	}
+
 	// Create CalDAV client
-	caldavClient, err := caldav.NewClient(authClient, iCloudBaseURL)
+	caldavClient, err := newDAVBackend(authClient, serverURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create CalDAV client: %w", err)
 	}
 
-	return &Client{
+	c := &Client{
 		backend: caldavClient,
-	}, nil
+		email:   email,
+	}
+	if opt.Principal != "" && opt.CalendarHomeSet != "" {
+		c.homeSetOnce.Do(func() {
+			c.principal = opt.Principal
+			c.calendarHomeSet = opt.CalendarHomeSet
+		})
+	}
+	return c, nil
 }
 
 // NewClientWithBackend creates a Client with a custom backend for testing.
@@ -158,6 +362,8 @@ func (c *Client) DiscoverCalendarHomeSet(ctx context.Context) (string, error) {
 			return
 		}
 
+		c.principal = principal
+
 		homeSet, err := c.backend.FindCalendarHomeSet(ctx, principal)
 		if err != nil {
 			c.homeSetErr = fmt.Errorf("failed to find calendar home set: %w", err)
@@ -169,32 +375,43 @@ func (c *Client) DiscoverCalendarHomeSet(ctx context.Context) (string, error) {
 	return c.calendarHomeSet, c.homeSetErr
 }
 
-// ListCalendars lists all available calendars
+// Principal returns the current user's principal path found by
+// DiscoverCalendarHomeSet, or "" before discovery.
+func (c *Client) Principal() string {
+	return c.principal
+}
+
+// ListCalendars lists all available calendars with their metadata, the
+// current user's access and whether they are shared by someone else.
 func (c *Client) ListCalendars(ctx context.Context) ([]Calendar, error) {
 	homeSet, err := c.DiscoverCalendarHomeSet(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	caldavCals, err := c.backend.FindCalendars(ctx, homeSet)
+	calendars, err := c.backend.FindCalendars(ctx, homeSet)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find calendars: %w", err)
 	}
 
-	calendars := make([]Calendar, 0, len(caldavCals))
-	for _, cal := range caldavCals {
-		calendars = append(calendars, Calendar{
-			Path:        cal.Path,
-			Name:        cal.Name,
-			Description: cal.Description,
-		})
+	for i := range calendars {
+		owner := calendars[i].Owner
+		calendars[i].Shared = owner != "" && c.principal != "" &&
+			strings.TrimSuffix(owner, "/") != strings.TrimSuffix(c.principal, "/")
 	}
-
 	return calendars, nil
 }
 
 // SearchEvents searches for events in a calendar with optional date filters
-func (c *Client) SearchEvents(ctx context.Context, calendarPath string, startTime, endTime *time.Time) ([]Event, error) {
+func (c *Client) SearchEvents(ctx context.Context, calendarPath string, startTime, endTime *time.Time, opts ...SearchOptions) ([]Event, error) {
+	var opt SearchOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.Expand && (startTime == nil || endTime == nil) {
+		return nil, fmt.Errorf("expanding recurring events requires both a start and an end time")
+	}
+
 	// Build the query
 	query := &caldav.CalendarQuery{
 		CompRequest: caldav.CalendarCompRequest{
@@ -208,13 +425,15 @@ func (c *Client) SearchEvents(ctx context.Context, calendarPath string, startTim
 		},
 	}
 
-	// Add time range filter if provided
-	if startTime != nil || endTime != nil {
+	// Add time range and text filters if provided
+	textFilters := opt.Filter.propFilters()
+	if startTime != nil || endTime != nil || len(textFilters) > 0 {
 		compFilter := caldav.CompFilter{
 			Name: "VCALENDAR",
 			Comps: []caldav.CompFilter{
 				{
-					Name: "VEVENT",
+					Name:  "VEVENT",
+					Props: textFilters,
 				},
 			},
 		}
@@ -229,38 +448,199 @@ func (c *Client) SearchEvents(ctx context.Context, calendarPath string, startTim
 		query.CompFilter = compFilter
 	}
 
+	if opt.Expand {
+		query.CompRequest.Expand = &caldav.CalendarExpandRequest{Start: *startTime, End: *endTime}
+	}
+
 	calendarObjects, err := c.backend.QueryCalendar(ctx, calendarPath, query)
+	if err != nil && len(textFilters) > 0 && reportUnsupported(err) {
+		// Servers that do not support text-match refuse the filter; the
+		// results are filtered locally instead.
+		slog.Debug("text-match refused, filtering locally", "path", calendarPath, "error", err)
+		query.CompFilter.Comps[0].Props = nil
+		if startTime == nil && endTime == nil {
+			query.CompFilter = caldav.CompFilter{}
+		}
+		calendarObjects, err = c.backend.QueryCalendar(ctx, calendarPath, query)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to query calendar: %w", err)
 	}
 
 	events := make([]Event, 0, len(calendarObjects))
 	for _, obj := range calendarObjects {
-		event, err := c.parseCalendarObject(&obj)
+		event, err := parseCalendarObject(&obj)
 		if err != nil {
 			slog.Warn("skipping unparseable calendar object", "path", obj.Path, "error", err)
 			continue
 		}
-		events = append(events, *event)
+		if opt.Expand {
+			occurrences, err := expandEvent(*event, *startTime, *endTime)
+			if err != nil {
+				slog.Warn("skipping calendar object with invalid recurrence", "path", obj.Path, "error", err)
+				continue
+			}
+			for _, occurrence := range occurrences {
+				if opt.Filter.matches(occurrence) {
+					events = append(events, occurrence)
+				}
+			}
+			continue
+		}
+		if opt.Filter.matches(*event) {
+			events = append(events, *event)
+		}
 	}
 
 	return events, nil
 }
 
+// expandEvent returns the occurrences of a parsed calendar object within the
+// range. A server that honoured <C:expand> returns one VEVENT per occurrence,
+// each with a RECURRENCE-ID and without RRULE or RDATE, which parse as an
+// event with overrides; a series it left unexpanded is expanded locally.
+func expandEvent(event Event, rangeStart, rangeEnd time.Time) ([]Event, error) {
+	if event.Recurrence != "" || len(event.RecurrenceDates) > 0 {
+		return ExpandRecurrence(event, rangeStart, rangeEnd)
+	}
+	instances := append([]Event{event}, event.Overrides...)
+	events := make([]Event, 0, len(instances))
+	for _, e := range instances {
+		if e.RecurrenceID != nil && e.Status == EventCancelled {
+			continue
+		}
+		e.Overrides = nil
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// GetEvent returns the event at eventPath together with its complete
+// calendar object. If nothing exists at eventPath, whose resource name is
+// usually the event's UID, the calendar is searched for an event with that
+// UID, since events created by other clients may be stored under other names.
+func (c *Client) GetEvent(ctx context.Context, eventPath string) (*Event, *EventObject, error) {
+	obj, _, err := c.getEvent(ctx, eventPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	event, err := parseCalendarObject(obj)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	return event, &EventObject{Path: obj.Path, ETag: event.ETag, Data: obj.Data}, nil
+}
+
+// getEvent fetches the event at eventPath. If nothing exists there, the
+// event is looked up by the UID in the resource name, since other clients
+// may store events under other names. It also returns the path the event
+// was found at.
+func (c *Client) getEvent(ctx context.Context, eventPath string) (*caldav.CalendarObject, string, error) {
+	return c.getObject(ctx, eventPath, ical.CompEvent)
+}
+
+// getObject is getEvent for objects holding a comp component, such as
+// VTODO for tasks.
+func (c *Client) getObject(ctx context.Context, objectPath, comp string) (*caldav.CalendarObject, string, error) {
+	obj, err := c.backend.GetCalendarObject(ctx, objectPath)
+	if httpStatus(err) != http.StatusNotFound {
+		return obj, objectPath, err
+	}
+	obj, err = c.findObject(ctx, calendarOf(objectPath), comp, eventUID(objectPath))
+	if err != nil {
+		return nil, "", err
+	}
+	return obj, obj.Path, nil
+}
+
+// eventUID returns the UID an event path built by GetEventPath names.
+func eventUID(eventPath string) string {
+	return strings.TrimSuffix(path.Base(eventPath), ".ics")
+}
+
+// calendarOf returns the path of the calendar collection holding eventPath.
+func calendarOf(eventPath string) string {
+	return path.Dir(eventPath) + "/"
+}
+
+// findObject looks up the object with the given UID in the calendar at
+// calendarPath with a calendar-query filtered on UID within comp components
+// (VEVENT or VTODO). It returns an error wrapping ErrEventNotFound if there
+// is none.
+func (c *Client) findObject(ctx context.Context, calendarPath, comp, uid string) (*caldav.CalendarObject, error) {
+	query := &caldav.CalendarQuery{
+		CompRequest: caldav.CalendarCompRequest{
+			Name:     "VCALENDAR",
+			AllProps: true,
+			AllComps: true,
+		},
+		CompFilter: caldav.CompFilter{
+			Name: "VCALENDAR",
+			Comps: []caldav.CompFilter{
+				{
+					Name:  comp,
+					Props: []caldav.PropFilter{{Name: ical.PropUID, TextMatch: &caldav.TextMatch{Text: uid}}},
+				},
+			},
+		},
+	}
+	objects, err := c.backend.QueryCalendar(ctx, calendarPath, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query calendar: %w", err)
+	}
+
+	// text-match is a substring match, so check the UID exactly
+	for i := range objects {
+		if objects[i].Data == nil {
+			continue
+		}
+		if found := findComponent(objects[i].Data, comp); found != nil {
+			if prop := found.Props.Get(ical.PropUID); prop != nil && prop.Value == uid {
+				return &objects[i], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no %s with UID %s in %s: %w", componentKind(comp), uid, calendarPath, ErrEventNotFound)
+}
+
+// componentKind names what a comp component is to the user.
+func componentKind(comp string) string {
+	if comp == ical.CompToDo {
+		return "task"
+	}
+	return "event"
+}
+
 // CreateEvent creates a new event in the specified calendar
 func (c *Client) CreateEvent(ctx context.Context, calendarPath string, event *Event) (string, error) {
+	if err := validateMetadata(event.Status, event.Class, event.URL, event.Priority); err != nil {
+		return "", fmt.Errorf("failed to create event: %w", err)
+	}
+	if event.Organizer != "" {
+		if err := ValidateOrganizer(event.Organizer); err != nil {
+			return "", fmt.Errorf("failed to create event: %w", err)
+		}
+	}
+
 	// Create iCalendar object
-	cal := ical.NewCalendar()
-	cal.Props.SetText(ical.PropVersion, "2.0")
-	cal.Props.SetText(ical.PropProductID, "-//mcp-icloud-calendar//EN")
+	cal := newCalendar()
 
-	// Create event component
+	// Create event component. An event read from a server starts from a
+	// copy of the component it was read from, so that the properties and
+	// components Event does not map are kept.
 	vevent := ical.NewEvent()
+	if event.Raw != nil {
+		vevent.Component = cloneComponent(event.Raw)
+		for _, name := range eventFieldProps {
+			vevent.Props.Del(name)
+		}
+	}
 
 	// Generate UID if not provided
 	uid := event.ID
 	if uid == "" {
-		uid = fmt.Sprintf("%s@mcp-icloud-calendar", uuid.New().String())
+		uid = NewEventID()
 	}
 	vevent.Props.SetText(ical.PropUID, uid)
 
@@ -274,69 +654,332 @@ func (c *Client) CreateEvent(ctx context.Context, calendarPath string, event *Ev
 		vevent.Props.SetText(ical.PropLocation, event.Location)
 	}
 
-	vevent.Props.SetDateTime(ical.PropDateTimeStart, event.StartTime)
-	vevent.Props.SetDateTime(ical.PropDateTimeEnd, event.EndTime)
-	vevent.Props.SetDateTime(ical.PropDateTimeStamp, time.Now())
+	if event.Status != "" {
+		vevent.Props.SetText(ical.PropStatus, event.Status)
+	}
+	setTransparency(vevent.Props, event.Transparent)
+	setCategories(vevent.Props, event.Categories)
+	if event.URL != "" {
+		vevent.Props.Set(&ical.Prop{Name: ical.PropURL, Value: event.URL, Params: ical.Params{}})
+	}
+	if event.Class != "" {
+		vevent.Props.SetText(ical.PropClass, event.Class)
+	}
+	if event.Priority != 0 {
+		setInt(vevent.Props, ical.PropPriority, event.Priority)
+	}
 
-	// Add attendees
-	for _, a := range event.Attendees {
-		prop := ical.Prop{
-			Name:   "ATTENDEE",
-			Value:  "mailto:" + a.Email,
-			Params: ical.Params{},
-		}
-		if a.Name != "" {
-			prop.Params["CN"] = []string{a.Name}
-		}
-		if a.Role != "" {
-			prop.Params["ROLE"] = []string{a.Role}
-		} else {
-			prop.Params["ROLE"] = []string{"REQ-PARTICIPANT"}
+	start, end := event.StartTime, event.EndTime
+	loc := time.UTC
+	switch {
+	case event.AllDay:
+		start, end = allDayRange(start, end)
+	case !event.Floating:
+		var err error
+		if loc, err = LoadTimezone(event.Timezone); err != nil {
+			return "", fmt.Errorf("invalid timezone %q: %w", event.Timezone, err)
 		}
-		if a.Status != "" {
-			prop.Params["PARTSTAT"] = []string{a.Status}
-		} else {
-			prop.Params["PARTSTAT"] = []string{"NEEDS-ACTION"}
+		start, end = start.In(loc), end.In(loc)
+		addTimezone(cal, loc, start)
+	}
+	setTimeProp(vevent.Props, ical.PropDateTimeStart, start, event.AllDay, event.Floating)
+	setTimeProp(vevent.Props, ical.PropDateTimeEnd, end, event.AllDay, event.Floating)
+	now := time.Now().UTC()
+	vevent.Props.SetDateTime(ical.PropDateTimeStamp, now)
+	vevent.Props.SetDateTime(ical.PropCreated, now)
+	vevent.Props.SetDateTime(ical.PropLastModified, now)
+
+	// Add recurrence; RDATE and EXDATE take the same form as DTSTART
+	if event.Recurrence != "" {
+		if err := ValidateRecurrenceRule(event.Recurrence); err != nil {
+			return "", fmt.Errorf("failed to create event: %w", err)
 		}
-		vevent.Props["ATTENDEE"] = append(vevent.Props["ATTENDEE"], prop)
+		setRecurrenceRule(vevent.Props, event.Recurrence)
+	}
+	setTimeList(vevent.Props, ical.PropRecurrenceDates, inForm(event.RecurrenceDates, event.AllDay, event.Floating, loc), event.AllDay, event.Floating)
+	setTimeList(vevent.Props, ical.PropExceptionDates, inForm(event.ExceptionDates, event.AllDay, event.Floating, loc), event.AllDay, event.Floating)
+
+	// Add attendees. With an ORGANIZER, servers that support RFC 6638
+	// scheduling deliver the invitations to them.
+	if event.Organizer != "" && len(event.Attendees) > 0 {
+		setOrganizer(vevent.Component, event.Organizer)
+	}
+	attendees := append([]Attendee(nil), event.Attendees...)
+	if err := applyAttendeeChanges(vevent.Component, AttendeeChanges{Replace: &attendees}); err != nil {
+		return "", fmt.Errorf("failed to create event: %w", err)
+	}
+	c.ensureOrganizer(ctx, vevent.Component)
+
+	if err := setAlarms(vevent.Component, event.Alarms); err != nil {
+		return "", fmt.Errorf("failed to create event: %w", err)
 	}
 
 	cal.Children = append(cal.Children, vevent.Component)
+	for _, override := range event.Overrides {
+		if override.Raw != nil {
+			comp := cloneComponent(override.Raw)
+			comp.Props.SetText(ical.PropUID, uid)
+			cal.Children = append(cal.Children, comp)
+		}
+	}
+
+	send, err := c.planClientScheduling(ctx, nil, cal)
+	if err != nil {
+		return "", fmt.Errorf("failed to create event: %w", err)
+	}
 
 	// Create the event path
 	eventPath := fmt.Sprintf("%s/%s.ics", strings.TrimSuffix(calendarPath, "/"), uid)
 
-	// Put the calendar object
-	_, err := c.backend.PutCalendarObject(ctx, eventPath, cal)
+	// Put the calendar object, refusing to overwrite an existing resource
+	_, err = c.backend.PutCalendarObject(ctx, eventPath, cal, precondition{ifNoneMatch: true})
+	if isPreconditionFailed(err) {
+		return "", fmt.Errorf("failed to create event: an event with UID %s already exists", uid)
+	}
 	if err != nil {
 		return "", fmt.Errorf("failed to create event: %w", err)
 	}
 
+	if err := send(ctx); err != nil {
+		return uid, fmt.Errorf("event %s was created, but %w", uid, err)
+	}
 	return uid, nil
 }
 
+// newCalendar creates an empty iCalendar object for a new resource.
+func newCalendar() *ical.Calendar {
+	cal := ical.NewCalendar()
+	cal.Props.SetText(ical.PropVersion, "2.0")
+	cal.Props.SetText(ical.PropProductID, "-//mcp-icloud-calendar//EN")
+	return cal
+}
+
+// NewEventID generates a unique event UID.
+func NewEventID() string {
+	return fmt.Sprintf("%s@mcp-icloud-calendar", uuid.New().String())
+}
+
 // UpdateEvent updates an existing event using pointer fields.
 // nil pointer = don't change, non-nil empty string = clear the field.
-func (c *Client) UpdateEvent(ctx context.Context, eventPath string, update *EventUpdate) error {
-	// Get the existing event
-	existingObj, err := c.backend.GetCalendarObject(ctx, eventPath)
+// The write is conditional on the ETag read from the server, so concurrent
+// edits surface as a *ConflictError instead of being overwritten.
+// UpdateEvent returns the UID of the series split off by
+// ScopeThisAndFollowing, or "" if the event was updated in place.
+func (c *Client) UpdateEvent(ctx context.Context, eventPath string, update *EventUpdate) (string, error) {
+	if err := ValidateScope(update.Scope); err != nil {
+		return "", err
+	}
+	occurrence := update.Scope == ScopeThis || update.Scope == ScopeThisAndFollowing
+	if occurrence && update.RecurrenceID == nil {
+		return "", fmt.Errorf("a recurrence ID is required for scope %q", update.Scope)
+	}
+	if update.Scope == ScopeThis && (update.Recurrence != nil || update.RecurrenceDates != nil || update.ExceptionDates != nil) {
+		return "", fmt.Errorf("recurrence can only be changed for a series, not a single occurrence")
+	}
+	if update.Recurrence != nil && *update.Recurrence != "" {
+		if err := ValidateRecurrenceRule(*update.Recurrence); err != nil {
+			return "", err
+		}
+	}
+	if err := validateMetadata(deref(update.Status), deref(update.Class), deref(update.URL), deref(update.Priority)); err != nil {
+		return "", err
+	}
+	if organizer := deref(update.Organizer); organizer != "" {
+		if err := ValidateOrganizer(organizer); err != nil {
+			return "", err
+		}
+	}
+
+	// Get the existing event, which may live under another name
+	existingObj, eventPath, ifMatch, err := c.getEventForUpdate(ctx, eventPath, update.ETag)
 	if err != nil {
-		return fmt.Errorf("failed to get existing event: %w", err)
+		return "", err
 	}
 
-	// Find the VEVENT component
-	var vevent *ical.Event
-	for _, child := range existingObj.Data.Children {
-		if child.Name == ical.CompEvent {
-			vevent = ical.NewEvent()
-			vevent.Component = child
-			break
+	master, overrides := splitEvents(existingObj.Data)
+	if master == nil {
+		return "", fmt.Errorf("no VEVENT component found in calendar object")
+	}
+	before := cloneCalendar(existingObj.Data)
+
+	target := master
+	if occurrence {
+		form, start, err := formOf(master)
+		if err != nil {
+			return "", err
+		}
+		rid := form.normalize(*update.RecurrenceID)
+		if err := checkOccurrence(master, rid); err != nil {
+			return "", err
+		}
+		switch {
+		case update.Scope == ScopeThis:
+			if target = findOverride(overrides, rid); target == nil {
+				if target, err = newOverride(master, form, rid); err != nil {
+					return "", err
+				}
+				existingObj.Data.Children = append(existingObj.Data.Children, target)
+			}
+		case rid.After(start):
+			return c.splitSeries(ctx, eventPath, before, existingObj.Data, ifMatch, master, form, start, rid, update)
 		}
 	}
 
-	if vevent == nil {
-		return fmt.Errorf("no VEVENT component found in calendar object")
+	if err := applyEventUpdate(existingObj.Data, &ical.Event{Component: target}, update); err != nil {
+		return "", err
+	}
+	if update.Attendees != nil || update.Organizer != nil {
+		c.ensureOrganizer(ctx, target)
+	}
+	send, err := c.planClientScheduling(ctx, before, existingObj.Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to update event: %w", err)
+	}
+
+	// Put the updated calendar object
+	_, err = c.backend.PutCalendarObject(ctx, eventPath, existingObj.Data, precondition{ifMatch: ifMatch})
+	if isPreconditionFailed(err) {
+		return "", c.refetchConflict(ctx, eventPath)
 	}
+	if err != nil {
+		return "", fmt.Errorf("failed to update event: %w", err)
+	}
+
+	if err := send(ctx); err != nil {
+		return "", fmt.Errorf("the event was updated, but %w", err)
+	}
+	return "", nil
+}
+
+// splitSeries applies update to the occurrence at rid and all later ones.
+// They move to a new calendar object with its own UID, holding a copy of the
+// series that starts at rid, and the original series is ended before rid.
+// The new object is written first and removed again if the original cannot
+// be updated, so a failure never loses occurrences. before is a copy of cal
+// as it was read. It returns the UID of the new series.
+func (c *Client) splitSeries(ctx context.Context, eventPath string, before, cal *ical.Calendar, ifMatch string, master *ical.Component, form seriesForm, start, rid time.Time, update *EventUpdate) (string, error) {
+	uid := update.NewSeriesID
+	if uid == "" {
+		uid = NewEventID()
+	}
+
+	following := ical.NewCalendar()
+	following.Props = cloneProps(cal.Props)
+	newMaster := cloneComponent(master)
+	// VTIMEZONEs go first, ahead of the events that reference them
+	for _, child := range cal.Children {
+		if child.Name == ical.CompTimezone {
+			following.Children = append(following.Children, cloneComponent(child))
+		}
+	}
+	following.Children = append(following.Children, newMaster)
+	for _, child := range cal.Children {
+		if child.Name != ical.CompEvent {
+			continue
+		}
+		if t, ok := recurrenceID(child); ok && !t.Before(rid) {
+			override := cloneComponent(child)
+			override.Props.SetText(ical.PropUID, uid)
+			following.Children = append(following.Children, override)
+		}
+	}
+
+	newMaster.Props.SetText(ical.PropUID, uid)
+	if err := moveTo(newMaster, form, rid); err != nil {
+		return "", err
+	}
+	if prop := master.Props.Get(ical.PropRecurrenceRule); prop != nil {
+		_, after, err := splitRule(prop.Value, form, start, rid)
+		if err != nil {
+			return "", err
+		}
+		if after == "" {
+			newMaster.Props.Del(ical.PropRecurrenceRule)
+		} else {
+			setRecurrenceRule(newMaster.Props, after)
+		}
+	}
+	for _, name := range []string{ical.PropRecurrenceDates, ical.PropExceptionDates} {
+		if err := filterTimeList(newMaster.Props, name, form, func(t time.Time) bool { return !t.Before(rid) }); err != nil {
+			return "", err
+		}
+	}
+	if err := applyEventUpdate(following, &ical.Event{Component: newMaster}, update); err != nil {
+		return "", err
+	}
+	if update.Attendees != nil || update.Organizer != nil {
+		c.ensureOrganizer(ctx, newMaster)
+	}
+
+	if err := endSeriesBefore(cal, master, form, start, rid); err != nil {
+		return "", err
+	}
+	incrementSequence(master)
+	now := time.Now().UTC()
+	master.Props.SetDateTime(ical.PropDateTimeStamp, now)
+	master.Props.SetDateTime(ical.PropLastModified, now)
+
+	sendFollowing, err := c.planClientScheduling(ctx, nil, following)
+	if err != nil {
+		return "", fmt.Errorf("failed to split event: %w", err)
+	}
+	sendOriginal, err := c.planClientScheduling(ctx, before, cal)
+	if err != nil {
+		return "", fmt.Errorf("failed to split event: %w", err)
+	}
+
+	newPath := c.GetEventPath(path.Dir(eventPath), uid)
+	_, err = c.backend.PutCalendarObject(ctx, newPath, following, precondition{ifNoneMatch: true})
+	if isPreconditionFailed(err) {
+		return "", fmt.Errorf("failed to split event: an event with UID %s already exists", uid)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to split event: %w", err)
+	}
+
+	_, err = c.backend.PutCalendarObject(ctx, eventPath, cal, precondition{ifMatch: ifMatch})
+	if err != nil {
+		if rerr := c.backend.Remove(ctx, newPath, precondition{}); rerr != nil {
+			slog.Warn("failed to remove split-off event after error", "path", newPath, "error", rerr)
+		}
+		if isPreconditionFailed(err) {
+			return "", c.refetchConflict(ctx, eventPath)
+		}
+		return "", fmt.Errorf("failed to update event: %w", err)
+	}
+
+	if err := sendOriginal(ctx); err != nil {
+		return "", fmt.Errorf("the event was split, but %w", err)
+	}
+	if err := sendFollowing(ctx); err != nil {
+		return "", fmt.Errorf("the event was split, but %w", err)
+	}
+	return uid, nil
+}
+
+// ensureOrganizer sets the user as ORGANIZER of comp if it has attendees
+// but no organizer, so that scheduling servers send the invitations.
+func (c *Client) ensureOrganizer(ctx context.Context, comp *ical.Component) {
+	if len(comp.Props[ical.PropAttendee]) == 0 || comp.Props.Get(ical.PropOrganizer) != nil {
+		return
+	}
+	if organizer := c.organizerAddress(ctx); organizer != "" {
+		comp.Props.Set(&ical.Prop{Name: ical.PropOrganizer, Value: organizer, Params: ical.Params{}})
+	}
+}
+
+// setOrganizer makes email the ORGANIZER of comp. An ORGANIZER that already
+// has that address is kept with its parameters, such as CN.
+func setOrganizer(comp *ical.Component, email string) {
+	if prop := comp.Props.Get(ical.PropOrganizer); prop == nil || !strings.EqualFold(mailtoAddress(prop.Value), email) {
+		comp.Props.Set(&ical.Prop{Name: ical.PropOrganizer, Value: "mailto:" + email, Params: ical.Params{}})
+	}
+}
+
+// applyEventUpdate applies the fields of update to vevent, which is the
+// master or an override in cal.
+func applyEventUpdate(cal *ical.Calendar, vevent *ical.Event, update *EventUpdate) error {
+	before := significantState(vevent.Component)
 
 	// Update properties: nil = skip, empty string = delete property
 	if update.Title != nil {
@@ -363,35 +1006,252 @@ func (c *Client) UpdateEvent(ctx context.Context, eventPath string, update *Even
 		}
 	}
 
-	if update.StartTime != nil {
-		vevent.Props.SetDateTime(ical.PropDateTimeStart, *update.StartTime)
+	if err := updateEventTimes(cal, vevent, update); err != nil {
+		return err
 	}
 
-	if update.EndTime != nil {
-		vevent.Props.SetDateTime(ical.PropDateTimeEnd, *update.EndTime)
+	if update.Recurrence != nil {
+		if *update.Recurrence == "" {
+			vevent.Props.Del(ical.PropRecurrenceRule)
+		} else {
+			setRecurrenceRule(vevent.Props, *update.Recurrence)
+		}
 	}
 
-	// Update timestamp
-	vevent.Props.SetDateTime(ical.PropDateTimeStamp, time.Now())
+	if update.Alarms != nil {
+		if err := setAlarms(vevent.Component, *update.Alarms); err != nil {
+			return err
+		}
+	}
 
-	// Put the updated calendar object
-	_, err = c.backend.PutCalendarObject(ctx, eventPath, existingObj.Data)
-	if err != nil {
-		return fmt.Errorf("failed to update event: %w", err)
+	if update.Attendees != nil {
+		if err := applyAttendeeChanges(vevent.Component, *update.Attendees); err != nil {
+			return err
+		}
+	}
+	if update.Organizer != nil {
+		if *update.Organizer == "" {
+			vevent.Props.Del(ical.PropOrganizer)
+		} else if len(vevent.Props[ical.PropAttendee]) > 0 {
+			setOrganizer(vevent.Component, *update.Organizer)
+		}
 	}
 
+	setOrDeleteText(vevent.Props, ical.PropStatus, update.Status)
+	if update.Transparent != nil {
+		setTransparency(vevent.Props, *update.Transparent)
+	}
+	if update.Categories != nil {
+		setCategories(vevent.Props, *update.Categories)
+	}
+	if update.URL != nil {
+		if *update.URL == "" {
+			vevent.Props.Del(ical.PropURL)
+		} else {
+			vevent.Props.Set(&ical.Prop{Name: ical.PropURL, Value: *update.URL, Params: ical.Params{}})
+		}
+	}
+	setOrDeleteText(vevent.Props, ical.PropClass, update.Class)
+	if update.Priority != nil {
+		if *update.Priority == 0 {
+			vevent.Props.Del(ical.PropPriority)
+		} else {
+			setInt(vevent.Props, ical.PropPriority, *update.Priority)
+		}
+	}
+
+	if significantState(vevent.Component) != before {
+		incrementSequence(vevent.Component)
+	}
+
+	// Update timestamps
+	now := time.Now().UTC()
+	vevent.Props.SetDateTime(ical.PropDateTimeStamp, now)
+	vevent.Props.SetDateTime(ical.PropLastModified, now)
 	return nil
 }
 
-// DeleteEvent deletes an event by its path
-func (c *Client) DeleteEvent(ctx context.Context, eventPath string) error {
-	err := c.backend.RemoveAll(ctx, eventPath)
+// DeleteEvent deletes an event by its path. If nothing exists at eventPath,
+// the event is looked up by UID as for GetEvent. A non-empty etag makes the
+// delete conditional; it fails with a *ConflictError if the event has
+// changed. The event is read first, since attendees the user schedules
+// themselves must be sent the cancellation. If it can be found neither at
+// eventPath nor by UID, the DELETE is still sent to eventPath, which some
+// servers accept even though they do not serve it to GET.
+func (c *Client) DeleteEvent(ctx context.Context, eventPath, etag string) error {
+	obj, resolved, err := c.getEvent(ctx, eventPath)
+	if errors.Is(err, ErrEventNotFound) {
+		// Without the event there is no one to send cancellations to
+		if derr := c.DeleteEventObject(ctx, eventPath, etag); httpStatus(derr) != http.StatusNotFound {
+			return derr
+		}
+		return fmt.Errorf("failed to delete event: %w", err)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to delete event: %w", err)
 	}
+	send, err := c.planClientScheduling(ctx, obj.Data, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete event: %w", err)
+	}
+
+	if err := c.DeleteEventObject(ctx, resolved, etag); err != nil {
+		return err
+	}
+
+	if err := send(ctx); err != nil {
+		return fmt.Errorf("the event was deleted, but %w", err)
+	}
+	return nil
+}
+
+// DeleteOccurrence deletes occurrences of the recurring event at eventPath:
+// the one originally starting at recurrenceID for ScopeThis, that one and
+// all later ones for ScopeThisAndFollowing, or the whole event for ScopeAll.
+// A single occurrence is removed with an EXDATE and loses its override, if
+// any; later occurrences are cut off by capping the series with an UNTIL.
+// A non-empty etag makes the change conditional, as for DeleteEvent.
+func (c *Client) DeleteOccurrence(ctx context.Context, eventPath string, recurrenceID time.Time, scope, etag string) error {
+	if err := ValidateScope(scope); err != nil {
+		return err
+	}
+	if scope == "" || scope == ScopeAll {
+		return c.DeleteEvent(ctx, eventPath, etag)
+	}
+
+	obj, eventPath, ifMatch, err := c.getEventForUpdate(ctx, eventPath, etag)
+	if err != nil {
+		return err
+	}
+	master, _ := splitEvents(obj.Data)
+	if master == nil {
+		return fmt.Errorf("no VEVENT component found in calendar object")
+	}
+	form, start, err := formOf(master)
+	if err != nil {
+		return err
+	}
+	rid := form.normalize(recurrenceID)
+	if err := checkOccurrence(master, rid); err != nil {
+		return err
+	}
+	before := cloneCalendar(obj.Data)
+
+	if scope == ScopeThis {
+		removeOverrides(obj.Data, rid.Equal)
+		master.Props.Add(form.prop(ical.PropExceptionDates, rid))
+	} else {
+		// Deleting from the first occurrence on deletes the whole series.
+		if !rid.After(start) {
+			return c.DeleteEvent(ctx, eventPath, ifMatch)
+		}
+		if err := endSeriesBefore(obj.Data, master, form, start, rid); err != nil {
+			return err
+		}
+	}
+	master.Props.SetDateTime(ical.PropDateTimeStamp, time.Now().UTC())
+	send, err := c.planClientScheduling(ctx, before, obj.Data)
+	if err != nil {
+		return fmt.Errorf("failed to delete occurrence: %w", err)
+	}
+
+	_, err = c.backend.PutCalendarObject(ctx, eventPath, obj.Data, precondition{ifMatch: ifMatch})
+	if isPreconditionFailed(err) {
+		return c.refetchConflict(ctx, eventPath)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete occurrence: %w", err)
+	}
+
+	if err := send(ctx); err != nil {
+		return fmt.Errorf("the occurrence was deleted, but %w", err)
+	}
 	return nil
 }
 
+// getForUpdate fetches the event at eventPath for a read-modify-write. It
+// returns the ETag to make the write conditional on: etag if given, else the
+// one just read. A server version that no longer has etag is reported as a
+// *ConflictError.
+func (c *Client) getForUpdate(ctx context.Context, eventPath, etag string) (*caldav.CalendarObject, string, error) {
+	obj, err := c.backend.GetCalendarObject(ctx, eventPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get existing event: %w", err)
+	}
+	ifMatch, err := c.ifMatch(eventPath, obj, etag)
+	if err != nil {
+		return nil, "", err
+	}
+	return obj, ifMatch, nil
+}
+
+// getEventForUpdate is getForUpdate for events, which are looked up by UID
+// if nothing exists at eventPath. It also returns the path the event was
+// found at, where it must be written back.
+func (c *Client) getEventForUpdate(ctx context.Context, eventPath, etag string) (*caldav.CalendarObject, string, string, error) {
+	return c.getObjectForUpdate(ctx, eventPath, ical.CompEvent, etag)
+}
+
+// getTaskForUpdate is getEventForUpdate for tasks.
+func (c *Client) getTaskForUpdate(ctx context.Context, taskPath, etag string) (*caldav.CalendarObject, string, string, error) {
+	return c.getObjectForUpdate(ctx, taskPath, ical.CompToDo, etag)
+}
+
+// getObjectForUpdate is getForUpdate for objects holding a comp component,
+// looked up as for getObject.
+func (c *Client) getObjectForUpdate(ctx context.Context, objectPath, comp, etag string) (*caldav.CalendarObject, string, string, error) {
+	obj, objectPath, err := c.getObject(ctx, objectPath, comp)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to get existing %s: %w", componentKind(comp), err)
+	}
+	ifMatch, err := c.ifMatch(objectPath, obj, etag)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return obj, objectPath, ifMatch, nil
+}
+
+// ifMatch returns the ETag to make a write of obj conditional on: etag if
+// given, else the one just read. A server version that no longer has etag
+// is reported as a *ConflictError. If-Match cannot use a weak ETag, so when
+// the one just read is weak the write is made without a condition; a weak
+// etag passed in makes the write fail with ErrWeakETag instead.
+func (c *Client) ifMatch(eventPath string, obj *caldav.CalendarObject, etag string) (string, error) {
+	if etag != "" {
+		want := normalizeETag(etag)
+		if obj.ETag != "" && obj.ETag != want {
+			return "", c.conflict(eventPath, obj)
+		}
+		return want, nil
+	}
+	if strings.HasPrefix(obj.ETag, "W/") {
+		return "", nil
+	}
+	return obj.ETag, nil
+}
+
+// conflict builds a *ConflictError carrying the given server version.
+func (c *Client) conflict(eventPath string, current *caldav.CalendarObject) error {
+	conflict := &ConflictError{Path: eventPath}
+	if event, err := parseCalendarObject(current); err == nil {
+		conflict.Current = event
+	} else if task, err := parseTaskObject(current); err == nil {
+		conflict.CurrentTask = task
+	}
+	return conflict
+}
+
+// refetchConflict builds a *ConflictError after a failed precondition,
+// fetching the server's current version of the event.
+func (c *Client) refetchConflict(ctx context.Context, eventPath string) error {
+	current, err := c.backend.GetCalendarObject(ctx, eventPath)
+	if err != nil {
+		slog.Warn("failed to fetch current version after conflict", "path", eventPath, "error", err)
+		return &ConflictError{Path: eventPath}
+	}
+	return c.conflict(eventPath, current)
+}
+
 // GetEventPath constructs the full path to an event
 func (c *Client) GetEventPath(calendarPath, eventID string) string {
 	calPath := strings.TrimSuffix(calendarPath, "/")
@@ -401,25 +1261,30 @@ func (c *Client) GetEventPath(calendarPath, eventID string) string {
 	return fmt.Sprintf("%s/%s", calPath, eventID)
 }
 
-// parseCalendarObject converts a CalDAV calendar object to our Event struct
-func (c *Client) parseCalendarObject(obj *caldav.CalendarObject) (*Event, error) {
-	// Find the VEVENT component
-	var vevent *ical.Event
-	for _, child := range obj.Data.Children {
-		if child.Name == ical.CompEvent {
-			vevent = ical.NewEvent()
-			vevent.Component = child
-			break
-		}
-	}
-
-	if vevent == nil {
+// parseCalendarObject converts a CalDAV calendar object to our Event struct.
+// Overrides of single occurrences are returned in the event's Overrides.
+func parseCalendarObject(obj *caldav.CalendarObject) (*Event, error) {
+	master, overrides := splitEvents(obj.Data)
+	if master == nil {
 		return nil, fmt.Errorf("no VEVENT component found")
 	}
 
-	event := &Event{
-		Path: obj.Path,
+	event := parseEvent(master)
+	event.Path = obj.Path
+	event.ETag = normalizeETag(obj.ETag)
+	for _, comp := range overrides {
+		override := parseEvent(comp)
+		override.Path = event.Path
+		override.ETag = event.ETag
+		event.Overrides = append(event.Overrides, *override)
 	}
+	return event, nil
+}
+
+// parseEvent converts a VEVENT component to our Event struct.
+func parseEvent(comp *ical.Component) *Event {
+	vevent := &ical.Event{Component: comp}
+	event := &Event{Raw: comp}
 
 	// Extract UID
 	if uid := vevent.Props.Get(ical.PropUID); uid != nil {
@@ -441,54 +1306,180 @@ func (c *Client) parseCalendarObject(obj *caldav.CalendarObject) (*Event, error)
 		event.Location = loc.Value
 	}
 
-	// Extract start time
+	// Extract start and end time. Zoned times stay in their TZID's location;
+	// dates and floating times are read as UTC wall-clock values and flagged
+	// so they are not mistaken for UTC instants.
 	if dtstart := vevent.Props.Get(ical.PropDateTimeStart); dtstart != nil {
-		startTime, err := dtstart.DateTime(time.UTC)
+		startTime, floating, err := parseTimeProp(dtstart)
 		if err == nil {
 			event.StartTime = startTime
+			event.AllDay = isDateValue(dtstart)
+			event.Floating = floating
 			if tzid := dtstart.Params.Get(ical.PropTimezoneID); tzid != "" {
 				event.Timezone = tzid
 			}
+			if endTime, err := eventEnd(vevent, startTime, event.AllDay); err == nil {
+				event.EndTime = endTime
+			}
 		}
 	}
 
-	// Extract end time
-	if dtend := vevent.Props.Get(ical.PropDateTimeEnd); dtend != nil {
-		endTime, err := dtend.DateTime(time.UTC)
-		if err == nil {
-			event.EndTime = endTime
+	if prop := vevent.Props.Get(ical.PropRecurrenceID); prop != nil {
+		if rid, floating, err := parseTimeProp(prop); err == nil {
+			event.RecurrenceID = &rid
+			event.OccurrenceID = OccurrenceID(event.ID, rid, isDateValue(prop), floating)
 		}
 	}
 
-	// Extract recurrence rule
+	parseMetadata(vevent.Props, event)
+
+	// Extract recurrence rule and dates
 	if rrule := vevent.Props.Get(ical.PropRecurrenceRule); rrule != nil {
 		event.Recurrence = rrule.Value
 	}
+	if rdates, err := parseTimeList(vevent.Props.Values(ical.PropRecurrenceDates)); err == nil {
+		event.RecurrenceDates = rdates
+	}
+	if exdates, err := parseTimeList(vevent.Props.Values(ical.PropExceptionDates)); err == nil {
+		event.ExceptionDates = exdates
+	}
+
+	if organizer := vevent.Props.Get(ical.PropOrganizer); organizer != nil {
+		event.Organizer = strings.TrimPrefix(strings.TrimPrefix(organizer.Value, "mailto:"), "MAILTO:")
+	}
 
 	// Extract attendees
-	for _, prop := range vevent.Props["ATTENDEE"] {
-		attendee := Attendee{}
-		val := prop.Value
-		if strings.HasPrefix(val, "mailto:") {
-			attendee.Email = strings.TrimPrefix(val, "mailto:")
+	for _, prop := range vevent.Props[ical.PropAttendee] {
+		if attendee, ok := parseAttendee(prop); ok {
+			event.Attendees = append(event.Attendees, attendee)
 		}
-		if cn := prop.Params.Get("CN"); cn != "" {
-			attendee.Name = cn
+	}
+
+	event.Alarms = parseAlarms(comp)
+
+	if event.Timezone == "" && !event.AllDay && !event.Floating {
+		event.Timezone = "UTC"
+	}
+
+	return event
+}
+
+// updateEventTimes applies the time-related fields of update to vevent,
+// keeping the event's current time model (all-day, floating or zoned) unless
+// update.AllDay or update.Timezone changes it. RDATE and EXDATE values follow
+// the model of DTSTART. Zoned times get a matching VTIMEZONE in cal.
+func updateEventTimes(cal *ical.Calendar, vevent *ical.Event, update *EventUpdate) error {
+	var start, end time.Time
+	var allDay, floating bool
+	loc := time.UTC
+
+	dtstart := vevent.Props.Get(ical.PropDateTimeStart)
+	hasEnd := vevent.Props.Get(ical.PropDateTimeEnd) != nil
+	if dtstart != nil {
+		var err error
+		allDay = isDateValue(dtstart)
+		start, floating, err = parseTimeProp(dtstart)
+		if err != nil {
+			return fmt.Errorf("failed to read event start: %w", err)
 		}
-		if role := prop.Params.Get("ROLE"); role != "" {
-			attendee.Role = role
+		end, err = eventEnd(vevent, start, allDay)
+		if err != nil {
+			return fmt.Errorf("failed to read event end: %w", err)
 		}
-		if status := prop.Params.Get("PARTSTAT"); status != "" {
-			attendee.Status = status
+		loc = start.Location()
+	}
+	wasAllDay, wasFloating := allDay, floating
+
+	rezoning := update.Timezone != nil
+	if rezoning {
+		var err error
+		if loc, err = LoadTimezone(*update.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone %q: %w", *update.Timezone, err)
 		}
-		if attendee.Email != "" {
-			event.Attendees = append(event.Attendees, attendee)
+		// A floating event pinned to a zone keeps its wall-clock time.
+		if floating {
+			start, end = wallClock(start, loc), wallClock(end, loc)
+			floating = false
 		}
 	}
 
-	if event.Timezone == "" {
-		event.Timezone = "UTC"
+	converting := update.AllDay != nil && *update.AllDay != allDay
+	if converting {
+		if !*update.AllDay && (update.StartTime == nil || update.EndTime == nil) {
+			return fmt.Errorf("startTime and endTime are required to convert an all-day event to a timed event")
+		}
+		allDay = *update.AllDay
+		floating = false
+	}
+	reshaping := converting || rezoning
+	if update.StartTime == nil && update.EndTime == nil && !reshaping &&
+		update.RecurrenceDates == nil && update.ExceptionDates == nil {
+		return nil
 	}
 
-	return event, nil
+	if update.StartTime != nil {
+		start = *update.StartTime
+	}
+	if update.EndTime != nil {
+		end = *update.EndTime
+	}
+	switch {
+	case allDay:
+		start, end = allDayRange(start, end)
+	case !floating:
+		start, end = start.In(loc), end.In(loc)
+		addTimezone(cal, loc, start)
+	}
+
+	if dtstart != nil || update.StartTime != nil {
+		setTimeProp(vevent.Props, ical.PropDateTimeStart, start, allDay, floating)
+	}
+	// An event defined by DURATION keeps it when only the start moves.
+	if update.EndTime != nil || (dtstart != nil && (hasEnd || converting)) {
+		setTimeProp(vevent.Props, ical.PropDateTimeEnd, end, allDay, floating)
+		vevent.Props.Del(ical.PropDuration)
+	}
+
+	// convert re-expresses an existing RDATE/EXDATE value, read in the
+	// event's old model, in its new one.
+	convert := func(t time.Time) time.Time {
+		switch {
+		case allDay:
+			return dateOf(t)
+		case floating:
+			return t
+		case wasAllDay:
+			return time.Date(t.Year(), t.Month(), t.Day(), start.Hour(), start.Minute(), start.Second(), 0, loc)
+		case wasFloating:
+			return wallClock(t, loc)
+		default:
+			return t.In(loc)
+		}
+	}
+	lists := []struct {
+		name    string
+		replace *[]time.Time
+	}{
+		{ical.PropRecurrenceDates, update.RecurrenceDates},
+		{ical.PropExceptionDates, update.ExceptionDates},
+	}
+	for _, list := range lists {
+		var times []time.Time
+		switch {
+		case list.replace != nil:
+			times = inForm(*list.replace, allDay, floating, loc)
+		case reshaping:
+			existing, err := parseTimeList(vevent.Props.Values(list.name))
+			if err != nil {
+				return fmt.Errorf("failed to read event %s: %w", list.name, err)
+			}
+			for _, t := range existing {
+				times = append(times, convert(t))
+			}
+		default:
+			continue
+		}
+		setTimeList(vevent.Props, list.name, times, allDay, floating)
+	}
+	return nil
 }
@@ -2,7 +2,9 @@ package caldav
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
@@ -18,24 +20,76 @@ type mockBackend struct {
 	homeSet    string
 	homeSetErr error
 
-	calendars  []extcaldav.Calendar
+	calendars  []Calendar
 	findCalErr error
+	// notCalendars lists the collections IsCalendar reports as not calendars.
+	notCalendars  map[string]bool
+	isCalendarErr error
 
 	queryResult []extcaldav.CalendarObject
+	// queryByPath overrides queryResult for the given collections.
+	queryByPath map[string][]extcaldav.CalendarObject
 	queryErr    error
+	// textMatchErr is returned for queries with text-match prop-filters.
+	textMatchErr error
 
-	putResult *extcaldav.CalendarObject
-	putErr    error
+	putResult    *extcaldav.CalendarObject
+	putErr       error
+	putErrByPath map[string]error
 
 	getResult *extcaldav.CalendarObject
 	getErr    error
 
-	removeErr error
+	removeErr       error
+	removeErrByPath map[string]error
+
+	moveErr error
+	copyErr error
+
+	calendarErr error
+
+	// syncs are returned by SyncCollection in turn
+	syncs    []*syncResponse
+	syncErrs []error
+	ctag     string
+	ctagErr  error
+
+	multiGetResult []extcaldav.CalendarObject
+	multiGetErr    error
+
+	freeBusyResult *ical.Calendar
+	freeBusyErr    error
+
+	scheduling    *schedulingInfo
+	schedulingErr error
+	outboxErr     error
 
 	// tracking
+	lastQuery      *extcaldav.CalendarQuery
+	putPaths       []string
+	putCals        map[string]*ical.Calendar
 	lastPutPath    string
+	lastPutCal     *ical.Calendar
+	lastPutCond    precondition
 	lastGetPath    string
 	lastRemovePath string
+	lastRemoveCond precondition
+	freeBusyCalls  int
+	removedPaths   []string
+	movedFrom      string
+	movedTo        string
+	moveCond       precondition
+	copiedFrom     string
+	copiedTo       string
+	madeCalendar   string
+	syncTokens     []string
+	multiGetPaths  [][]string
+	patchedPath    string
+	calendarProps  calendarProps
+	outboxPath     string
+	outboxFrom     string
+	outboxTo       []string
+	outboxCal      *ical.Calendar
 }
 
 func (m *mockBackend) FindCurrentUserPrincipal(_ context.Context) (string, error) {
@@ -46,16 +100,76 @@ func (m *mockBackend) FindCalendarHomeSet(_ context.Context, _ string) (string,
 	return m.homeSet, m.homeSetErr
 }
 
-func (m *mockBackend) FindCalendars(_ context.Context, _ string) ([]extcaldav.Calendar, error) {
+func (m *mockBackend) FindCalendars(_ context.Context, _ string) ([]Calendar, error) {
 	return m.calendars, m.findCalErr
 }
 
-func (m *mockBackend) QueryCalendar(_ context.Context, _ string, _ *extcaldav.CalendarQuery) ([]extcaldav.CalendarObject, error) {
+func (m *mockBackend) IsCalendar(_ context.Context, path string) (bool, error) {
+	return !m.notCalendars[path], m.isCalendarErr
+}
+
+func (m *mockBackend) QueryCalendar(_ context.Context, path string, query *extcaldav.CalendarQuery) ([]extcaldav.CalendarObject, error) {
+	m.lastQuery = query
+	if m.textMatchErr != nil && len(query.CompFilter.Comps) > 0 && len(query.CompFilter.Comps[0].Props) > 0 {
+		return nil, m.textMatchErr
+	}
+	if objects, ok := m.queryByPath[path]; ok {
+		return objects, m.queryErr
+	}
 	return m.queryResult, m.queryErr
 }
 
-func (m *mockBackend) PutCalendarObject(_ context.Context, path string, _ *ical.Calendar) (*extcaldav.CalendarObject, error) {
+// MultiGetCalendar returns the objects of multiGetResult at the requested
+// paths.
+func (m *mockBackend) MultiGetCalendar(_ context.Context, _ string, multiGet *extcaldav.CalendarMultiGet) ([]extcaldav.CalendarObject, error) {
+	m.multiGetPaths = append(m.multiGetPaths, multiGet.Paths)
+	if m.multiGetErr != nil {
+		return nil, m.multiGetErr
+	}
+	var objects []extcaldav.CalendarObject
+	for _, p := range multiGet.Paths {
+		for _, obj := range m.multiGetResult {
+			if obj.Path == p {
+				objects = append(objects, obj)
+			}
+		}
+	}
+	return objects, nil
+}
+
+func (m *mockBackend) SyncCollection(_ context.Context, _ string, syncToken string) (*syncResponse, error) {
+	m.syncTokens = append(m.syncTokens, syncToken)
+	if len(m.syncErrs) > 0 {
+		err := m.syncErrs[0]
+		m.syncErrs = m.syncErrs[1:]
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(m.syncs) == 0 {
+		return &syncResponse{}, nil
+	}
+	resp := m.syncs[0]
+	m.syncs = m.syncs[1:]
+	return resp, nil
+}
+
+func (m *mockBackend) CalendarCTag(_ context.Context, _ string) (string, error) {
+	return m.ctag, m.ctagErr
+}
+
+func (m *mockBackend) PutCalendarObject(_ context.Context, path string, cal *ical.Calendar, cond precondition) (*extcaldav.CalendarObject, error) {
+	m.putPaths = append(m.putPaths, path)
+	if m.putCals == nil {
+		m.putCals = make(map[string]*ical.Calendar)
+	}
+	m.putCals[path] = cal
 	m.lastPutPath = path
+	m.lastPutCal = cal
+	m.lastPutCond = cond
+	if err := m.putErrByPath[path]; err != nil {
+		return nil, err
+	}
 	return m.putResult, m.putErr
 }
 
@@ -64,11 +178,61 @@ func (m *mockBackend) GetCalendarObject(_ context.Context, path string) (*extcal
 	return m.getResult, m.getErr
 }
 
-func (m *mockBackend) RemoveAll(_ context.Context, path string) error {
+func (m *mockBackend) Remove(_ context.Context, path string, cond precondition) error {
 	m.lastRemovePath = path
+	m.lastRemoveCond = cond
+	m.removedPaths = append(m.removedPaths, path)
+	if err := m.removeErrByPath[path]; err != nil {
+		return err
+	}
 	return m.removeErr
 }
 
+func (m *mockBackend) MoveCalendarObject(_ context.Context, path, dest string, cond precondition) error {
+	m.movedFrom = path
+	m.movedTo = dest
+	m.moveCond = cond
+	return m.moveErr
+}
+
+func (m *mockBackend) CopyCalendarObject(_ context.Context, path, dest string) error {
+	m.copiedFrom = path
+	m.copiedTo = dest
+	return m.copyErr
+}
+
+func (m *mockBackend) MakeCalendar(_ context.Context, path string, props calendarProps) error {
+	m.madeCalendar = path
+	m.calendarProps = props
+	return m.calendarErr
+}
+
+func (m *mockBackend) PatchCalendar(_ context.Context, path string, props calendarProps) error {
+	m.patchedPath = path
+	m.calendarProps = props
+	return m.calendarErr
+}
+
+func (m *mockBackend) FreeBusyQuery(_ context.Context, _ string, _, _ time.Time) (*ical.Calendar, error) {
+	m.freeBusyCalls++
+	return m.freeBusyResult, m.freeBusyErr
+}
+
+func (m *mockBackend) FindSchedulingInfo(_ context.Context, _ string) (*schedulingInfo, error) {
+	if m.scheduling == nil && m.schedulingErr == nil {
+		return &schedulingInfo{}, nil
+	}
+	return m.scheduling, m.schedulingErr
+}
+
+func (m *mockBackend) PostOutbox(_ context.Context, path, originator string, recipients []string, cal *ical.Calendar) error {
+	m.outboxPath = path
+	m.outboxFrom = originator
+	m.outboxTo = recipients
+	m.outboxCal = cal
+	return m.outboxErr
+}
+
 func TestDefaultClientOptions(t *testing.T) {
 	opts := DefaultClientOptions()
 	if opts.MaxConnsPerHost != 10 {
@@ -144,6 +308,25 @@ func TestDiscoverCalendarHomeSet_CachedAfterFirstCall(t *testing.T) {
 	}
 }
 
+func TestNewClient_KnownHomeSetSkipsDiscovery(t *testing.T) {
+	c, err := NewClient("user@icloud.com", "password", ClientOptions{
+		Principal:       "/123/principal/",
+		CalendarHomeSet: "/123/calendars/",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.backend = &mockBackend{principalErr: fmt.Errorf("should not be called")}
+
+	homeSet, err := c.DiscoverCalendarHomeSet(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if homeSet != "/123/calendars/" || c.Principal() != "/123/principal/" {
+		t.Errorf("homeSet = %q, principal = %q", homeSet, c.Principal())
+	}
+}
+
 func TestDiscoverCalendarHomeSet_PrincipalError(t *testing.T) {
 	mb := &mockBackend{
 		principalErr: fmt.Errorf("principal not found"),
@@ -173,7 +356,7 @@ func TestListCalendars_Success(t *testing.T) {
 	mb := &mockBackend{
 		principal: "/principals/user/",
 		homeSet:   "/calendars/user/",
-		calendars: []extcaldav.Calendar{
+		calendars: []Calendar{
 			{Path: "/cal/work", Name: "Work", Description: "Work calendar"},
 			{Path: "/cal/personal", Name: "Personal"},
 		},
@@ -195,6 +378,29 @@ func TestListCalendars_Success(t *testing.T) {
 	}
 }
 
+func TestListCalendars_Shared(t *testing.T) {
+	mb := &mockBackend{
+		principal: "/principals/user/",
+		homeSet:   "/calendars/user/",
+		calendars: []Calendar{
+			{Path: "/cal/work/", Owner: "/principals/user"},
+			{Path: "/cal/family/", Owner: "/principals/partner/", ReadOnly: true},
+			{Path: "/cal/holidays/"},
+		},
+	}
+	c := NewClientWithBackend(mb)
+
+	cals, err := c.ListCalendars(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, want := range []bool{false, true, false} {
+		if cals[i].Shared != want {
+			t.Errorf("%s: Shared = %v, want %v", cals[i].Path, cals[i].Shared, want)
+		}
+	}
+}
+
 func TestListCalendars_DiscoverError(t *testing.T) {
 	mb := &mockBackend{
 		principalErr: fmt.Errorf("discovery failed"),
@@ -225,7 +431,7 @@ func TestListCalendars_Empty(t *testing.T) {
 	mb := &mockBackend{
 		principal: "/principals/user/",
 		homeSet:   "/calendars/user/",
-		calendars: []extcaldav.Calendar{},
+		calendars: []Calendar{},
 	}
 	c := NewClientWithBackend(mb)
 
@@ -493,7 +699,7 @@ func TestUpdateEvent_Success(t *testing.T) {
 	newTitle := "New Title"
 	update := &EventUpdate{Title: &newTitle}
 
-	err := c.UpdateEvent(context.Background(), "/cal/event.ics", update)
+	_, err := c.UpdateEvent(context.Background(), "/cal/event.ics", update)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -517,7 +723,7 @@ func TestUpdateEvent_ClearDescription(t *testing.T) {
 	emptyStr := ""
 	update := &EventUpdate{Description: &emptyStr}
 
-	err := c.UpdateEvent(context.Background(), "/cal/event.ics", update)
+	_, err := c.UpdateEvent(context.Background(), "/cal/event.ics", update)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -541,7 +747,7 @@ func TestUpdateEvent_ClearTitleAndLocation(t *testing.T) {
 	emptyStr := ""
 	update := &EventUpdate{Title: &emptyStr, Location: &emptyStr}
 
-	err := c.UpdateEvent(context.Background(), "/cal/event.ics", update)
+	_, err := c.UpdateEvent(context.Background(), "/cal/event.ics", update)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -565,7 +771,7 @@ func TestUpdateEvent_UpdateTimes(t *testing.T) {
 	newEnd := time.Date(2024, 2, 1, 11, 0, 0, 0, time.UTC)
 	update := &EventUpdate{StartTime: &newStart, EndTime: &newEnd}
 
-	err := c.UpdateEvent(context.Background(), "/cal/event.ics", update)
+	_, err := c.UpdateEvent(context.Background(), "/cal/event.ics", update)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -578,7 +784,7 @@ func TestUpdateEvent_GetError(t *testing.T) {
 	c := NewClientWithBackend(mb)
 
 	title := "New"
-	err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Title: &title})
+	_, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Title: &title})
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -594,7 +800,7 @@ func TestUpdateEvent_NoVEVENT(t *testing.T) {
 	c := NewClientWithBackend(mb)
 
 	title := "New"
-	err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Title: &title})
+	_, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Title: &title})
 	if err == nil {
 		t.Fatal("expected error for missing VEVENT")
 	}
@@ -615,17 +821,17 @@ func TestUpdateEvent_PutError(t *testing.T) {
 	c := NewClientWithBackend(mb)
 
 	title := "New"
-	err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Title: &title})
+	_, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Title: &title})
 	if err == nil {
 		t.Fatal("expected error from put")
 	}
 }
 
 func TestDeleteEvent_Success(t *testing.T) {
-	mb := &mockBackend{}
+	mb := &mockBackend{getResult: makeExistingObject("etag-1")}
 	c := NewClientWithBackend(mb)
 
-	err := c.DeleteEvent(context.Background(), "/cal/event.ics")
+	err := c.DeleteEvent(context.Background(), "/cal/event.ics", "")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -636,11 +842,12 @@ func TestDeleteEvent_Success(t *testing.T) {
 
 func TestDeleteEvent_Error(t *testing.T) {
 	mb := &mockBackend{
+		getResult: makeExistingObject("etag-1"),
 		removeErr: fmt.Errorf("permission denied"),
 	}
 	c := NewClientWithBackend(mb)
 
-	err := c.DeleteEvent(context.Background(), "/cal/event.ics")
+	err := c.DeleteEvent(context.Background(), "/cal/event.ics", "")
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -678,8 +885,7 @@ func TestParseCalendarObject_FullEvent(t *testing.T) {
 		Data: cal,
 	}
 
-	c := &Client{}
-	event, err := c.parseCalendarObject(obj)
+	event, err := parseCalendarObject(obj)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -723,8 +929,7 @@ func TestParseCalendarObject_NoVEVENT(t *testing.T) {
 	cal := ical.NewCalendar()
 	obj := &extcaldav.CalendarObject{Path: "/cal/bad.ics", Data: cal}
 
-	c := &Client{}
-	_, err := c.parseCalendarObject(obj)
+	_, err := parseCalendarObject(obj)
 	if err == nil {
 		t.Fatal("expected error for missing VEVENT")
 	}
@@ -738,8 +943,7 @@ func TestParseCalendarObject_MinimalEvent(t *testing.T) {
 	cal.Children = append(cal.Children, vevent.Component)
 	obj := &extcaldav.CalendarObject{Path: "/cal/min.ics", Data: cal}
 
-	c := &Client{}
-	event, err := c.parseCalendarObject(obj)
+	event, err := parseCalendarObject(obj)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -767,8 +971,7 @@ func TestParseCalendarObject_AttendeeWithoutEmail(t *testing.T) {
 	cal.Children = append(cal.Children, vevent.Component)
 	obj := &extcaldav.CalendarObject{Path: "/cal/event.ics", Data: cal}
 
-	c := &Client{}
-	event, err := c.parseCalendarObject(obj)
+	event, err := parseCalendarObject(obj)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -818,8 +1021,757 @@ func TestUpdateEvent_SetNonEmptyDescriptionAndLocation(t *testing.T) {
 	loc := "Room C"
 	update := &EventUpdate{Description: &desc, Location: &loc}
 
-	err := c.UpdateEvent(context.Background(), "/cal/event.ics", update)
+	_, err := c.UpdateEvent(context.Background(), "/cal/event.ics", update)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCreateEvent_RefusesToOverwrite(t *testing.T) {
+	mb := &mockBackend{putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	event := &Event{
+		Title:     "Meeting",
+		StartTime: time.Date(2024, 1, 15, 14, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC),
+	}
+	if _, err := c.CreateEvent(context.Background(), "/cal/work", event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mb.lastPutCond.ifNoneMatch {
+		t.Error("expected If-None-Match on create")
+	}
+
+	mb.putErr = &statusError{code: 412}
+	if _, err := c.CreateEvent(context.Background(), "/cal/work", event); err == nil {
+		t.Fatal("expected error when the UID already exists")
+	}
+}
+
+// makeExistingObject returns a calendar object as GetCalendarObject would.
+func makeExistingObject(etag string) *extcaldav.CalendarObject {
+	obj := makeCalendarObject("/cal/event.ics", "uid-1", "Title",
+		time.Date(2024, 1, 15, 14, 0, 0, 0, time.UTC), time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC))
+	obj.ETag = etag
+	return &obj
+}
+
+func TestUpdateEvent_ConditionalOnFetchedETag(t *testing.T) {
+	mb := &mockBackend{
+		getResult: makeExistingObject("etag-1"),
+		putResult: &extcaldav.CalendarObject{},
+	}
+	c := NewClientWithBackend(mb)
+
+	title := "New"
+	if _, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Title: &title}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mb.lastPutCond.ifMatch != "etag-1" {
+		t.Errorf("If-Match = %q, want etag-1", mb.lastPutCond.ifMatch)
+	}
+}
+
+func TestUpdateEvent_StaleETagReturnsConflict(t *testing.T) {
+	mb := &mockBackend{
+		getResult: makeExistingObject("etag-2"),
+		putResult: &extcaldav.CalendarObject{},
+	}
+	c := NewClientWithBackend(mb)
+
+	title := "New"
+	_, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Title: &title, ETag: `"etag-1"`})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected *ConflictError, got %v", err)
+	}
+	if conflict.Current == nil || conflict.Current.ETag != "etag-2" {
+		t.Errorf("conflict should carry the current version, got %+v", conflict.Current)
+	}
+	if mb.lastPutPath != "" {
+		t.Error("stale update must not be written")
+	}
+}
+
+func TestUpdateEvent_MatchingQuotedETag(t *testing.T) {
+	mb := &mockBackend{
+		getResult: makeExistingObject("etag-1"),
+		putResult: &extcaldav.CalendarObject{},
+	}
+	c := NewClientWithBackend(mb)
+
+	title := "New"
+	if _, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Title: &title, ETag: `"etag-1"`}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestUpdateEvent_PreconditionFailedReturnsConflict(t *testing.T) {
+	mb := &mockBackend{
+		getResult: makeExistingObject("etag-1"),
+		putErr:    &statusError{code: 412},
+	}
+	c := NewClientWithBackend(mb)
+
+	title := "New"
+	_, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Title: &title})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected *ConflictError, got %v", err)
+	}
+	if conflict.Path != "/cal/event.ics" {
+		t.Errorf("conflict path = %q", conflict.Path)
+	}
+}
+
+func TestDeleteEvent_WithETag(t *testing.T) {
+	mb := &mockBackend{getResult: makeExistingObject("etag-1")}
+	c := NewClientWithBackend(mb)
+
+	if err := c.DeleteEvent(context.Background(), "/cal/event.ics", `"etag-1"`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mb.lastRemoveCond.ifMatch != "etag-1" {
+		t.Errorf("If-Match = %q, want etag-1", mb.lastRemoveCond.ifMatch)
+	}
+}
+
+func TestDeleteEvent_Conflict(t *testing.T) {
+	mb := &mockBackend{
+		removeErr: &statusError{code: 412},
+		getResult: makeExistingObject("etag-2"),
+	}
+	c := NewClientWithBackend(mb)
+
+	err := c.DeleteEvent(context.Background(), "/cal/event.ics", "etag-1")
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected *ConflictError, got %v", err)
+	}
+	if conflict.Current == nil || conflict.Current.Title != "Title" {
+		t.Errorf("conflict should carry the current version, got %+v", conflict.Current)
+	}
+}
+
+func TestParseCalendarObject_ETag(t *testing.T) {
+	event, err := parseCalendarObject(makeExistingObject("abc123"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.ETag != "abc123" {
+		t.Errorf("ETag = %q, want abc123", event.ETag)
+	}
+}
+
+// putEvent returns the VEVENT of the last calendar object written to mb.
+func putEvent(t *testing.T, mb *mockBackend) *ical.Component {
+	t.Helper()
+	if mb.lastPutCal == nil {
+		t.Fatal("expected a calendar object to be written")
+	}
+	for _, child := range mb.lastPutCal.Children {
+		if child.Name == ical.CompEvent {
+			return child
+		}
+	}
+	t.Fatal("no VEVENT in written calendar object")
+	return nil
+}
+
+func TestCreateEvent_AllDay(t *testing.T) {
+	mb := &mockBackend{putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	event := &Event{
+		Title:     "Holiday",
+		StartTime: time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC),
+		AllDay:    true,
+	}
+	if _, err := c.CreateEvent(context.Background(), "/cal/work", event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	vevent := putEvent(t, mb)
+	dtstart := vevent.Props.Get(ical.PropDateTimeStart)
+	if dtstart.Value != "20241225" || dtstart.ValueType() != ical.ValueDate {
+		t.Errorf("DTSTART = %q (%s), want 20241225 as DATE", dtstart.Value, dtstart.ValueType())
+	}
+	// An end equal to the start means a single day; DTEND is exclusive.
+	if dtend := vevent.Props.Get(ical.PropDateTimeEnd); dtend.Value != "20241226" {
+		t.Errorf("DTEND = %q, want 20241226", dtend.Value)
+	}
+}
+
+func TestCreateEvent_Floating(t *testing.T) {
+	mb := &mockBackend{putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	event := &Event{
+		Title:     "Wake up",
+		StartTime: time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 3, 1, 7, 15, 0, 0, time.UTC),
+		Floating:  true,
+	}
+	if _, err := c.CreateEvent(context.Background(), "/cal/work", event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dtstart := putEvent(t, mb).Props.Get(ical.PropDateTimeStart)
+	if dtstart.Value != "20240301T070000" {
+		t.Errorf("DTSTART = %q, want 20240301T070000", dtstart.Value)
+	}
+	if tzid := dtstart.Params.Get(ical.PropTimezoneID); tzid != "" {
+		t.Errorf("floating DTSTART has TZID %q", tzid)
+	}
+}
+
+func TestParseCalendarObject_AllDay(t *testing.T) {
+	vevent := ical.NewEvent()
+	vevent.Props.SetText(ical.PropUID, "uid-birthday")
+	vevent.Props.SetDate(ical.PropDateTimeStart, time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC))
+
+	cal := ical.NewCalendar()
+	cal.Children = append(cal.Children, vevent.Component)
+
+	event, err := parseCalendarObject(&extcaldav.CalendarObject{Path: "/cal/b.ics", Data: cal})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !event.AllDay {
+		t.Error("expected AllDay")
+	}
+	if want := time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC); !event.StartTime.Equal(want) {
+		t.Errorf("StartTime = %v, want %v", event.StartTime, want)
+	}
+	// Without DTEND an all-day event lasts one day.
+	if want := time.Date(2024, 5, 5, 0, 0, 0, 0, time.UTC); !event.EndTime.Equal(want) {
+		t.Errorf("EndTime = %v, want %v", event.EndTime, want)
+	}
+	if event.Timezone != "" {
+		t.Errorf("Timezone = %q, want empty for all-day event", event.Timezone)
+	}
+}
+
+func TestParseCalendarObject_Floating(t *testing.T) {
+	vevent := ical.NewEvent()
+	vevent.Props.SetText(ical.PropUID, "uid-floating")
+	dtstart := ical.NewProp(ical.PropDateTimeStart)
+	dtstart.Value = "20240301T070000"
+	vevent.Props.Set(dtstart)
+	duration := ical.NewProp(ical.PropDuration)
+	duration.SetDuration(30 * time.Minute)
+	vevent.Props.Set(duration)
+
+	cal := ical.NewCalendar()
+	cal.Children = append(cal.Children, vevent.Component)
+
+	event, err := parseCalendarObject(&extcaldav.CalendarObject{Path: "/cal/f.ics", Data: cal})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !event.Floating || event.AllDay {
+		t.Errorf("Floating = %v, AllDay = %v, want floating only", event.Floating, event.AllDay)
+	}
+	if want := time.Date(2024, 3, 1, 7, 30, 0, 0, time.UTC); !event.EndTime.Equal(want) {
+		t.Errorf("EndTime = %v, want %v (from DURATION)", event.EndTime, want)
+	}
+}
+
+func TestUpdateEvent_ConvertToAllDay(t *testing.T) {
+	mb := &mockBackend{
+		getResult: makeExistingObject(""),
+		putResult: &extcaldav.CalendarObject{},
+	}
+	start := time.Date(2024, 1, 15, 14, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 16, 9, 0, 0, 0, time.UTC)
+	for _, child := range mb.getResult.Data.Children {
+		child.Props.SetDateTime(ical.PropDateTimeStart, start)
+		child.Props.SetDateTime(ical.PropDateTimeEnd, end)
+	}
+	c := NewClientWithBackend(mb)
+
+	allDay := true
+	if _, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{AllDay: &allDay}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	vevent := putEvent(t, mb)
+	if v := vevent.Props.Get(ical.PropDateTimeStart).Value; v != "20240115" {
+		t.Errorf("DTSTART = %q, want 20240115", v)
+	}
+	// The event ran into the 16th, so that day is included.
+	if v := vevent.Props.Get(ical.PropDateTimeEnd).Value; v != "20240117" {
+		t.Errorf("DTEND = %q, want 20240117", v)
+	}
+}
+
+func TestUpdateEvent_ConvertFromAllDayRequiresTimes(t *testing.T) {
+	mb := &mockBackend{
+		getResult: makeExistingObject(""),
+		putResult: &extcaldav.CalendarObject{},
+	}
+	for _, child := range mb.getResult.Data.Children {
+		child.Props.SetDate(ical.PropDateTimeStart, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+	}
+	c := NewClientWithBackend(mb)
+
+	allDay := false
+	if _, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{AllDay: &allDay}); err == nil {
+		t.Fatal("expected error converting to timed event without times")
+	}
+	if mb.lastPutCal != nil {
+		t.Error("event should not have been written")
+	}
+}
+
+func TestUpdateEvent_KeepsAllDay(t *testing.T) {
+	mb := &mockBackend{
+		getResult: makeExistingObject(""),
+		putResult: &extcaldav.CalendarObject{},
+	}
+	for _, child := range mb.getResult.Data.Children {
+		child.Props.SetDate(ical.PropDateTimeStart, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+		child.Props.SetDate(ical.PropDateTimeEnd, time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC))
+	}
+	c := NewClientWithBackend(mb)
+
+	newStart := time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)
+	newEnd := time.Date(2024, 1, 22, 0, 0, 0, 0, time.UTC)
+	if _, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{StartTime: &newStart, EndTime: &newEnd}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	vevent := putEvent(t, mb)
+	dtstart := vevent.Props.Get(ical.PropDateTimeStart)
+	if dtstart.Value != "20240120" || dtstart.ValueType() != ical.ValueDate {
+		t.Errorf("DTSTART = %q (%s), want 20240120 as DATE", dtstart.Value, dtstart.ValueType())
+	}
+	if v := vevent.Props.Get(ical.PropDateTimeEnd).Value; v != "20240122" {
+		t.Errorf("DTEND = %q, want 20240122", v)
+	}
+}
+
+func TestCreateEvent_Timezone(t *testing.T) {
+	mb := &mockBackend{putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	event := &Event{
+		Title:     "Standup",
+		StartTime: time.Date(2024, 3, 4, 14, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 3, 4, 14, 30, 0, 0, time.UTC),
+		Timezone:  "America/New_York",
+	}
+	if _, err := c.CreateEvent(context.Background(), "/cal/work", event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dtstart := putEvent(t, mb).Props.Get(ical.PropDateTimeStart)
+	if dtstart.Value != "20240304T090000" || dtstart.Params.Get(ical.PropTimezoneID) != "America/New_York" {
+		t.Errorf("DTSTART = %q TZID=%q, want 20240304T090000 in America/New_York",
+			dtstart.Value, dtstart.Params.Get(ical.PropTimezoneID))
+	}
+	if tz := timezoneComponent(mb.lastPutCal, "America/New_York"); tz == nil {
+		t.Error("expected a matching VTIMEZONE")
+	}
+}
+
+func TestCreateEvent_InvalidTimezone(t *testing.T) {
+	mb := &mockBackend{putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	event := &Event{Title: "X", StartTime: time.Now(), EndTime: time.Now(), Timezone: "Nowhere/Special"}
+	if _, err := c.CreateEvent(context.Background(), "/cal/work", event); err == nil {
+		t.Fatal("expected error for unknown timezone")
+	}
+}
+
+func TestCreateEvent_FixedOffsetWrittenAsUTC(t *testing.T) {
+	mb := &mockBackend{putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	start, _ := time.Parse(time.RFC3339, "2024-03-04T09:00:00-05:00")
+	event := &Event{Title: "X", StartTime: start, EndTime: start.Add(time.Hour)}
+	if _, err := c.CreateEvent(context.Background(), "/cal/work", event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dtstart := putEvent(t, mb).Props.Get(ical.PropDateTimeStart)
+	if dtstart.Value != "20240304T140000Z" {
+		t.Errorf("DTSTART = %q, want 20240304T140000Z", dtstart.Value)
+	}
+	if _, ok := dtstart.Params[ical.PropTimezoneID]; ok {
+		t.Error("unexpected TZID for a fixed offset")
+	}
+}
+
+func TestUpdateEvent_KeepsEventTimezone(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+	mb := &mockBackend{
+		getResult: makeExistingObject(""),
+		putResult: &extcaldav.CalendarObject{},
+	}
+	for _, child := range mb.getResult.Data.Children {
+		child.Props.SetDateTime(ical.PropDateTimeStart, time.Date(2024, 3, 4, 9, 0, 0, 0, ny))
+		child.Props.SetDateTime(ical.PropDateTimeEnd, time.Date(2024, 3, 4, 10, 0, 0, 0, ny))
+	}
+	c := NewClientWithBackend(mb)
+
+	newStart := time.Date(2024, 3, 5, 15, 0, 0, 0, time.UTC)
+	if _, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{StartTime: &newStart}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dtstart := putEvent(t, mb).Props.Get(ical.PropDateTimeStart)
+	if dtstart.Value != "20240305T100000" || dtstart.Params.Get(ical.PropTimezoneID) != "America/New_York" {
+		t.Errorf("DTSTART = %q TZID=%q, want 20240305T100000 in America/New_York",
+			dtstart.Value, dtstart.Params.Get(ical.PropTimezoneID))
+	}
+	if timezoneComponent(mb.lastPutCal, "America/New_York") == nil {
+		t.Error("expected a VTIMEZONE to be added for the event's zone")
+	}
+}
+
+func TestUpdateEvent_ChangeTimezone(t *testing.T) {
+	mb := &mockBackend{
+		getResult: makeExistingObject(""),
+		putResult: &extcaldav.CalendarObject{},
+	}
+	c := NewClientWithBackend(mb)
+
+	zone := "Europe/Berlin"
+	if _, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Timezone: &zone}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The instant is kept and re-expressed in the new zone.
+	vevent := putEvent(t, mb)
+	dtstart := vevent.Props.Get(ical.PropDateTimeStart)
+	if dtstart.Value != "20240115T150000" || dtstart.Params.Get(ical.PropTimezoneID) != zone {
+		t.Errorf("DTSTART = %q TZID=%q, want 20240115T150000 in %s", dtstart.Value, dtstart.Params.Get(ical.PropTimezoneID), zone)
+	}
+	if v := vevent.Props.Get(ical.PropDateTimeEnd).Value; v != "20240115T160000" {
+		t.Errorf("DTEND = %q, want 20240115T160000", v)
+	}
+}
+
+func TestParseCalendarObject_Timezone(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+	vevent := ical.NewEvent()
+	vevent.Props.SetText(ical.PropUID, "uid-tz")
+	vevent.Props.SetDateTime(ical.PropDateTimeStart, time.Date(2024, 7, 1, 9, 0, 0, 0, ny))
+	vevent.Props.SetDateTime(ical.PropDateTimeEnd, time.Date(2024, 7, 1, 10, 0, 0, 0, ny))
+
+	cal := ical.NewCalendar()
+	cal.Children = append(cal.Children, vevent.Component)
+
+	event, err := parseCalendarObject(&extcaldav.CalendarObject{Path: "/cal/tz.ics", Data: cal})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.Timezone != "America/New_York" || event.StartTime.Location().String() != "America/New_York" {
+		t.Errorf("Timezone = %q, location = %s", event.Timezone, event.StartTime.Location())
+	}
+	if want := time.Date(2024, 7, 1, 13, 0, 0, 0, time.UTC); !event.StartTime.Equal(want) {
+		t.Errorf("StartTime = %v, want %v", event.StartTime, want)
+	}
+}
+
+func TestParseCalendarObject_UnknownTZID(t *testing.T) {
+	vevent := ical.NewEvent()
+	vevent.Props.SetText(ical.PropUID, "uid-windows-tz")
+	dtstart := ical.NewProp(ical.PropDateTimeStart)
+	dtstart.Value = "20240701T090000"
+	dtstart.Params.Set(ical.PropTimezoneID, "Eastern Standard Time")
+	vevent.Props.Set(dtstart)
+
+	cal := ical.NewCalendar()
+	cal.Children = append(cal.Children, vevent.Component)
+
+	event, err := parseCalendarObject(&extcaldav.CalendarObject{Path: "/cal/w.ics", Data: cal})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !event.Floating || event.Timezone != "Eastern Standard Time" {
+		t.Errorf("Floating = %v, Timezone = %q", event.Floating, event.Timezone)
+	}
+	if want := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC); !event.StartTime.Equal(want) {
+		t.Errorf("StartTime = %v, want wall clock %v", event.StartTime, want)
+	}
+}
+
+func TestCreateEvent_Recurring(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+	mb := &mockBackend{putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, ny)
+	event := &Event{
+		Title:           "Weekly sync",
+		StartTime:       start,
+		EndTime:         start.Add(time.Hour),
+		Timezone:        "America/New_York",
+		Recurrence:      "RRULE:FREQ=WEEKLY;COUNT=52",
+		RecurrenceDates: []time.Time{time.Date(2024, 1, 3, 14, 0, 0, 0, time.UTC)},
+		ExceptionDates:  []time.Time{start.AddDate(0, 0, 7)},
+	}
+	if _, err := c.CreateEvent(context.Background(), "/cal/work", event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	vevent := putEvent(t, mb)
+	if rule := vevent.Props.Get(ical.PropRecurrenceRule); rule == nil || rule.Value != "FREQ=WEEKLY;COUNT=52" {
+		t.Errorf("RRULE = %v, want FREQ=WEEKLY;COUNT=52", rule)
+	}
+	rdate := vevent.Props.Get(ical.PropRecurrenceDates)
+	if rdate == nil || rdate.Value != "20240103T090000" || rdate.Params.Get(ical.PropTimezoneID) != "America/New_York" {
+		t.Errorf("RDATE = %+v, want 20240103T090000 in America/New_York", rdate)
+	}
+	if exdate := vevent.Props.Get(ical.PropExceptionDates); exdate == nil || exdate.Value != "20240108T090000" {
+		t.Errorf("EXDATE = %+v, want 20240108T090000", exdate)
+	}
+}
+
+func TestCreateEvent_InvalidRecurrence(t *testing.T) {
+	mb := &mockBackend{putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	event := &Event{Title: "X", StartTime: time.Now(), EndTime: time.Now(), Recurrence: "FREQ=SOMETIMES"}
+	if _, err := c.CreateEvent(context.Background(), "/cal/work", event); err == nil {
+		t.Fatal("expected error for invalid RRULE")
+	}
+	if mb.lastPutCal != nil {
+		t.Error("event should not have been written")
+	}
+}
+
+func TestUpdateEvent_Recurrence(t *testing.T) {
+	mb := &mockBackend{
+		getResult: makeExistingObject(""),
+		putResult: &extcaldav.CalendarObject{},
+	}
+	c := NewClientWithBackend(mb)
+
+	rule := "FREQ=DAILY;COUNT=5"
+	exdates := []time.Time{time.Date(2024, 1, 17, 14, 0, 0, 0, time.UTC)}
+	if _, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Recurrence: &rule, ExceptionDates: &exdates}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	vevent := putEvent(t, mb)
+	if v := vevent.Props.Get(ical.PropRecurrenceRule).Value; v != rule {
+		t.Errorf("RRULE = %q, want %q", v, rule)
+	}
+	if v := vevent.Props.Get(ical.PropExceptionDates).Value; v != "20240117T140000Z" {
+		t.Errorf("EXDATE = %q, want 20240117T140000Z", v)
+	}
+
+	// Clearing the rule and the exceptions
+	clear := ""
+	none := []time.Time{}
+	mb.getResult = &extcaldav.CalendarObject{Data: mb.lastPutCal}
+	if _, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Recurrence: &clear, ExceptionDates: &none}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	vevent = putEvent(t, mb)
+	if vevent.Props.Get(ical.PropRecurrenceRule) != nil || vevent.Props.Get(ical.PropExceptionDates) != nil {
+		t.Error("expected RRULE and EXDATE to be removed")
+	}
+}
+
+func TestUpdateEvent_InvalidRecurrence(t *testing.T) {
+	mb := &mockBackend{getResult: makeExistingObject("")}
+	c := NewClientWithBackend(mb)
+
+	rule := "COUNT=3"
+	if _, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Recurrence: &rule}); err == nil {
+		t.Fatal("expected error for invalid RRULE")
+	}
+	if mb.lastGetPath != "" {
+		t.Error("invalid rule should be rejected before contacting the server")
+	}
+}
+
+func TestUpdateEvent_ConvertToAllDayConvertsExDates(t *testing.T) {
+	mb := &mockBackend{
+		getResult: makeExistingObject(""),
+		putResult: &extcaldav.CalendarObject{},
+	}
+	for _, child := range mb.getResult.Data.Children {
+		child.Props.Add(timeProp(ical.PropExceptionDates, time.Date(2024, 1, 22, 14, 0, 0, 0, time.UTC), false, false))
+	}
+	c := NewClientWithBackend(mb)
+
+	allDay := true
+	if _, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{AllDay: &allDay}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	exdate := putEvent(t, mb).Props.Get(ical.PropExceptionDates)
+	if exdate.Value != "20240122" || exdate.ValueType() != ical.ValueDate {
+		t.Errorf("EXDATE = %q (%s), want 20240122 as DATE", exdate.Value, exdate.ValueType())
+	}
+}
+
+func TestParseCalendarObject_RecurrenceDates(t *testing.T) {
+	vevent := ical.NewEvent()
+	vevent.Props.SetText(ical.PropUID, "uid-r")
+	vevent.Props.SetDateTime(ical.PropDateTimeStart, time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	rdate := ical.NewProp(ical.PropRecurrenceDates)
+	rdate.Value = "20240105T090000Z,20240106T090000Z"
+	vevent.Props.Add(rdate)
+	period := ical.NewProp(ical.PropRecurrenceDates)
+	period.SetValueType(ical.ValuePeriod)
+	period.Value = "20240107T090000Z/PT1H"
+	vevent.Props.Add(period)
+	vevent.Props.Add(timeProp(ical.PropExceptionDates, time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC), false, false))
+
+	cal := ical.NewCalendar()
+	cal.Children = append(cal.Children, vevent.Component)
+
+	event, err := parseCalendarObject(&extcaldav.CalendarObject{Path: "/cal/r.ics", Data: cal})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if len(event.RecurrenceDates) != 3 {
+		t.Errorf("RecurrenceDates = %v, want 3 values", event.RecurrenceDates)
+	}
+	if len(event.ExceptionDates) != 1 {
+		t.Errorf("ExceptionDates = %v, want 1 value", event.ExceptionDates)
+	}
+}
+
+// uidObject returns a calendar object at path holding an event with uid.
+func uidObject(t *testing.T, path, uid string) extcaldav.CalendarObject {
+	t.Helper()
+	return extcaldav.CalendarObject{Path: path, ETag: `"etag-` + uid + `"`, Data: decodeCalendar(t, `
+BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//test//EN
+BEGIN:VEVENT
+UID:`+uid+`
+DTSTAMP:20250101T080000Z
+DTSTART:20250106T100000Z
+DTEND:20250106T110000Z
+SUMMARY:Dentist
+END:VEVENT
+END:VCALENDAR`)}
+}
+
+func TestGetEvent(t *testing.T) {
+	obj := uidObject(t, "/cal/uid-1.ics", "uid-1")
+	mb := &mockBackend{getResult: &obj}
+	c := NewClientWithBackend(mb)
+
+	event, raw, err := c.GetEvent(context.Background(), "/cal/uid-1.ics")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.ID != "uid-1" || event.Title != "Dentist" || event.ETag != "etag-uid-1" {
+		t.Errorf("event = %+v", event)
+	}
+	if raw.Path != "/cal/uid-1.ics" || raw.ETag != "etag-uid-1" || raw.Data != obj.Data {
+		t.Errorf("raw = %+v", raw)
+	}
+	if mb.lastQuery != nil {
+		t.Error("calendar should not be queried when the path exists")
+	}
+}
+
+func TestGetEvent_FindsByUID(t *testing.T) {
+	mb := &mockBackend{
+		getErr: &statusError{code: http.StatusNotFound},
+		queryResult: []extcaldav.CalendarObject{
+			uidObject(t, "/cal/AB12-CD34.ics", "uid-10"),
+			uidObject(t, "/cal/EF56-7890.ics", "uid-1"),
+		},
+	}
+	c := NewClientWithBackend(mb)
+
+	event, _, err := c.GetEvent(context.Background(), "/cal/uid-1.ics")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.ID != "uid-1" || event.Path != "/cal/EF56-7890.ics" {
+		t.Errorf("got %s at %s, want uid-1 at /cal/EF56-7890.ics", event.ID, event.Path)
+	}
+
+	filter := mb.lastQuery.CompFilter.Comps[0].Props
+	if len(filter) != 1 || filter[0].Name != ical.PropUID || filter[0].TextMatch.Text != "uid-1" {
+		t.Errorf("prop-filter = %+v, want UID text-match uid-1", filter)
+	}
+}
+
+func TestGetEvent_NotFound(t *testing.T) {
+	mb := &mockBackend{
+		getErr:      &statusError{code: http.StatusNotFound},
+		queryResult: []extcaldav.CalendarObject{uidObject(t, "/cal/uid-10.ics", "uid-10")},
+	}
+	c := NewClientWithBackend(mb)
+
+	if _, _, err := c.GetEvent(context.Background(), "/cal/uid-1.ics"); !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("err = %v, want ErrEventNotFound", err)
+	}
+
+	// Other failures are reported as they are
+	mb.getErr = &statusError{code: http.StatusInternalServerError}
+	mb.lastQuery = nil
+	if _, _, err := c.GetEvent(context.Background(), "/cal/uid-1.ics"); err == nil || errors.Is(err, ErrEventNotFound) {
+		t.Errorf("err = %v, want the server error", err)
+	}
+	if mb.lastQuery != nil {
+		t.Error("calendar should only be queried after a 404")
+	}
+}
+
+func TestUpdateEvent_ResolvesPathByUID(t *testing.T) {
+	mb := &mockBackend{
+		getErr:      &statusError{code: http.StatusNotFound},
+		queryResult: []extcaldav.CalendarObject{uidObject(t, "/cal/AB12-CD34.ics", "uid-1")},
+		putResult:   &extcaldav.CalendarObject{},
+	}
+	c := NewClientWithBackend(mb)
+
+	title := "Dentist (moved)"
+	if _, err := c.UpdateEvent(context.Background(), "/cal/uid-1.ics", &EventUpdate{Title: &title}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mb.lastPutPath != "/cal/AB12-CD34.ics" {
+		t.Errorf("PUT %s, want the event's actual path", mb.lastPutPath)
+	}
+	if mb.lastPutCond.ifMatch != `"etag-uid-1"` {
+		t.Errorf("If-Match = %q, want the ETag of the found event", mb.lastPutCond.ifMatch)
+	}
+}
+
+func TestDeleteEvent_ResolvesPathByUID(t *testing.T) {
+	mb := &mockBackend{
+		getErr:      &statusError{code: http.StatusNotFound},
+		queryResult: []extcaldav.CalendarObject{uidObject(t, "/cal/AB12-CD34.ics", "uid-1")},
+	}
+	c := NewClientWithBackend(mb)
+
+	if err := c.DeleteEvent(context.Background(), "/cal/uid-1.ics", "etag-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mb.removedPaths) != 1 || mb.removedPaths[0] != "/cal/AB12-CD34.ics" {
+		t.Errorf("removed %v, want only the actual path", mb.removedPaths)
+	}
+	if mb.lastRemoveCond.ifMatch != "etag-1" {
+		t.Errorf("If-Match = %q, want etag-1", mb.lastRemoveCond.ifMatch)
+	}
+
+	// An event that cannot be found is still deleted at the given path,
+	// and reported as not found if the DELETE fails with 404 too
+	mb.queryResult = nil
+	mb.removedPaths = nil
+	if err := c.DeleteEvent(context.Background(), "/cal/AB12-CD34.ics", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mb.removedPaths) != 1 || mb.removedPaths[0] != "/cal/AB12-CD34.ics" {
+		t.Errorf("removed %v, want the given path", mb.removedPaths)
+	}
+	mb.removeErr = &statusError{code: http.StatusNotFound}
+	if err := c.DeleteEvent(context.Background(), "/cal/uid-2.ics", ""); !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("err = %v, want ErrEventNotFound", err)
+	}
 }
@@ -0,0 +1,185 @@
+package caldav
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+)
+
+const (
+	icalDateFormat     = "20060102"
+	icalFloatingFormat = "20060102T150405"
+)
+
+// isDateValue reports whether prop holds a date without a time (VALUE=DATE).
+func isDateValue(prop *ical.Prop) bool {
+	switch prop.ValueType() {
+	case ical.ValueDate:
+		return true
+	case ical.ValueDefault:
+		return len(prop.Value) == len(icalDateFormat)
+	}
+	return false
+}
+
+// isFloatingValue reports whether prop holds a date-time that is not bound to
+// any time zone: no trailing "Z" and no TZID parameter.
+func isFloatingValue(prop *ical.Prop) bool {
+	return !isDateValue(prop) &&
+		!strings.HasSuffix(prop.Value, "Z") &&
+		prop.Params.Get(ical.PropTimezoneID) == ""
+}
+
+// setTimeProp writes a DTSTART/DTEND style property. All-day values are written
+// as VALUE=DATE and floating values as a local time without TZID, using the
+// wall clock of t in its own location. Other values carry the TZID of t's
+// location, or are written in UTC if it has no IANA name.
+func setTimeProp(props ical.Props, name string, t time.Time, allDay, floating bool) {
+	props.Set(timeProp(name, t, allDay, floating))
+}
+
+// setTimeList replaces a multi-valued property such as RDATE or EXDATE, writing
+// one property per value in the same form as setTimeProp.
+func setTimeList(props ical.Props, name string, times []time.Time, allDay, floating bool) {
+	props.Del(name)
+	for _, t := range times {
+		props.Add(timeProp(name, t, allDay, floating))
+	}
+}
+
+func timeProp(name string, t time.Time, allDay, floating bool) *ical.Prop {
+	prop := ical.NewProp(name)
+	switch {
+	case allDay:
+		prop.SetDate(t)
+	case floating:
+		prop.SetValueType(ical.ValueDateTime)
+		prop.Value = t.Format(icalFloatingFormat)
+	case isUTC(t.Location()):
+		prop.SetDateTime(t.UTC())
+	default:
+		prop.SetDateTime(t)
+	}
+	return prop
+}
+
+// parseTimeList reads every value of a multi-valued property such as RDATE or
+// EXDATE, which may hold several comma-separated values per line. PERIOD
+// values contribute their start.
+func parseTimeList(props []ical.Prop) ([]time.Time, error) {
+	var times []time.Time
+	for _, prop := range props {
+		for _, value := range strings.Split(prop.Value, ",") {
+			single := prop
+			single.Value, _, _ = strings.Cut(value, "/")
+			if single.ValueType() == ical.ValuePeriod {
+				single.Params = ical.Params{}
+				if tzid := prop.Params.Get(ical.PropTimezoneID); tzid != "" {
+					single.Params.Set(ical.PropTimezoneID, tzid)
+				}
+			}
+			t, _, err := parseTimeProp(&single)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s value %q: %w", prop.Name, value, err)
+			}
+			times = append(times, t)
+		}
+	}
+	return times, nil
+}
+
+// parseTimeProp reads a DTSTART/DTEND style property. Dates and floating times
+// are returned as UTC wall-clock values. A TZID that is not a known IANA zone
+// is read as a floating time, since its offset cannot be determined.
+func parseTimeProp(prop *ical.Prop) (t time.Time, floating bool, err error) {
+	t, err = prop.DateTime(time.UTC)
+	if err == nil {
+		return t, isFloatingValue(prop), nil
+	}
+	if tzid := prop.Params.Get(ical.PropTimezoneID); tzid != "" && len(prop.Value) == len(icalFloatingFormat) {
+		if t, perr := time.ParseInLocation(icalFloatingFormat, prop.Value, time.UTC); perr == nil {
+			return t, true, nil
+		}
+	}
+	return time.Time{}, false, err
+}
+
+// eventEnd returns the exclusive end of vevent, which starts at start. It
+// falls back to DURATION and then to the RFC 5545 defaults: one day for
+// all-day events and zero length otherwise.
+func eventEnd(vevent *ical.Event, start time.Time, allDay bool) (time.Time, error) {
+	if dtend := vevent.Props.Get(ical.PropDateTimeEnd); dtend != nil {
+		end, _, err := parseTimeProp(dtend)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if allDay && !end.After(start) {
+			end = start.AddDate(0, 0, 1)
+		}
+		return end, nil
+	}
+	if prop := vevent.Props.Get(ical.PropDuration); prop != nil {
+		dur, err := prop.Duration()
+		if err != nil {
+			return time.Time{}, err
+		}
+		return start.Add(dur), nil
+	}
+	if allDay {
+		return start.AddDate(0, 0, 1), nil
+	}
+	return start, nil
+}
+
+// inForm converts times to the form of an event's DTSTART: dates for all-day
+// events, unchanged wall clocks for floating ones, or instants in loc.
+func inForm(times []time.Time, allDay, floating bool, loc *time.Location) []time.Time {
+	converted := make([]time.Time, len(times))
+	for i, t := range times {
+		switch {
+		case allDay:
+			converted[i] = dateOf(t)
+		case floating:
+			converted[i] = t
+		default:
+			converted[i] = t.In(loc)
+		}
+	}
+	return converted
+}
+
+// wallClock returns the date and time of day of t as seen in its own
+// location, re-expressed in loc.
+func wallClock(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
+
+// dateOf returns midnight UTC of the calendar date of t in its own location.
+// All-day events carry their dates in this form.
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// allDayRange converts start and end into the exclusive date range of an
+// all-day event. An end that is not after start yields a single day, and an
+// end partway through a day includes that day.
+func allDayRange(start, end time.Time) (time.Time, time.Time) {
+	startDate := dateOf(start)
+	endDate := dateOf(end)
+	if !wallClock(end, time.UTC).Equal(endDate) {
+		endDate = endDate.AddDate(0, 0, 1)
+	}
+	if !endDate.After(startDate) {
+		endDate = startDate.AddDate(0, 0, 1)
+	}
+	return startDate, endDate
+}
+
+// parseDuration parses an iCalendar DURATION value such as "-PT15M" or "P1D".
+func parseDuration(value string) (time.Duration, error) {
+	prop := ical.NewProp(ical.PropDuration)
+	prop.Value = strings.ToUpper(strings.TrimSpace(value))
+	return prop.Duration()
+}
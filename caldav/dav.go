@@ -0,0 +1,846 @@
+package caldav
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav"
+	extcaldav "github.com/emersion/go-webdav/caldav"
+)
+
+// davBackend is the production backend. It embeds the go-webdav CalDAV client
+// and adds the requests that client does not support, such as conditional
+// writes.
+type davBackend struct {
+	*extcaldav.Client
+	http     webdav.HTTPClient
+	endpoint *url.URL
+	// query is a second go-webdav client whose HTTP client reports error
+	// statuses as a *statusError; see QueryCalendar.
+	query *extcaldav.Client
+}
+
+// Compile-time assertion that davBackend satisfies backend.
+var _ backend = (*davBackend)(nil)
+
+func newDAVBackend(hc webdav.HTTPClient, endpoint string) (*davBackend, error) {
+	client, err := extcaldav.NewClient(hc, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	query, err := extcaldav.NewClient(statusHTTPClient{hc}, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &davBackend{Client: client, http: hc, endpoint: u, query: query}, nil
+}
+
+// statusHTTPClient turns non-2xx responses into a *statusError, which the
+// go-webdav client then returns unchanged.
+type statusHTTPClient struct {
+	webdav.HTTPClient
+}
+
+func (c statusHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, &statusError{code: resp.StatusCode, body: strings.TrimSpace(string(body))}
+	}
+	return resp, nil
+}
+
+// statusError is returned by davBackend when the server answers with a
+// non-2xx status code.
+type statusError struct {
+	code int
+	body string
+}
+
+func (e *statusError) Error() string {
+	msg := fmt.Sprintf("%d %s", e.code, http.StatusText(e.code))
+	if e.body != "" {
+		msg += ": " + e.body
+	}
+	return msg
+}
+
+// precondition holds the conditional headers sent with a write request.
+type precondition struct {
+	ifMatch     string // only write if the resource still has this ETag
+	ifNoneMatch bool   // only write if the resource does not exist yet
+}
+
+// apply sets the conditional headers on req. If-Match requires strong
+// comparison, so a weak ETag can never match; rather than writing
+// unconditionally, apply fails with ErrWeakETag.
+func (p precondition) apply(req *http.Request) error {
+	if strings.HasPrefix(p.ifMatch, "W/") {
+		return fmt.Errorf("%w (%s)", ErrWeakETag, p.ifMatch)
+	}
+	if p.ifMatch != "" {
+		req.Header.Set("If-Match", strconv.Quote(p.ifMatch))
+	}
+	if p.ifNoneMatch {
+		req.Header.Set("If-None-Match", "*")
+	}
+	return nil
+}
+
+func (b *davBackend) newRequest(ctx context.Context, method, p string, body io.Reader) (*http.Request, error) {
+	if !strings.HasPrefix(p, "/") {
+		p = path.Join(b.endpoint.Path, p)
+	}
+	u := url.URL{Scheme: b.endpoint.Scheme, User: b.endpoint.User, Host: b.endpoint.Host, Path: p}
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+// do sends req and converts non-2xx responses into a *statusError.
+func (b *davBackend) do(req *http.Request) (*http.Response, error) {
+	return statusHTTPClient{b.http}.Do(req)
+}
+
+// QueryCalendar sends a calendar-query REPORT. Unlike the go-webdav client
+// it reports error statuses as a *statusError, so callers can tell a filter
+// the server does not support from other failures.
+func (b *davBackend) QueryCalendar(ctx context.Context, p string, query *extcaldav.CalendarQuery) ([]extcaldav.CalendarObject, error) {
+	return b.query.QueryCalendar(ctx, p, query)
+}
+
+// GetCalendarObject downloads the calendar object at path. Unlike the
+// go-webdav client it reports error statuses as a *statusError, so callers
+// can tell a missing resource from other failures.
+func (b *davBackend) GetCalendarObject(ctx context.Context, p string) (*extcaldav.CalendarObject, error) {
+	req, err := b.newRequest(ctx, http.MethodGet, p, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", ical.MIMEType)
+
+	resp, err := b.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	cal, err := ical.NewDecoder(resp.Body).Decode()
+	if err != nil {
+		return nil, fmt.Errorf("invalid calendar data: %w", err)
+	}
+
+	obj := &extcaldav.CalendarObject{Path: resp.Request.URL.Path, Data: cal}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		obj.ETag = normalizeETag(etag)
+	}
+	if lastModified := resp.Header.Get("Last-Modified"); lastModified != "" {
+		if t, err := http.ParseTime(lastModified); err == nil {
+			obj.ModTime = t
+		}
+	}
+	return obj, nil
+}
+
+// PutCalendarObject uploads cal to path, honouring the given precondition.
+func (b *davBackend) PutCalendarObject(ctx context.Context, p string, cal *ical.Calendar, cond precondition) (*extcaldav.CalendarObject, error) {
+	var buf bytes.Buffer
+	if err := ical.NewEncoder(&buf).Encode(cal); err != nil {
+		return nil, err
+	}
+
+	req, err := b.newRequest(ctx, http.MethodPut, p, &buf)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", ical.MIMEType)
+	if err := cond.apply(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := b.do(req)
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+
+	obj := &extcaldav.CalendarObject{Path: p}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		obj.ETag = normalizeETag(etag)
+	}
+	return obj, nil
+}
+
+// Remove deletes the resource at path, honouring the given precondition.
+func (b *davBackend) Remove(ctx context.Context, p string, cond precondition) error {
+	req, err := b.newRequest(ctx, http.MethodDelete, p, nil)
+	if err != nil {
+		return err
+	}
+	if err := cond.apply(req); err != nil {
+		return err
+	}
+
+	resp, err := b.do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	return nil
+}
+
+// MoveCalendarObject moves the resource at path to dest with a WebDAV MOVE,
+// honouring the given precondition. An existing resource at dest is never
+// overwritten.
+func (b *davBackend) MoveCalendarObject(ctx context.Context, p, dest string, cond precondition) error {
+	return b.transfer(ctx, "MOVE", p, dest, cond)
+}
+
+// CopyCalendarObject copies the resource at path to dest with a WebDAV COPY.
+// An existing resource at dest is never overwritten.
+func (b *davBackend) CopyCalendarObject(ctx context.Context, p, dest string) error {
+	return b.transfer(ctx, "COPY", p, dest, precondition{})
+}
+
+// transfer sends a MOVE or COPY request (RFC 4918 sections 9.8 and 9.9) with
+// Overwrite: F, so the server answers 412 if dest already exists.
+func (b *davBackend) transfer(ctx context.Context, method, p, dest string, cond precondition) error {
+	req, err := b.newRequest(ctx, method, p, nil)
+	if err != nil {
+		return err
+	}
+	destReq, err := b.newRequest(ctx, method, dest, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Destination", destReq.URL.String())
+	req.Header.Set("Overwrite", "F")
+	if err := cond.apply(req); err != nil {
+		return err
+	}
+
+	resp, err := b.do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	return nil
+}
+
+// davNamespaces declares the prefixes used in request bodies for WebDAV,
+// CalDAV, Apple's iCal and CalendarServer properties.
+const davNamespaces = `xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav" xmlns:A="http://apple.com/ns/ical/" xmlns:CS="http://calendarserver.org/ns/"`
+
+// davNames is a WebDAV property whose value is a list of empty elements,
+// such as DAV:resourcetype.
+type davNames struct {
+	Names []struct {
+		XMLName xml.Name
+	} `xml:",any"`
+}
+
+func (n davNames) has(space, local string) bool {
+	for _, name := range n.Names {
+		if name.XMLName.Space == space && name.XMLName.Local == local {
+			return true
+		}
+	}
+	return false
+}
+
+// hrefPath returns the path of href, which servers may send as a path or as
+// an absolute URL.
+func hrefPath(href string) string {
+	href = strings.TrimSpace(href)
+	if u, err := url.Parse(href); err == nil && u.Path != "" {
+		return u.Path
+	}
+	return href
+}
+
+// FindCalendars lists the calendar collections in homeSet with a PROPFIND
+// request. Unlike the go-webdav client it also reads Apple's color and
+// order, the current user's privileges (RFC 3744), the getctag and the
+// owner.
+func (b *davBackend) FindCalendars(ctx context.Context, homeSet string) ([]Calendar, error) {
+	body := `<?xml version="1.0" encoding="utf-8"?>
+<D:propfind ` + davNamespaces + `>
+  <D:prop>
+    <D:resourcetype/>
+    <D:displayname/>
+    <C:calendar-description/>
+    <A:calendar-color/>
+    <A:calendar-order/>
+    <C:supported-calendar-component-set/>
+    <D:current-user-privilege-set/>
+    <CS:getctag/>
+    <D:owner/>
+  </D:prop>
+</D:propfind>`
+
+	req, err := b.newRequest(ctx, "PROPFIND", homeSet, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", "1")
+
+	resp, err := b.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var ms struct {
+		Responses []struct {
+			Href      string `xml:"href"`
+			Propstats []struct {
+				Prop struct {
+					ResourceType davNames `xml:"DAV: resourcetype"`
+					DisplayName  string   `xml:"DAV: displayname"`
+					Description  string   `xml:"urn:ietf:params:xml:ns:caldav calendar-description"`
+					Color        string   `xml:"http://apple.com/ns/ical/ calendar-color"`
+					Order        string   `xml:"http://apple.com/ns/ical/ calendar-order"`
+					Components   struct {
+						Comps []struct {
+							Name string `xml:"name,attr"`
+						} `xml:"urn:ietf:params:xml:ns:caldav comp"`
+					} `xml:"urn:ietf:params:xml:ns:caldav supported-calendar-component-set"`
+					Privileges *struct {
+						Privileges []davNames `xml:"DAV: privilege"`
+					} `xml:"DAV: current-user-privilege-set"`
+					CTag  string   `xml:"http://calendarserver.org/ns/ getctag"`
+					Owner davHrefs `xml:"DAV: owner"`
+				} `xml:"prop"`
+				Status string `xml:"status"`
+			} `xml:"propstat"`
+		} `xml:"response"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("failed to decode calendar properties: %w", err)
+	}
+
+	var calendars []Calendar
+	for _, r := range ms.Responses {
+		cal := Calendar{Path: hrefPath(r.Href)}
+		isCalendar := false
+		for _, ps := range r.Propstats {
+			if !strings.Contains(ps.Status, " 200 ") {
+				continue
+			}
+			prop := ps.Prop
+			if prop.ResourceType.has("urn:ietf:params:xml:ns:caldav", "calendar") {
+				isCalendar = true
+			}
+			if prop.DisplayName != "" {
+				cal.Name = strings.TrimSpace(prop.DisplayName)
+			}
+			if prop.Description != "" {
+				cal.Description = strings.TrimSpace(prop.Description)
+			}
+			if prop.Color != "" {
+				cal.Color = strings.TrimSpace(prop.Color)
+			}
+			if order, err := strconv.Atoi(strings.TrimSpace(prop.Order)); err == nil {
+				cal.Order = order
+			}
+			for _, comp := range prop.Components.Comps {
+				cal.SupportedComponents = append(cal.SupportedComponents, strings.ToUpper(comp.Name))
+			}
+			if prop.Privileges != nil {
+				cal.ReadOnly = !canWrite(prop.Privileges.Privileges)
+			}
+			if prop.CTag != "" {
+				cal.CTag = strings.TrimSpace(prop.CTag)
+			}
+			if owner := prop.Owner.first(); owner != "" {
+				cal.Owner = hrefPath(owner)
+			}
+		}
+		// The home set itself, the scheduling inbox and outbox and other
+		// collections are not calendars
+		if isCalendar {
+			calendars = append(calendars, cal)
+		}
+	}
+	return calendars, nil
+}
+
+// IsCalendar reports whether the collection at path has the CALDAV:calendar
+// resource type, with a Depth 0 PROPFIND.
+func (b *davBackend) IsCalendar(ctx context.Context, p string) (bool, error) {
+	body := `<?xml version="1.0" encoding="utf-8"?>
+<D:propfind ` + davNamespaces + `>
+  <D:prop><D:resourcetype/></D:prop>
+</D:propfind>`
+
+	req, err := b.newRequest(ctx, "PROPFIND", p, strings.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", "0")
+
+	resp, err := b.do(req)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var ms struct {
+		Responses []struct {
+			Propstats []struct {
+				ResourceType davNames `xml:"prop>resourcetype"`
+				Status       string   `xml:"status"`
+			} `xml:"propstat"`
+		} `xml:"response"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return false, fmt.Errorf("failed to decode resource type: %w", err)
+	}
+	for _, r := range ms.Responses {
+		for _, ps := range r.Propstats {
+			if strings.Contains(ps.Status, " 200 ") && ps.ResourceType.has("urn:ietf:params:xml:ns:caldav", "calendar") {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// syncResponse is the answer to a sync-collection REPORT.
+type syncResponse struct {
+	token   string
+	changed []string // paths of members added or changed since the token
+	deleted []string // paths of members removed since the token
+	// truncated is set when the server returned only part of the changes;
+	// the rest follow by syncing again from token.
+	truncated bool
+}
+
+// SyncCollection sends a sync-collection REPORT (RFC 6578) for the
+// collection at path, listing the members changed since syncToken, or all
+// members if syncToken is empty.
+func (b *davBackend) SyncCollection(ctx context.Context, p, syncToken string) (*syncResponse, error) {
+	var token strings.Builder
+	_ = xml.EscapeText(&token, []byte(syncToken))
+	body := `<?xml version="1.0" encoding="utf-8"?>
+<D:sync-collection xmlns:D="DAV:">
+  <D:sync-token>` + token.String() + `</D:sync-token>
+  <D:sync-level>1</D:sync-level>
+  <D:prop><D:getetag/></D:prop>
+</D:sync-collection>`
+
+	req, err := b.newRequest(ctx, "REPORT", p, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+
+	resp, err := b.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var ms struct {
+		Responses []struct {
+			Href      string `xml:"href"`
+			Status    string `xml:"status"`
+			Propstats []struct {
+				Status string `xml:"status"`
+			} `xml:"propstat"`
+		} `xml:"response"`
+		SyncToken string `xml:"sync-token"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("failed to decode sync-collection response: %w", err)
+	}
+
+	result := &syncResponse{token: strings.TrimSpace(ms.SyncToken)}
+	collection := strings.TrimSuffix(req.URL.Path, "/")
+	for _, r := range ms.Responses {
+		href := hrefPath(r.Href)
+		if strings.TrimSuffix(href, "/") == collection {
+			// The collection itself only appears to say the response was
+			// truncated (507 Insufficient Storage)
+			if strings.Contains(r.Status, " 507 ") {
+				result.truncated = true
+			}
+			continue
+		}
+		switch {
+		case strings.Contains(r.Status, " 404 "):
+			result.deleted = append(result.deleted, href)
+		case len(r.Propstats) > 0:
+			result.changed = append(result.changed, href)
+		}
+	}
+	return result, nil
+}
+
+// CalendarCTag reads the CalendarServer getctag of the collection at path,
+// which changes whenever any member changes. It is empty if the server does
+// not support it.
+func (b *davBackend) CalendarCTag(ctx context.Context, p string) (string, error) {
+	body := `<?xml version="1.0" encoding="utf-8"?>
+<D:propfind ` + davNamespaces + `>
+  <D:prop><CS:getctag/></D:prop>
+</D:propfind>`
+
+	req, err := b.newRequest(ctx, "PROPFIND", p, strings.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", "0")
+
+	resp, err := b.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var ms struct {
+		Responses []struct {
+			Propstats []struct {
+				CTag   string `xml:"prop>getctag"`
+				Status string `xml:"status"`
+			} `xml:"propstat"`
+		} `xml:"response"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return "", fmt.Errorf("failed to decode getctag: %w", err)
+	}
+	for _, r := range ms.Responses {
+		for _, ps := range r.Propstats {
+			if strings.Contains(ps.Status, " 200 ") && strings.TrimSpace(ps.CTag) != "" {
+				return strings.TrimSpace(ps.CTag), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// canWrite reports whether privileges allow adding or changing calendar
+// objects.
+func canWrite(privileges []davNames) bool {
+	for _, p := range privileges {
+		for _, name := range []string{"all", "write", "write-content", "bind"} {
+			if p.has("DAV:", name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// calendarProps holds the calendar collection properties written by
+// MakeCalendar and PatchCalendar. Nil fields are left out; pointers to empty
+// strings remove the property.
+type calendarProps struct {
+	name        *string
+	description *string
+	color       *string
+	order       *int
+	components  []string
+}
+
+// xml returns the property elements to set and to remove.
+func (p calendarProps) xml() (set, remove string) {
+	var s, r strings.Builder
+	text := func(elem string, v *string) {
+		switch {
+		case v == nil:
+		case *v == "":
+			r.WriteString("<" + elem + "/>")
+		default:
+			s.WriteString("<" + elem + ">")
+			_ = xml.EscapeText(&s, []byte(*v))
+			s.WriteString("</" + elem + ">")
+		}
+	}
+	text("D:displayname", p.name)
+	text("C:calendar-description", p.description)
+	text("A:calendar-color", p.color)
+	if p.order != nil {
+		fmt.Fprintf(&s, "<A:calendar-order>%d</A:calendar-order>", *p.order)
+	}
+	if len(p.components) > 0 {
+		s.WriteString("<C:supported-calendar-component-set>")
+		for _, comp := range p.components {
+			s.WriteString(`<C:comp name="`)
+			_ = xml.EscapeText(&s, []byte(comp))
+			s.WriteString(`"/>`)
+		}
+		s.WriteString("</C:supported-calendar-component-set>")
+	}
+	return s.String(), r.String()
+}
+
+// MakeCalendar creates a calendar collection at path with a MKCALENDAR
+// request (RFC 4791 section 5.3.1), setting the given properties.
+func (b *davBackend) MakeCalendar(ctx context.Context, p string, props calendarProps) error {
+	var body io.Reader
+	if set, _ := props.xml(); set != "" {
+		body = strings.NewReader(`<?xml version="1.0" encoding="utf-8"?>
+<C:mkcalendar ` + davNamespaces + `>
+  <D:set><D:prop>` + set + `</D:prop></D:set>
+</C:mkcalendar>`)
+	}
+
+	req, err := b.newRequest(ctx, "MKCALENDAR", p, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	}
+
+	resp, err := b.do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	return nil
+}
+
+// PatchCalendar sets and removes properties of the collection at path with a
+// PROPPATCH request (RFC 4918 section 9.2). Properties the server refuses
+// are reported as an error.
+func (b *davBackend) PatchCalendar(ctx context.Context, p string, props calendarProps) error {
+	set, remove := props.xml()
+	var body strings.Builder
+	body.WriteString(`<?xml version="1.0" encoding="utf-8"?>
+<D:propertyupdate ` + davNamespaces + `>`)
+	if set != "" {
+		body.WriteString("\n  <D:set><D:prop>" + set + "</D:prop></D:set>")
+	}
+	if remove != "" {
+		body.WriteString("\n  <D:remove><D:prop>" + remove + "</D:prop></D:remove>")
+	}
+	body.WriteString("\n</D:propertyupdate>")
+
+	req, err := b.newRequest(ctx, "PROPPATCH", p, strings.NewReader(body.String()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+
+	resp, err := b.do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var ms struct {
+		Responses []struct {
+			Propstats []struct {
+				Prop struct {
+					Props []struct {
+						XMLName xml.Name
+					} `xml:",any"`
+				} `xml:"prop"`
+				Status string `xml:"status"`
+			} `xml:"propstat"`
+		} `xml:"response"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		// Servers may answer with an empty body on success.
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return fmt.Errorf("failed to decode PROPPATCH response: %w", err)
+	}
+	// A refused property fails the others with 424 Failed Dependency, which
+	// says nothing about them
+	var failed []string
+	for _, r := range ms.Responses {
+		for _, ps := range r.Propstats {
+			status := strings.TrimSpace(ps.Status)
+			if strings.Contains(status, " 200 ") || strings.Contains(status, " 424 ") {
+				continue
+			}
+			for _, prop := range ps.Prop.Props {
+				failed = append(failed, fmt.Sprintf("%s (%s)", prop.XMLName.Local, status))
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("server refused to change %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// FreeBusyQuery sends a CALDAV:free-busy-query REPORT (RFC 4791 section 7.10)
+// for the calendar at path and returns the VFREEBUSY the server answers with.
+func (b *davBackend) FreeBusyQuery(ctx context.Context, p string, start, end time.Time) (*ical.Calendar, error) {
+	const utcFormat = "20060102T150405Z"
+	body := fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<C:free-busy-query xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <C:time-range start="%s" end="%s"/>
+</C:free-busy-query>`, start.UTC().Format(utcFormat), end.UTC().Format(utcFormat))
+
+	req, err := b.newRequest(ctx, "REPORT", p, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", "1")
+
+	resp, err := b.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	cal, err := ical.NewDecoder(resp.Body).Decode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode free-busy response: %w", err)
+	}
+	return cal, nil
+}
+
+// davHrefs is a WebDAV property whose value is a list of DAV:href elements.
+type davHrefs struct {
+	Hrefs []string `xml:"DAV: href"`
+}
+
+func (h davHrefs) first() string {
+	if len(h.Hrefs) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(h.Hrefs[0])
+}
+
+// FindSchedulingInfo reads the RFC 6638 scheduling properties of the
+// principal: its scheduling inbox and outbox and its calendar user
+// addresses. Properties the server does not support are left empty.
+func (b *davBackend) FindSchedulingInfo(ctx context.Context, principal string) (*schedulingInfo, error) {
+	const body = `<?xml version="1.0" encoding="utf-8"?>
+<D:propfind xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop>
+    <C:schedule-inbox-URL/>
+    <C:schedule-outbox-URL/>
+    <C:calendar-user-address-set/>
+  </D:prop>
+</D:propfind>`
+
+	req, err := b.newRequest(ctx, "PROPFIND", principal, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", "0")
+
+	resp, err := b.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var ms struct {
+		Responses []struct {
+			Propstats []struct {
+				Prop struct {
+					Inbox     davHrefs `xml:"urn:ietf:params:xml:ns:caldav schedule-inbox-URL"`
+					Outbox    davHrefs `xml:"urn:ietf:params:xml:ns:caldav schedule-outbox-URL"`
+					Addresses davHrefs `xml:"urn:ietf:params:xml:ns:caldav calendar-user-address-set"`
+				} `xml:"prop"`
+				Status string `xml:"status"`
+			} `xml:"propstat"`
+		} `xml:"response"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("failed to decode scheduling properties: %w", err)
+	}
+
+	info := &schedulingInfo{}
+	for _, r := range ms.Responses {
+		for _, ps := range r.Propstats {
+			if !strings.Contains(ps.Status, " 200 ") {
+				continue
+			}
+			if href := ps.Prop.Inbox.first(); href != "" {
+				info.inbox = href
+			}
+			if href := ps.Prop.Outbox.first(); href != "" {
+				info.outbox = href
+			}
+			for _, addr := range ps.Prop.Addresses.Hrefs {
+				info.addresses = append(info.addresses, strings.TrimSpace(addr))
+			}
+		}
+	}
+	return info, nil
+}
+
+// PostOutbox delivers the iTIP message cal through the scheduling outbox
+// at path (RFC 6638 section 5). Recipients the server could not deliver to
+// are reported as an error.
+func (b *davBackend) PostOutbox(ctx context.Context, p, originator string, recipients []string, cal *ical.Calendar) error {
+	var buf bytes.Buffer
+	if err := ical.NewEncoder(&buf).Encode(cal); err != nil {
+		return err
+	}
+
+	req, err := b.newRequest(ctx, http.MethodPost, p, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", ical.MIMEType+"; charset=utf-8")
+	req.Header.Set("Originator", originator)
+	for _, r := range recipients {
+		req.Header.Add("Recipient", r)
+	}
+
+	resp, err := b.do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var sr struct {
+		Responses []struct {
+			Recipient string `xml:"recipient>href"`
+			Status    string `xml:"request-status"`
+		} `xml:"response"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&sr); err != nil {
+		// Servers may answer with an empty body on success.
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return fmt.Errorf("failed to decode schedule response: %w", err)
+	}
+	var failed []string
+	for _, r := range sr.Responses {
+		if !strings.HasPrefix(strings.TrimSpace(r.Status), "2.") {
+			failed = append(failed, fmt.Sprintf("%s (%s)", strings.TrimSpace(r.Recipient), strings.TrimSpace(r.Status)))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("delivery failed for %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
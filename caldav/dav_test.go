@@ -0,0 +1,697 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+	extcaldav "github.com/emersion/go-webdav/caldav"
+)
+
+func newTestCalendar() *ical.Calendar {
+	event := ical.NewEvent()
+	event.Props.SetText(ical.PropUID, "uid-1")
+	event.Props.SetText(ical.PropDateTimeStamp, "20240101T000000Z")
+	event.Props.SetText(ical.PropSummary, "Meeting")
+
+	cal := ical.NewCalendar()
+	cal.Props.SetText(ical.PropVersion, "2.0")
+	cal.Props.SetText(ical.PropProductID, "-//test//EN")
+	cal.Children = append(cal.Children, event.Component)
+	return cal
+}
+
+func TestDAVBackend_PutCalendarObject_Conditional(t *testing.T) {
+	var ifMatch, ifNoneMatch, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifMatch = r.Header.Get("If-Match")
+		ifNoneMatch = r.Header.Get("If-None-Match")
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.Header().Set("ETag", `"new-etag"`)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	b, err := newDAVBackend(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	obj, err := b.PutCalendarObject(context.Background(), "/cal/uid-1.ics", newTestCalendar(), precondition{ifMatch: "old-etag"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ifMatch != `"old-etag"` {
+		t.Errorf("If-Match = %q, want \"old-etag\"", ifMatch)
+	}
+	if ifNoneMatch != "" {
+		t.Errorf("If-None-Match = %q, want empty", ifNoneMatch)
+	}
+	if obj.ETag != "new-etag" {
+		t.Errorf("ETag = %q, want new-etag", obj.ETag)
+	}
+	if body == "" {
+		t.Error("expected iCalendar body")
+	}
+
+	if _, err := b.PutCalendarObject(context.Background(), "/cal/uid-1.ics", newTestCalendar(), precondition{ifNoneMatch: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ifNoneMatch != "*" || ifMatch != "" {
+		t.Errorf("If-None-Match = %q, If-Match = %q", ifNoneMatch, ifMatch)
+	}
+}
+
+func TestDAVBackend_WeakETagRefusesWrite(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	b, _ := newDAVBackend(srv.Client(), srv.URL)
+	if err := b.Remove(context.Background(), "/cal/uid-1.ics", precondition{ifMatch: "W/abc"}); !errors.Is(err, ErrWeakETag) {
+		t.Fatalf("expected ErrWeakETag, got %v", err)
+	}
+	if _, err := b.PutCalendarObject(context.Background(), "/cal/uid-1.ics", newTestCalendar(), precondition{ifMatch: "W/abc"}); !errors.Is(err, ErrWeakETag) {
+		t.Fatalf("expected ErrWeakETag, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("expected no unconditional writes, got %d requests", requests)
+	}
+}
+
+func TestClient_WeakServerETagWritesUnconditionally(t *testing.T) {
+	series := makeSeriesObject("").Data
+	series.Props.SetText(ical.PropVersion, "2.0")
+	series.Props.SetText(ical.PropProductID, "-//test//EN")
+	series.Children[0].Props.SetText(ical.PropDateTimeStamp, "20240101T000000Z")
+	var writes []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "text/calendar")
+			w.Header().Set("ETag", `W/"1"`)
+			if err := ical.NewEncoder(w).Encode(series); err != nil {
+				t.Errorf("failed to encode event: %v", err)
+			}
+			return
+		}
+		writes = append(writes, r.Method+" If-Match="+r.Header.Get("If-Match"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	b, _ := newDAVBackend(srv.Client(), srv.URL)
+	c := NewClientWithBackend(b)
+	ctx := context.Background()
+
+	title := "Renamed"
+	if _, err := c.UpdateEvent(ctx, "/cal/event.ics", &EventUpdate{Title: &title}); err != nil {
+		t.Fatalf("UpdateEvent: unexpected error: %v", err)
+	}
+	rid := time.Date(2024, 1, 22, 14, 0, 0, 0, time.UTC)
+	if err := c.DeleteOccurrence(ctx, "/cal/event.ics", rid, ScopeThis, ""); err != nil {
+		t.Fatalf("DeleteOccurrence: unexpected error: %v", err)
+	}
+	if err := c.DeleteEvent(ctx, "/cal/event.ics", ""); err != nil {
+		t.Fatalf("DeleteEvent: unexpected error: %v", err)
+	}
+	want := []string{"PUT If-Match=", "PUT If-Match=", "DELETE If-Match="}
+	if strings.Join(writes, ", ") != strings.Join(want, ", ") {
+		t.Errorf("writes = %v, want %v", writes, want)
+	}
+
+	// A weak ETag passed in still cannot make a write conditional
+	writes = nil
+	if _, err := c.UpdateEvent(ctx, "/cal/event.ics", &EventUpdate{Title: &title, ETag: `W/"1"`}); !errors.Is(err, ErrWeakETag) {
+		t.Fatalf("expected ErrWeakETag, got %v", err)
+	}
+	if len(writes) != 0 {
+		t.Errorf("writes = %v, want none", writes)
+	}
+}
+
+func TestDAVBackend_PreconditionFailed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("method = %s, want DELETE", r.Method)
+		}
+		w.WriteHeader(http.StatusPreconditionFailed)
+	}))
+	defer srv.Close()
+
+	b, _ := newDAVBackend(srv.Client(), srv.URL)
+	err := b.Remove(context.Background(), "/cal/uid-1.ics", precondition{ifMatch: "stale"})
+	if !isPreconditionFailed(err) {
+		t.Fatalf("expected 412 status error, got %v", err)
+	}
+}
+
+func TestNormalizeETag(t *testing.T) {
+	tests := map[string]string{
+		`"abc"`:   "abc",
+		`abc`:     "abc",
+		` "abc" `: "abc",
+		`W/"abc"`: "W/abc",
+		``:        "",
+	}
+	for in, want := range tests {
+		if got := normalizeETag(in); got != want {
+			t.Errorf("normalizeETag(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDAVBackend_QueryCalendarStatusError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = io.WriteString(w, `<D:error xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav"><C:supported-filter/></D:error>`)
+	}))
+	defer srv.Close()
+
+	b, _ := newDAVBackend(srv.Client(), srv.URL)
+	_, err := b.QueryCalendar(context.Background(), "/cal/work/", &extcaldav.CalendarQuery{
+		CompFilter: extcaldav.CompFilter{Name: "VCALENDAR"},
+	})
+	if httpStatus(err) != http.StatusForbidden || !reportUnsupported(err) {
+		t.Fatalf("expected a 403 supported-filter status error, got %v", err)
+	}
+}
+
+func TestDAVBackend_IsCalendar(t *testing.T) {
+	var depth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		depth = r.Header.Get("Depth")
+		resourceType := `<C:calendar/>`
+		if strings.HasSuffix(r.URL.Path, "/inbox/") {
+			resourceType = `<C:schedule-inbox/>`
+		}
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(http.StatusMultiStatus)
+		_, _ = io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?>
+<multistatus xmlns="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <response>
+    <href>`+r.URL.Path+`</href>
+    <propstat>
+      <prop><resourcetype><collection/>`+resourceType+`</resourcetype></prop>
+      <status>HTTP/1.1 200 OK</status>
+    </propstat>
+  </response>
+</multistatus>`)
+	}))
+	defer srv.Close()
+
+	b, _ := newDAVBackend(srv.Client(), srv.URL)
+	if ok, err := b.IsCalendar(context.Background(), "/cal/work/"); err != nil || !ok {
+		t.Errorf("work: IsCalendar = %v, %v; want true", ok, err)
+	}
+	if depth != "0" {
+		t.Errorf("Depth = %q, want 0", depth)
+	}
+	if ok, err := b.IsCalendar(context.Background(), "/cal/inbox/"); err != nil || ok {
+		t.Errorf("inbox: IsCalendar = %v, %v; want false", ok, err)
+	}
+}
+
+func TestDAVBackend_FreeBusyQuery(t *testing.T) {
+	var method, depth, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		depth = r.Header.Get("Depth")
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.Header().Set("Content-Type", "text/calendar")
+		_, _ = io.WriteString(w, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n"+
+			"BEGIN:VFREEBUSY\r\nDTSTAMP:20250101T000000Z\r\n"+
+			"FREEBUSY:20250310T090000Z/20250310T100000Z\r\n"+
+			"END:VFREEBUSY\r\nEND:VCALENDAR\r\n")
+	}))
+	defer srv.Close()
+
+	b, _ := newDAVBackend(srv.Client(), srv.URL)
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	cal, err := b.FreeBusyQuery(context.Background(), "/cal/work/", start, start.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if method != "REPORT" || depth != "1" {
+		t.Errorf("method = %s, Depth = %q", method, depth)
+	}
+	if !strings.Contains(body, "free-busy-query") || !strings.Contains(body, `start="20250310T000000Z" end="20250311T000000Z"`) {
+		t.Errorf("unexpected request body: %s", body)
+	}
+	if len(cal.Children) != 1 || cal.Children[0].Name != ical.CompFreeBusy {
+		t.Errorf("expected a VFREEBUSY, got %+v", cal.Children)
+	}
+}
+
+func TestDAVBackend_FindSchedulingInfo(t *testing.T) {
+	var method, depth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		depth = r.Header.Get("Depth")
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(http.StatusMultiStatus)
+		_, _ = io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?>
+<multistatus xmlns="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <response>
+    <href>/123/principal/</href>
+    <propstat>
+      <prop>
+        <C:schedule-inbox-URL><href>/123/calendars/inbox/</href></C:schedule-inbox-URL>
+        <C:calendar-user-address-set>
+          <href>mailto:me@icloud.com</href>
+          <href>urn:uuid:123</href>
+        </C:calendar-user-address-set>
+      </prop>
+      <status>HTTP/1.1 200 OK</status>
+    </propstat>
+    <propstat>
+      <prop><C:schedule-outbox-URL/></prop>
+      <status>HTTP/1.1 404 Not Found</status>
+    </propstat>
+  </response>
+</multistatus>`)
+	}))
+	defer srv.Close()
+
+	b, _ := newDAVBackend(srv.Client(), srv.URL)
+	info, err := b.FindSchedulingInfo(context.Background(), "/123/principal/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if method != "PROPFIND" || depth != "0" {
+		t.Errorf("method = %s, Depth = %q", method, depth)
+	}
+	if info.inbox != "/123/calendars/inbox/" || info.outbox != "" {
+		t.Errorf("inbox = %q, outbox = %q", info.inbox, info.outbox)
+	}
+	if len(info.addresses) != 2 || info.addresses[0] != "mailto:me@icloud.com" {
+		t.Errorf("addresses = %v", info.addresses)
+	}
+}
+
+func TestDAVBackend_PostOutbox(t *testing.T) {
+	var contentType, originator string
+	var recipients []string
+	status := "2.0;Success"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		originator = r.Header.Get("Originator")
+		recipients = r.Header.Values("Recipient")
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		_, _ = io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?>
+<C:schedule-response xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <C:response>
+    <C:recipient><D:href>mailto:boss@example.com</D:href></C:recipient>
+    <C:request-status>`+status+`</C:request-status>
+  </C:response>
+</C:schedule-response>`)
+	}))
+	defer srv.Close()
+
+	b, _ := newDAVBackend(srv.Client(), srv.URL)
+	err := b.PostOutbox(context.Background(), "/123/calendars/outbox/", "mailto:me@icloud.com", []string{"mailto:boss@example.com"}, newTestCalendar())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(contentType, "text/calendar") || originator != "mailto:me@icloud.com" {
+		t.Errorf("Content-Type = %q, Originator = %q", contentType, originator)
+	}
+	if len(recipients) != 1 || recipients[0] != "mailto:boss@example.com" {
+		t.Errorf("Recipient = %v", recipients)
+	}
+
+	status = "3.7;Invalid calendar user"
+	err = b.PostOutbox(context.Background(), "/123/calendars/outbox/", "mailto:me@icloud.com", []string{"mailto:boss@example.com"}, newTestCalendar())
+	if err == nil || !strings.Contains(err.Error(), "boss@example.com") {
+		t.Errorf("err = %v, want a delivery failure for the recipient", err)
+	}
+}
+
+func TestDAVBackend_MoveCalendarObject(t *testing.T) {
+	var method, destination, overwrite, ifMatch, reqPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		reqPath = r.URL.Path
+		destination = r.Header.Get("Destination")
+		overwrite = r.Header.Get("Overwrite")
+		ifMatch = r.Header.Get("If-Match")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	b, _ := newDAVBackend(srv.Client(), srv.URL)
+	err := b.MoveCalendarObject(context.Background(), "/cal/work/uid-1.ics", "/cal/home/uid-1.ics", precondition{ifMatch: "etag-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if method != "MOVE" || reqPath != "/cal/work/uid-1.ics" {
+		t.Errorf("request = %s %s, want MOVE /cal/work/uid-1.ics", method, reqPath)
+	}
+	if destination != srv.URL+"/cal/home/uid-1.ics" {
+		t.Errorf("Destination = %q, want an absolute URL", destination)
+	}
+	if overwrite != "F" || ifMatch != `"etag-1"` {
+		t.Errorf("Overwrite = %q, If-Match = %q", overwrite, ifMatch)
+	}
+
+	if err := b.CopyCalendarObject(context.Background(), "/cal/work/uid-1.ics", "/cal/home/uid-1.ics"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if method != "COPY" || overwrite != "F" || ifMatch != "" {
+		t.Errorf("method = %s, Overwrite = %q, If-Match = %q", method, overwrite, ifMatch)
+	}
+}
+
+func TestDAVBackend_GetCalendarObject(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cal/uid-1.ics" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("ETag", `"etag-1"`)
+		_ = ical.NewEncoder(w).Encode(newTestCalendar())
+	}))
+	defer srv.Close()
+
+	b, _ := newDAVBackend(srv.Client(), srv.URL)
+	obj, err := b.GetCalendarObject(context.Background(), "/cal/uid-1.ics")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if obj.Path != "/cal/uid-1.ics" || obj.ETag != "etag-1" || len(obj.Data.Children) != 1 {
+		t.Errorf("obj = %+v", obj)
+	}
+
+	_, err = b.GetCalendarObject(context.Background(), "/cal/other.ics")
+	if httpStatus(err) != http.StatusNotFound {
+		t.Errorf("err = %v, want a 404 status error", err)
+	}
+}
+
+func TestDAVBackend_MakeCalendar(t *testing.T) {
+	var method, reqPath, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		reqPath = r.URL.Path
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	b, _ := newDAVBackend(srv.Client(), srv.URL)
+	name, color := "Project <X> & Co", "#FF2968"
+	err := b.MakeCalendar(context.Background(), "/cal/project-x/", calendarProps{
+		name:       &name,
+		color:      &color,
+		components: []string{"VEVENT"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if method != "MKCALENDAR" || reqPath != "/cal/project-x/" {
+		t.Errorf("request = %s %s, want MKCALENDAR /cal/project-x/", method, reqPath)
+	}
+	for _, want := range []string{
+		"<D:displayname>Project &lt;X&gt; &amp; Co</D:displayname>",
+		"<A:calendar-color>#FF2968</A:calendar-color>",
+		`<C:supported-calendar-component-set><C:comp name="VEVENT"/></C:supported-calendar-component-set>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body does not contain %s:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "calendar-description") {
+		t.Errorf("body sets a description that was not given:\n%s", body)
+	}
+}
+
+func TestDAVBackend_PatchCalendar(t *testing.T) {
+	var method, body string
+	status := "HTTP/1.1 200 OK"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(http.StatusMultiStatus)
+		_, _ = io.WriteString(w, `<?xml version="1.0" encoding="utf-8"?>
+<D:multistatus xmlns:D="DAV:" xmlns:A="http://apple.com/ns/ical/">
+  <D:response>
+    <D:href>/cal/work/</D:href>
+    <D:propstat>
+      <D:prop><A:calendar-color/></D:prop>
+      <D:status>`+status+`</D:status>
+    </D:propstat>
+    <D:propstat>
+      <D:prop><A:calendar-order/></D:prop>
+      <D:status>HTTP/1.1 424 Failed Dependency</D:status>
+    </D:propstat>
+  </D:response>
+</D:multistatus>`)
+	}))
+	defer srv.Close()
+
+	b, _ := newDAVBackend(srv.Client(), srv.URL)
+	color, description, order := "#1BADF8", "", 2
+	props := calendarProps{color: &color, description: &description, order: &order}
+	if err := b.PatchCalendar(context.Background(), "/cal/work/", props); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if method != "PROPPATCH" {
+		t.Errorf("method = %s, want PROPPATCH", method)
+	}
+	for _, want := range []string{
+		"<D:set><D:prop><A:calendar-color>#1BADF8</A:calendar-color><A:calendar-order>2</A:calendar-order></D:prop></D:set>",
+		"<D:remove><D:prop><C:calendar-description/></D:prop></D:remove>",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body does not contain %s:\n%s", want, body)
+		}
+	}
+
+	status = "HTTP/1.1 403 Forbidden"
+	err := b.PatchCalendar(context.Background(), "/cal/work/", props)
+	if err == nil || !strings.Contains(err.Error(), "calendar-color (HTTP/1.1 403 Forbidden)") || strings.Contains(err.Error(), "calendar-order") {
+		t.Errorf("err = %v, want only the refused calendar-color", err)
+	}
+}
+
+func TestDAVBackend_FindCalendars(t *testing.T) {
+	var depth, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		depth = r.Header.Get("Depth")
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(http.StatusMultiStatus)
+		_, _ = io.WriteString(w, `<?xml version="1.0" encoding="utf-8"?>
+<D:multistatus xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav" xmlns:A="http://apple.com/ns/ical/" xmlns:CS="http://calendarserver.org/ns/">
+  <D:response>
+    <D:href>/123/calendars/</D:href>
+    <D:propstat>
+      <D:prop><D:resourcetype><D:collection/></D:resourcetype></D:prop>
+      <D:status>HTTP/1.1 200 OK</D:status>
+    </D:propstat>
+  </D:response>
+  <D:response>
+    <D:href>https://caldav.example.com/123/calendars/work/</D:href>
+    <D:propstat>
+      <D:prop>
+        <D:resourcetype><D:collection/><C:calendar/></D:resourcetype>
+        <D:displayname>Work</D:displayname>
+        <A:calendar-color>#FF2968FF</A:calendar-color>
+        <A:calendar-order>2</A:calendar-order>
+        <C:supported-calendar-component-set><C:comp name="VEVENT"/></C:supported-calendar-component-set>
+        <D:current-user-privilege-set>
+          <D:privilege><D:read/></D:privilege>
+          <D:privilege><D:write/></D:privilege>
+        </D:current-user-privilege-set>
+        <CS:getctag>ctag-1</CS:getctag>
+        <D:owner><D:href>/123/principal/</D:href></D:owner>
+      </D:prop>
+      <D:status>HTTP/1.1 200 OK</D:status>
+    </D:propstat>
+    <D:propstat>
+      <D:prop><C:calendar-description/></D:prop>
+      <D:status>HTTP/1.1 404 Not Found</D:status>
+    </D:propstat>
+  </D:response>
+  <D:response>
+    <D:href>/123/calendars/holidays/</D:href>
+    <D:propstat>
+      <D:prop>
+        <D:resourcetype><D:collection/><C:calendar/></D:resourcetype>
+        <D:displayname>Holidays</D:displayname>
+        <C:supported-calendar-component-set><C:comp name="VEVENT"/><C:comp name="vtodo"/></C:supported-calendar-component-set>
+        <D:current-user-privilege-set>
+          <D:privilege><D:read/></D:privilege>
+        </D:current-user-privilege-set>
+      </D:prop>
+      <D:status>HTTP/1.1 200 OK</D:status>
+    </D:propstat>
+  </D:response>
+</D:multistatus>`)
+	}))
+	defer srv.Close()
+
+	b, _ := newDAVBackend(srv.Client(), srv.URL)
+	cals, err := b.FindCalendars(context.Background(), "/123/calendars/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if depth != "1" || !strings.Contains(body, "<A:calendar-color/>") || !strings.Contains(body, "<D:current-user-privilege-set/>") {
+		t.Errorf("Depth = %q, body:\n%s", depth, body)
+	}
+	if len(cals) != 2 {
+		t.Fatalf("got %d calendars, want the 2 calendar collections: %+v", len(cals), cals)
+	}
+
+	work := cals[0]
+	if work.Path != "/123/calendars/work/" || work.Name != "Work" || work.Color != "#FF2968FF" || work.Order != 2 {
+		t.Errorf("work = %+v", work)
+	}
+	if work.ReadOnly || work.CTag != "ctag-1" || work.Owner != "/123/principal/" || work.Description != "" {
+		t.Errorf("work = %+v", work)
+	}
+
+	holidays := cals[1]
+	if !holidays.ReadOnly {
+		t.Error("holidays: want ReadOnly without a write privilege")
+	}
+	if len(holidays.SupportedComponents) != 2 || holidays.SupportedComponents[1] != "VTODO" {
+		t.Errorf("holidays components = %v", holidays.SupportedComponents)
+	}
+}
+
+func TestDAVBackend_SyncCollection(t *testing.T) {
+	var method, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(http.StatusMultiStatus)
+		_, _ = io.WriteString(w, `<?xml version="1.0" encoding="utf-8"?>
+<D:multistatus xmlns:D="DAV:">
+  <D:response>
+    <D:href>/cal/changed.ics</D:href>
+    <D:propstat>
+      <D:prop><D:getetag>"e1"</D:getetag></D:prop>
+      <D:status>HTTP/1.1 200 OK</D:status>
+    </D:propstat>
+  </D:response>
+  <D:response>
+    <D:href>https://caldav.example.com/cal/gone.ics</D:href>
+    <D:status>HTTP/1.1 404 Not Found</D:status>
+  </D:response>
+  <D:response>
+    <D:href>/cal/</D:href>
+    <D:status>HTTP/1.1 507 Insufficient Storage</D:status>
+  </D:response>
+  <D:sync-token>https://caldav.example.com/sync/2</D:sync-token>
+</D:multistatus>`)
+	}))
+	defer srv.Close()
+
+	b, _ := newDAVBackend(srv.Client(), srv.URL)
+	resp, err := b.SyncCollection(context.Background(), "/cal/", "https://caldav.example.com/sync/1?a&b")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if method != "REPORT" {
+		t.Errorf("method = %s, want REPORT", method)
+	}
+	if !strings.Contains(body, "<D:sync-token>https://caldav.example.com/sync/1?a&amp;b</D:sync-token>") {
+		t.Errorf("request does not carry the escaped sync token:\n%s", body)
+	}
+	if resp.token != "https://caldav.example.com/sync/2" {
+		t.Errorf("token = %q", resp.token)
+	}
+	if len(resp.changed) != 1 || resp.changed[0] != "/cal/changed.ics" {
+		t.Errorf("changed = %v, want [/cal/changed.ics]", resp.changed)
+	}
+	if len(resp.deleted) != 1 || resp.deleted[0] != "/cal/gone.ics" {
+		t.Errorf("deleted = %v, want [/cal/gone.ics]", resp.deleted)
+	}
+	if !resp.truncated {
+		t.Error("expected the 507 on the collection to mark the response truncated")
+	}
+}
+
+func TestDAVBackend_SyncCollection_InvalidToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = io.WriteString(w, `<D:error xmlns:D="DAV:"><D:valid-sync-token/></D:error>`)
+	}))
+	defer srv.Close()
+
+	b, _ := newDAVBackend(srv.Client(), srv.URL)
+	_, err := b.SyncCollection(context.Background(), "/cal/", "stale")
+	if !invalidSyncToken(err) {
+		t.Errorf("invalidSyncToken(%v) = false, want true", err)
+	}
+}
+
+func TestSyncUnsupported(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want bool
+	}{
+		{&statusError{code: http.StatusMethodNotAllowed}, true},
+		{&statusError{code: http.StatusNotImplemented}, true},
+		{&statusError{code: http.StatusForbidden, body: `<D:error xmlns:D="DAV:"><D:supported-report/></D:error>`}, true},
+		{&statusError{code: http.StatusForbidden}, false},
+		{&statusError{code: http.StatusNotFound}, false},
+		{&statusError{code: http.StatusTooManyRequests}, false},
+		{&statusError{code: http.StatusInternalServerError}, false},
+		{&statusError{code: http.StatusServiceUnavailable}, false},
+		{fmt.Errorf("connection refused"), false},
+	} {
+		if got := syncUnsupported(tc.err); got != tc.want {
+			t.Errorf("syncUnsupported(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestDAVBackend_CalendarCTag(t *testing.T) {
+	var depth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		depth = r.Header.Get("Depth")
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(http.StatusMultiStatus)
+		_, _ = io.WriteString(w, `<?xml version="1.0" encoding="utf-8"?>
+<D:multistatus xmlns:D="DAV:" xmlns:CS="http://calendarserver.org/ns/">
+  <D:response>
+    <D:href>/cal/</D:href>
+    <D:propstat>
+      <D:prop><CS:getctag> ctag-7 </CS:getctag></D:prop>
+      <D:status>HTTP/1.1 200 OK</D:status>
+    </D:propstat>
+  </D:response>
+</D:multistatus>`)
+	}))
+	defer srv.Close()
+
+	b, _ := newDAVBackend(srv.Client(), srv.URL)
+	ctag, err := b.CalendarCTag(context.Background(), "/cal/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ctag != "ctag-7" || depth != "0" {
+		t.Errorf("ctag = %q, depth = %q, want ctag-7 at depth 0", ctag, depth)
+	}
+}
@@ -0,0 +1,100 @@
+package caldav
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/emersion/go-webdav"
+	"github.com/emersion/go-webdav/caldav"
+)
+
+const wellKnownPath = "/.well-known/caldav"
+
+// ResolveServerURL turns a configured server value into the CalDAV context URL
+// that principal discovery should start from.
+//
+// An empty value selects iCloud. A full URL with a path is used as-is. A bare
+// domain (e.g. "fastmail.com") is bootstrapped as described in RFC 6764: DNS
+// SRV/TXT records are consulted first, then https://<domain>/.well-known/caldav.
+// Well-known URLs are followed to the location the server redirects to.
+//
+// hc must not follow redirects itself, otherwise the redirect target is lost.
+func ResolveServerURL(ctx context.Context, hc webdav.HTTPClient, server string) (string, error) {
+	server = strings.TrimSpace(server)
+	if server == "" {
+		return iCloudBaseURL, nil
+	}
+
+	var u *url.URL
+	if strings.Contains(server, "://") {
+		parsed, err := url.Parse(server)
+		if err != nil {
+			return "", fmt.Errorf("invalid server URL: %w", err)
+		}
+		if parsed.Scheme != "http" && parsed.Scheme != "https" {
+			return "", fmt.Errorf("invalid server URL: unsupported scheme %q", parsed.Scheme)
+		}
+		if parsed.Host == "" {
+			return "", fmt.Errorf("invalid server URL: missing host")
+		}
+		if parsed.Path != "" && parsed.Path != "/" && parsed.Path != wellKnownPath {
+			return parsed.String(), nil
+		}
+		u = &url.URL{Scheme: parsed.Scheme, Host: parsed.Host, Path: wellKnownPath}
+	} else {
+		domain := strings.TrimSuffix(server, "/")
+		if strings.ContainsAny(domain, "/?#@") {
+			return "", fmt.Errorf("invalid server domain %q", server)
+		}
+		contextURL, err := caldav.DiscoverContextURL(ctx, domain)
+		if err != nil {
+			slog.Debug("CalDAV SRV lookup failed, trying well-known URL", "domain", domain, "error", err)
+			contextURL = "https://" + domain + wellKnownPath
+		}
+		u, err = url.Parse(contextURL)
+		if err != nil {
+			return "", fmt.Errorf("invalid discovered context URL %q: %w", contextURL, err)
+		}
+		if u.Path != wellKnownPath {
+			return u.String(), nil
+		}
+	}
+
+	return resolveWellKnown(ctx, hc, u)
+}
+
+// resolveWellKnown probes a /.well-known/caldav URL and returns the location
+// the server redirects to. Servers that answer the well-known URL directly are
+// used as-is, and servers that do not know it fall back to their root URL.
+func resolveWellKnown(ctx context.Context, hc webdav.HTTPClient, u *url.URL) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "PROPFIND", u.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build well-known request: %w", err)
+	}
+	req.Header.Set("Depth", "0")
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to query %s: %w", u, err)
+	}
+	_ = resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 300 && resp.StatusCode < 400:
+		loc, err := resp.Location()
+		if err != nil {
+			return "", fmt.Errorf("invalid well-known redirect from %s: %w", u, err)
+		}
+		return loc.String(), nil
+	case resp.StatusCode/100 == 2:
+		return u.String(), nil
+	default:
+		root := &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/"}
+		slog.Debug("well-known CalDAV URL not available, using server root", "url", u.String(), "status", resp.StatusCode)
+		return root.String(), nil
+	}
+}
@@ -0,0 +1,96 @@
+package caldav
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// noRedirectClient mirrors the discovery client built by NewClient.
+func noRedirectClient() *http.Client {
+	return &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func TestResolveServerURL_EmptyDefaultsToICloud(t *testing.T) {
+	got, err := ResolveServerURL(context.Background(), noRedirectClient(), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != iCloudBaseURL {
+		t.Errorf("ResolveServerURL(\"\") = %q, want %q", got, iCloudBaseURL)
+	}
+}
+
+func TestResolveServerURL_FullURLUsedAsIs(t *testing.T) {
+	got, err := ResolveServerURL(context.Background(), noRedirectClient(), "https://cloud.example.com/remote.php/dav")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "https://cloud.example.com/remote.php/dav" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestResolveServerURL_FollowsWellKnownRedirect(t *testing.T) {
+	var gotMethod string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != wellKnownPath {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		gotMethod = r.Method
+		http.Redirect(w, r, "/dav/calendars/", http.StatusMovedPermanently)
+	}))
+	defer srv.Close()
+
+	got, err := ResolveServerURL(context.Background(), noRedirectClient(), srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != srv.URL+"/dav/calendars/" {
+		t.Errorf("got %q, want %q", got, srv.URL+"/dav/calendars/")
+	}
+	if gotMethod != "PROPFIND" {
+		t.Errorf("method = %q, want PROPFIND", gotMethod)
+	}
+}
+
+func TestResolveServerURL_WellKnownServedDirectly(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusMultiStatus)
+	}))
+	defer srv.Close()
+
+	got, err := ResolveServerURL(context.Background(), noRedirectClient(), srv.URL+"/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != srv.URL+wellKnownPath {
+		t.Errorf("got %q, want %q", got, srv.URL+wellKnownPath)
+	}
+}
+
+func TestResolveServerURL_WellKnownMissingFallsBackToRoot(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	got, err := ResolveServerURL(context.Background(), noRedirectClient(), srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != srv.URL+"/" {
+		t.Errorf("got %q, want %q", got, srv.URL+"/")
+	}
+}
+
+func TestResolveServerURL_InvalidValues(t *testing.T) {
+	for _, server := range []string{"ftp://example.com", "https://", "example.com/dav"} {
+		if _, err := ResolveServerURL(context.Background(), noRedirectClient(), server); err == nil {
+			t.Errorf("ResolveServerURL(%q) expected error", server)
+		}
+	}
+}
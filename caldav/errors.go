@@ -0,0 +1,93 @@
+package caldav
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrEventNotFound is returned when no event with the requested UID exists.
+var ErrEventNotFound = errors.New("event not found")
+
+// ErrWeakETag is returned when a write is conditional on a weak ETag. If-Match
+// never matches a weak ETag, so the write cannot be made safely.
+var ErrWeakETag = errors.New("the server gave the resource a weak ETag, which conditional writes cannot use")
+
+// ConflictError is returned when a write is rejected because the event was
+// changed on the server after it was read (HTTP 412 Precondition Failed).
+type ConflictError struct {
+	Path string
+	// Current is the server's current version of the event, or nil if it
+	// could not be fetched (for example because it was deleted).
+	Current *Event
+	// CurrentTask is set instead of Current when the object is a task.
+	CurrentTask *Task
+}
+
+func (e *ConflictError) Error() string {
+	if e.CurrentTask != nil {
+		return fmt.Sprintf("task %s was modified on the server", e.Path)
+	}
+	return fmt.Sprintf("event %s was modified on the server", e.Path)
+}
+
+// StaleError is returned along with results answered from the local cache
+// because the server could not be reached. The results are as of the last
+// successful sync, at Since.
+type StaleError struct {
+	Since time.Time
+	Err   error
+}
+
+func (e *StaleError) Error() string {
+	return fmt.Sprintf("server unreachable, results are from the cache as of %s: %v", e.Since.Format(time.RFC3339), e.Err)
+}
+
+func (e *StaleError) Unwrap() error {
+	return e.Err
+}
+
+// httpStatus returns the HTTP status code carried by err, or 0 if err did not
+// come from an HTTP response.
+func httpStatus(err error) int {
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.code
+	}
+	return 0
+}
+
+// reportUnsupported reports whether err is the server refusing a REPORT
+// because it does not support the filter or the report itself: a 400 or 501,
+// or a 403/409 naming the CALDAV:supported-filter, CALDAV:supported-collation
+// or DAV:supported-report precondition. Other failures, such as an expired
+// login or an outage, are returned rather than worked around.
+func reportUnsupported(err error) bool {
+	switch httpStatus(err) {
+	case http.StatusBadRequest, http.StatusNotImplemented:
+		return true
+	case http.StatusForbidden, http.StatusConflict:
+		msg := err.Error()
+		return strings.Contains(msg, "supported-filter") || strings.Contains(msg, "supported-collation") ||
+			strings.Contains(msg, "supported-report")
+	}
+	return false
+}
+
+func isPreconditionFailed(err error) bool {
+	return httpStatus(err) == http.StatusPreconditionFailed
+}
+
+// normalizeETag strips the quotes around an entity tag so that values from
+// headers, multistatus responses and tool arguments compare equal.
+func normalizeETag(etag string) string {
+	etag = strings.TrimSpace(etag)
+	weak := strings.HasPrefix(etag, "W/")
+	etag = strings.Trim(strings.TrimPrefix(etag, "W/"), `"`)
+	if weak {
+		return "W/" + etag
+	}
+	return etag
+}
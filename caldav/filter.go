@@ -0,0 +1,110 @@
+package caldav
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav/caldav"
+)
+
+// EventFilter restricts SearchEvents to events whose fields contain the
+// given texts, ignoring case. Empty fields match every event; an event must
+// match all the others.
+type EventFilter struct {
+	Title       string
+	Description string
+	Location    string
+	// Attendee matches the email address of any attendee.
+	Attendee string
+	// Category matches any of the event's categories.
+	Category string
+}
+
+// IsZero reports whether f matches every event.
+func (f EventFilter) IsZero() bool {
+	return f == EventFilter{}
+}
+
+// propFilters returns the CalDAV prop-filters for f (RFC 4791 section
+// 9.7.2). Their text-match uses the i;ascii-casemap collation, which only
+// ignores the case of ASCII letters, so texts with other letters are left to
+// the local filter to avoid losing matches.
+func (f EventFilter) propFilters() []caldav.PropFilter {
+	var filters []caldav.PropFilter
+	for _, field := range []struct{ name, text string }{
+		{ical.PropSummary, f.Title},
+		{ical.PropDescription, f.Description},
+		{ical.PropLocation, f.Location},
+		{ical.PropAttendee, f.Attendee},
+		{ical.PropCategories, f.Category},
+	} {
+		if field.text == "" || !isASCII(field.text) {
+			continue
+		}
+		filters = append(filters, caldav.PropFilter{
+			Name:      field.name,
+			TextMatch: &caldav.TextMatch{Text: field.text},
+		})
+	}
+	return filters
+}
+
+// matches reports whether event, or one of its overrides, matches f.
+func (f EventFilter) matches(event Event) bool {
+	if f.matchesEvent(event) {
+		return true
+	}
+	for _, override := range event.Overrides {
+		if f.matchesEvent(override) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f EventFilter) matchesEvent(e Event) bool {
+	if !containsFold(e.Title, f.Title) || !containsFold(e.Description, f.Description) || !containsFold(e.Location, f.Location) {
+		return false
+	}
+	if f.Attendee != "" && !anyContainsFold(attendeeEmails(e.Attendees), f.Attendee) {
+		return false
+	}
+	if f.Category != "" && !anyContainsFold(e.Categories, f.Category) {
+		return false
+	}
+	return true
+}
+
+// containsFold reports whether s contains substr, ignoring case.
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// anyContainsFold reports whether one of values contains substr, ignoring
+// case.
+func anyContainsFold(values []string, substr string) bool {
+	for _, v := range values {
+		if containsFold(v, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func attendeeEmails(attendees []Attendee) []string {
+	emails := make([]string, len(attendees))
+	for i, a := range attendees {
+		emails[i] = a.Email
+	}
+	return emails
+}
+
+func isASCII(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,176 @@
+package caldav
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+	extcaldav "github.com/emersion/go-webdav/caldav"
+)
+
+// filterObjects returns two calendar objects: a dentist appointment with an
+// attendee and a category, and a team meeting.
+func filterObjects(t *testing.T) []extcaldav.CalendarObject {
+	t.Helper()
+	dentist := extcaldav.CalendarObject{Path: "/cal/dentist.ics", Data: decodeCalendar(t, `
+BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//test//EN
+BEGIN:VEVENT
+UID:dentist
+DTSTAMP:20250101T080000Z
+DTSTART:20250310T090000Z
+DTEND:20250310T100000Z
+SUMMARY:Dentist appointment
+LOCATION:Main Street 5
+CATEGORIES:Health,Personal
+ATTENDEE;CN=Dr. Smith:mailto:Smith@Clinic.example
+END:VEVENT
+END:VCALENDAR`)}
+	meeting := extcaldav.CalendarObject{Path: "/cal/meeting.ics", Data: decodeCalendar(t, `
+BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//test//EN
+BEGIN:VEVENT
+UID:meeting
+DTSTAMP:20250101T080000Z
+DTSTART:20250310T140000Z
+DTEND:20250310T150000Z
+SUMMARY:Team meeting
+DESCRIPTION:Quarterly planning
+END:VEVENT
+END:VCALENDAR`)}
+	return []extcaldav.CalendarObject{dentist, meeting}
+}
+
+func TestSearchEvents_TextFilter(t *testing.T) {
+	mb := &mockBackend{queryResult: filterObjects(t)}
+	c := NewClientWithBackend(mb)
+
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	// The mock server ignores the prop-filters, so the results show the
+	// local filtering
+	events, err := c.SearchEvents(context.Background(), "/cal/", &start, &end, SearchOptions{
+		Filter: EventFilter{Title: "DENTIST", Category: "health"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != "dentist" {
+		t.Fatalf("events = %+v, want only the dentist appointment", events)
+	}
+
+	filter := mb.lastQuery.CompFilter.Comps[0]
+	if filter.Start != start || filter.End != end {
+		t.Errorf("time range = %v-%v, want it kept", filter.Start, filter.End)
+	}
+	if len(filter.Props) != 2 || filter.Props[0].Name != ical.PropSummary || filter.Props[0].TextMatch.Text != "DENTIST" ||
+		filter.Props[1].Name != ical.PropCategories {
+		t.Errorf("prop-filters = %+v, want SUMMARY and CATEGORIES text-match", filter.Props)
+	}
+}
+
+func TestSearchEvents_TextMatchRefused(t *testing.T) {
+	mb := &mockBackend{
+		queryResult:  filterObjects(t),
+		textMatchErr: &statusError{code: http.StatusForbidden, body: "supported-filter"},
+	}
+	c := NewClientWithBackend(mb)
+
+	events, err := c.SearchEvents(context.Background(), "/cal/", nil, nil, SearchOptions{
+		Filter: EventFilter{Attendee: "smith@clinic"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != "dentist" {
+		t.Errorf("events = %+v, want the dentist appointment filtered locally", events)
+	}
+	if len(mb.lastQuery.CompFilter.Comps) != 0 {
+		t.Errorf("retried query filter = %+v, want none", mb.lastQuery.CompFilter)
+	}
+}
+
+func TestSearchEvents_TextMatchFailureIsReturned(t *testing.T) {
+	for _, err := range []error{
+		&statusError{code: http.StatusUnauthorized},
+		&statusError{code: http.StatusForbidden},
+		&statusError{code: http.StatusInternalServerError},
+		fmt.Errorf("connection reset"),
+	} {
+		mb := &mockBackend{queryResult: filterObjects(t), textMatchErr: err}
+		c := NewClientWithBackend(mb)
+
+		_, got := c.SearchEvents(context.Background(), "/cal/", nil, nil, SearchOptions{
+			Filter: EventFilter{Attendee: "smith@clinic"},
+		})
+		if got == nil {
+			t.Errorf("%v: expected error", err)
+		}
+		if len(mb.lastQuery.CompFilter.Comps) == 0 {
+			t.Errorf("%v: query was retried without the filter", err)
+		}
+	}
+}
+
+func TestReportUnsupported(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&statusError{code: http.StatusBadRequest}, true},
+		{&statusError{code: http.StatusNotImplemented}, true},
+		{&statusError{code: http.StatusForbidden, body: "<C:supported-filter/>"}, true},
+		{&statusError{code: http.StatusConflict, body: "<C:supported-collation/>"}, true},
+		{&statusError{code: http.StatusForbidden}, false},
+		{&statusError{code: http.StatusUnauthorized}, false},
+		{&statusError{code: http.StatusServiceUnavailable}, false},
+		{fmt.Errorf("connection reset"), false},
+	}
+	for _, tt := range tests {
+		if got := reportUnsupported(tt.err); got != tt.want {
+			t.Errorf("reportUnsupported(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestEventFilter_Matches(t *testing.T) {
+	event := Event{
+		Title:       "Dentist appointment",
+		Description: "Bring the insurance card",
+		Location:    "Main Street 5",
+		Attendees:   []Attendee{{Email: "Smith@Clinic.example", Name: "Dr. Smith"}},
+		Categories:  []string{"Health", "Personal"},
+		Overrides:   []Event{{Title: "Dentist (moved to Zürich)"}},
+	}
+	tests := []struct {
+		filter EventFilter
+		want   bool
+	}{
+		{EventFilter{}, true},
+		{EventFilter{Title: "dentist", Location: "main street"}, true},
+		{EventFilter{Description: "INSURANCE"}, true},
+		{EventFilter{Attendee: "smith@clinic.example"}, true},
+		{EventFilter{Attendee: "Dr. Smith"}, false},
+		{EventFilter{Category: "pers"}, true},
+		{EventFilter{Category: "work"}, false},
+		{EventFilter{Title: "dentist", Location: "elm street"}, false},
+		{EventFilter{Title: "ZÜRICH"}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.matches(event); got != tt.want {
+			t.Errorf("%+v matches = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestEventFilter_NonASCIIIsFilteredLocally(t *testing.T) {
+	filters := EventFilter{Title: "Zürich", Location: "Zurich"}.propFilters()
+	if len(filters) != 1 || filters[0].Name != ical.PropLocation {
+		t.Errorf("prop-filters = %+v, want only LOCATION", filters)
+	}
+}
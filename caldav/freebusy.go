@@ -0,0 +1,162 @@
+package caldav
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+)
+
+// BusyPeriod is an interval during which a calendar is busy.
+type BusyPeriod struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// FreeBusy returns the busy periods of a calendar between start and end,
+// sorted and merged. The server is asked with a free-busy-query REPORT (RFC
+// 4791 section 7.10); if it does not support the report, the periods are
+// computed from the events in the range instead. Either way, transparent and
+// cancelled events do not count. Floating and all-day events are placed in
+// the zone of start.
+func (c *Client) FreeBusy(ctx context.Context, calendarPath string, start, end time.Time) ([]BusyPeriod, error) {
+	if !end.After(start) {
+		return nil, fmt.Errorf("end must be after start")
+	}
+
+	cal, err := c.backend.FreeBusyQuery(ctx, calendarPath, start, end)
+	switch {
+	case err == nil:
+		periods, perr := parseFreeBusy(cal)
+		if perr == nil {
+			return MergeBusyPeriods(periods, start, end), nil
+		}
+		slog.Warn("unreadable free-busy response, computing from events", "path", calendarPath, "error", perr)
+	case reportUnsupported(err):
+		// Servers that do not support free-busy-query on calendar
+		// collections refuse the report.
+		slog.Debug("free-busy-query refused, computing from events", "path", calendarPath, "error", err)
+	default:
+		return nil, fmt.Errorf("failed to query free/busy: %w", err)
+	}
+
+	events, err := c.SearchEvents(ctx, calendarPath, &start, &end, SearchOptions{Expand: true})
+	if err != nil {
+		return nil, err
+	}
+	return MergeBusyPeriods(busyFromEvents(events, start.Location()), start, end), nil
+}
+
+// MergeBusyPeriods clips periods to the range from start to end, drops
+// empty ones, and merges those that overlap or touch. The result is sorted.
+func MergeBusyPeriods(periods []BusyPeriod, start, end time.Time) []BusyPeriod {
+	clipped := make([]BusyPeriod, 0, len(periods))
+	for _, p := range periods {
+		if p.Start.Before(start) {
+			p.Start = start
+		}
+		if p.End.After(end) {
+			p.End = end
+		}
+		if p.End.After(p.Start) {
+			clipped = append(clipped, p)
+		}
+	}
+	sort.Slice(clipped, func(i, j int) bool { return clipped[i].Start.Before(clipped[j].Start) })
+
+	merged := make([]BusyPeriod, 0, len(clipped))
+	for _, p := range clipped {
+		if n := len(merged); n > 0 && !p.Start.After(merged[n-1].End) {
+			if p.End.After(merged[n-1].End) {
+				merged[n-1].End = p.End
+			}
+			continue
+		}
+		merged = append(merged, p)
+	}
+	return merged
+}
+
+// BusyPeriod returns the time e blocks, or false if it is transparent or
+// cancelled and blocks none. Dates and floating times are read as
+// wall-clock times in loc.
+func (e Event) BusyPeriod(loc *time.Location) (BusyPeriod, bool) {
+	if e.Transparent || e.Status == EventCancelled {
+		return BusyPeriod{}, false
+	}
+	start, end := e.StartTime, e.EndTime
+	if e.AllDay || e.Floating {
+		start, end = wallClock(start, loc), wallClock(end, loc)
+	}
+	return BusyPeriod{Start: start, End: end}, true
+}
+
+// busyFromEvents returns the times blocked by events. Dates and floating
+// times are read as wall-clock times in loc.
+func busyFromEvents(events []Event, loc *time.Location) []BusyPeriod {
+	periods := make([]BusyPeriod, 0, len(events))
+	for _, e := range events {
+		if period, ok := e.BusyPeriod(loc); ok {
+			periods = append(periods, period)
+		}
+	}
+	return periods
+}
+
+// parseFreeBusy reads the busy periods of the VFREEBUSY components in cal.
+// Periods with FBTYPE=FREE are skipped; all other types count as busy.
+func parseFreeBusy(cal *ical.Calendar) ([]BusyPeriod, error) {
+	var periods []BusyPeriod
+	found := false
+	for _, child := range cal.Children {
+		if child.Name != ical.CompFreeBusy {
+			continue
+		}
+		found = true
+		for _, prop := range child.Props.Values(ical.PropFreeBusy) {
+			if strings.EqualFold(prop.Params.Get(ical.ParamFreeBusyType), "FREE") {
+				continue
+			}
+			for _, value := range strings.Split(prop.Value, ",") {
+				period, err := parsePeriod(value)
+				if err != nil {
+					return nil, err
+				}
+				periods = append(periods, period)
+			}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("no VFREEBUSY component found")
+	}
+	return periods, nil
+}
+
+// parsePeriod parses an iCalendar PERIOD value: a UTC start followed by
+// either an end or a duration, such as "19970308T160000Z/PT8H30M".
+func parsePeriod(value string) (BusyPeriod, error) {
+	startStr, endStr, ok := strings.Cut(strings.TrimSpace(value), "/")
+	if !ok {
+		return BusyPeriod{}, fmt.Errorf("invalid period %q", value)
+	}
+	start, err := time.Parse(icalFloatingFormat+"Z", startStr)
+	if err != nil {
+		return BusyPeriod{}, fmt.Errorf("invalid period start %q: %w", value, err)
+	}
+	if strings.HasPrefix(strings.TrimPrefix(endStr, "+"), "P") {
+		d, err := parseDuration(endStr)
+		if err != nil {
+			return BusyPeriod{}, fmt.Errorf("invalid period duration %q: %w", value, err)
+		}
+		return BusyPeriod{Start: start, End: start.Add(d)}, nil
+	}
+	end, err := time.Parse(icalFloatingFormat+"Z", endStr)
+	if err != nil {
+		return BusyPeriod{}, fmt.Errorf("invalid period end %q: %w", value, err)
+	}
+	return BusyPeriod{Start: start, End: end}, nil
+}
@@ -0,0 +1,176 @@
+package caldav
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+	extcaldav "github.com/emersion/go-webdav/caldav"
+)
+
+// makeFreeBusyCalendar returns a VFREEBUSY with a FREEBUSY property for each
+// value, and one with FBTYPE=FREE for each free value.
+func makeFreeBusyCalendar(busy []string, free ...string) *ical.Calendar {
+	fb := ical.NewComponent(ical.CompFreeBusy)
+	for _, v := range busy {
+		fb.Props.Add(&ical.Prop{Name: ical.PropFreeBusy, Value: v, Params: ical.Params{}})
+	}
+	for _, v := range free {
+		fb.Props.Add(&ical.Prop{Name: ical.PropFreeBusy, Value: v, Params: ical.Params{ical.ParamFreeBusyType: {"FREE"}}})
+	}
+	cal := ical.NewCalendar()
+	cal.Children = append(cal.Children, fb)
+	return cal
+}
+
+func TestFreeBusy_FromReport(t *testing.T) {
+	mb := &mockBackend{freeBusyResult: makeFreeBusyCalendar([]string{
+		"20250310T130000Z/PT1H,20250310T090000Z/20250310T100000Z",
+		"20250310T093000Z/20250310T103000Z",
+		"20250310T220000Z/20250311T020000Z",
+	}, "20250310T150000Z/20250310T160000Z")}
+	c := NewClientWithBackend(mb)
+
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+	busy, err := c.FreeBusy(context.Background(), "/cal/work", start, end)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []BusyPeriod{
+		{time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC), time.Date(2025, 3, 10, 10, 30, 0, 0, time.UTC)},
+		{time.Date(2025, 3, 10, 13, 0, 0, 0, time.UTC), time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)},
+		{time.Date(2025, 3, 10, 22, 0, 0, 0, time.UTC), end},
+	}
+	if len(busy) != len(want) {
+		t.Fatalf("busy = %v, want %v", busy, want)
+	}
+	for i := range want {
+		if !busy[i].Start.Equal(want[i].Start) || !busy[i].End.Equal(want[i].End) {
+			t.Errorf("busy[%d] = %v, want %v", i, busy[i], want[i])
+		}
+	}
+	if mb.lastQuery != nil {
+		t.Error("events should not be queried when the server answers the REPORT")
+	}
+}
+
+func TestFreeBusy_FallsBackToEvents(t *testing.T) {
+	meeting := makeCalendarObject("/cal/work/1.ics", "1", "Meeting",
+		time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC), time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC))
+	free := makeCalendarObject("/cal/work/2.ics", "2", "Focus time",
+		time.Date(2025, 3, 10, 11, 0, 0, 0, time.UTC), time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC))
+	free.Data.Children[0].Props.SetText(ical.PropTransparency, "TRANSPARENT")
+	cancelled := makeCalendarObject("/cal/work/3.ics", "3", "Cancelled",
+		time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC), time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC))
+	cancelled.Data.Children[0].Props.SetText(ical.PropStatus, "CANCELLED")
+
+	mb := &mockBackend{
+		freeBusyErr: &statusError{code: http.StatusForbidden, body: `<D:error xmlns:D="DAV:"><D:supported-report/></D:error>`},
+		queryResult: []extcaldav.CalendarObject{meeting, free, cancelled},
+	}
+	c := NewClientWithBackend(mb)
+
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	busy, err := c.FreeBusy(context.Background(), "/cal/work", start, start.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(busy) != 1 || !busy[0].Start.Equal(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("busy = %v, want only the meeting", busy)
+	}
+	if mb.lastQuery == nil || mb.lastQuery.CompRequest.Expand == nil {
+		t.Error("expected an expanded event query as fallback")
+	}
+}
+
+func TestFreeBusy_AllDayEventsInZoneOfStart(t *testing.T) {
+	vevent := ical.NewEvent()
+	vevent.Props.SetText(ical.PropUID, "holiday")
+	vevent.Props.SetDate(ical.PropDateTimeStart, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC))
+	vevent.Props.SetDate(ical.PropDateTimeEnd, time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC))
+	cal := ical.NewCalendar()
+	cal.Children = append(cal.Children, vevent.Component)
+
+	mb := &mockBackend{
+		freeBusyErr: &statusError{code: http.StatusNotImplemented},
+		queryResult: []extcaldav.CalendarObject{{Path: "/cal/work/holiday.ics", Data: cal}},
+	}
+	c := NewClientWithBackend(mb)
+
+	ny, _ := time.LoadLocation("America/New_York")
+	start := time.Date(2025, 3, 9, 0, 0, 0, 0, ny)
+	busy, err := c.FreeBusy(context.Background(), "/cal/work", start, start.AddDate(0, 0, 3))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := time.Date(2025, 3, 10, 0, 0, 0, 0, ny)
+	if len(busy) != 1 || !busy[0].Start.Equal(want) || !busy[0].End.Equal(want.AddDate(0, 0, 1)) {
+		t.Errorf("busy = %v, want 10 March in New York", busy)
+	}
+}
+
+func TestFreeBusy_NetworkErrorIsReturned(t *testing.T) {
+	mb := &mockBackend{freeBusyErr: fmt.Errorf("connection reset")}
+	c := NewClientWithBackend(mb)
+
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	if _, err := c.FreeBusy(context.Background(), "/cal/work", start, start.Add(time.Hour)); err == nil {
+		t.Fatal("expected error")
+	}
+	if mb.lastQuery != nil {
+		t.Error("a transport error should not fall back to an event query")
+	}
+}
+
+func TestFreeBusy_OtherStatusIsReturned(t *testing.T) {
+	for _, err := range []error{
+		&statusError{code: http.StatusUnauthorized},
+		&statusError{code: http.StatusForbidden},
+		&statusError{code: http.StatusServiceUnavailable},
+	} {
+		mb := &mockBackend{freeBusyErr: err}
+		c := NewClientWithBackend(mb)
+
+		start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+		if _, got := c.FreeBusy(context.Background(), "/cal/work", start, start.Add(time.Hour)); got == nil {
+			t.Errorf("%v: expected error", err)
+		}
+		if mb.lastQuery != nil {
+			t.Errorf("%v: should not fall back to an event query", err)
+		}
+	}
+}
+
+func TestFreeBusy_InvalidRange(t *testing.T) {
+	c := NewClientWithBackend(&mockBackend{})
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	if _, err := c.FreeBusy(context.Background(), "/cal/work", start, start); err == nil {
+		t.Fatal("expected error for empty range")
+	}
+}
+
+func TestMergeBusyPeriods(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2025, 3, 10, h, 0, 0, 0, time.UTC) }
+	got := MergeBusyPeriods([]BusyPeriod{
+		{at(14), at(15)},
+		{at(9), at(10)},
+		{at(10), at(11)}, // touches the previous one
+		{at(5), at(7)},   // before the range
+		{at(16), at(16)}, // empty
+	}, at(6), at(18))
+
+	want := []BusyPeriod{{at(6), at(7)}, {at(9), at(11)}, {at(14), at(15)}}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Start.Equal(want[i].Start) || !got[i].End.Equal(want[i].End) {
+			t.Errorf("got[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
@@ -3,17 +3,37 @@ package caldav
 import (
 	"context"
 	"time"
+
+	"github.com/emersion/go-ical"
 )
 
 // CalendarService defines the interface for calendar operations.
 type CalendarService interface {
 	DiscoverCalendarHomeSet(ctx context.Context) (string, error)
 	ListCalendars(ctx context.Context) ([]Calendar, error)
-	SearchEvents(ctx context.Context, calendarPath string, startTime, endTime *time.Time) ([]Event, error)
+	CreateCalendar(ctx context.Context, calendar *Calendar) (string, error)
+	UpdateCalendar(ctx context.Context, calendarPath string, update *CalendarUpdate) error
+	DeleteCalendar(ctx context.Context, calendarPath string) error
+	SearchEvents(ctx context.Context, calendarPath string, startTime, endTime *time.Time, opts ...SearchOptions) ([]Event, error)
+	SyncCalendar(ctx context.Context, calendarPath, token string) (*SyncResult, error)
+	GetEvent(ctx context.Context, eventPath string) (*Event, *EventObject, error)
 	CreateEvent(ctx context.Context, calendarPath string, event *Event) (string, error)
-	UpdateEvent(ctx context.Context, eventPath string, update *EventUpdate) error
-	DeleteEvent(ctx context.Context, eventPath string) error
+	UpdateEvent(ctx context.Context, eventPath string, update *EventUpdate) (string, error)
+	DeleteEvent(ctx context.Context, eventPath, etag string) error
+	DeleteOccurrence(ctx context.Context, eventPath string, recurrenceID time.Time, scope, etag string) error
 	GetEventPath(calendarPath, eventID string) string
+	GetEventObject(ctx context.Context, eventPath string) (*EventObject, error)
+	PutEventObject(ctx context.Context, eventPath string, data *ical.Calendar) error
+	DeleteEventObject(ctx context.Context, eventPath, etag string) error
+	MoveEvent(ctx context.Context, eventPath, calendarPath, etag string) (string, error)
+	CopyEvent(ctx context.Context, eventPath, calendarPath string) (string, error)
+	SearchTasks(ctx context.Context, calendarPath string, includeCompleted bool) ([]Task, error)
+	CreateTask(ctx context.Context, calendarPath string, task *Task) (string, error)
+	UpdateTask(ctx context.Context, taskPath string, update *TaskUpdate) error
+	CompleteTask(ctx context.Context, taskPath, etag string) error
+	FreeBusy(ctx context.Context, calendarPath string, start, end time.Time) ([]BusyPeriod, error)
+	ListInvitations(ctx context.Context, start, end time.Time) ([]Invitation, error)
+	RespondToInvitation(ctx context.Context, path string, response InvitationResponse) error
 }
 
 // Compile-time assertion that Client implements CalendarService.
@@ -0,0 +1,200 @@
+package caldav
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+)
+
+// Event statuses (RFC 5545 STATUS values for VEVENT).
+const (
+	EventTentative = "TENTATIVE"
+	EventConfirmed = "CONFIRMED"
+	EventCancelled = "CANCELLED"
+)
+
+// Event access classifications (CLASS values).
+const (
+	ClassPublic       = "PUBLIC"
+	ClassPrivate      = "PRIVATE"
+	ClassConfidential = "CONFIDENTIAL"
+)
+
+// ValidateEventStatus checks that status is a VEVENT status.
+func ValidateEventStatus(status string) error {
+	switch status {
+	case EventTentative, EventConfirmed, EventCancelled:
+		return nil
+	}
+	return fmt.Errorf("invalid event status %q: use %s, %s or %s", status, EventTentative, EventConfirmed, EventCancelled)
+}
+
+// ValidateClass checks that class is an access classification.
+func ValidateClass(class string) error {
+	switch class {
+	case ClassPublic, ClassPrivate, ClassConfidential:
+		return nil
+	}
+	return fmt.Errorf("invalid class %q: use %s, %s or %s", class, ClassPublic, ClassPrivate, ClassConfidential)
+}
+
+// ValidateEventURL checks that link is an absolute URL.
+func ValidateEventURL(link string) error {
+	u, err := url.Parse(link)
+	if err != nil || !u.IsAbs() || u.Host == "" && u.Opaque == "" {
+		return fmt.Errorf("invalid URL %q: use an absolute URL such as https://example.com/agenda", link)
+	}
+	return nil
+}
+
+// ValidateOrganizer checks that organizer is a plain email address.
+func ValidateOrganizer(organizer string) error {
+	addr, err := mail.ParseAddress(organizer)
+	if err != nil || addr.Address != organizer {
+		return fmt.Errorf("invalid organizer %q: use a plain email address such as alice@example.com", organizer)
+	}
+	return nil
+}
+
+// validateMetadata checks the metadata fields shared by Event and
+// EventUpdate. Empty values are not checked.
+func validateMetadata(status, class, link string, priority int) error {
+	if status != "" {
+		if err := ValidateEventStatus(status); err != nil {
+			return err
+		}
+	}
+	if class != "" {
+		if err := ValidateClass(class); err != nil {
+			return err
+		}
+	}
+	if link != "" {
+		if err := ValidateEventURL(link); err != nil {
+			return err
+		}
+	}
+	return validatePriority(priority)
+}
+
+// parseMetadata reads the metadata properties of vevent into event.
+func parseMetadata(props ical.Props, event *Event) {
+	if status := props.Get(ical.PropStatus); status != nil {
+		event.Status = strings.ToUpper(status.Value)
+	}
+	if transp := props.Get(ical.PropTransparency); transp != nil {
+		event.Transparent = strings.EqualFold(transp.Value, "TRANSPARENT")
+	}
+	for _, prop := range props.Values(ical.PropCategories) {
+		categories, err := prop.TextList()
+		if err != nil {
+			continue
+		}
+		for _, category := range categories {
+			if category = strings.TrimSpace(category); category != "" {
+				event.Categories = append(event.Categories, category)
+			}
+		}
+	}
+	if link := props.Get(ical.PropURL); link != nil {
+		event.URL = link.Value
+	}
+	if class := props.Get(ical.PropClass); class != nil {
+		event.Class = strings.ToUpper(class.Value)
+	}
+	if prop := props.Get(ical.PropPriority); prop != nil {
+		if priority, err := prop.Int(); err == nil {
+			event.Priority = priority
+		}
+	}
+	if prop := props.Get(ical.PropSequence); prop != nil {
+		if sequence, err := prop.Int(); err == nil {
+			event.Sequence = sequence
+		}
+	}
+	event.Created = timestamp(props, ical.PropCreated)
+	event.LastModified = timestamp(props, ical.PropLastModified)
+}
+
+// timestamp reads a UTC date-time property such as CREATED, or returns nil.
+func timestamp(props ical.Props, name string) *time.Time {
+	prop := props.Get(name)
+	if prop == nil {
+		return nil
+	}
+	t, err := prop.DateTime(time.UTC)
+	if err != nil {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
+
+// setTransparency writes TRANSP.
+func setTransparency(props ical.Props, transparent bool) {
+	value := "OPAQUE"
+	if transparent {
+		value = "TRANSPARENT"
+	}
+	props.Set(&ical.Prop{Name: ical.PropTransparency, Value: value, Params: ical.Params{}})
+}
+
+// setCategories replaces CATEGORIES with a single property listing
+// categories, or removes it if there are none.
+func setCategories(props ical.Props, categories []string) {
+	props.Del(ical.PropCategories)
+	if len(categories) == 0 {
+		return
+	}
+	prop := ical.NewProp(ical.PropCategories)
+	prop.SetTextList(categories)
+	props.Set(prop)
+}
+
+// significantProps are the VEVENT properties whose change RFC 5545 counts as
+// significant: the organizer increases SEQUENCE so that attendees' copies
+// are replaced.
+var significantProps = []string{
+	ical.PropDateTimeStart,
+	ical.PropDateTimeEnd,
+	ical.PropDuration,
+	ical.PropRecurrenceRule,
+	ical.PropRecurrenceDates,
+	ical.PropExceptionDates,
+	ical.PropStatus,
+}
+
+// significantState renders the significant properties of comp, so that two
+// renderings differ only if one of them changed.
+func significantState(comp *ical.Component) string {
+	var b strings.Builder
+	for _, name := range significantProps {
+		for _, prop := range comp.Props[name] {
+			fmt.Fprintf(&b, "%s%v:%s\n", name, prop.Params, prop.Value)
+		}
+	}
+	return b.String()
+}
+
+// incrementSequence increases the SEQUENCE of comp by one.
+func incrementSequence(comp *ical.Component) {
+	sequence := 0
+	if prop := comp.Props.Get(ical.PropSequence); prop != nil {
+		sequence, _ = strconv.Atoi(strings.TrimSpace(prop.Value))
+	}
+	setInt(comp.Props, ical.PropSequence, sequence+1)
+}
+
+// deref returns *p, or the zero value if p is nil.
+func deref[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
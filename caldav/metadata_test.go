@@ -0,0 +1,245 @@
+package caldav
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+	extcaldav "github.com/emersion/go-webdav/caldav"
+)
+
+func TestParseCalendarObject_Metadata(t *testing.T) {
+	obj := &extcaldav.CalendarObject{Path: "/cal/uid-1.ics", Data: decodeCalendar(t, `
+BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//test//EN
+BEGIN:VEVENT
+UID:uid-1
+DTSTAMP:20250101T080000Z
+DTSTART:20250106T100000Z
+DTEND:20250106T110000Z
+STATUS:tentative
+TRANSP:TRANSPARENT
+CATEGORIES:Work,Travel
+CATEGORIES:Planning
+URL:https://example.com/agenda
+CLASS:CONFIDENTIAL
+PRIORITY:2
+SEQUENCE:7
+CREATED:20241215T101500Z
+LAST-MODIFIED:20250101T080000Z
+END:VEVENT
+END:VCALENDAR`)}
+
+	event, err := parseCalendarObject(obj)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.Status != EventTentative || !event.Transparent {
+		t.Errorf("Status = %q, Transparent = %v", event.Status, event.Transparent)
+	}
+	if len(event.Categories) != 3 || event.Categories[0] != "Work" || event.Categories[2] != "Planning" {
+		t.Errorf("Categories = %v", event.Categories)
+	}
+	if event.URL != "https://example.com/agenda" || event.Class != ClassConfidential {
+		t.Errorf("URL = %q, Class = %q", event.URL, event.Class)
+	}
+	if event.Priority != 2 || event.Sequence != 7 {
+		t.Errorf("Priority = %d, Sequence = %d", event.Priority, event.Sequence)
+	}
+	if event.Created == nil || !event.Created.Equal(time.Date(2024, 12, 15, 10, 15, 0, 0, time.UTC)) {
+		t.Errorf("Created = %v", event.Created)
+	}
+	if event.LastModified == nil || !event.LastModified.Equal(time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("LastModified = %v", event.LastModified)
+	}
+}
+
+func TestCreateEvent_Metadata(t *testing.T) {
+	mb := &mockBackend{putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	_, err := c.CreateEvent(context.Background(), "/cal/work", &Event{
+		Title:       "Offsite",
+		StartTime:   time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC),
+		EndTime:     time.Date(2025, 3, 10, 17, 0, 0, 0, time.UTC),
+		Status:      EventTentative,
+		Transparent: true,
+		Categories:  []string{"Work", "Travel, abroad"},
+		URL:         "https://example.com/offsite",
+		Class:       ClassPrivate,
+		Priority:    1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	vevent := putEvent(t, mb)
+	for name, want := range map[string]string{
+		ical.PropStatus:       "TENTATIVE",
+		ical.PropTransparency: "TRANSPARENT",
+		ical.PropURL:          "https://example.com/offsite",
+		ical.PropClass:        "PRIVATE",
+		ical.PropPriority:     "1",
+	} {
+		if got := vevent.Props.Get(name); got == nil || got.Value != want {
+			t.Errorf("%s = %v, want %q", name, got, want)
+		}
+	}
+	categories, err := vevent.Props.Get(ical.PropCategories).TextList()
+	if err != nil || len(categories) != 2 || categories[1] != "Travel, abroad" {
+		t.Errorf("CATEGORIES = %v (%v)", categories, err)
+	}
+	for _, name := range []string{ical.PropCreated, ical.PropLastModified} {
+		if vevent.Props.Get(name) == nil {
+			t.Errorf("%s not set", name)
+		}
+	}
+}
+
+func TestCreateEvent_InvalidMetadata(t *testing.T) {
+	tests := []struct {
+		name  string
+		event Event
+	}{
+		{"status", Event{Status: "MAYBE"}},
+		{"class", Event{Class: "SECRET"}},
+		{"url", Event{URL: "not a url"}},
+		{"priority", Event{Priority: 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mb := &mockBackend{putResult: &extcaldav.CalendarObject{}}
+			c := NewClientWithBackend(mb)
+			tt.event.Title = "Meeting"
+			tt.event.StartTime = time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+			tt.event.EndTime = tt.event.StartTime.Add(time.Hour)
+			if _, err := c.CreateEvent(context.Background(), "/cal/work", &tt.event); err == nil {
+				t.Fatal("expected error")
+			}
+			if mb.lastPutCal != nil {
+				t.Error("nothing should be written")
+			}
+		})
+	}
+}
+
+// metadataObject returns a calendar object with an event at SEQUENCE 2
+// that has every metadata property set.
+func metadataObject(t *testing.T) *extcaldav.CalendarObject {
+	t.Helper()
+	return &extcaldav.CalendarObject{Path: "/cal/uid-1.ics", ETag: `"etag-1"`, Data: decodeCalendar(t, `
+BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//test//EN
+BEGIN:VEVENT
+UID:uid-1
+DTSTAMP:20250101T080000Z
+DTSTART:20250106T100000Z
+DTEND:20250106T110000Z
+SUMMARY:Review
+STATUS:CONFIRMED
+TRANSP:OPAQUE
+CATEGORIES:Work
+URL:https://example.com/review
+CLASS:PUBLIC
+PRIORITY:5
+SEQUENCE:2
+END:VEVENT
+END:VCALENDAR`)}
+}
+
+func TestUpdateEvent_Metadata(t *testing.T) {
+	mb := &mockBackend{getResult: metadataObject(t), putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	transparent := true
+	categories := []string{"Work", "Review"}
+	class := ClassPrivate
+	priority := 1
+	empty := ""
+	_, err := c.UpdateEvent(context.Background(), "/cal/uid-1.ics", &EventUpdate{
+		Transparent: &transparent,
+		Categories:  &categories,
+		URL:         &empty,
+		Class:       &class,
+		Priority:    &priority,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	event := parseEvent(putEvent(t, mb))
+	if !event.Transparent || len(event.Categories) != 2 || event.URL != "" || event.Class != ClassPrivate || event.Priority != 1 {
+		t.Errorf("event = %+v", event)
+	}
+	if event.Status != EventConfirmed {
+		t.Errorf("Status = %q, want it kept", event.Status)
+	}
+	if event.Sequence != 2 {
+		t.Errorf("Sequence = %d, want 2 after a minor change", event.Sequence)
+	}
+	if event.LastModified == nil {
+		t.Error("LAST-MODIFIED not set")
+	}
+}
+
+func TestUpdateEvent_IncrementsSequence(t *testing.T) {
+	start := time.Date(2025, 1, 6, 14, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	sameStart := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+	cancelled := EventCancelled
+	title := "Review (moved)"
+	rule := "FREQ=WEEKLY"
+
+	tests := []struct {
+		name   string
+		update EventUpdate
+		want   int
+	}{
+		{"title", EventUpdate{Title: &title}, 2},
+		{"times", EventUpdate{StartTime: &start, EndTime: &end}, 3},
+		{"same times", EventUpdate{StartTime: &sameStart}, 2},
+		{"cancel", EventUpdate{Status: &cancelled}, 3},
+		{"recurrence", EventUpdate{Recurrence: &rule}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mb := &mockBackend{getResult: metadataObject(t), putResult: &extcaldav.CalendarObject{}}
+			c := NewClientWithBackend(mb)
+			if _, err := c.UpdateEvent(context.Background(), "/cal/uid-1.ics", &tt.update); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := parseEvent(putEvent(t, mb)).Sequence; got != tt.want {
+				t.Errorf("Sequence = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateEvent_InvalidMetadata(t *testing.T) {
+	mb := &mockBackend{getResult: metadataObject(t), putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	status := "DONE"
+	if _, err := c.UpdateEvent(context.Background(), "/cal/uid-1.ics", &EventUpdate{Status: &status}); err == nil {
+		t.Fatal("expected error")
+	}
+	if mb.lastPutCal != nil {
+		t.Error("nothing should be written")
+	}
+}
+
+func TestValidateEventURL(t *testing.T) {
+	for _, link := range []string{"https://example.com/a?b=c", "mailto:alice@example.com"} {
+		if err := ValidateEventURL(link); err != nil {
+			t.Errorf("ValidateEventURL(%q) = %v", link, err)
+		}
+	}
+	for _, link := range []string{"example.com", "/relative/path", "not a url"} {
+		if err := ValidateEventURL(link); err == nil {
+			t.Errorf("ValidateEventURL(%q) should fail", link)
+		}
+	}
+}
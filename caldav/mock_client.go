@@ -2,30 +2,83 @@ package caldav
 
 import (
 	"context"
+	"fmt"
+	"path"
+	"strings"
 	"time"
+
+	"github.com/emersion/go-ical"
 )
 
 // MockClient implements CalendarService for testing.
 type MockClient struct {
-	Calendars      []Calendar
-	Events         []Event
+	Calendars   []Calendar
+	Events      []Event
+	Tasks       []Task
+	Busy        []BusyPeriod
+	Invitations []Invitation
+	// Syncs are returned by SyncCalendar in turn; without them it returns
+	// Events as a full sync
+	Syncs []*SyncResult
+	// Object is returned by GetEventObject, and by GetEvent with the event
+	Object         *EventObject
 	CreatedEventID string
-	Err            error
+	// NewSeriesID is returned by UpdateEvent
+	NewSeriesID string
+	Err         error
 	// Per-method error overrides
 	ListCalendarsErr error
+	CalendarErr      error
 	SearchEventsErr  error
+	GetEventErr      error
 	CreateEventErr   error
 	UpdateEventErr   error
 	DeleteEventErr   error
 	DiscoverErr      error
+	TaskErr          error
+	FreeBusyErr      error
+	InvitationErr    error
+	MoveErr          error
+	PutObjectErr     error
+	SyncErr          error
 	// Tracking
-	LastUpdatePath  string
-	LastUpdateEvent *EventUpdate
-	LastDeletePath  string
-	LastCreateEvent *Event
-	CreateCallCount int
-	DeleteCallCount int
-	SearchCallCount int
+	LastUpdatePath       string
+	LastUpdateEvent      *EventUpdate
+	LastDeletePath       string
+	LastDeleteETag       string
+	LastCreateEvent      *Event
+	LastSearchOpts       SearchOptions
+	LastGetPath          string
+	CreateCallCount      int
+	DeleteCallCount      int
+	DeleteObjectCount    int
+	SearchCallCount      int
+	SyncCallCount        int
+	LastSyncToken        string
+	LastTaskPath         string
+	LastTaskETag         string
+	LastTaskUpdate       *TaskUpdate
+	LastCreateTask       *Task
+	LastIncludeCompleted bool
+	CompleteCallCount    int
+	FreeBusyPaths        []string
+	LastInvitationPath   string
+	LastResponse         *InvitationResponse
+	LastMovePath         string
+	LastMoveCalendar     string
+	LastMoveETag         string
+	MoveCallCount        int
+	CopyCallCount        int
+	LastPutObjectPath    string
+	LastPutObject        *ical.Calendar
+	LastCreateCalendar   *Calendar
+	LastCalendarPath     string
+	LastCalendarUpdate   *CalendarUpdate
+	CreateCalendarCount  int
+	DeleteCalendarCount  int
+	// Set by DeleteOccurrence only
+	LastDeleteScope        string
+	LastDeleteRecurrenceID time.Time
 }
 
 var _ CalendarService = (*MockClient)(nil)
@@ -50,15 +103,106 @@ func (m *MockClient) ListCalendars(ctx context.Context) ([]Calendar, error) {
 	return m.Calendars, nil
 }
 
-func (m *MockClient) SearchEvents(ctx context.Context, calendarPath string, startTime, endTime *time.Time) ([]Event, error) {
+// CreateCalendar records the calendar and returns a path in the mock's
+// calendar home.
+func (m *MockClient) CreateCalendar(ctx context.Context, calendar *Calendar) (string, error) {
+	m.CreateCalendarCount++
+	m.LastCreateCalendar = calendar
+	if m.CalendarErr != nil {
+		return "", m.CalendarErr
+	}
+	if m.Err != nil {
+		return "", m.Err
+	}
+	return "/calendars/user/new-calendar/", nil
+}
+
+func (m *MockClient) UpdateCalendar(ctx context.Context, calendarPath string, update *CalendarUpdate) error {
+	m.LastCalendarPath = calendarPath
+	m.LastCalendarUpdate = update
+	if m.CalendarErr != nil {
+		return m.CalendarErr
+	}
+	return m.Err
+}
+
+func (m *MockClient) DeleteCalendar(ctx context.Context, calendarPath string) error {
+	m.DeleteCalendarCount++
+	m.LastCalendarPath = calendarPath
+	if m.CalendarErr != nil {
+		return m.CalendarErr
+	}
+	return m.Err
+}
+
+// SearchEvents returns the Events matching SearchOptions.Filter. With
+// SearchOptions.Expand, they are expanded as a server that ignores
+// <C:expand> would leave them to the client.
+func (m *MockClient) SearchEvents(ctx context.Context, calendarPath string, startTime, endTime *time.Time, opts ...SearchOptions) ([]Event, error) {
 	m.SearchCallCount++
+	m.LastSearchOpts = SearchOptions{}
+	if len(opts) > 0 {
+		m.LastSearchOpts = opts[0]
+	}
 	if m.SearchEventsErr != nil {
 		return nil, m.SearchEventsErr
 	}
 	if m.Err != nil {
 		return nil, m.Err
 	}
-	return m.Events, nil
+	var events []Event
+	for _, e := range m.Events {
+		if m.LastSearchOpts.Expand && startTime != nil && endTime != nil {
+			occurrences, err := ExpandRecurrence(e, *startTime, *endTime)
+			if err != nil {
+				return nil, err
+			}
+			for _, o := range occurrences {
+				if m.LastSearchOpts.Filter.matches(o) {
+					events = append(events, o)
+				}
+			}
+		} else if m.LastSearchOpts.Filter.matches(e) {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func (m *MockClient) SyncCalendar(ctx context.Context, calendarPath, token string) (*SyncResult, error) {
+	m.SyncCallCount++
+	m.LastSyncToken = token
+	if m.SyncErr != nil {
+		return nil, m.SyncErr
+	}
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	if len(m.Syncs) > 0 {
+		result := m.Syncs[0]
+		m.Syncs = m.Syncs[1:]
+		return result, nil
+	}
+	return &SyncResult{Token: "mock-token", Full: true, Changed: m.Events}, nil
+}
+
+// GetEvent returns the first of Events whose Path is eventPath or whose ID
+// is the resource name of eventPath.
+func (m *MockClient) GetEvent(ctx context.Context, eventPath string) (*Event, *EventObject, error) {
+	m.LastGetPath = eventPath
+	if m.GetEventErr != nil {
+		return nil, nil, m.GetEventErr
+	}
+	if m.Err != nil {
+		return nil, nil, m.Err
+	}
+	uid := strings.TrimSuffix(path.Base(eventPath), ".ics")
+	for i := range m.Events {
+		if e := m.Events[i]; e.Path == eventPath || e.ID == uid {
+			return &e, m.Object, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("failed to get event: no event with UID %s: %w", uid, ErrEventNotFound)
 }
 
 func (m *MockClient) CreateEvent(ctx context.Context, calendarPath string, event *Event) (string, error) {
@@ -77,21 +221,22 @@ func (m *MockClient) CreateEvent(ctx context.Context, calendarPath string, event
 	return id, nil
 }
 
-func (m *MockClient) UpdateEvent(ctx context.Context, eventPath string, update *EventUpdate) error {
+func (m *MockClient) UpdateEvent(ctx context.Context, eventPath string, update *EventUpdate) (string, error) {
 	m.LastUpdatePath = eventPath
 	m.LastUpdateEvent = update
 	if m.UpdateEventErr != nil {
-		return m.UpdateEventErr
+		return "", m.UpdateEventErr
 	}
 	if m.Err != nil {
-		return m.Err
+		return "", m.Err
 	}
-	return nil
+	return m.NewSeriesID, nil
 }
 
-func (m *MockClient) DeleteEvent(ctx context.Context, eventPath string) error {
+func (m *MockClient) DeleteEvent(ctx context.Context, eventPath, etag string) error {
 	m.DeleteCallCount++
 	m.LastDeletePath = eventPath
+	m.LastDeleteETag = etag
 	if m.DeleteEventErr != nil {
 		return m.DeleteEventErr
 	}
@@ -101,7 +246,143 @@ func (m *MockClient) DeleteEvent(ctx context.Context, eventPath string) error {
 	return nil
 }
 
+func (m *MockClient) DeleteOccurrence(ctx context.Context, eventPath string, recurrenceID time.Time, scope, etag string) error {
+	m.LastDeleteScope = scope
+	m.LastDeleteRecurrenceID = recurrenceID
+	return m.DeleteEvent(ctx, eventPath, etag)
+}
+
 func (m *MockClient) GetEventPath(calendarPath, eventID string) string {
 	c := &Client{}
 	return c.GetEventPath(calendarPath, eventID)
 }
+
+func (m *MockClient) GetEventObject(ctx context.Context, eventPath string) (*EventObject, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	if m.Object == nil {
+		return nil, fmt.Errorf("failed to get event: 404 Not Found")
+	}
+	return m.Object, nil
+}
+
+func (m *MockClient) PutEventObject(ctx context.Context, eventPath string, data *ical.Calendar) error {
+	m.LastPutObjectPath = eventPath
+	m.LastPutObject = data
+	if m.PutObjectErr != nil {
+		return m.PutObjectErr
+	}
+	return m.Err
+}
+
+// DeleteEventObject is recorded like DeleteEvent, and also counted apart.
+func (m *MockClient) DeleteEventObject(ctx context.Context, eventPath, etag string) error {
+	m.DeleteObjectCount++
+	return m.DeleteEvent(ctx, eventPath, etag)
+}
+
+// MoveEvent records the move and returns the path the event would get.
+func (m *MockClient) MoveEvent(ctx context.Context, eventPath, calendarPath, etag string) (string, error) {
+	m.MoveCallCount++
+	m.LastMovePath = eventPath
+	m.LastMoveCalendar = calendarPath
+	m.LastMoveETag = etag
+	if m.MoveErr != nil {
+		return "", m.MoveErr
+	}
+	if m.Err != nil {
+		return "", m.Err
+	}
+	return transferPath(eventPath, calendarPath)
+}
+
+// CopyEvent records the copy like MoveEvent, without an etag.
+func (m *MockClient) CopyEvent(ctx context.Context, eventPath, calendarPath string) (string, error) {
+	m.CopyCallCount++
+	m.LastMovePath = eventPath
+	m.LastMoveCalendar = calendarPath
+	if m.MoveErr != nil {
+		return "", m.MoveErr
+	}
+	if m.Err != nil {
+		return "", m.Err
+	}
+	return transferPath(eventPath, calendarPath)
+}
+
+func (m *MockClient) SearchTasks(ctx context.Context, calendarPath string, includeCompleted bool) ([]Task, error) {
+	m.LastIncludeCompleted = includeCompleted
+	if m.TaskErr != nil {
+		return nil, m.TaskErr
+	}
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Tasks, nil
+}
+
+func (m *MockClient) CreateTask(ctx context.Context, calendarPath string, task *Task) (string, error) {
+	m.LastCreateTask = task
+	if m.TaskErr != nil {
+		return "", m.TaskErr
+	}
+	if m.Err != nil {
+		return "", m.Err
+	}
+	id := m.CreatedEventID
+	if id == "" {
+		id = "mock-task-id"
+	}
+	return id, nil
+}
+
+func (m *MockClient) UpdateTask(ctx context.Context, taskPath string, update *TaskUpdate) error {
+	m.LastTaskPath = taskPath
+	m.LastTaskUpdate = update
+	if m.TaskErr != nil {
+		return m.TaskErr
+	}
+	return m.Err
+}
+
+func (m *MockClient) CompleteTask(ctx context.Context, taskPath, etag string) error {
+	m.CompleteCallCount++
+	m.LastTaskPath = taskPath
+	m.LastTaskETag = etag
+	if m.TaskErr != nil {
+		return m.TaskErr
+	}
+	return m.Err
+}
+
+// FreeBusy returns Busy for every calendar.
+func (m *MockClient) FreeBusy(ctx context.Context, calendarPath string, start, end time.Time) ([]BusyPeriod, error) {
+	m.FreeBusyPaths = append(m.FreeBusyPaths, calendarPath)
+	if m.FreeBusyErr != nil {
+		return nil, m.FreeBusyErr
+	}
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Busy, nil
+}
+
+func (m *MockClient) ListInvitations(ctx context.Context, start, end time.Time) ([]Invitation, error) {
+	if m.InvitationErr != nil {
+		return nil, m.InvitationErr
+	}
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Invitations, nil
+}
+
+func (m *MockClient) RespondToInvitation(ctx context.Context, path string, response InvitationResponse) error {
+	m.LastInvitationPath = path
+	m.LastResponse = &response
+	if m.InvitationErr != nil {
+		return m.InvitationErr
+	}
+	return m.Err
+}
@@ -0,0 +1,130 @@
+package caldav
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/emersion/go-ical"
+)
+
+// EventObject is the complete calendar object of an event as stored on the
+// server, including overrides, time zones and every property Event does not
+// map.
+type EventObject struct {
+	Path string
+	ETag string
+	Data *ical.Calendar
+}
+
+// GetEventObject returns the calendar object at eventPath unchanged. Like
+// GetEvent, it looks the event up by UID if nothing exists at eventPath.
+func (c *Client) GetEventObject(ctx context.Context, eventPath string) (*EventObject, error) {
+	obj, _, err := c.getEvent(ctx, eventPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	if obj.Data == nil {
+		return nil, fmt.Errorf("failed to get event: %s has no calendar data", eventPath)
+	}
+	return &EventObject{Path: obj.Path, ETag: normalizeETag(obj.ETag), Data: obj.Data}, nil
+}
+
+// PutEventObject writes data unchanged to eventPath, refusing to overwrite an
+// existing resource.
+func (c *Client) PutEventObject(ctx context.Context, eventPath string, data *ical.Calendar) error {
+	_, err := c.backend.PutCalendarObject(ctx, eventPath, data, precondition{ifNoneMatch: true})
+	if isPreconditionFailed(err) {
+		return fmt.Errorf("failed to write event: %s already exists", eventPath)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+	return nil
+}
+
+// DeleteEventObject removes the calendar object at eventPath, unlike
+// DeleteEvent without looking it up by UID or sending cancellations to its
+// attendees, as when the event lives on elsewhere. A non-empty etag makes the
+// delete conditional, as for DeleteEvent.
+func (c *Client) DeleteEventObject(ctx context.Context, eventPath, etag string) error {
+	err := c.backend.Remove(ctx, eventPath, precondition{ifMatch: normalizeETag(etag)})
+	if isPreconditionFailed(err) {
+		return c.refetchConflict(ctx, eventPath)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete event: %w", err)
+	}
+	return nil
+}
+
+// MoveEvent moves the event at eventPath into the calendar at calendarPath
+// and returns its new path. Like GetEvent, it looks the event up by UID if
+// nothing exists at eventPath. The server moves the resource itself, so the
+// event keeps its UID, resource name and every property. A non-empty etag
+// makes the move conditional, as for DeleteEvent.
+func (c *Client) MoveEvent(ctx context.Context, eventPath, calendarPath, etag string) (string, error) {
+	obj, eventPath, err := c.getEvent(ctx, eventPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to move event: %w", err)
+	}
+	dest, err := transferPath(eventPath, calendarPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to move event: %w", err)
+	}
+
+	ifMatch := normalizeETag(etag)
+	if ifMatch != "" && obj.ETag != "" && normalizeETag(obj.ETag) != ifMatch {
+		return "", c.conflict(eventPath, obj)
+	}
+	err = c.backend.MoveCalendarObject(ctx, eventPath, dest, precondition{ifMatch: ifMatch})
+	if isPreconditionFailed(err) {
+		// Either the event changed or the target calendar already holds an
+		// event with the same name; the current version tells which.
+		if ifMatch != "" {
+			current, gerr := c.backend.GetCalendarObject(ctx, eventPath)
+			if gerr != nil || normalizeETag(current.ETag) != ifMatch {
+				return "", c.refetchConflict(ctx, eventPath)
+			}
+		}
+		return "", fmt.Errorf("failed to move event: %s already exists", dest)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to move event: %w", err)
+	}
+	return dest, nil
+}
+
+// CopyEvent copies the event at eventPath into the calendar at calendarPath
+// and returns the path of the copy, which keeps the event's UID. The event
+// is looked up as for MoveEvent.
+func (c *Client) CopyEvent(ctx context.Context, eventPath, calendarPath string) (string, error) {
+	_, eventPath, err := c.getEvent(ctx, eventPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to copy event: %w", err)
+	}
+	dest, err := transferPath(eventPath, calendarPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to copy event: %w", err)
+	}
+
+	err = c.backend.CopyCalendarObject(ctx, eventPath, dest)
+	if isPreconditionFailed(err) {
+		return "", fmt.Errorf("failed to copy event: %s already exists", dest)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to copy event: %w", err)
+	}
+	return dest, nil
+}
+
+// transferPath returns the path eventPath gets in the calendar at
+// calendarPath.
+func transferPath(eventPath, calendarPath string) (string, error) {
+	dest := path.Join(strings.TrimSuffix(calendarPath, "/"), path.Base(eventPath))
+	if dest == path.Clean(eventPath) {
+		return "", fmt.Errorf("the event is already in %s", calendarPath)
+	}
+	return dest, nil
+}
@@ -0,0 +1,139 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	extcaldav "github.com/emersion/go-webdav/caldav"
+)
+
+func TestMoveEvent(t *testing.T) {
+	mb := &mockBackend{getResult: makeExistingObject(`"etag-1"`)}
+	c := NewClientWithBackend(mb)
+
+	newPath, err := c.MoveEvent(context.Background(), "/cal/work/uid-1.ics", "/cal/home/", `"etag-1"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if newPath != "/cal/home/uid-1.ics" {
+		t.Errorf("newPath = %q, want /cal/home/uid-1.ics", newPath)
+	}
+	if mb.movedFrom != "/cal/work/uid-1.ics" || mb.movedTo != newPath {
+		t.Errorf("moved %q to %q", mb.movedFrom, mb.movedTo)
+	}
+	if mb.moveCond.ifMatch != "etag-1" {
+		t.Errorf("If-Match = %q, want etag-1", mb.moveCond.ifMatch)
+	}
+}
+
+func TestMoveEvent_SameCalendar(t *testing.T) {
+	mb := &mockBackend{getResult: makeExistingObject("etag-1")}
+	c := NewClientWithBackend(mb)
+
+	if _, err := c.MoveEvent(context.Background(), "/cal/work/uid-1.ics", "/cal/work/", ""); err == nil {
+		t.Fatal("expected error")
+	}
+	if mb.movedFrom != "" {
+		t.Error("nothing should be moved")
+	}
+}
+
+func TestMoveEvent_PreconditionFailed(t *testing.T) {
+	tests := []struct {
+		name         string
+		currentETag  string
+		wantConflict bool
+	}{
+		{"event changed", `"etag-2"`, true},
+		{"target exists", `"etag-1"`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := metadataObject(t)
+			obj.ETag = tt.currentETag
+			mb := &mockBackend{moveErr: &statusError{code: 412}, getResult: obj}
+			c := NewClientWithBackend(mb)
+
+			_, err := c.MoveEvent(context.Background(), "/cal/uid-1.ics", "/cal/home/", "etag-1")
+			var conflict *ConflictError
+			if got := errors.As(err, &conflict); got != tt.wantConflict {
+				t.Fatalf("err = %v, want conflict %v", err, tt.wantConflict)
+			}
+			if !tt.wantConflict && (err == nil || !strings.Contains(err.Error(), "already exists")) {
+				t.Errorf("err = %v, want an already exists error", err)
+			}
+		})
+	}
+}
+
+func TestMoveEvent_ResolvesPathByUID(t *testing.T) {
+	mb := &mockBackend{
+		getErr:      &statusError{code: http.StatusNotFound},
+		queryResult: []extcaldav.CalendarObject{uidObject(t, "/cal/work/AB12-CD34.ics", "uid-1")},
+	}
+	c := NewClientWithBackend(mb)
+
+	newPath, err := c.MoveEvent(context.Background(), "/cal/work/uid-1.ics", "/cal/home/", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mb.movedFrom != "/cal/work/AB12-CD34.ics" || newPath != "/cal/home/AB12-CD34.ics" {
+		t.Errorf("moved %q to %q, want the event's actual resource", mb.movedFrom, newPath)
+	}
+
+	if _, err := c.CopyEvent(context.Background(), "/cal/work/uid-1.ics", "/cal/home/"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mb.copiedFrom != "/cal/work/AB12-CD34.ics" {
+		t.Errorf("copied %q, want the event's actual resource", mb.copiedFrom)
+	}
+
+	// An event that cannot be found is reported as such, without a MOVE
+	mb.queryResult = nil
+	mb.movedFrom = ""
+	if _, err := c.MoveEvent(context.Background(), "/cal/work/uid-2.ics", "/cal/home/", ""); !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("err = %v, want ErrEventNotFound", err)
+	}
+	if mb.movedFrom != "" {
+		t.Error("nothing should be moved")
+	}
+}
+
+func TestCopyEvent(t *testing.T) {
+	mb := &mockBackend{getResult: makeExistingObject("etag-1")}
+	c := NewClientWithBackend(mb)
+
+	newPath, err := c.CopyEvent(context.Background(), "/cal/work/uid-1.ics", "/cal/home")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if newPath != "/cal/home/uid-1.ics" || mb.copiedFrom != "/cal/work/uid-1.ics" || mb.copiedTo != newPath {
+		t.Errorf("copied %q to %q, returned %q", mb.copiedFrom, mb.copiedTo, newPath)
+	}
+
+	mb.copyErr = &statusError{code: 412}
+	if _, err := c.CopyEvent(context.Background(), "/cal/work/uid-1.ics", "/cal/home"); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("err = %v, want an already exists error", err)
+	}
+}
+
+func TestPutEventObject_RefusesOverwrite(t *testing.T) {
+	mb := &mockBackend{putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+	obj := metadataObject(t)
+
+	if err := c.PutEventObject(context.Background(), "/cal/home/uid-1.ics", obj.Data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mb.lastPutCond.ifNoneMatch || mb.lastPutCal != obj.Data {
+		t.Errorf("cond = %+v, want If-None-Match with the data unchanged", mb.lastPutCond)
+	}
+
+	mb.putErr = &statusError{code: 412}
+	if err := c.PutEventObject(context.Background(), "/cal/home/uid-1.ics", obj.Data); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("err = %v, want an already exists error", err)
+	}
+}
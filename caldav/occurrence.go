@@ -0,0 +1,335 @@
+package caldav
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/teambition/rrule-go"
+)
+
+// Scopes of a change to one occurrence of a recurring event.
+const (
+	// ScopeAll changes the whole series.
+	ScopeAll = "all"
+	// ScopeThis changes a single occurrence, written as an override
+	// component (or an EXDATE for deletions) in the same calendar object.
+	ScopeThis = "this"
+	// ScopeThisAndFollowing changes an occurrence and all later ones by
+	// ending the series before it with an UNTIL.
+	ScopeThisAndFollowing = "thisAndFollowing"
+)
+
+// ValidateScope checks that scope is one of ScopeAll, ScopeThis or
+// ScopeThisAndFollowing. An empty scope means ScopeAll.
+func ValidateScope(scope string) error {
+	switch scope {
+	case "", ScopeAll, ScopeThis, ScopeThisAndFollowing:
+		return nil
+	}
+	return fmt.Errorf("invalid scope %q: use %q, %q or %q", scope, ScopeThis, ScopeThisAndFollowing, ScopeAll)
+}
+
+// OccurrenceID returns a stable identifier for the occurrence of the event
+// with the given UID that originally started at rid: the UID and the
+// RECURRENCE-ID value joined by "#", such as "abc@host#20250310T090000Z".
+// Dates and floating times keep their form; other times are given in UTC.
+func OccurrenceID(uid string, rid time.Time, allDay, floating bool) string {
+	var value string
+	switch {
+	case allDay:
+		value = rid.Format(icalDateFormat)
+	case floating:
+		value = rid.Format(icalFloatingFormat)
+	default:
+		value = rid.UTC().Format(icalFloatingFormat) + "Z"
+	}
+	return uid + "#" + value
+}
+
+// ParseOccurrenceID splits an identifier made by OccurrenceID into the UID
+// and the original start of the occurrence. ok is false if id is a plain UID.
+func ParseOccurrenceID(id string) (uid string, rid time.Time, ok bool) {
+	i := strings.LastIndex(id, "#")
+	if i <= 0 {
+		return id, time.Time{}, false
+	}
+	for _, layout := range []string{icalFloatingFormat + "Z", icalFloatingFormat, icalDateFormat} {
+		if t, err := time.Parse(layout, id[i+1:]); err == nil {
+			return id[:i], t, true
+		}
+	}
+	return id, time.Time{}, false
+}
+
+// seriesForm is the form of a series' DTSTART. RECURRENCE-ID, RDATE and
+// EXDATE values of the series are written in the same form.
+type seriesForm struct {
+	allDay, floating bool
+	loc              *time.Location
+}
+
+// formOf reads the form and start of the series defined by master.
+func formOf(master *ical.Component) (seriesForm, time.Time, error) {
+	dtstart := master.Props.Get(ical.PropDateTimeStart)
+	if dtstart == nil {
+		return seriesForm{}, time.Time{}, fmt.Errorf("event has no start time")
+	}
+	start, floating, err := parseTimeProp(dtstart)
+	if err != nil {
+		return seriesForm{}, time.Time{}, fmt.Errorf("failed to read event start: %w", err)
+	}
+	return seriesForm{allDay: isDateValue(dtstart), floating: floating, loc: start.Location()}, start, nil
+}
+
+// normalize converts t, the original start of an occurrence, to the form of
+// the series.
+func (f seriesForm) normalize(t time.Time) time.Time {
+	switch {
+	case f.allDay:
+		return dateOf(t)
+	case f.floating:
+		return wallClock(t, time.UTC)
+	default:
+		return t.In(f.loc)
+	}
+}
+
+func (f seriesForm) prop(name string, t time.Time) *ical.Prop {
+	return timeProp(name, t, f.allDay, f.floating)
+}
+
+// splitEvents returns the master VEVENT of cal, the one without a
+// RECURRENCE-ID, and the overrides of its occurrences. An object holding only
+// overrides, as sent to attendees invited to a single occurrence, has its
+// first VEVENT returned as the master.
+func splitEvents(cal *ical.Calendar) (master *ical.Component, overrides []*ical.Component) {
+	var events []*ical.Component
+	for _, child := range cal.Children {
+		if child.Name != ical.CompEvent {
+			continue
+		}
+		if master == nil && child.Props.Get(ical.PropRecurrenceID) == nil {
+			master = child
+			continue
+		}
+		events = append(events, child)
+	}
+	if master == nil && len(events) > 0 {
+		master, events = events[0], events[1:]
+	}
+	return master, events
+}
+
+// recurrenceID reads the RECURRENCE-ID of an override.
+func recurrenceID(comp *ical.Component) (time.Time, bool) {
+	prop := comp.Props.Get(ical.PropRecurrenceID)
+	if prop == nil {
+		return time.Time{}, false
+	}
+	t, _, err := parseTimeProp(prop)
+	return t, err == nil
+}
+
+// findOverride returns the override of the occurrence at rid, or nil.
+func findOverride(overrides []*ical.Component, rid time.Time) *ical.Component {
+	for _, comp := range overrides {
+		if t, ok := recurrenceID(comp); ok && t.Equal(rid) {
+			return comp
+		}
+	}
+	return nil
+}
+
+// removeOverrides removes the overrides in cal whose RECURRENCE-ID matches.
+func removeOverrides(cal *ical.Calendar, match func(rid time.Time) bool) {
+	children := cal.Children[:0]
+	for _, child := range cal.Children {
+		if child.Name == ical.CompEvent {
+			if rid, ok := recurrenceID(child); ok && match(rid) {
+				continue
+			}
+		}
+		children = append(children, child)
+	}
+	cal.Children = children
+}
+
+// checkOccurrence returns an error unless the series defined by master has
+// an occurrence starting at rid.
+func checkOccurrence(master *ical.Component, rid time.Time) error {
+	event := parseEvent(master)
+	if event.Recurrence == "" && len(event.RecurrenceDates) == 0 {
+		return fmt.Errorf("event is not recurring")
+	}
+	occurrences, err := ExpandRecurrence(*event, rid, rid)
+	if err != nil {
+		return err
+	}
+	if len(occurrences) == 0 {
+		return fmt.Errorf("event has no occurrence at %s", rid.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// newOverride creates an override for the occurrence of master at rid. It
+// copies the master's properties, except those that define the series, and
+// its alarms.
+func newOverride(master *ical.Component, form seriesForm, rid time.Time) (*ical.Component, error) {
+	comp := cloneComponent(master)
+	comp.Props.Del(ical.PropRecurrenceRule)
+	comp.Props.Del(ical.PropRecurrenceDates)
+	comp.Props.Del(ical.PropExceptionDates)
+	if err := moveTo(comp, form, rid); err != nil {
+		return nil, err
+	}
+	comp.Props.Set(form.prop(ical.PropRecurrenceID, rid))
+	return comp, nil
+}
+
+// moveTo moves comp to start at t, keeping its length.
+func moveTo(comp *ical.Component, form seriesForm, t time.Time) error {
+	if dtstart := comp.Props.Get(ical.PropDateTimeStart); dtstart != nil && comp.Props.Get(ical.PropDateTimeEnd) != nil {
+		start, _, err := parseTimeProp(dtstart)
+		if err != nil {
+			return fmt.Errorf("failed to read event start: %w", err)
+		}
+		end, err := eventEnd(&ical.Event{Component: comp}, start, form.allDay)
+		if err != nil {
+			return fmt.Errorf("failed to read event end: %w", err)
+		}
+		if form.allDay {
+			end = t.AddDate(0, 0, int(end.Sub(start).Hours()/24))
+		} else {
+			end = t.Add(end.Sub(start))
+		}
+		comp.Props.Set(form.prop(ical.PropDateTimeEnd, end))
+	}
+	comp.Props.Set(form.prop(ical.PropDateTimeStart, t))
+	return nil
+}
+
+// endSeriesBefore ends the series of master before rid: its RRULE is capped
+// with an UNTIL, and RDATEs, EXDATEs and overrides from rid on are dropped.
+func endSeriesBefore(cal *ical.Calendar, master *ical.Component, form seriesForm, start, rid time.Time) error {
+	if prop := master.Props.Get(ical.PropRecurrenceRule); prop != nil {
+		before, _, err := splitRule(prop.Value, form, start, rid)
+		if err != nil {
+			return err
+		}
+		setRecurrenceRule(master.Props, before)
+	}
+	if err := filterTimeList(master.Props, ical.PropRecurrenceDates, form, func(t time.Time) bool { return t.Before(rid) }); err != nil {
+		return err
+	}
+	if err := filterTimeList(master.Props, ical.PropExceptionDates, form, func(t time.Time) bool { return t.Before(rid) }); err != nil {
+		return err
+	}
+	removeOverrides(cal, func(t time.Time) bool { return !t.Before(rid) })
+	return nil
+}
+
+// filterTimeList keeps the values of an RDATE or EXDATE list for which keep
+// returns true. The list is only rewritten if a value is dropped.
+func filterTimeList(props ical.Props, name string, form seriesForm, keep func(time.Time) bool) error {
+	times, err := parseTimeList(props.Values(name))
+	if err != nil {
+		return fmt.Errorf("failed to read event %s: %w", name, err)
+	}
+	kept := make([]time.Time, 0, len(times))
+	for _, t := range times {
+		if keep(t) {
+			kept = append(kept, t)
+		}
+	}
+	if len(kept) != len(times) {
+		setTimeList(props, name, kept, form.allDay, form.floating)
+	}
+	return nil
+}
+
+// splitRule splits the RRULE of a series starting at start into the rule
+// for the occurrences before rid, which gets an UNTIL, and the rule for the
+// occurrences from rid on, whose COUNT is reduced by the occurrences before.
+// The rule after is empty if the RRULE has no occurrences from rid on.
+func splitRule(rule string, form seriesForm, start, rid time.Time) (before, after string, err error) {
+	rule = normalizeRecurrenceRule(rule)
+	opt, err := rrule.StrToROptionInLocation(rule, start.Location())
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse recurrence rule: %w", err)
+	}
+	opt.Dtstart = start
+	r, err := rrule.NewRRule(*opt)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create recurrence rule: %w", err)
+	}
+
+	var parts []string
+	for _, part := range strings.Split(rule, ";") {
+		if !strings.HasPrefix(part, "COUNT=") && !strings.HasPrefix(part, "UNTIL=") {
+			parts = append(parts, part)
+		}
+	}
+	after = rule
+	if opt.Count > 0 {
+		done := len(r.Between(start, rid.Add(-time.Second), true))
+		if done >= opt.Count {
+			return rule, "", nil
+		}
+		after = strings.Join(append(parts[:len(parts):len(parts)], "COUNT="+strconv.Itoa(opt.Count-done)), ";")
+	} else if !opt.Until.IsZero() && opt.Until.Before(rid) {
+		return rule, "", nil
+	}
+
+	var until string
+	switch {
+	case form.allDay:
+		until = rid.AddDate(0, 0, -1).Format(icalDateFormat)
+	case form.floating:
+		until = rid.Add(-time.Second).Format(icalFloatingFormat)
+	default:
+		until = rid.Add(-time.Second).UTC().Format(icalFloatingFormat) + "Z"
+	}
+	before = strings.Join(append(parts, "UNTIL="+until), ";")
+	return before, after, nil
+}
+
+// cloneCalendar returns a deep copy of cal.
+func cloneCalendar(cal *ical.Calendar) *ical.Calendar {
+	clone := ical.NewCalendar()
+	clone.Props = cloneProps(cal.Props)
+	for _, child := range cal.Children {
+		clone.Children = append(clone.Children, cloneComponent(child))
+	}
+	return clone
+}
+
+// cloneComponent returns a deep copy of comp.
+func cloneComponent(comp *ical.Component) *ical.Component {
+	clone := ical.NewComponent(comp.Name)
+	clone.Props = cloneProps(comp.Props)
+	for _, child := range comp.Children {
+		clone.Children = append(clone.Children, cloneComponent(child))
+	}
+	return clone
+}
+
+// cloneProps returns a deep copy of props.
+func cloneProps(props ical.Props) ical.Props {
+	clone := make(ical.Props, len(props))
+	for name, values := range props {
+		cloned := make([]ical.Prop, len(values))
+		for i, prop := range values {
+			params := make(ical.Params, len(prop.Params))
+			for k, v := range prop.Params {
+				params[k] = append([]string(nil), v...)
+			}
+			prop.Params = params
+			cloned[i] = prop
+		}
+		clone[name] = cloned
+	}
+	return clone
+}
@@ -0,0 +1,527 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+	extcaldav "github.com/emersion/go-webdav/caldav"
+)
+
+// makeSeriesObject returns a weekly series of five occurrences, on Mondays
+// from 2024-01-15 at 14:00 UTC, as GetCalendarObject would.
+func makeSeriesObject(etag string) *extcaldav.CalendarObject {
+	obj := makeExistingObject(etag)
+	setRecurrenceRule(obj.Data.Children[0].Props, "FREQ=WEEKLY;COUNT=5")
+	return obj
+}
+
+// eventComponents returns the VEVENTs of cal.
+func eventComponents(cal *ical.Calendar) []*ical.Component {
+	var events []*ical.Component
+	for _, child := range cal.Children {
+		if child.Name == ical.CompEvent {
+			events = append(events, child)
+		}
+	}
+	return events
+}
+
+func TestValidateScope(t *testing.T) {
+	for _, scope := range []string{"", ScopeAll, ScopeThis, ScopeThisAndFollowing} {
+		if err := ValidateScope(scope); err != nil {
+			t.Errorf("ValidateScope(%q) = %v", scope, err)
+		}
+	}
+	if err := ValidateScope("future"); err == nil {
+		t.Error("expected error for unknown scope")
+	}
+}
+
+func TestUpdateEvent_ThisOccurrenceWritesOverride(t *testing.T) {
+	mb := &mockBackend{getResult: makeSeriesObject("etag-1"), putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	rid := time.Date(2024, 1, 22, 14, 0, 0, 0, time.UTC)
+	title := "Moved"
+	newStart := time.Date(2024, 1, 23, 10, 0, 0, 0, time.UTC)
+	update := &EventUpdate{Title: &title, StartTime: &newStart, RecurrenceID: &rid, Scope: ScopeThis}
+	if _, err := c.UpdateEvent(context.Background(), "/cal/event.ics", update); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events := eventComponents(mb.lastPutCal)
+	if len(events) != 2 {
+		t.Fatalf("expected master and override, got %d VEVENTs", len(events))
+	}
+	master, override := events[0], events[1]
+	if got := master.Props.Get(ical.PropSummary).Value; got != "Title" {
+		t.Errorf("master SUMMARY = %q, want it unchanged", got)
+	}
+	if got := master.Props.Get(ical.PropRecurrenceRule).Value; got != "FREQ=WEEKLY;COUNT=5" {
+		t.Errorf("master RRULE = %q, want it unchanged", got)
+	}
+	if got := override.Props.Get(ical.PropRecurrenceID).Value; got != "20240122T140000Z" {
+		t.Errorf("RECURRENCE-ID = %q", got)
+	}
+	if got := override.Props.Get(ical.PropUID).Value; got != "uid-1" {
+		t.Errorf("override UID = %q, want the series UID", got)
+	}
+	if got := override.Props.Get(ical.PropSummary).Value; got != "Moved" {
+		t.Errorf("override SUMMARY = %q", got)
+	}
+	if got := override.Props.Get(ical.PropDateTimeStart).Value; got != "20240123T100000Z" {
+		t.Errorf("override DTSTART = %q", got)
+	}
+	if got := override.Props.Get(ical.PropDateTimeEnd).Value; got != "20240122T150000Z" {
+		t.Errorf("override DTEND = %q, want the occurrence's end", got)
+	}
+	if override.Props.Get(ical.PropRecurrenceRule) != nil {
+		t.Error("override must not carry an RRULE")
+	}
+	if mb.lastPutCond.ifMatch != "etag-1" {
+		t.Errorf("If-Match = %q, want etag-1", mb.lastPutCond.ifMatch)
+	}
+}
+
+func TestUpdateEvent_ThisOccurrenceEditsExistingOverride(t *testing.T) {
+	obj := makeSeriesObject("etag-1")
+	rid := time.Date(2024, 1, 22, 14, 0, 0, 0, time.UTC)
+	override, err := newOverride(obj.Data.Children[0], seriesForm{loc: time.UTC}, rid)
+	if err != nil {
+		t.Fatalf("newOverride: %v", err)
+	}
+	override.Props.SetText(ical.PropLocation, "Room 1")
+	obj.Data.Children = append(obj.Data.Children, override)
+
+	mb := &mockBackend{getResult: obj, putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	title := "Edited"
+	update := &EventUpdate{Title: &title, RecurrenceID: &rid, Scope: ScopeThis}
+	if _, err := c.UpdateEvent(context.Background(), "/cal/event.ics", update); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events := eventComponents(mb.lastPutCal)
+	if len(events) != 2 {
+		t.Fatalf("expected the override to be edited in place, got %d VEVENTs", len(events))
+	}
+	if got := events[1].Props.Get(ical.PropSummary).Value; got != "Edited" {
+		t.Errorf("override SUMMARY = %q", got)
+	}
+	if got := events[1].Props.Get(ical.PropLocation).Value; got != "Room 1" {
+		t.Errorf("override LOCATION = %q, want it kept", got)
+	}
+}
+
+func TestUpdateEvent_ThisOccurrenceValidation(t *testing.T) {
+	rid := time.Date(2024, 1, 22, 14, 0, 0, 0, time.UTC)
+	notAnOccurrence := time.Date(2024, 1, 23, 14, 0, 0, 0, time.UTC)
+	rule := "FREQ=DAILY"
+	title := "New"
+
+	tests := []struct {
+		name   string
+		obj    *extcaldav.CalendarObject
+		update *EventUpdate
+	}{
+		{"missing recurrence ID", makeSeriesObject(""), &EventUpdate{Title: &title, Scope: ScopeThis}},
+		{"unknown scope", makeSeriesObject(""), &EventUpdate{Title: &title, Scope: "some"}},
+		{"rule for one occurrence", makeSeriesObject(""), &EventUpdate{Recurrence: &rule, RecurrenceID: &rid, Scope: ScopeThis}},
+		{"not an occurrence", makeSeriesObject(""), &EventUpdate{Title: &title, RecurrenceID: &notAnOccurrence, Scope: ScopeThis}},
+		{"not recurring", makeExistingObject(""), &EventUpdate{Title: &title, RecurrenceID: &rid, Scope: ScopeThis}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mb := &mockBackend{getResult: tt.obj, putResult: &extcaldav.CalendarObject{}}
+			c := NewClientWithBackend(mb)
+			if _, err := c.UpdateEvent(context.Background(), "/cal/event.ics", tt.update); err == nil {
+				t.Fatal("expected error")
+			}
+			if mb.lastPutCal != nil {
+				t.Error("nothing should be written")
+			}
+		})
+	}
+}
+
+func TestUpdateEvent_AllDayOccurrence(t *testing.T) {
+	obj := makeExistingObject("")
+	vevent := obj.Data.Children[0]
+	setTimeProp(vevent.Props, ical.PropDateTimeStart, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), true, false)
+	setTimeProp(vevent.Props, ical.PropDateTimeEnd, time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC), true, false)
+	setRecurrenceRule(vevent.Props, "FREQ=DAILY;COUNT=10")
+
+	mb := &mockBackend{getResult: obj, putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	// A timestamp selects the occurrence on its date
+	rid := time.Date(2024, 1, 17, 9, 30, 0, 0, time.UTC)
+	title := "Offsite"
+	if _, err := c.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Title: &title, RecurrenceID: &rid, Scope: ScopeThis}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	override := eventComponents(mb.lastPutCal)[1]
+	prop := override.Props.Get(ical.PropRecurrenceID)
+	if prop.Value != "20240117" || prop.ValueType() != ical.ValueDate {
+		t.Errorf("RECURRENCE-ID = %q (%s), want DATE 20240117", prop.Value, prop.ValueType())
+	}
+	if got := override.Props.Get(ical.PropDateTimeEnd).Value; got != "20240118" {
+		t.Errorf("override DTEND = %q, want 20240118", got)
+	}
+}
+
+func TestUpdateEvent_ThisAndFollowingSplitsSeries(t *testing.T) {
+	obj := makeSeriesObject("etag-1")
+	// An override of a later occurrence moves to the new series
+	later, err := newOverride(obj.Data.Children[0], seriesForm{loc: time.UTC}, time.Date(2024, 2, 5, 14, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("newOverride: %v", err)
+	}
+	obj.Data.Children = append(obj.Data.Children, later)
+
+	mb := &mockBackend{getResult: obj, putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	rid := time.Date(2024, 1, 29, 14, 0, 0, 0, time.UTC)
+	title := "New format"
+	update := &EventUpdate{Title: &title, RecurrenceID: &rid, Scope: ScopeThisAndFollowing, NewSeriesID: "uid-2"}
+	newSeriesID, err := c.UpdateEvent(context.Background(), "/cal/event.ics", update)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if newSeriesID != "uid-2" {
+		t.Errorf("new series UID = %q, want uid-2", newSeriesID)
+	}
+	if len(mb.putPaths) != 2 || mb.putPaths[0] != "/cal/uid-2.ics" || mb.putPaths[1] != "/cal/event.ics" {
+		t.Fatalf("puts = %v, want the new series first, then the original", mb.putPaths)
+	}
+
+	// The original ends before the split and loses the later override
+	original := eventComponents(mb.putCals["/cal/event.ics"])
+	if len(original) != 1 {
+		t.Fatalf("original has %d VEVENTs, want 1", len(original))
+	}
+	if got := original[0].Props.Get(ical.PropRecurrenceRule).Value; got != "FREQ=WEEKLY;UNTIL=20240129T135959Z" {
+		t.Errorf("original RRULE = %q", got)
+	}
+	if got := original[0].Props.Get(ical.PropSummary).Value; got != "Title" {
+		t.Errorf("original SUMMARY = %q, want it unchanged", got)
+	}
+	if mb.lastPutCond.ifMatch != "etag-1" {
+		t.Errorf("original If-Match = %q, want etag-1", mb.lastPutCond.ifMatch)
+	}
+
+	// The new series starts at the split with the remaining count
+	following := eventComponents(mb.putCals["/cal/uid-2.ics"])
+	if len(following) != 2 {
+		t.Fatalf("new series has %d VEVENTs, want master and override", len(following))
+	}
+	master := following[0]
+	if got := master.Props.Get(ical.PropUID).Value; got != "uid-2" {
+		t.Errorf("new UID = %q", got)
+	}
+	if got := master.Props.Get(ical.PropDateTimeStart).Value; got != "20240129T140000Z" {
+		t.Errorf("new DTSTART = %q", got)
+	}
+	if got := master.Props.Get(ical.PropDateTimeEnd).Value; got != "20240129T150000Z" {
+		t.Errorf("new DTEND = %q", got)
+	}
+	if got := master.Props.Get(ical.PropRecurrenceRule).Value; got != "FREQ=WEEKLY;COUNT=3" {
+		t.Errorf("new RRULE = %q", got)
+	}
+	if got := master.Props.Get(ical.PropSummary).Value; got != "New format" {
+		t.Errorf("new SUMMARY = %q", got)
+	}
+	if got := following[1].Props.Get(ical.PropUID).Value; got != "uid-2" {
+		t.Errorf("moved override UID = %q, want uid-2", got)
+	}
+}
+
+func TestUpdateEvent_ThisAndFollowingFromFirstOccurrence(t *testing.T) {
+	mb := &mockBackend{getResult: makeSeriesObject(""), putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	rid := time.Date(2024, 1, 15, 14, 0, 0, 0, time.UTC)
+	title := "Renamed"
+	update := &EventUpdate{Title: &title, RecurrenceID: &rid, Scope: ScopeThisAndFollowing}
+	newSeriesID, err := c.UpdateEvent(context.Background(), "/cal/event.ics", update)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if newSeriesID != "" {
+		t.Errorf("new series UID = %q, want none for a whole-series update", newSeriesID)
+	}
+	if len(mb.putPaths) != 1 || mb.putPaths[0] != "/cal/event.ics" {
+		t.Fatalf("puts = %v, want the event updated in place", mb.putPaths)
+	}
+	if got := putEvent(t, mb).Props.Get(ical.PropSummary).Value; got != "Renamed" {
+		t.Errorf("SUMMARY = %q", got)
+	}
+}
+
+func TestUpdateEvent_ThisAndFollowingRollsBackOnConflict(t *testing.T) {
+	mb := &mockBackend{
+		getResult:    makeSeriesObject("etag-1"),
+		putResult:    &extcaldav.CalendarObject{},
+		putErrByPath: map[string]error{"/cal/event.ics": &statusError{code: 412}},
+	}
+	c := NewClientWithBackend(mb)
+
+	rid := time.Date(2024, 1, 29, 14, 0, 0, 0, time.UTC)
+	title := "New format"
+	update := &EventUpdate{Title: &title, RecurrenceID: &rid, Scope: ScopeThisAndFollowing, NewSeriesID: "uid-2"}
+	_, err := c.UpdateEvent(context.Background(), "/cal/event.ics", update)
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected *ConflictError, got %v", err)
+	}
+	if mb.lastRemovePath != "/cal/uid-2.ics" {
+		t.Errorf("removed %q, want the new series removed again", mb.lastRemovePath)
+	}
+}
+
+func TestDeleteOccurrence_This(t *testing.T) {
+	obj := makeSeriesObject("etag-1")
+	rid := time.Date(2024, 1, 22, 14, 0, 0, 0, time.UTC)
+	override, err := newOverride(obj.Data.Children[0], seriesForm{loc: time.UTC}, rid)
+	if err != nil {
+		t.Fatalf("newOverride: %v", err)
+	}
+	obj.Data.Children = append(obj.Data.Children, override)
+
+	mb := &mockBackend{getResult: obj, putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	if err := c.DeleteOccurrence(context.Background(), "/cal/event.ics", rid, ScopeThis, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	events := eventComponents(mb.lastPutCal)
+	if len(events) != 1 {
+		t.Fatalf("expected the override to be removed, got %d VEVENTs", len(events))
+	}
+	exdates := events[0].Props.Values(ical.PropExceptionDates)
+	if len(exdates) != 1 || exdates[0].Value != "20240122T140000Z" {
+		t.Errorf("EXDATE = %+v", exdates)
+	}
+	if mb.lastPutCond.ifMatch != "etag-1" {
+		t.Errorf("If-Match = %q, want etag-1", mb.lastPutCond.ifMatch)
+	}
+}
+
+func TestDeleteOccurrence_ThisAndFollowing(t *testing.T) {
+	mb := &mockBackend{getResult: makeSeriesObject(""), putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+
+	rid := time.Date(2024, 2, 5, 14, 0, 0, 0, time.UTC)
+	if err := c.DeleteOccurrence(context.Background(), "/cal/event.ics", rid, ScopeThisAndFollowing, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := putEvent(t, mb).Props.Get(ical.PropRecurrenceRule).Value; got != "FREQ=WEEKLY;UNTIL=20240205T135959Z" {
+		t.Errorf("RRULE = %q", got)
+	}
+}
+
+func TestDeleteOccurrence_FromFirstDeletesSeries(t *testing.T) {
+	mb := &mockBackend{getResult: makeSeriesObject("etag-1")}
+	c := NewClientWithBackend(mb)
+
+	rid := time.Date(2024, 1, 15, 14, 0, 0, 0, time.UTC)
+	if err := c.DeleteOccurrence(context.Background(), "/cal/event.ics", rid, ScopeThisAndFollowing, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mb.lastRemovePath != "/cal/event.ics" || mb.lastRemoveCond.ifMatch != "etag-1" {
+		t.Errorf("remove = %q if-match %q, want the whole event removed conditionally", mb.lastRemovePath, mb.lastRemoveCond.ifMatch)
+	}
+	if mb.lastPutCal != nil {
+		t.Error("nothing should be written")
+	}
+}
+
+func TestDeleteOccurrence_StaleETag(t *testing.T) {
+	mb := &mockBackend{getResult: makeSeriesObject("etag-2")}
+	c := NewClientWithBackend(mb)
+
+	rid := time.Date(2024, 1, 22, 14, 0, 0, 0, time.UTC)
+	err := c.DeleteOccurrence(context.Background(), "/cal/event.ics", rid, ScopeThis, "etag-1")
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected *ConflictError, got %v", err)
+	}
+}
+
+func TestSplitRule(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rid := time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		rule          string
+		form          seriesForm
+		before, after string
+	}{
+		{"all-day until is a date", "FREQ=DAILY", seriesForm{allDay: true, loc: time.UTC}, "FREQ=DAILY;UNTIL=20240103", "FREQ=DAILY"},
+		{"floating until is local", "FREQ=DAILY", seriesForm{floating: true, loc: time.UTC}, "FREQ=DAILY;UNTIL=20240103T235959", "FREQ=DAILY"},
+		{"count is split", "FREQ=DAILY;COUNT=10", seriesForm{loc: time.UTC}, "FREQ=DAILY;UNTIL=20240103T235959Z", "FREQ=DAILY;COUNT=7"},
+		{"count already used up", "FREQ=DAILY;COUNT=2", seriesForm{loc: time.UTC}, "FREQ=DAILY;COUNT=2", ""},
+		{"until already earlier", "FREQ=DAILY;UNTIL=20240102T000000Z", seriesForm{loc: time.UTC}, "FREQ=DAILY;UNTIL=20240102T000000Z", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, after, err := splitRule(tt.rule, tt.form, start, rid)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if before != tt.before || after != tt.after {
+				t.Errorf("splitRule = %q, %q; want %q, %q", before, after, tt.before, tt.after)
+			}
+		})
+	}
+}
+
+func TestParseCalendarObject_Overrides(t *testing.T) {
+	obj := makeSeriesObject("etag-1")
+	rid := time.Date(2024, 1, 22, 14, 0, 0, 0, time.UTC)
+	override, err := newOverride(obj.Data.Children[0], seriesForm{loc: time.UTC}, rid)
+	if err != nil {
+		t.Fatalf("newOverride: %v", err)
+	}
+	override.Props.SetText(ical.PropStatus, "CANCELLED")
+	// Overrides may come before the master
+	obj.Data.Children = append([]*ical.Component{override}, obj.Data.Children...)
+
+	event, err := parseCalendarObject(obj)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.RecurrenceID != nil || !strings.HasPrefix(event.Recurrence, "FREQ=WEEKLY") {
+		t.Errorf("expected the master to be parsed, got %+v", event)
+	}
+	if len(event.Overrides) != 1 {
+		t.Fatalf("expected 1 override, got %d", len(event.Overrides))
+	}
+	o := event.Overrides[0]
+	if o.RecurrenceID == nil || !o.RecurrenceID.Equal(rid) {
+		t.Errorf("override RecurrenceID = %v, want %v", o.RecurrenceID, rid)
+	}
+	if o.Status != "CANCELLED" || o.ETag != "etag-1" || o.Path != obj.Path {
+		t.Errorf("override = %+v", o)
+	}
+}
+
+func TestOccurrenceID(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+	tests := []struct {
+		name             string
+		rid              time.Time
+		allDay, floating bool
+		want             string
+	}{
+		{"zoned in UTC", time.Date(2025, 3, 10, 9, 0, 0, 0, ny), false, false, "uid-1#20250310T130000Z"},
+		{"all-day", time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), true, false, "uid-1#20250310"},
+		{"floating", time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC), false, true, "uid-1#20250310T090000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := OccurrenceID("uid-1", tt.rid, tt.allDay, tt.floating)
+			if id != tt.want {
+				t.Fatalf("OccurrenceID = %q, want %q", id, tt.want)
+			}
+			uid, rid, ok := ParseOccurrenceID(id)
+			if !ok || uid != "uid-1" {
+				t.Fatalf("ParseOccurrenceID(%q) = %q, %v", id, uid, ok)
+			}
+			if !rid.Equal(tt.rid) {
+				t.Errorf("rid = %v, want %v", rid, tt.rid)
+			}
+		})
+	}
+
+	for _, id := range []string{"uid-1", "uid#1", "#20250310"} {
+		if _, _, ok := ParseOccurrenceID(id); ok {
+			t.Errorf("ParseOccurrenceID(%q) should not be an occurrence", id)
+		}
+	}
+}
+
+func TestSearchEvents_ExpandRequestsServerExpansion(t *testing.T) {
+	mb := &mockBackend{}
+	c := NewClientWithBackend(mb)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := c.SearchEvents(context.Background(), "/cal", &start, &end, SearchOptions{Expand: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expand := mb.lastQuery.CompRequest.Expand
+	if expand == nil || !expand.Start.Equal(start) || !expand.End.Equal(end) {
+		t.Errorf("expand = %+v, want the search range", expand)
+	}
+
+	if _, err := c.SearchEvents(context.Background(), "/cal", &start, nil, SearchOptions{Expand: true}); err == nil {
+		t.Error("expected error when expanding without an end time")
+	}
+}
+
+func TestSearchEvents_ExpandedByServer(t *testing.T) {
+	// A server honouring <C:expand> returns one VEVENT per occurrence
+	obj := makeCalendarObject("/cal/series.ics", "uid-1", "Standup",
+		time.Date(2024, 1, 15, 14, 0, 0, 0, time.UTC), time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC))
+	for i, day := range []int{15, 22, 29} {
+		var vevent *ical.Component
+		if i == 0 {
+			vevent = obj.Data.Children[0]
+		} else {
+			vevent = cloneComponent(obj.Data.Children[0])
+			obj.Data.Children = append(obj.Data.Children, vevent)
+		}
+		rid := time.Date(2024, 1, day, 14, 0, 0, 0, time.UTC)
+		if err := moveTo(vevent, seriesForm{loc: time.UTC}, rid); err != nil {
+			t.Fatal(err)
+		}
+		vevent.Props.SetDateTime(ical.PropRecurrenceID, rid)
+		if day == 22 {
+			vevent.Props.SetText(ical.PropStatus, "CANCELLED")
+		}
+	}
+	mb := &mockBackend{queryResult: []extcaldav.CalendarObject{obj}}
+	c := NewClientWithBackend(mb)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	events, err := c.SearchEvents(context.Background(), "/cal", &start, &end, SearchOptions{Expand: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 occurrences (one cancelled), got %d", len(events))
+	}
+	if events[1].OccurrenceID != "uid-1#20240129T140000Z" || len(events[0].Overrides) != 0 {
+		t.Errorf("unexpected occurrence: %+v", events[1])
+	}
+}
+
+func TestSearchEvents_ExpandsLocallyWhenServerDoesNot(t *testing.T) {
+	obj := makeSeriesObject("etag-1")
+	mb := &mockBackend{queryResult: []extcaldav.CalendarObject{*obj}}
+	c := NewClientWithBackend(mb)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	events, err := c.SearchEvents(context.Background(), "/cal", &start, &end, SearchOptions{Expand: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 occurrences in January, got %d", len(events))
+	}
+	for _, e := range events {
+		if e.RecurrenceID == nil || e.OccurrenceID != OccurrenceID("uid-1", *e.RecurrenceID, false, false) || e.ETag != "etag-1" {
+			t.Errorf("occurrence = %+v", e)
+		}
+	}
+}
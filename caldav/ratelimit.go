@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/emersion/go-ical"
 	"golang.org/x/time/rate"
 )
 
@@ -45,11 +46,46 @@ func (r *RateLimitedClient) ListCalendars(ctx context.Context) ([]Calendar, erro
 	return r.inner.ListCalendars(ctx)
 }
 
-func (r *RateLimitedClient) SearchEvents(ctx context.Context, calendarPath string, startTime, endTime *time.Time) ([]Event, error) {
+func (r *RateLimitedClient) CreateCalendar(ctx context.Context, calendar *Calendar) (string, error) {
+	if err := r.wait(ctx); err != nil {
+		return "", err
+	}
+	return r.inner.CreateCalendar(ctx, calendar)
+}
+
+func (r *RateLimitedClient) UpdateCalendar(ctx context.Context, calendarPath string, update *CalendarUpdate) error {
+	if err := r.wait(ctx); err != nil {
+		return err
+	}
+	return r.inner.UpdateCalendar(ctx, calendarPath, update)
+}
+
+func (r *RateLimitedClient) DeleteCalendar(ctx context.Context, calendarPath string) error {
+	if err := r.wait(ctx); err != nil {
+		return err
+	}
+	return r.inner.DeleteCalendar(ctx, calendarPath)
+}
+
+func (r *RateLimitedClient) SearchEvents(ctx context.Context, calendarPath string, startTime, endTime *time.Time, opts ...SearchOptions) ([]Event, error) {
 	if err := r.wait(ctx); err != nil {
 		return nil, err
 	}
-	return r.inner.SearchEvents(ctx, calendarPath, startTime, endTime)
+	return r.inner.SearchEvents(ctx, calendarPath, startTime, endTime, opts...)
+}
+
+func (r *RateLimitedClient) SyncCalendar(ctx context.Context, calendarPath, token string) (*SyncResult, error) {
+	if err := r.wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.inner.SyncCalendar(ctx, calendarPath, token)
+}
+
+func (r *RateLimitedClient) GetEvent(ctx context.Context, eventPath string) (*Event, *EventObject, error) {
+	if err := r.wait(ctx); err != nil {
+		return nil, nil, err
+	}
+	return r.inner.GetEvent(ctx, eventPath)
 }
 
 func (r *RateLimitedClient) CreateEvent(ctx context.Context, calendarPath string, event *Event) (string, error) {
@@ -59,20 +95,111 @@ func (r *RateLimitedClient) CreateEvent(ctx context.Context, calendarPath string
 	return r.inner.CreateEvent(ctx, calendarPath, event)
 }
 
-func (r *RateLimitedClient) UpdateEvent(ctx context.Context, eventPath string, update *EventUpdate) error {
+func (r *RateLimitedClient) UpdateEvent(ctx context.Context, eventPath string, update *EventUpdate) (string, error) {
 	if err := r.wait(ctx); err != nil {
-		return err
+		return "", err
 	}
 	return r.inner.UpdateEvent(ctx, eventPath, update)
 }
 
-func (r *RateLimitedClient) DeleteEvent(ctx context.Context, eventPath string) error {
+func (r *RateLimitedClient) DeleteEvent(ctx context.Context, eventPath, etag string) error {
+	if err := r.wait(ctx); err != nil {
+		return err
+	}
+	return r.inner.DeleteEvent(ctx, eventPath, etag)
+}
+
+func (r *RateLimitedClient) DeleteOccurrence(ctx context.Context, eventPath string, recurrenceID time.Time, scope, etag string) error {
 	if err := r.wait(ctx); err != nil {
 		return err
 	}
-	return r.inner.DeleteEvent(ctx, eventPath)
+	return r.inner.DeleteOccurrence(ctx, eventPath, recurrenceID, scope, etag)
 }
 
 func (r *RateLimitedClient) GetEventPath(calendarPath, eventID string) string {
 	return r.inner.GetEventPath(calendarPath, eventID)
 }
+
+func (r *RateLimitedClient) GetEventObject(ctx context.Context, eventPath string) (*EventObject, error) {
+	if err := r.wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.inner.GetEventObject(ctx, eventPath)
+}
+
+func (r *RateLimitedClient) PutEventObject(ctx context.Context, eventPath string, data *ical.Calendar) error {
+	if err := r.wait(ctx); err != nil {
+		return err
+	}
+	return r.inner.PutEventObject(ctx, eventPath, data)
+}
+
+func (r *RateLimitedClient) DeleteEventObject(ctx context.Context, eventPath, etag string) error {
+	if err := r.wait(ctx); err != nil {
+		return err
+	}
+	return r.inner.DeleteEventObject(ctx, eventPath, etag)
+}
+
+func (r *RateLimitedClient) MoveEvent(ctx context.Context, eventPath, calendarPath, etag string) (string, error) {
+	if err := r.wait(ctx); err != nil {
+		return "", err
+	}
+	return r.inner.MoveEvent(ctx, eventPath, calendarPath, etag)
+}
+
+func (r *RateLimitedClient) CopyEvent(ctx context.Context, eventPath, calendarPath string) (string, error) {
+	if err := r.wait(ctx); err != nil {
+		return "", err
+	}
+	return r.inner.CopyEvent(ctx, eventPath, calendarPath)
+}
+
+func (r *RateLimitedClient) SearchTasks(ctx context.Context, calendarPath string, includeCompleted bool) ([]Task, error) {
+	if err := r.wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.inner.SearchTasks(ctx, calendarPath, includeCompleted)
+}
+
+func (r *RateLimitedClient) CreateTask(ctx context.Context, calendarPath string, task *Task) (string, error) {
+	if err := r.wait(ctx); err != nil {
+		return "", err
+	}
+	return r.inner.CreateTask(ctx, calendarPath, task)
+}
+
+func (r *RateLimitedClient) UpdateTask(ctx context.Context, taskPath string, update *TaskUpdate) error {
+	if err := r.wait(ctx); err != nil {
+		return err
+	}
+	return r.inner.UpdateTask(ctx, taskPath, update)
+}
+
+func (r *RateLimitedClient) CompleteTask(ctx context.Context, taskPath, etag string) error {
+	if err := r.wait(ctx); err != nil {
+		return err
+	}
+	return r.inner.CompleteTask(ctx, taskPath, etag)
+}
+
+func (r *RateLimitedClient) FreeBusy(ctx context.Context, calendarPath string, start, end time.Time) ([]BusyPeriod, error) {
+	if err := r.wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.inner.FreeBusy(ctx, calendarPath, start, end)
+}
+
+func (r *RateLimitedClient) ListInvitations(ctx context.Context, start, end time.Time) ([]Invitation, error) {
+	if err := r.wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.inner.ListInvitations(ctx, start, end)
+}
+
+func (r *RateLimitedClient) RespondToInvitation(ctx context.Context, path string, response InvitationResponse) error {
+	if err := r.wait(ctx); err != nil {
+		return err
+	}
+	return r.inner.RespondToInvitation(ctx, path, response)
+}
@@ -150,7 +150,7 @@ func TestRateLimitedClient_UpdateEvent_Success(t *testing.T) {
 	rl := NewRateLimitedClient(mock, 100, 10)
 
 	title := "Updated"
-	err := rl.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Title: &title})
+	_, err := rl.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Title: &title})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -164,7 +164,7 @@ func TestRateLimitedClient_UpdateEvent_CancelledContext(t *testing.T) {
 	defer cancel()
 
 	title := "Updated"
-	err := rl.UpdateEvent(ctx, "/cal/event.ics", &EventUpdate{Title: &title})
+	_, err := rl.UpdateEvent(ctx, "/cal/event.ics", &EventUpdate{Title: &title})
 	if err == nil {
 		t.Fatal("expected error from cancelled context")
 	}
@@ -175,7 +175,7 @@ func TestRateLimitedClient_UpdateEvent_InnerError(t *testing.T) {
 	rl := NewRateLimitedClient(mock, 100, 10)
 
 	title := "Updated"
-	err := rl.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Title: &title})
+	_, err := rl.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Title: &title})
 	if err == nil {
 		t.Fatal("expected error from inner client")
 	}
@@ -185,7 +185,7 @@ func TestRateLimitedClient_DeleteEvent_Success(t *testing.T) {
 	mock := &MockClient{}
 	rl := NewRateLimitedClient(mock, 100, 10)
 
-	err := rl.DeleteEvent(context.Background(), "/cal/event.ics")
+	err := rl.DeleteEvent(context.Background(), "/cal/event.ics", "")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -198,7 +198,7 @@ func TestRateLimitedClient_DeleteEvent_CancelledContext(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
 	defer cancel()
 
-	err := rl.DeleteEvent(ctx, "/cal/event.ics")
+	err := rl.DeleteEvent(ctx, "/cal/event.ics", "")
 	if err == nil {
 		t.Fatal("expected error from cancelled context")
 	}
@@ -208,12 +208,34 @@ func TestRateLimitedClient_DeleteEvent_InnerError(t *testing.T) {
 	mock := &MockClient{DeleteEventErr: fmt.Errorf("delete failed")}
 	rl := NewRateLimitedClient(mock, 100, 10)
 
-	err := rl.DeleteEvent(context.Background(), "/cal/event.ics")
+	err := rl.DeleteEvent(context.Background(), "/cal/event.ics", "")
 	if err == nil {
 		t.Fatal("expected error from inner client")
 	}
 }
 
+func TestRateLimitedClient_CalendarLifecycle_CancelledContext(t *testing.T) {
+	mock := &MockClient{}
+	rl := NewRateLimitedClient(mock, 0.001, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := rl.CreateCalendar(ctx, &Calendar{Name: "Project X"}); err == nil {
+		t.Error("CreateCalendar: expected error from cancelled context")
+	}
+	name := "Renamed"
+	if err := rl.UpdateCalendar(ctx, "/cal/work/", &CalendarUpdate{Name: &name}); err == nil {
+		t.Error("UpdateCalendar: expected error from cancelled context")
+	}
+	if err := rl.DeleteCalendar(ctx, "/cal/work/"); err == nil {
+		t.Error("DeleteCalendar: expected error from cancelled context")
+	}
+	if mock.CreateCalendarCount != 0 || mock.LastCalendarPath != "" {
+		t.Error("inner client called despite cancelled context")
+	}
+}
+
 func TestRateLimitedClient_GetEventPath(t *testing.T) {
 	mock := &MockClient{}
 	rl := NewRateLimitedClient(mock, 100, 10)
@@ -2,52 +2,145 @@ package caldav
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/emersion/go-ical"
 	"github.com/teambition/rrule-go"
 )
 
-// ExpandRecurrence expands a recurrence rule into individual event occurrences
-// within the given time range.
-func ExpandRecurrence(event Event, rangeStart, rangeEnd time.Time) ([]Event, error) {
-	if event.Recurrence == "" {
-		return []Event{event}, nil
+// ValidateRecurrenceRule checks that rule is a valid RFC 5545 RRULE value such
+// as "FREQ=WEEKLY;BYDAY=MO;COUNT=10". An "RRULE:" prefix is accepted.
+func ValidateRecurrenceRule(rule string) error {
+	rule = normalizeRecurrenceRule(rule)
+	if rule == "" {
+		return fmt.Errorf("recurrence rule cannot be empty")
+	}
+	if strings.ContainsAny(rule, "\r\n") || strings.Contains(rule, "DTSTART") {
+		return fmt.Errorf("recurrence rule must be a single RRULE value without DTSTART")
+	}
+	opt, err := rrule.StrToROption(rule)
+	if err != nil {
+		return fmt.Errorf("invalid recurrence rule: %w", err)
 	}
+	if opt.Count > 0 && !opt.Until.IsZero() {
+		return fmt.Errorf("invalid recurrence rule: COUNT and UNTIL cannot both be set")
+	}
+	if _, err := rrule.NewRRule(*opt); err != nil {
+		return fmt.Errorf("invalid recurrence rule: %w", err)
+	}
+	return nil
+}
 
-	ruleStr := fmt.Sprintf("DTSTART:%s\nRRULE:%s",
-		event.StartTime.UTC().Format("20060102T150405Z"),
-		event.Recurrence,
-	)
+// normalizeRecurrenceRule strips whitespace and an "RRULE:" prefix.
+func normalizeRecurrenceRule(rule string) string {
+	rule = strings.ToUpper(strings.TrimSpace(rule))
+	return strings.TrimPrefix(rule, "RRULE:")
+}
 
-	rOption, err := rrule.StrToROption(ruleStr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse recurrence rule: %w", err)
+// setRecurrenceRule writes rule as the RRULE of a component.
+func setRecurrenceRule(props ical.Props, rule string) {
+	props.Set(&ical.Prop{Name: ical.PropRecurrenceRule, Value: normalizeRecurrenceRule(rule), Params: ical.Params{}})
+}
+
+// recurrenceSet returns the start times of an event's RRULE and RDATEs,
+// minus its EXDATEs. Occurrences are computed in the event's own zone so
+// that a 9am meeting stays at 9am across DST changes. All-day and floating
+// events happen at the same wall-clock time in every zone, so they are
+// computed in loc; this keeps an all-day occurrence on its date however a
+// range is expressed.
+func recurrenceSet(event Event, loc *time.Location) (*rrule.Set, error) {
+	local := func(t time.Time) time.Time { return t }
+	if event.AllDay || event.Floating {
+		local = func(t time.Time) time.Time { return wallClock(t, loc) }
 	}
+	dtstart := local(event.StartTime)
+
+	set := &rrule.Set{}
+	if event.Recurrence != "" {
+		rOption, err := rrule.StrToROptionInLocation(normalizeRecurrenceRule(event.Recurrence), dtstart.Location())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse recurrence rule: %w", err)
+		}
+		rOption.Dtstart = dtstart
 
-	rule, err := rrule.NewRRule(*rOption)
+		rule, err := rrule.NewRRule(*rOption)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create recurrence rule: %w", err)
+		}
+		set.RRule(rule)
+	} else {
+		// Without an RRULE, DTSTART is the first of the RDATE instances.
+		set.RDate(dtstart)
+	}
+	for _, t := range event.RecurrenceDates {
+		set.RDate(local(t))
+	}
+	for _, t := range event.ExceptionDates {
+		set.ExDate(local(t))
+	}
+	return set, nil
+}
+
+// ExpandRecurrence expands an event's RRULE and RDATEs, minus its EXDATEs,
+// into individual occurrences within the given time range. Occurrences with
+// an override take its place, wherever it moved them, and cancelled ones are
+// left out. Each occurrence has its RecurrenceID and OccurrenceID set.
+// Events without an RRULE or RDATEs are returned unchanged.
+func ExpandRecurrence(event Event, rangeStart, rangeEnd time.Time) ([]Event, error) {
+	if event.Recurrence == "" && len(event.RecurrenceDates) == 0 {
+		return []Event{event}, nil
+	}
+
+	set, err := recurrenceSet(event, rangeStart.Location())
 	if err != nil {
-		return nil, fmt.Errorf("failed to create recurrence rule: %w", err)
+		return nil, err
 	}
 
-	occurrences := rule.Between(rangeStart, rangeEnd, true)
+	occurrences := set.Between(rangeStart, rangeEnd, true)
 	duration := event.EndTime.Sub(event.StartTime)
 
+	overridden := make(map[int64]bool, len(event.Overrides))
+	for _, o := range event.Overrides {
+		if o.RecurrenceID != nil {
+			overridden[o.RecurrenceID.Unix()] = true
+		}
+	}
+
 	events := make([]Event, 0, len(occurrences))
 	for _, occ := range occurrences {
-		e := Event{
-			ID:          event.ID,
-			Path:        event.Path,
-			Title:       event.Title,
-			Description: event.Description,
-			Location:    event.Location,
-			StartTime:   occ,
-			EndTime:     occ.Add(duration),
-			Recurrence:  event.Recurrence,
-			Timezone:    event.Timezone,
-			Attendees:   event.Attendees,
+		if event.AllDay || event.Floating {
+			occ = wallClock(occ, time.UTC)
 		}
+		if overridden[occ.Unix()] {
+			continue
+		}
+		e := event
+		e.StartTime = occ
+		e.EndTime = occ.Add(duration)
+		e.RecurrenceID = &occ
+		e.OccurrenceID = OccurrenceID(event.ID, occ, event.AllDay, event.Floating)
+		e.Overrides = nil
 		events = append(events, e)
 	}
 
+	// Overrides are placed by their own start, which may lie in the range
+	// even when the original occurrence does not.
+	for _, o := range event.Overrides {
+		if o.RecurrenceID == nil || o.Status == EventCancelled {
+			continue
+		}
+		start := o.StartTime
+		if o.AllDay || o.Floating {
+			start = wallClock(start, rangeStart.Location())
+		}
+		if start.Before(rangeStart) || start.After(rangeEnd) {
+			continue
+		}
+		events = append(events, o)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].StartTime.Before(events[j].StartTime) })
+
 	return events, nil
 }
@@ -83,3 +83,227 @@ func TestExpandRecurrence_Weekly(t *testing.T) {
 		t.Errorf("expected 4 occurrences, got %d", len(events))
 	}
 }
+
+func TestExpandRecurrence_AllDayKeepsDate(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	event := Event{
+		ID:         "e3",
+		Title:      "Rent due",
+		StartTime:  start,
+		EndTime:    start.AddDate(0, 0, 1),
+		Recurrence: "FREQ=MONTHLY;COUNT=6",
+		AllDay:     true,
+		ETag:       "etag-1",
+	}
+
+	// A range expressed in a zone west of UTC must still see each occurrence
+	// on its own date rather than on the previous evening.
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	rangeStart := time.Date(2024, 4, 1, 0, 0, 0, 0, ny)
+	rangeEnd := time.Date(2024, 5, 31, 0, 0, 0, 0, ny)
+
+	events, err := ExpandRecurrence(event, rangeStart, rangeEnd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 occurrences, got %d", len(events))
+	}
+
+	want := []time.Time{
+		time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for i, e := range events {
+		if !e.StartTime.Equal(want[i]) {
+			t.Errorf("occurrence %d start = %v, want %v", i, e.StartTime, want[i])
+		}
+		if !e.EndTime.Equal(want[i].AddDate(0, 0, 1)) {
+			t.Errorf("occurrence %d end = %v, want next day", i, e.EndTime)
+		}
+		if !e.AllDay || e.ETag != "etag-1" {
+			t.Errorf("occurrence %d lost fields: AllDay=%v ETag=%q", i, e.AllDay, e.ETag)
+		}
+	}
+}
+
+func TestExpandRecurrence_AllDayUntilDate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	event := Event{
+		ID:         "e4",
+		StartTime:  start,
+		EndTime:    start.AddDate(0, 0, 1),
+		Recurrence: "FREQ=DAILY;UNTIL=20240103",
+		AllDay:     true,
+	}
+
+	events, err := ExpandRecurrence(event, start, start.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 occurrences (UNTIL is inclusive), got %d", len(events))
+	}
+}
+
+func TestExpandRecurrence_KeepsLocalTimeAcrossDST(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, ny)
+	event := Event{
+		ID:         "e5",
+		StartTime:  start,
+		EndTime:    start.Add(time.Hour),
+		Recurrence: "FREQ=WEEKLY;COUNT=3",
+		Timezone:   "America/New_York",
+	}
+
+	events, err := ExpandRecurrence(event, start, start.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 occurrences, got %d", len(events))
+	}
+
+	// DST starts on 10 March; the meeting stays at 9am local time.
+	for i, e := range events {
+		local := e.StartTime.In(ny)
+		if local.Hour() != 9 {
+			t.Errorf("occurrence %d at %v, want 9am New York time", i, local)
+		}
+	}
+	if events[0].StartTime.UTC().Hour() == events[2].StartTime.UTC().Hour() {
+		t.Error("expected the UTC hour to shift across the DST change")
+	}
+}
+
+func TestValidateRecurrenceRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		wantErr bool
+	}{
+		{"weekly with count", "FREQ=WEEKLY;BYDAY=MO;COUNT=52", false},
+		{"rrule prefix", "RRULE:FREQ=DAILY;INTERVAL=2", false},
+		{"lower case", "freq=monthly;bymonthday=1", false},
+		{"until", "FREQ=YEARLY;UNTIL=20301231T000000Z", false},
+		{"empty", "", true},
+		{"missing freq", "BYDAY=MO", true},
+		{"unknown part", "FREQ=WEEKLY;SOMETIMES=YES", true},
+		{"count and until", "FREQ=DAILY;COUNT=3;UNTIL=20301231T000000Z", true},
+		{"embedded dtstart", "FREQ=DAILY;DTSTART=20240101T000000Z", true},
+		{"multiple lines", "FREQ=DAILY\nFREQ=WEEKLY", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRecurrenceRule(tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRecurrenceRule(%q) error = %v, wantErr %v", tt.rule, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExpandRecurrence_RDateAndExDate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	event := Event{
+		ID:              "e6",
+		StartTime:       start,
+		EndTime:         start.Add(time.Hour),
+		Recurrence:      "FREQ=WEEKLY;COUNT=4",
+		RecurrenceDates: []time.Time{time.Date(2024, 1, 10, 15, 0, 0, 0, time.UTC)},
+		ExceptionDates:  []time.Time{start.AddDate(0, 0, 7)},
+	}
+
+	events, err := ExpandRecurrence(event, start, start.AddDate(0, 2, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []time.Time{
+		start,
+		time.Date(2024, 1, 10, 15, 0, 0, 0, time.UTC),
+		start.AddDate(0, 0, 14),
+		start.AddDate(0, 0, 21),
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d occurrences, got %d", len(want), len(events))
+	}
+	for i, e := range events {
+		if !e.StartTime.Equal(want[i]) {
+			t.Errorf("occurrence %d = %v, want %v", i, e.StartTime, want[i])
+		}
+	}
+}
+
+func TestExpandRecurrence_RDateOnly(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	event := Event{
+		ID:              "e7",
+		StartTime:       start,
+		EndTime:         start.Add(time.Hour),
+		RecurrenceDates: []time.Time{start.AddDate(0, 0, 3)},
+	}
+
+	events, err := ExpandRecurrence(event, start, start.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// DTSTART counts as the first instance.
+	if len(events) != 2 {
+		t.Fatalf("expected 2 occurrences, got %d", len(events))
+	}
+}
+
+func TestExpandRecurrence_Overrides(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	moved := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	cancelled := time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC)
+	intoRange := time.Date(2024, 1, 8, 10, 0, 0, 0, time.UTC)
+	event := Event{
+		ID:         "e1",
+		Title:      "Standup",
+		StartTime:  start,
+		EndTime:    start.Add(30 * time.Minute),
+		Recurrence: "FREQ=DAILY;COUNT=10",
+		Overrides: []Event{
+			{ID: "e1", Title: "Late standup", StartTime: moved.Add(5 * time.Hour), EndTime: moved.Add(5*time.Hour + 30*time.Minute), RecurrenceID: &moved},
+			{ID: "e1", Title: "Standup", StartTime: cancelled, EndTime: cancelled.Add(30 * time.Minute), RecurrenceID: &cancelled, Status: "CANCELLED"},
+			// Moved from outside the range into it
+			{ID: "e1", Title: "Early standup", StartTime: start.Add(-time.Hour), EndTime: start.Add(-30 * time.Minute), RecurrenceID: &intoRange},
+		},
+	}
+
+	rangeStart := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rangeEnd := time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)
+	events, err := ExpandRecurrence(event, rangeStart, rangeEnd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct {
+		title string
+		start time.Time
+		rid   time.Time
+	}{
+		{"Early standup", start.Add(-time.Hour), intoRange},
+		{"Standup", start, start},
+		{"Late standup", moved.Add(5 * time.Hour), moved},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d occurrences, got %d: %+v", len(want), len(events), events)
+	}
+	for i, w := range want {
+		e := events[i]
+		if e.Title != w.title || !e.StartTime.Equal(w.start) || e.RecurrenceID == nil || !e.RecurrenceID.Equal(w.rid) {
+			t.Errorf("occurrence %d = %q at %v (rid %v), want %q at %v (rid %v)", i, e.Title, e.StartTime, e.RecurrenceID, w.title, w.start, w.rid)
+		}
+		if len(e.Overrides) != 0 {
+			t.Errorf("occurrence %d should not carry overrides", i)
+		}
+	}
+}
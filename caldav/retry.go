@@ -2,9 +2,12 @@ package caldav
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"math"
 	"time"
+
+	"github.com/emersion/go-ical"
 )
 
 // RetryClient wraps a CalendarService with retry logic using exponential backoff.
@@ -33,6 +36,12 @@ func (r *RetryClient) retry(ctx context.Context, operation string, fn func() err
 		if lastErr == nil {
 			return nil
 		}
+		// A conflict, a missing event or a weak ETag will not resolve itself
+		// by trying again
+		var conflict *ConflictError
+		if errors.As(lastErr, &conflict) || errors.Is(lastErr, ErrEventNotFound) || errors.Is(lastErr, ErrWeakETag) {
+			return lastErr
+		}
 		if attempt == r.maxRetry {
 			break
 		}
@@ -69,35 +78,168 @@ func (r *RetryClient) ListCalendars(ctx context.Context) ([]Calendar, error) {
 	return result, err
 }
 
+// CreateCalendar does NOT retry (not idempotent).
+func (r *RetryClient) CreateCalendar(ctx context.Context, calendar *Calendar) (string, error) {
+	return r.inner.CreateCalendar(ctx, calendar)
+}
+
+// UpdateCalendar retries (idempotent).
+func (r *RetryClient) UpdateCalendar(ctx context.Context, calendarPath string, update *CalendarUpdate) error {
+	return r.retry(ctx, "UpdateCalendar", func() error {
+		return r.inner.UpdateCalendar(ctx, calendarPath, update)
+	})
+}
+
+// DeleteCalendar retries (idempotent).
+func (r *RetryClient) DeleteCalendar(ctx context.Context, calendarPath string) error {
+	return r.retry(ctx, "DeleteCalendar", func() error {
+		return r.inner.DeleteCalendar(ctx, calendarPath)
+	})
+}
+
 // SearchEvents retries (idempotent).
-func (r *RetryClient) SearchEvents(ctx context.Context, calendarPath string, startTime, endTime *time.Time) ([]Event, error) {
+func (r *RetryClient) SearchEvents(ctx context.Context, calendarPath string, startTime, endTime *time.Time, opts ...SearchOptions) ([]Event, error) {
 	var result []Event
 	err := r.retry(ctx, "SearchEvents", func() error {
 		var e error
-		result, e = r.inner.SearchEvents(ctx, calendarPath, startTime, endTime)
+		result, e = r.inner.SearchEvents(ctx, calendarPath, startTime, endTime, opts...)
+		return e
+	})
+	return result, err
+}
+
+// SyncCalendar retries (idempotent).
+func (r *RetryClient) SyncCalendar(ctx context.Context, calendarPath, token string) (*SyncResult, error) {
+	var result *SyncResult
+	err := r.retry(ctx, "SyncCalendar", func() error {
+		var e error
+		result, e = r.inner.SyncCalendar(ctx, calendarPath, token)
 		return e
 	})
 	return result, err
 }
 
+// GetEvent retries (idempotent).
+func (r *RetryClient) GetEvent(ctx context.Context, eventPath string) (*Event, *EventObject, error) {
+	var event *Event
+	var obj *EventObject
+	err := r.retry(ctx, "GetEvent", func() error {
+		var e error
+		event, obj, e = r.inner.GetEvent(ctx, eventPath)
+		return e
+	})
+	return event, obj, err
+}
+
 // CreateEvent does NOT retry (not idempotent).
 func (r *RetryClient) CreateEvent(ctx context.Context, calendarPath string, event *Event) (string, error) {
 	return r.inner.CreateEvent(ctx, calendarPath, event)
 }
 
 // UpdateEvent does NOT retry (not idempotent).
-func (r *RetryClient) UpdateEvent(ctx context.Context, eventPath string, update *EventUpdate) error {
+func (r *RetryClient) UpdateEvent(ctx context.Context, eventPath string, update *EventUpdate) (string, error) {
 	return r.inner.UpdateEvent(ctx, eventPath, update)
 }
 
 // DeleteEvent retries (idempotent).
-func (r *RetryClient) DeleteEvent(ctx context.Context, eventPath string) error {
+func (r *RetryClient) DeleteEvent(ctx context.Context, eventPath, etag string) error {
 	return r.retry(ctx, "DeleteEvent", func() error {
-		return r.inner.DeleteEvent(ctx, eventPath)
+		return r.inner.DeleteEvent(ctx, eventPath, etag)
 	})
 }
 
+// DeleteOccurrence does NOT retry (not idempotent).
+func (r *RetryClient) DeleteOccurrence(ctx context.Context, eventPath string, recurrenceID time.Time, scope, etag string) error {
+	return r.inner.DeleteOccurrence(ctx, eventPath, recurrenceID, scope, etag)
+}
+
 // GetEventPath delegates to the inner client.
 func (r *RetryClient) GetEventPath(calendarPath, eventID string) string {
 	return r.inner.GetEventPath(calendarPath, eventID)
 }
+
+// GetEventObject retries (idempotent).
+func (r *RetryClient) GetEventObject(ctx context.Context, eventPath string) (*EventObject, error) {
+	var result *EventObject
+	err := r.retry(ctx, "GetEventObject", func() error {
+		var e error
+		result, e = r.inner.GetEventObject(ctx, eventPath)
+		return e
+	})
+	return result, err
+}
+
+// PutEventObject does NOT retry (not idempotent).
+func (r *RetryClient) PutEventObject(ctx context.Context, eventPath string, data *ical.Calendar) error {
+	return r.inner.PutEventObject(ctx, eventPath, data)
+}
+
+// DeleteEventObject retries (idempotent).
+func (r *RetryClient) DeleteEventObject(ctx context.Context, eventPath, etag string) error {
+	return r.retry(ctx, "DeleteEventObject", func() error {
+		return r.inner.DeleteEventObject(ctx, eventPath, etag)
+	})
+}
+
+// MoveEvent does NOT retry (not idempotent).
+func (r *RetryClient) MoveEvent(ctx context.Context, eventPath, calendarPath, etag string) (string, error) {
+	return r.inner.MoveEvent(ctx, eventPath, calendarPath, etag)
+}
+
+// CopyEvent does NOT retry (not idempotent).
+func (r *RetryClient) CopyEvent(ctx context.Context, eventPath, calendarPath string) (string, error) {
+	return r.inner.CopyEvent(ctx, eventPath, calendarPath)
+}
+
+// SearchTasks retries (idempotent).
+func (r *RetryClient) SearchTasks(ctx context.Context, calendarPath string, includeCompleted bool) ([]Task, error) {
+	var result []Task
+	err := r.retry(ctx, "SearchTasks", func() error {
+		var e error
+		result, e = r.inner.SearchTasks(ctx, calendarPath, includeCompleted)
+		return e
+	})
+	return result, err
+}
+
+// CreateTask does NOT retry (not idempotent).
+func (r *RetryClient) CreateTask(ctx context.Context, calendarPath string, task *Task) (string, error) {
+	return r.inner.CreateTask(ctx, calendarPath, task)
+}
+
+// UpdateTask does NOT retry (not idempotent).
+func (r *RetryClient) UpdateTask(ctx context.Context, taskPath string, update *TaskUpdate) error {
+	return r.inner.UpdateTask(ctx, taskPath, update)
+}
+
+// CompleteTask does NOT retry (not idempotent).
+func (r *RetryClient) CompleteTask(ctx context.Context, taskPath, etag string) error {
+	return r.inner.CompleteTask(ctx, taskPath, etag)
+}
+
+// FreeBusy retries (idempotent).
+func (r *RetryClient) FreeBusy(ctx context.Context, calendarPath string, start, end time.Time) ([]BusyPeriod, error) {
+	var result []BusyPeriod
+	err := r.retry(ctx, "FreeBusy", func() error {
+		var e error
+		result, e = r.inner.FreeBusy(ctx, calendarPath, start, end)
+		return e
+	})
+	return result, err
+}
+
+// ListInvitations retries (idempotent).
+func (r *RetryClient) ListInvitations(ctx context.Context, start, end time.Time) ([]Invitation, error) {
+	var result []Invitation
+	err := r.retry(ctx, "ListInvitations", func() error {
+		var e error
+		result, e = r.inner.ListInvitations(ctx, start, end)
+		return e
+	})
+	return result, err
+}
+
+// RespondToInvitation does NOT retry (not idempotent).
+func (r *RetryClient) RespondToInvitation(ctx context.Context, path string, response InvitationResponse) error {
+	return r.inner.RespondToInvitation(ctx, path, response)
+}
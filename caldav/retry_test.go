@@ -29,7 +29,7 @@ func TestRetryClient_SearchEvents_SucceedsAfterRetry(t *testing.T) {
 	}
 
 	// Wrap with a custom error-then-succeed pattern
-	failOnce := &failOnceMock{inner: mock, failCount: 1}
+	failOnce := &failOnceMock{CalendarService: mock, failCount: 1}
 
 	rc := NewRetryClient(failOnce, 2, 1*time.Millisecond)
 	events, err := rc.SearchEvents(context.Background(), "/cal", nil, nil)
@@ -63,7 +63,7 @@ func TestRetryClient_DeleteEvent_Retries(t *testing.T) {
 	}
 
 	rc := NewRetryClient(mock, 2, 1*time.Millisecond)
-	err := rc.DeleteEvent(context.Background(), "/cal/event.ics")
+	err := rc.DeleteEvent(context.Background(), "/cal/event.ics", "")
 	if err == nil {
 		t.Fatal("expected error after retries exhausted")
 	}
@@ -72,6 +72,56 @@ func TestRetryClient_DeleteEvent_Retries(t *testing.T) {
 	}
 }
 
+func TestRetryClient_DeleteEvent_WeakETagNoRetry(t *testing.T) {
+	mock := &MockClient{
+		DeleteEventErr: fmt.Errorf("failed to delete event: %w", ErrWeakETag),
+	}
+
+	rc := NewRetryClient(mock, 2, 1*time.Millisecond)
+	if err := rc.DeleteEvent(context.Background(), "/cal/event.ics", "W/abc"); err == nil {
+		t.Fatal("expected error")
+	}
+	if mock.DeleteCallCount != 1 {
+		t.Errorf("expected 1 call (no retry), got %d", mock.DeleteCallCount)
+	}
+}
+
+func TestRetryClient_CreateCalendar_NoRetry(t *testing.T) {
+	mock := &MockClient{CalendarErr: fmt.Errorf("server error")}
+
+	rc := NewRetryClient(mock, 3, 1*time.Millisecond)
+	if _, err := rc.CreateCalendar(context.Background(), &Calendar{Name: "Project X"}); err == nil {
+		t.Fatal("expected error from CreateCalendar")
+	}
+	if mock.CreateCalendarCount != 1 {
+		t.Errorf("expected 1 call (no retry), got %d", mock.CreateCalendarCount)
+	}
+}
+
+func TestRetryClient_DeleteCalendar_Retries(t *testing.T) {
+	mock := &MockClient{CalendarErr: fmt.Errorf("timeout")}
+
+	rc := NewRetryClient(mock, 2, 1*time.Millisecond)
+	if err := rc.DeleteCalendar(context.Background(), "/cal/work/"); err == nil {
+		t.Fatal("expected error after retries exhausted")
+	}
+	if mock.DeleteCalendarCount != 3 {
+		t.Errorf("expected 3 calls, got %d", mock.DeleteCalendarCount)
+	}
+}
+
+func TestRetryClient_SyncCalendar_Retries(t *testing.T) {
+	mock := &MockClient{SyncErr: fmt.Errorf("connection reset")}
+
+	rc := NewRetryClient(mock, 2, 1*time.Millisecond)
+	if _, err := rc.SyncCalendar(context.Background(), "/cal/", "token"); err == nil {
+		t.Fatal("expected error after retries exhausted")
+	}
+	if mock.SyncCallCount != 3 {
+		t.Errorf("expected 3 calls, got %d", mock.SyncCallCount)
+	}
+}
+
 func TestRetryClient_ContextCancellation(t *testing.T) {
 	mock := &MockClient{
 		SearchEventsErr: fmt.Errorf("keep failing"),
@@ -140,7 +190,7 @@ func TestRetryClient_UpdateEvent_NoRetry(t *testing.T) {
 	rc := NewRetryClient(mock, 3, 1*time.Millisecond)
 
 	title := "Updated"
-	err := rc.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Title: &title})
+	_, err := rc.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Title: &title})
 	if err == nil {
 		t.Fatal("expected error from UpdateEvent")
 	}
@@ -151,7 +201,7 @@ func TestRetryClient_UpdateEvent_Success(t *testing.T) {
 	rc := NewRetryClient(mock, 2, 1*time.Millisecond)
 
 	title := "Updated"
-	err := rc.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Title: &title})
+	_, err := rc.UpdateEvent(context.Background(), "/cal/event.ics", &EventUpdate{Title: &title})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -174,7 +224,7 @@ func TestRetryClient_DeleteEvent_Success(t *testing.T) {
 	mock := &MockClient{}
 	rc := NewRetryClient(mock, 2, 1*time.Millisecond)
 
-	err := rc.DeleteEvent(context.Background(), "/cal/event.ics")
+	err := rc.DeleteEvent(context.Background(), "/cal/event.ics", "")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -221,43 +271,35 @@ func TestRetryClient_SearchEvents_WithTimeParams(t *testing.T) {
 	}
 }
 
-// failOnceMock wraps a CalendarService and fails the first N SearchEvents calls
+// failOnceMock wraps a CalendarService and fails the first N SearchEvents calls.
+// All other methods are delegated to the embedded service.
 type failOnceMock struct {
-	inner     CalendarService
+	CalendarService
 	failCount int
 	calls     int
 }
 
 var _ CalendarService = (*failOnceMock)(nil)
 
-func (f *failOnceMock) DiscoverCalendarHomeSet(ctx context.Context) (string, error) {
-	return f.inner.DiscoverCalendarHomeSet(ctx)
-}
-
-func (f *failOnceMock) ListCalendars(ctx context.Context) ([]Calendar, error) {
-	return f.inner.ListCalendars(ctx)
-}
-
-func (f *failOnceMock) SearchEvents(ctx context.Context, path string, start, end *time.Time) ([]Event, error) {
+func (f *failOnceMock) SearchEvents(ctx context.Context, path string, start, end *time.Time, opts ...SearchOptions) ([]Event, error) {
 	f.calls++
 	if f.calls <= f.failCount {
 		return nil, fmt.Errorf("transient error (call %d)", f.calls)
 	}
-	return f.inner.SearchEvents(ctx, path, start, end)
+	return f.CalendarService.SearchEvents(ctx, path, start, end, opts...)
 }
 
-func (f *failOnceMock) CreateEvent(ctx context.Context, path string, event *Event) (string, error) {
-	return f.inner.CreateEvent(ctx, path, event)
-}
-
-func (f *failOnceMock) UpdateEvent(ctx context.Context, path string, update *EventUpdate) error {
-	return f.inner.UpdateEvent(ctx, path, update)
-}
-
-func (f *failOnceMock) DeleteEvent(ctx context.Context, path string) error {
-	return f.inner.DeleteEvent(ctx, path)
-}
+func TestRetryClient_DoesNotRetryConflicts(t *testing.T) {
+	mock := &MockClient{
+		DeleteEventErr: &ConflictError{Path: "/cal/event.ics"},
+	}
+	rc := NewRetryClient(mock, 3, 1*time.Millisecond)
 
-func (f *failOnceMock) GetEventPath(calendarPath, eventID string) string {
-	return f.inner.GetEventPath(calendarPath, eventID)
+	err := rc.DeleteEvent(context.Background(), "/cal/event.ics", "etag-1")
+	if err == nil {
+		t.Fatal("expected conflict error")
+	}
+	if mock.DeleteCallCount != 1 {
+		t.Errorf("DeleteCallCount = %d, want 1 (no retry on conflict)", mock.DeleteCallCount)
+	}
 }
@@ -0,0 +1,164 @@
+package caldav
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+	extcaldav "github.com/emersion/go-webdav/caldav"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata/roundtrip")
+
+// originalStamps are the DTSTAMP, CREATED and LAST-MODIFIED values in
+// testdata/roundtrip/event.ics.
+var originalStamps = map[string]bool{"20250101T080000Z": true, "20241215T101500Z": true}
+
+// newStamp matches the timestamps a write path may set to the current time.
+var newStamp = regexp.MustCompile(`(?m)^(DTSTAMP|CREATED|LAST-MODIFIED):\d{8}T\d{6}Z$`)
+
+// roundTripObject reads testdata/roundtrip/event.ics, an iCloud event with
+// properties and components Event does not map.
+func roundTripObject(t *testing.T) *extcaldav.CalendarObject {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "roundtrip", "event.ics"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &extcaldav.CalendarObject{
+		Path: "/home/work/weekly-sync.ics",
+		ETag: `"etag-1"`,
+		Data: decodeCalendar(t, string(data)),
+	}
+}
+
+// encodePuts renders the calendar objects written to mb in order, with the
+// timestamps set during the test replaced by a placeholder.
+func encodePuts(t *testing.T, mb *mockBackend) string {
+	t.Helper()
+	var out strings.Builder
+	for _, path := range mb.putPaths {
+		var buf bytes.Buffer
+		if err := ical.NewEncoder(&buf).Encode(mb.putCals[path]); err != nil {
+			t.Fatalf("failed to encode %s: %v", path, err)
+		}
+		out.WriteString("PUT " + path + "\n")
+		out.WriteString(strings.ReplaceAll(buf.String(), "\r\n", "\n"))
+	}
+	return newStamp.ReplaceAllStringFunc(out.String(), func(line string) string {
+		name, value, _ := strings.Cut(line, ":")
+		if originalStamps[value] {
+			return line
+		}
+		return name + ":<now>"
+	})
+}
+
+// TestRoundTrip_Golden runs each write path on testdata/roundtrip/event.ics
+// and compares what is written with testdata/roundtrip/<name>.golden. Every
+// property and component a write path does not change must survive. Run
+// with -update to rewrite the golden files after an intended change.
+func TestRoundTrip_Golden(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	title := "Weekly team sync"
+	start := time.Date(2025, 1, 6, 11, 0, 0, 0, berlin)
+	end := time.Date(2025, 1, 6, 12, 0, 0, 0, berlin)
+	occurrence := time.Date(2025, 1, 27, 10, 0, 0, 0, berlin)
+	following := time.Date(2025, 2, 3, 10, 0, 0, 0, berlin)
+
+	tests := []struct {
+		name string
+		run  func(c *Client, obj *extcaldav.CalendarObject) error
+	}{
+		{"update_title", func(c *Client, obj *extcaldav.CalendarObject) error {
+			_, err := c.UpdateEvent(context.Background(), obj.Path, &EventUpdate{Title: &title})
+			return err
+		}},
+		{"update_times", func(c *Client, obj *extcaldav.CalendarObject) error {
+			_, err := c.UpdateEvent(context.Background(), obj.Path, &EventUpdate{StartTime: &start, EndTime: &end})
+			return err
+		}},
+		{"update_alarms_unchanged", func(c *Client, obj *extcaldav.CalendarObject) error {
+			event, err := parseCalendarObject(obj)
+			if err != nil {
+				return err
+			}
+			_, err = c.UpdateEvent(context.Background(), obj.Path, &EventUpdate{Alarms: &event.Alarms})
+			return err
+		}},
+		{"update_attendees", func(c *Client, obj *extcaldav.CalendarObject) error {
+			_, err := c.UpdateEvent(context.Background(), obj.Path, &EventUpdate{Attendees: &AttendeeChanges{
+				Add:    []Attendee{{Email: "alice@example.com", Role: "CHAIR"}, {Email: "dave@example.com"}},
+				Remove: []string{"bob@example.com"},
+			}})
+			return err
+		}},
+		{"update_occurrence", func(c *Client, obj *extcaldav.CalendarObject) error {
+			_, err := c.UpdateEvent(context.Background(), obj.Path, &EventUpdate{Title: &title, RecurrenceID: &occurrence, Scope: ScopeThis})
+			return err
+		}},
+		{"update_following", func(c *Client, obj *extcaldav.CalendarObject) error {
+			_, err := c.UpdateEvent(context.Background(), obj.Path, &EventUpdate{
+				Title:        &title,
+				RecurrenceID: &following,
+				Scope:        ScopeThisAndFollowing,
+				NewSeriesID:  "weekly-sync-2@example.com",
+			})
+			return err
+		}},
+		{"create_from_event", func(c *Client, obj *extcaldav.CalendarObject) error {
+			event, err := parseCalendarObject(obj)
+			if err != nil {
+				return err
+			}
+			_, err = c.CreateEvent(context.Background(), "/home/personal/", event)
+			return err
+		}},
+		{"put_event_object", func(c *Client, obj *extcaldav.CalendarObject) error {
+			// A move between accounts writes the object it read unchanged
+			event, err := c.GetEventObject(context.Background(), obj.Path)
+			if err != nil {
+				return err
+			}
+			return c.PutEventObject(context.Background(), "/home/personal/weekly-sync.ics", event.Data)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := roundTripObject(t)
+			mb := newSchedulingBackend()
+			mb.getResult = obj
+			c := NewClientWithBackend(mb)
+
+			if err := tt.run(c, obj); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := encodePuts(t, mb)
+
+			golden := filepath.Join("testdata", "roundtrip", tt.name+".golden")
+			if *updateGolden {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			if got != string(want) {
+				t.Errorf("written objects differ from %s (run with -update to accept):\n%s", golden, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,602 @@
+package caldav
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav/caldav"
+)
+
+// Participation statuses (RFC 5545 PARTSTAT) of an attendee.
+const (
+	PartStatNeedsAction = "NEEDS-ACTION"
+	PartStatAccepted    = "ACCEPTED"
+	PartStatDeclined    = "DECLINED"
+	PartStatTentative   = "TENTATIVE"
+)
+
+// Scheduling agents (RFC 6638 section 7.1): who delivers the scheduling
+// messages of an ORGANIZER or ATTENDEE. With ScheduleAgentClient the server
+// leaves it to this client, which posts them to the scheduling outbox; with
+// ScheduleAgentNone nobody sends them.
+const (
+	ScheduleAgentServer = "SERVER"
+	ScheduleAgentClient = "CLIENT"
+	ScheduleAgentNone   = "NONE"
+)
+
+// paramScheduleAgent is the SCHEDULE-AGENT parameter, which go-ical has no
+// constant for.
+const paramScheduleAgent = "SCHEDULE-AGENT"
+
+// ValidateScheduleAgent checks that agent is empty or a valid
+// SCHEDULE-AGENT value.
+func ValidateScheduleAgent(agent string) error {
+	switch strings.ToUpper(agent) {
+	case "", ScheduleAgentServer, ScheduleAgentClient, ScheduleAgentNone:
+		return nil
+	}
+	return fmt.Errorf("invalid scheduleAgent %q: use %s, %s or %s", agent, ScheduleAgentServer, ScheduleAgentClient, ScheduleAgentNone)
+}
+
+// scheduleAgent returns the SCHEDULE-AGENT of prop, ScheduleAgentServer if
+// it has none.
+func scheduleAgent(prop *ical.Prop) string {
+	if prop != nil {
+		if agent := prop.Params.Get(paramScheduleAgent); agent != "" {
+			return strings.ToUpper(agent)
+		}
+	}
+	return ScheduleAgentServer
+}
+
+// schedulingInfo holds the RFC 6638 scheduling properties of the user's
+// principal. Servers without scheduling support leave them empty.
+type schedulingInfo struct {
+	inbox  string
+	outbox string
+	// addresses are the user's calendar user addresses, such as
+	// "mailto:user@icloud.com".
+	addresses []string
+}
+
+// Invitation is an event the user is invited to and has not answered yet.
+type Invitation struct {
+	Event
+	// CalendarPath is the calendar holding the invitation. It is empty for
+	// invitations found only in the scheduling inbox.
+	CalendarPath string `json:"calendarPath,omitempty"`
+	// InInbox is set for scheduling inbox messages (RFC 6638 section 2.2).
+	// Event.Path is then the path of the message.
+	InInbox bool `json:"inInbox,omitempty"`
+	// ScheduleAgent is set when the server does not deliver the reply:
+	// ScheduleAgentClient if RespondToInvitation sends it itself, and
+	// ScheduleAgentNone if the organizer receives no reply.
+	ScheduleAgent string `json:"scheduleAgent,omitempty"`
+}
+
+// InvitationResponse is the user's answer to an invitation.
+type InvitationResponse struct {
+	// Status is PartStatAccepted, PartStatDeclined or PartStatTentative.
+	Status string
+	// Comment is an optional note to the organizer.
+	Comment string
+	// ETag, if set, makes the response fail with a *ConflictError unless
+	// the invitation still has this ETag on the server.
+	ETag string
+}
+
+// ValidateInvitationResponse checks that status is a valid answer to an
+// invitation.
+func ValidateInvitationResponse(status string) error {
+	switch status {
+	case PartStatAccepted, PartStatDeclined, PartStatTentative:
+		return nil
+	}
+	return fmt.Errorf("invalid response %q: use %s, %s or %s", status, PartStatAccepted, PartStatDeclined, PartStatTentative)
+}
+
+// scheduling returns the scheduling properties of the user's principal.
+// The result is cached after the first call using sync.Once.
+func (c *Client) scheduling(ctx context.Context) (*schedulingInfo, error) {
+	if _, err := c.DiscoverCalendarHomeSet(ctx); err != nil {
+		return nil, err
+	}
+	c.schedulingOnce.Do(func() {
+		info, err := c.backend.FindSchedulingInfo(ctx, c.principal)
+		if err != nil {
+			c.schedulingErr = fmt.Errorf("failed to find scheduling properties: %w", err)
+			return
+		}
+		c.schedulingInfo = info
+	})
+	return c.schedulingInfo, c.schedulingErr
+}
+
+// userAddresses returns the calendar user addresses of the account: those
+// the server reports and the account email. Scheduling lookup errors are
+// logged and only the email is used.
+func (c *Client) userAddresses(ctx context.Context) (*schedulingInfo, []string) {
+	info, err := c.scheduling(ctx)
+	if err != nil {
+		slog.Warn("scheduling properties unavailable, using account email", "error", err)
+		info = &schedulingInfo{}
+	}
+	var addresses []string
+	for _, addr := range info.addresses {
+		if strings.HasPrefix(strings.ToLower(addr), "mailto:") {
+			addresses = append(addresses, addr)
+		}
+	}
+	if c.email != "" {
+		addresses = append(addresses, "mailto:"+c.email)
+	}
+	return info, addresses
+}
+
+// organizerAddress returns the address to set as ORGANIZER of events the
+// user creates, or "" if it is unknown.
+func (c *Client) organizerAddress(ctx context.Context) string {
+	_, addresses := c.userAddresses(ctx)
+	if len(addresses) == 0 {
+		return ""
+	}
+	return addresses[0]
+}
+
+// isUserAddress reports whether the calendar user address value, such as
+// "mailto:a@example.com", is one of addresses.
+func isUserAddress(value string, addresses []string) bool {
+	value = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(value)), "mailto:")
+	for _, addr := range addresses {
+		if strings.TrimPrefix(strings.ToLower(addr), "mailto:") == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ListInvitations returns the invitations the user has not answered: events
+// between start and end in the user's event calendars that list the user as
+// an attendee with status NEEDS-ACTION, and invitation messages waiting in
+// the scheduling inbox. Events the user organizes are left out.
+func (c *Client) ListInvitations(ctx context.Context, start, end time.Time) ([]Invitation, error) {
+	info, addresses := c.userAddresses(ctx)
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("the account's calendar user address is unknown")
+	}
+
+	calendars, err := c.ListCalendars(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations := []Invitation{}
+	seen := make(map[string]bool)
+	for _, cal := range calendars {
+		if !cal.Supports(ical.CompEvent) || (info.inbox != "" && cal.Path == info.inbox) {
+			continue
+		}
+		events, err := c.SearchEvents(ctx, cal.Path, &start, &end)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range events {
+			agent, ok := awaitsResponse(&e, addresses)
+			if seen[e.ID] || !ok {
+				continue
+			}
+			seen[e.ID] = true
+			invitation := Invitation{Event: e, CalendarPath: cal.Path}
+			if agent != ScheduleAgentServer {
+				invitation.ScheduleAgent = agent
+			}
+			invitations = append(invitations, invitation)
+		}
+	}
+
+	if info.inbox != "" {
+		messages, err := c.inboxMessages(ctx, info.inbox)
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range messages {
+			if _, ok := awaitsResponse(&msg, addresses); seen[msg.ID] || !ok {
+				continue
+			}
+			seen[msg.ID] = true
+			invitations = append(invitations, Invitation{Event: msg, InInbox: true})
+		}
+	}
+
+	sort.SliceStable(invitations, func(i, j int) bool {
+		return invitations[i].StartTime.Before(invitations[j].StartTime)
+	})
+	return invitations, nil
+}
+
+// awaitsResponse reports whether e invites one of addresses, who has not
+// answered yet, and was organized by someone else. It also returns who
+// delivers the reply, as for replyAgent.
+func awaitsResponse(e *Event, addresses []string) (string, bool) {
+	if e.Organizer != "" && isUserAddress(e.Organizer, addresses) {
+		return "", false
+	}
+	for _, a := range e.Attendees {
+		if isUserAddress(a.Email, addresses) {
+			if a.Status != "" && !strings.EqualFold(a.Status, PartStatNeedsAction) {
+				return "", false
+			}
+			if e.Raw != nil {
+				return replyAgent(e.Raw, addresses), true
+			}
+			return ScheduleAgentServer, true
+		}
+	}
+	return "", false
+}
+
+// replyAgent returns who delivers the user's reply to the invitation comp:
+// the SCHEDULE-AGENT of its ORGANIZER (RFC 6638 section 3.2.2), or failing
+// that of the user's ATTENDEE, as left by the organizer's client.
+func replyAgent(comp *ical.Component, addresses []string) string {
+	if prop := comp.Props.Get(ical.PropOrganizer); prop != nil && prop.Params.Get(paramScheduleAgent) != "" {
+		return scheduleAgent(prop)
+	}
+	props := comp.Props[ical.PropAttendee]
+	for i := range props {
+		if isUserAddress(props[i].Value, addresses) {
+			return scheduleAgent(&props[i])
+		}
+	}
+	return ScheduleAgentServer
+}
+
+// inboxMessages returns the invitation (METHOD:REQUEST) messages in the
+// scheduling inbox, parsed as events.
+func (c *Client) inboxMessages(ctx context.Context, inbox string) ([]Event, error) {
+	query := &caldav.CalendarQuery{
+		CompRequest: caldav.CalendarCompRequest{
+			Name:     "VCALENDAR",
+			AllProps: true,
+			Comps: []caldav.CalendarCompRequest{
+				{Name: "VEVENT", AllProps: true},
+			},
+		},
+		CompFilter: caldav.CompFilter{
+			Name:  "VCALENDAR",
+			Comps: []caldav.CompFilter{{Name: "VEVENT"}},
+		},
+	}
+	objects, err := c.backend.QueryCalendar(ctx, inbox, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scheduling inbox: %w", err)
+	}
+
+	messages := make([]Event, 0, len(objects))
+	for _, obj := range objects {
+		if method := obj.Data.Props.Get(ical.PropMethod); method == nil || !strings.EqualFold(method.Value, "REQUEST") {
+			continue
+		}
+		event, err := parseCalendarObject(&obj)
+		if err != nil {
+			slog.Warn("skipping unparseable inbox message", "path", obj.Path, "error", err)
+			continue
+		}
+		messages = append(messages, *event)
+	}
+	return messages, nil
+}
+
+// RespondToInvitation sets the user's participation status in the
+// invitation at path, which is an event in one of the user's calendars or a
+// message in the scheduling inbox, as returned by ListInvitations.
+//
+// For an event in a calendar, the updated event is written back and the
+// server sends the reply to the organizer (RFC 6638 implicit scheduling);
+// matching messages in the scheduling inbox are then removed. If the
+// invitation's SCHEDULE-AGENT is CLIENT, the server sends nothing, so the
+// iTIP REPLY is posted to the scheduling outbox here; with NONE, no reply
+// is sent. For an inbox message, an iTIP REPLY is posted to the scheduling
+// outbox and the message is removed.
+func (c *Client) RespondToInvitation(ctx context.Context, path string, response InvitationResponse) error {
+	if err := ValidateInvitationResponse(response.Status); err != nil {
+		return err
+	}
+	info, addresses := c.userAddresses(ctx)
+	if len(addresses) == 0 {
+		return fmt.Errorf("the account's calendar user address is unknown")
+	}
+
+	obj, ifMatch, err := c.getForUpdate(ctx, path, response.ETag)
+	if err != nil {
+		return err
+	}
+	attendee, ok := setParticipation(obj.Data, addresses, response)
+	if !ok {
+		return fmt.Errorf("you are not an attendee of this event")
+	}
+
+	if info.inbox != "" && strings.HasPrefix(path, info.inbox) {
+		return c.replyFromInbox(ctx, info, path, obj.Data, attendee)
+	}
+
+	master, _ := splitEvents(obj.Data)
+	agent := ScheduleAgentServer
+	if master != nil {
+		agent = replyAgent(master, addresses)
+	}
+	if agent == ScheduleAgentClient && info.outbox == "" {
+		return fmt.Errorf("the invitation asks this client to send the reply, but the server has no scheduling outbox to send it through")
+	}
+
+	_, err = c.backend.PutCalendarObject(ctx, path, obj.Data, precondition{ifMatch: ifMatch})
+	if isPreconditionFailed(err) {
+		return c.refetchConflict(ctx, path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save response: %w", err)
+	}
+
+	if agent == ScheduleAgentClient {
+		if err := c.sendReply(ctx, info, obj.Data, attendee); err != nil {
+			return fmt.Errorf("the response was saved, but %w", err)
+		}
+	}
+	if info.inbox != "" && master != nil {
+		c.removeInboxMessages(ctx, info.inbox, master.Props.Get(ical.PropUID))
+	}
+	return nil
+}
+
+// setParticipation sets the PARTSTAT of the user's ATTENDEE in every VEVENT
+// of cal and records response.Comment as COMMENT. It returns the user's
+// attendee address as written in the event, or false if the user is not
+// invited.
+func setParticipation(cal *ical.Calendar, addresses []string, response InvitationResponse) (string, bool) {
+	var attendee string
+	for _, child := range cal.Children {
+		if child.Name != ical.CompEvent {
+			continue
+		}
+		found := false
+		props := child.Props[ical.PropAttendee]
+		for i := range props {
+			if !isUserAddress(props[i].Value, addresses) {
+				continue
+			}
+			if props[i].Params == nil {
+				props[i].Params = ical.Params{}
+			}
+			props[i].Params.Set(ical.ParamParticipationStatus, response.Status)
+			props[i].Params.Del(ical.ParamRSVP)
+			attendee = props[i].Value
+			found = true
+		}
+		if !found {
+			continue
+		}
+		child.Props.Del(ical.PropComment)
+		if response.Comment != "" {
+			child.Props.SetText(ical.PropComment, response.Comment)
+		}
+		child.Props.SetDateTime(ical.PropDateTimeStamp, time.Now().UTC())
+	}
+	return attendee, attendee != ""
+}
+
+// replyFromInbox answers the invitation message at path by posting an iTIP
+// REPLY built from the updated request to the scheduling outbox, then
+// removes the message.
+func (c *Client) replyFromInbox(ctx context.Context, info *schedulingInfo, path string, request *ical.Calendar, attendee string) error {
+	if err := c.sendReply(ctx, info, request, attendee); err != nil {
+		return err
+	}
+	if err := c.backend.Remove(ctx, path, precondition{}); err != nil {
+		slog.Warn("failed to remove answered inbox message", "path", path, "error", err)
+	}
+	return nil
+}
+
+// sendReply posts the iTIP REPLY to request from attendee, whose PARTSTAT
+// has already been set, to the organizer through the scheduling outbox.
+func (c *Client) sendReply(ctx context.Context, info *schedulingInfo, request *ical.Calendar, attendee string) error {
+	if info.outbox == "" {
+		return fmt.Errorf("the server has no scheduling outbox to send the reply through")
+	}
+	reply, organizer := newReply(request, attendee)
+	if organizer == "" {
+		return fmt.Errorf("the invitation has no organizer to reply to")
+	}
+	if err := c.backend.PostOutbox(ctx, info.outbox, attendee, []string{organizer}, reply); err != nil {
+		return fmt.Errorf("failed to send reply: %w", err)
+	}
+	return nil
+}
+
+// newReply builds the iTIP REPLY (RFC 5546 section 3.2.3) to request from
+// attendee, whose PARTSTAT has already been set. It also returns the
+// organizer's address.
+func newReply(request *ical.Calendar, attendee string) (*ical.Calendar, string) {
+	reply := newCalendar()
+	reply.Props.SetText(ical.PropMethod, "REPLY")
+
+	var organizer string
+	for _, child := range request.Children {
+		switch child.Name {
+		case ical.CompTimezone:
+			reply.Children = append(reply.Children, child)
+		case ical.CompEvent:
+			vevent := ical.NewComponent(ical.CompEvent)
+			for _, name := range []string{ical.PropUID, ical.PropDateTimeStamp, ical.PropOrganizer, ical.PropRecurrenceID, ical.PropSequence, ical.PropDateTimeStart, ical.PropDateTimeEnd, ical.PropDuration, ical.PropSummary, ical.PropComment} {
+				if prop := child.Props.Get(name); prop != nil {
+					vevent.Props.Set(prop)
+				}
+			}
+			for _, prop := range child.Props.Values(ical.PropAttendee) {
+				if strings.EqualFold(prop.Value, attendee) {
+					p := prop
+					vevent.Props.Add(&p)
+				}
+			}
+			if prop := child.Props.Get(ical.PropOrganizer); prop != nil && organizer == "" {
+				organizer = prop.Value
+			}
+			reply.Children = append(reply.Children, vevent)
+		}
+	}
+	return reply, organizer
+}
+
+// removeInboxMessages deletes the scheduling inbox messages about the event
+// with the given UID once the user has answered it. Failures are logged.
+func (c *Client) removeInboxMessages(ctx context.Context, inbox string, uid *ical.Prop) {
+	if uid == nil {
+		return
+	}
+	messages, err := c.inboxMessages(ctx, inbox)
+	if err != nil {
+		slog.Warn("failed to read scheduling inbox", "error", err)
+		return
+	}
+	for _, msg := range messages {
+		if msg.ID != uid.Value {
+			continue
+		}
+		if err := c.backend.Remove(ctx, msg.Path, precondition{}); err != nil {
+			slog.Warn("failed to remove answered inbox message", "path", msg.Path, "error", err)
+		}
+	}
+}
+
+// planClientScheduling works out the iTIP messages the user must send
+// themselves when their event changes from before to after, either of which
+// may be nil: a REQUEST to the attendees of after with SCHEDULE-AGENT=CLIENT,
+// and a CANCEL to those of before that are no longer invited. The server
+// sends these attendees nothing (RFC 6638 section 7.1). It fails if there
+// are messages to send but no scheduling outbox, so that the change can be
+// refused before it is made. The returned function sends the messages once
+// the change is saved.
+func (c *Client) planClientScheduling(ctx context.Context, before, after *ical.Calendar) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	if !hasClientScheduledAttendee(before) && !hasClientScheduledAttendee(after) {
+		return noop, nil
+	}
+
+	info, addresses := c.userAddresses(ctx)
+	invited := clientScheduledAttendees(after, addresses)
+	var removed []string
+	for _, addr := range clientScheduledAttendees(before, addresses) {
+		if !isUserAddress(addr, invited) {
+			removed = append(removed, addr)
+		}
+	}
+	if len(invited) == 0 && len(removed) == 0 {
+		return noop, nil
+	}
+	if info.outbox == "" {
+		return nil, fmt.Errorf("attendees with scheduleAgent CLIENT must be sent their invitations by this client, but the server has no scheduling outbox to send them through")
+	}
+
+	return func(ctx context.Context) error {
+		if len(invited) > 0 {
+			if err := c.postSchedulingMessage(ctx, info.outbox, "REQUEST", after, invited); err != nil {
+				return err
+			}
+		}
+		if len(removed) > 0 {
+			if err := c.postSchedulingMessage(ctx, info.outbox, "CANCEL", before, removed); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil
+}
+
+// hasClientScheduledAttendee reports whether an event in cal has an
+// attendee with SCHEDULE-AGENT=CLIENT.
+func hasClientScheduledAttendee(cal *ical.Calendar) bool {
+	if cal == nil {
+		return false
+	}
+	for _, child := range cal.Children {
+		if child.Name != ical.CompEvent {
+			continue
+		}
+		props := child.Props[ical.PropAttendee]
+		for i := range props {
+			if scheduleAgent(&props[i]) == ScheduleAgentClient {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// clientScheduledAttendees returns the addresses of the attendees with
+// SCHEDULE-AGENT=CLIENT of the events in cal that one of addresses
+// organizes. Attendee copies of invitations are left alone.
+func clientScheduledAttendees(cal *ical.Calendar, addresses []string) []string {
+	if cal == nil {
+		return nil
+	}
+	var attendees []string
+	for _, child := range cal.Children {
+		if child.Name != ical.CompEvent {
+			continue
+		}
+		organizer := child.Props.Get(ical.PropOrganizer)
+		if organizer == nil || !isUserAddress(organizer.Value, addresses) {
+			continue
+		}
+		props := child.Props[ical.PropAttendee]
+		for i := range props {
+			addr := props[i].Value
+			if scheduleAgent(&props[i]) == ScheduleAgentClient && !isUserAddress(addr, addresses) && !isUserAddress(addr, attendees) {
+				attendees = append(attendees, addr)
+			}
+		}
+	}
+	return attendees
+}
+
+// postSchedulingMessage posts an iTIP REQUEST or CANCEL (RFC 5546 sections
+// 3.2.2 and 3.2.5) for the events in cal to recipients through the
+// scheduling outbox. Alarms are left out, since they are the user's own.
+func (c *Client) postSchedulingMessage(ctx context.Context, outbox, method string, cal *ical.Calendar, recipients []string) error {
+	msg := ical.NewCalendar()
+	msg.Props = cloneProps(cal.Props)
+	msg.Props.SetText(ical.PropMethod, method)
+
+	var organizer string
+	for _, child := range cal.Children {
+		comp := cloneComponent(child)
+		if comp.Name == ical.CompEvent {
+			var children []*ical.Component
+			for _, sub := range comp.Children {
+				if sub.Name != ical.CompAlarm {
+					children = append(children, sub)
+				}
+			}
+			comp.Children = children
+			if method == "CANCEL" {
+				comp.Props.SetText(ical.PropStatus, EventCancelled)
+				incrementSequence(comp)
+			}
+			if prop := comp.Props.Get(ical.PropOrganizer); prop != nil && organizer == "" {
+				organizer = prop.Value
+			}
+		}
+		msg.Children = append(msg.Children, comp)
+	}
+
+	if err := c.backend.PostOutbox(ctx, outbox, organizer, recipients, msg); err != nil {
+		return fmt.Errorf("failed to send the %s to %s: %w", strings.ToLower(method), strings.Join(recipients, ", "), err)
+	}
+	return nil
+}
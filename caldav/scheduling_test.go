@@ -0,0 +1,582 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+	extcaldav "github.com/emersion/go-webdav/caldav"
+)
+
+// decodeCalendar parses iCalendar text with CRLF line endings added.
+func decodeCalendar(t *testing.T, text string) *ical.Calendar {
+	t.Helper()
+	text = strings.ReplaceAll(strings.TrimSpace(text), "\n", "\r\n") + "\r\n"
+	cal, err := ical.NewDecoder(strings.NewReader(text)).Decode()
+	if err != nil {
+		t.Fatalf("invalid test calendar: %v", err)
+	}
+	return cal
+}
+
+// makeInvitation returns a calendar object inviting me@icloud.com, with
+// method set as METHOD unless it is empty.
+func makeInvitation(t *testing.T, path, uid, partstat, method string) extcaldav.CalendarObject {
+	t.Helper()
+	methodLine := ""
+	if method != "" {
+		methodLine = "METHOD:" + method + "\n"
+	}
+	cal := decodeCalendar(t, `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//test//EN
+`+methodLine+`BEGIN:VEVENT
+UID:`+uid+`
+DTSTAMP:20250301T000000Z
+DTSTART:20250310T150000Z
+DTEND:20250310T160000Z
+SUMMARY:Quarterly review
+SEQUENCE:2
+ORGANIZER;CN=Boss:mailto:boss@example.com
+ATTENDEE;PARTSTAT=ACCEPTED:mailto:boss@example.com
+ATTENDEE;PARTSTAT=`+partstat+`;RSVP=TRUE:mailto:me@icloud.com
+ATTENDEE;PARTSTAT=NEEDS-ACTION:mailto:carol@example.com
+END:VEVENT
+END:VCALENDAR`)
+	return extcaldav.CalendarObject{Path: path, ETag: `"etag-1"`, Data: cal}
+}
+
+func newSchedulingBackend() *mockBackend {
+	return &mockBackend{
+		principal: "/principal/",
+		homeSet:   "/home/",
+		calendars: []Calendar{
+			{Path: "/home/work/", SupportedComponents: []string{"VEVENT"}},
+			{Path: "/home/reminders/", SupportedComponents: []string{"VTODO"}},
+		},
+		scheduling: &schedulingInfo{
+			inbox:     "/home/inbox/",
+			outbox:    "/home/outbox/",
+			addresses: []string{"mailto:me@icloud.com", "urn:uuid:1234"},
+		},
+		putResult: &extcaldav.CalendarObject{},
+	}
+}
+
+// attendeeProp returns the ATTENDEE of comp with the given address.
+func attendeeProp(t *testing.T, comp *ical.Component, address string) ical.Prop {
+	t.Helper()
+	for _, prop := range comp.Props.Values(ical.PropAttendee) {
+		if prop.Value == address {
+			return prop
+		}
+	}
+	t.Fatalf("no ATTENDEE %s", address)
+	return ical.Prop{}
+}
+
+func TestCreateEvent_SetsOrganizerForAttendees(t *testing.T) {
+	mb := newSchedulingBackend()
+	c := NewClientWithBackend(mb)
+
+	_, err := c.CreateEvent(context.Background(), "/home/work/", &Event{
+		Title:     "Planning",
+		StartTime: time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC),
+		Attendees: []Attendee{{Email: "alice@example.com"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	vevent := putEvent(t, mb)
+	if organizer := vevent.Props.Get(ical.PropOrganizer); organizer == nil || organizer.Value != "mailto:me@icloud.com" {
+		t.Errorf("ORGANIZER = %v, want mailto:me@icloud.com", organizer)
+	}
+	alice := attendeeProp(t, vevent, "mailto:alice@example.com")
+	if alice.Params.Get(ical.ParamRSVP) != "TRUE" {
+		t.Errorf("RSVP = %q, want TRUE", alice.Params.Get(ical.ParamRSVP))
+	}
+}
+
+func TestCreateEvent_OrganizerFallsBackToAccountEmail(t *testing.T) {
+	mb := &mockBackend{principal: "/principal/", homeSet: "/home/", putResult: &extcaldav.CalendarObject{}}
+	c := NewClientWithBackend(mb)
+	c.email = "me@example.com"
+
+	_, err := c.CreateEvent(context.Background(), "/home/work/", &Event{
+		Title:     "Planning",
+		StartTime: time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC),
+		Attendees: []Attendee{{Email: "alice@example.com"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if organizer := putEvent(t, mb).Props.Get(ical.PropOrganizer); organizer == nil || organizer.Value != "mailto:me@example.com" {
+		t.Errorf("ORGANIZER = %v, want mailto:me@example.com", organizer)
+	}
+}
+
+func TestCreateEvent_NoOrganizerWithoutAttendees(t *testing.T) {
+	mb := newSchedulingBackend()
+	c := NewClientWithBackend(mb)
+
+	_, err := c.CreateEvent(context.Background(), "/home/work/", &Event{
+		Title:     "Focus",
+		StartTime: time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if organizer := putEvent(t, mb).Props.Get(ical.PropOrganizer); organizer != nil {
+		t.Errorf("ORGANIZER = %v, want none", organizer.Value)
+	}
+}
+
+func TestListInvitations(t *testing.T) {
+	mb := newSchedulingBackend()
+	own := makeInvitation(t, "/home/work/own.ics", "own", "ACCEPTED", "")
+	own.Data.Children[0].Props.Set(&ical.Prop{Name: ical.PropOrganizer, Value: "mailto:me@icloud.com", Params: ical.Params{}})
+	mb.queryByPath = map[string][]extcaldav.CalendarObject{
+		"/home/work/": {
+			makeInvitation(t, "/home/work/pending.ics", "pending", "NEEDS-ACTION", ""),
+			makeInvitation(t, "/home/work/answered.ics", "answered", "ACCEPTED", ""),
+			own,
+		},
+		"/home/inbox/": {
+			// A copy of the pending invitation and one only in the inbox
+			makeInvitation(t, "/home/inbox/m1.ics", "pending", "NEEDS-ACTION", "REQUEST"),
+			makeInvitation(t, "/home/inbox/m2.ics", "inbox-only", "NEEDS-ACTION", "REQUEST"),
+			makeInvitation(t, "/home/inbox/m3.ics", "cancelled", "NEEDS-ACTION", "CANCEL"),
+		},
+	}
+	c := NewClientWithBackend(mb)
+
+	invitations, err := c.ListInvitations(context.Background(), time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(invitations) != 2 {
+		t.Fatalf("got %d invitations, want 2: %+v", len(invitations), invitations)
+	}
+	byID := map[string]Invitation{}
+	for _, inv := range invitations {
+		byID[inv.ID] = inv
+	}
+	if inv := byID["pending"]; inv.CalendarPath != "/home/work/" || inv.InInbox || inv.Path != "/home/work/pending.ics" {
+		t.Errorf("pending = %+v, want the calendar copy", inv)
+	}
+	if inv := byID["inbox-only"]; !inv.InInbox || inv.Path != "/home/inbox/m2.ics" || inv.Organizer != "boss@example.com" {
+		t.Errorf("inbox-only = %+v, want the inbox message", inv)
+	}
+}
+
+func TestListInvitations_UnknownAddress(t *testing.T) {
+	mb := &mockBackend{principal: "/principal/", homeSet: "/home/"}
+	c := NewClientWithBackend(mb)
+
+	if _, err := c.ListInvitations(context.Background(), time.Now(), time.Now().Add(time.Hour)); err == nil {
+		t.Fatal("expected an error without any calendar user address")
+	}
+}
+
+func TestRespondToInvitation_CalendarCopy(t *testing.T) {
+	mb := newSchedulingBackend()
+	invitation := makeInvitation(t, "/home/work/pending.ics", "pending", "NEEDS-ACTION", "")
+	mb.getResult = &invitation
+	mb.queryByPath = map[string][]extcaldav.CalendarObject{
+		"/home/inbox/": {
+			makeInvitation(t, "/home/inbox/m1.ics", "pending", "NEEDS-ACTION", "REQUEST"),
+			makeInvitation(t, "/home/inbox/m2.ics", "other", "NEEDS-ACTION", "REQUEST"),
+		},
+	}
+	c := NewClientWithBackend(mb)
+
+	err := c.RespondToInvitation(context.Background(), "/home/work/pending.ics", InvitationResponse{Status: PartStatAccepted, Comment: "See you there"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if mb.lastPutPath != "/home/work/pending.ics" || mb.lastPutCond.ifMatch != invitation.ETag {
+		t.Errorf("put %s with %+v, want conditional write of the event", mb.lastPutPath, mb.lastPutCond)
+	}
+	vevent := putEvent(t, mb)
+	me := attendeeProp(t, vevent, "mailto:me@icloud.com")
+	if me.Params.Get(ical.ParamParticipationStatus) != PartStatAccepted || me.Params.Get(ical.ParamRSVP) != "" {
+		t.Errorf("my ATTENDEE params = %v, want PARTSTAT=ACCEPTED without RSVP", me.Params)
+	}
+	if carol := attendeeProp(t, vevent, "mailto:carol@example.com"); carol.Params.Get(ical.ParamParticipationStatus) != PartStatNeedsAction {
+		t.Error("other attendees should be left alone")
+	}
+	if comment := vevent.Props.Get(ical.PropComment); comment == nil || comment.Value != "See you there" {
+		t.Errorf("COMMENT = %v", comment)
+	}
+	if len(mb.removedPaths) != 1 || mb.removedPaths[0] != "/home/inbox/m1.ics" {
+		t.Errorf("removed %v, want only the matching inbox message", mb.removedPaths)
+	}
+	if mb.outboxCal != nil {
+		t.Error("the server sends the reply for calendar copies; nothing should be posted")
+	}
+}
+
+func TestRespondToInvitation_InboxMessage(t *testing.T) {
+	mb := newSchedulingBackend()
+	message := makeInvitation(t, "/home/inbox/m2.ics", "inbox-only", "NEEDS-ACTION", "REQUEST")
+	mb.getResult = &message
+	c := NewClientWithBackend(mb)
+
+	err := c.RespondToInvitation(context.Background(), "/home/inbox/m2.ics", InvitationResponse{Status: PartStatDeclined})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(mb.putPaths) != 0 {
+		t.Errorf("wrote %v, want no writes for an inbox message", mb.putPaths)
+	}
+	if mb.outboxPath != "/home/outbox/" || mb.outboxFrom != "mailto:me@icloud.com" {
+		t.Errorf("posted to %s from %s", mb.outboxPath, mb.outboxFrom)
+	}
+	if len(mb.outboxTo) != 1 || mb.outboxTo[0] != "mailto:boss@example.com" {
+		t.Errorf("recipients = %v, want the organizer", mb.outboxTo)
+	}
+
+	reply := mb.outboxCal
+	if method := reply.Props.Get(ical.PropMethod); method == nil || method.Value != "REPLY" {
+		t.Errorf("METHOD = %v, want REPLY", method)
+	}
+	vevent := reply.Children[0]
+	attendees := vevent.Props.Values(ical.PropAttendee)
+	if len(attendees) != 1 || attendees[0].Params.Get(ical.ParamParticipationStatus) != PartStatDeclined {
+		t.Errorf("reply attendees = %v, want only mine, declined", attendees)
+	}
+	if uid := vevent.Props.Get(ical.PropUID); uid == nil || uid.Value != "inbox-only" {
+		t.Errorf("UID = %v", uid)
+	}
+	if seq := vevent.Props.Get(ical.PropSequence); seq == nil || seq.Value != "2" {
+		t.Errorf("SEQUENCE = %v, want the request's", seq)
+	}
+	if len(mb.removedPaths) != 1 || mb.removedPaths[0] != "/home/inbox/m2.ics" {
+		t.Errorf("removed %v, want the answered message", mb.removedPaths)
+	}
+}
+
+func TestRespondToInvitation_NotInvited(t *testing.T) {
+	mb := newSchedulingBackend()
+	mb.scheduling.addresses = []string{"mailto:someone-else@icloud.com"}
+	invitation := makeInvitation(t, "/home/work/pending.ics", "pending", "NEEDS-ACTION", "")
+	mb.getResult = &invitation
+	c := NewClientWithBackend(mb)
+
+	if err := c.RespondToInvitation(context.Background(), "/home/work/pending.ics", InvitationResponse{Status: PartStatAccepted}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(mb.putPaths) != 0 {
+		t.Error("nothing should be written")
+	}
+}
+
+func TestRespondToInvitation_Conflict(t *testing.T) {
+	mb := newSchedulingBackend()
+	invitation := makeInvitation(t, "/home/work/pending.ics", "pending", "NEEDS-ACTION", "")
+	mb.getResult = &invitation
+	c := NewClientWithBackend(mb)
+
+	err := c.RespondToInvitation(context.Background(), "/home/work/pending.ics", InvitationResponse{Status: PartStatAccepted, ETag: "stale"})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("err = %v, want *ConflictError", err)
+	}
+}
+
+func TestValidateInvitationResponse(t *testing.T) {
+	for _, status := range []string{PartStatAccepted, PartStatDeclined, PartStatTentative} {
+		if err := ValidateInvitationResponse(status); err != nil {
+			t.Errorf("%s: unexpected error %v", status, err)
+		}
+	}
+	for _, status := range []string{PartStatNeedsAction, "accepted", ""} {
+		if err := ValidateInvitationResponse(status); err == nil {
+			t.Errorf("%q: expected error", status)
+		}
+	}
+}
+
+// makeOrganizedEvent returns an event organized by me@icloud.com that
+// alice@example.com is invited to with SCHEDULE-AGENT=CLIENT and
+// bob@example.com through the server.
+func makeOrganizedEvent(t *testing.T, path string) extcaldav.CalendarObject {
+	t.Helper()
+	cal := decodeCalendar(t, `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//test//EN
+BEGIN:VEVENT
+UID:planning
+DTSTAMP:20250301T000000Z
+DTSTART:20250310T090000Z
+DTEND:20250310T100000Z
+SUMMARY:Planning
+SEQUENCE:1
+ORGANIZER:mailto:me@icloud.com
+ATTENDEE;PARTSTAT=NEEDS-ACTION;SCHEDULE-AGENT=CLIENT:mailto:alice@example.com
+ATTENDEE;PARTSTAT=NEEDS-ACTION:mailto:bob@example.com
+BEGIN:VALARM
+ACTION:DISPLAY
+TRIGGER:-PT15M
+END:VALARM
+END:VEVENT
+END:VCALENDAR`)
+	return extcaldav.CalendarObject{Path: path, ETag: `"etag-1"`, Data: cal}
+}
+
+func TestCreateEvent_SendsRequestToClientScheduledAttendees(t *testing.T) {
+	mb := newSchedulingBackend()
+	c := NewClientWithBackend(mb)
+
+	_, err := c.CreateEvent(context.Background(), "/home/work/", &Event{
+		Title:     "Planning",
+		StartTime: time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC),
+		Attendees: []Attendee{{Email: "alice@example.com", ScheduleAgent: "client"}, {Email: "bob@example.com"}},
+		Alarms:    []Alarm{{Action: "DISPLAY", Trigger: "-PT15M"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	vevent := putEvent(t, mb)
+	if agent := attendeeProp(t, vevent, "mailto:alice@example.com").Params.Get(paramScheduleAgent); agent != ScheduleAgentClient {
+		t.Errorf("SCHEDULE-AGENT = %q, want CLIENT", agent)
+	}
+	if mb.outboxPath != "/home/outbox/" || mb.outboxFrom != "mailto:me@icloud.com" {
+		t.Errorf("posted to %s from %s", mb.outboxPath, mb.outboxFrom)
+	}
+	if len(mb.outboxTo) != 1 || mb.outboxTo[0] != "mailto:alice@example.com" {
+		t.Errorf("recipients = %v, want only the client-scheduled attendee", mb.outboxTo)
+	}
+	if method := mb.outboxCal.Props.Get(ical.PropMethod); method == nil || method.Value != "REQUEST" {
+		t.Errorf("METHOD = %v, want REQUEST", method)
+	}
+	if len(mb.outboxCal.Children[0].Children) != 0 {
+		t.Error("alarms should be left out of the request")
+	}
+	if len(vevent.Children) != 1 {
+		t.Error("the stored event should keep its alarm")
+	}
+}
+
+func TestCreateEvent_ClientScheduledWithoutOutbox(t *testing.T) {
+	mb := newSchedulingBackend()
+	mb.scheduling.outbox = ""
+	c := NewClientWithBackend(mb)
+
+	_, err := c.CreateEvent(context.Background(), "/home/work/", &Event{
+		Title:     "Planning",
+		StartTime: time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC),
+		Attendees: []Attendee{{Email: "alice@example.com", ScheduleAgent: ScheduleAgentClient}},
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(mb.putPaths) != 0 {
+		t.Error("the event should not be created when its invitations cannot be sent")
+	}
+}
+
+func TestCreateEvent_InvalidScheduleAgent(t *testing.T) {
+	c := NewClientWithBackend(newSchedulingBackend())
+
+	_, err := c.CreateEvent(context.Background(), "/home/work/", &Event{
+		Title:     "Planning",
+		StartTime: time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC),
+		Attendees: []Attendee{{Email: "alice@example.com", ScheduleAgent: "EMAIL"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "scheduleAgent") {
+		t.Fatalf("err = %v, want an invalid scheduleAgent error", err)
+	}
+}
+
+func TestUpdateEvent_CancelsRemovedClientScheduledAttendee(t *testing.T) {
+	mb := newSchedulingBackend()
+	event := makeOrganizedEvent(t, "/home/work/planning.ics")
+	mb.getResult = &event
+	c := NewClientWithBackend(mb)
+
+	_, err := c.UpdateEvent(context.Background(), "/home/work/planning.ics", &EventUpdate{
+		Attendees: &AttendeeChanges{Remove: []string{"alice@example.com"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(mb.outboxTo) != 1 || mb.outboxTo[0] != "mailto:alice@example.com" {
+		t.Fatalf("recipients = %v, want the removed attendee", mb.outboxTo)
+	}
+	if method := mb.outboxCal.Props.Get(ical.PropMethod); method == nil || method.Value != "CANCEL" {
+		t.Errorf("METHOD = %v, want CANCEL", method)
+	}
+	vevent := mb.outboxCal.Children[0]
+	if status := vevent.Props.Get(ical.PropStatus); status == nil || status.Value != EventCancelled {
+		t.Errorf("STATUS = %v, want CANCELLED", status)
+	}
+	if seq := vevent.Props.Get(ical.PropSequence); seq == nil || seq.Value != "2" {
+		t.Errorf("SEQUENCE = %v, want 2", seq)
+	}
+}
+
+func TestUpdateEvent_SendsRequestToClientScheduledAttendees(t *testing.T) {
+	mb := newSchedulingBackend()
+	event := makeOrganizedEvent(t, "/home/work/planning.ics")
+	mb.getResult = &event
+	c := NewClientWithBackend(mb)
+
+	title := "Planning (moved)"
+	if _, err := c.UpdateEvent(context.Background(), "/home/work/planning.ics", &EventUpdate{Title: &title}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mb.outboxTo) != 1 || mb.outboxTo[0] != "mailto:alice@example.com" {
+		t.Fatalf("recipients = %v, want the client-scheduled attendee", mb.outboxTo)
+	}
+	if summary := mb.outboxCal.Children[0].Props.Get(ical.PropSummary); summary == nil || summary.Value != title {
+		t.Errorf("SUMMARY = %v, want the updated title", summary)
+	}
+}
+
+func TestDeleteEvent_CancelsClientScheduledAttendees(t *testing.T) {
+	mb := newSchedulingBackend()
+	event := makeOrganizedEvent(t, "/home/work/planning.ics")
+	mb.getResult = &event
+	c := NewClientWithBackend(mb)
+
+	if err := c.DeleteEvent(context.Background(), "/home/work/planning.ics", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mb.removedPaths) != 1 {
+		t.Errorf("removed %v, want the event", mb.removedPaths)
+	}
+	if len(mb.outboxTo) != 1 || mb.outboxTo[0] != "mailto:alice@example.com" {
+		t.Fatalf("recipients = %v, want the client-scheduled attendee", mb.outboxTo)
+	}
+	if method := mb.outboxCal.Props.Get(ical.PropMethod); method == nil || method.Value != "CANCEL" {
+		t.Errorf("METHOD = %v, want CANCEL", method)
+	}
+
+	// A failure to send is reported, after the event is deleted
+	mb.outboxErr = errors.New("503 Service Unavailable")
+	if err := c.DeleteEvent(context.Background(), "/home/work/planning.ics", ""); err == nil || !strings.Contains(err.Error(), "deleted") {
+		t.Errorf("err = %v, want a send failure after deleting", err)
+	}
+}
+
+func TestDeleteEventObject_SendsNoCancellation(t *testing.T) {
+	mb := newSchedulingBackend()
+	event := makeOrganizedEvent(t, "/home/work/planning.ics")
+	mb.getResult = &event
+	c := NewClientWithBackend(mb)
+
+	if err := c.DeleteEventObject(context.Background(), "/home/work/planning.ics", "etag-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mb.removedPaths) != 1 || mb.lastRemoveCond.ifMatch != "etag-1" {
+		t.Errorf("removed %v with If-Match %q, want the event with etag-1", mb.removedPaths, mb.lastRemoveCond.ifMatch)
+	}
+	if mb.outboxCal != nil || len(mb.outboxTo) != 0 {
+		t.Errorf("posted to %v, want nothing sent to the outbox", mb.outboxTo)
+	}
+}
+
+func TestListInvitations_ScheduleAgent(t *testing.T) {
+	mb := newSchedulingBackend()
+	mb.scheduling.inbox = ""
+	client := makeInvitation(t, "/home/work/client.ics", "client", "NEEDS-ACTION", "")
+	client.Data.Children[0].Props.Get(ical.PropOrganizer).Params.Set(paramScheduleAgent, ScheduleAgentClient)
+	mb.queryByPath = map[string][]extcaldav.CalendarObject{
+		"/home/work/": {client, makeInvitation(t, "/home/work/server.ics", "server", "NEEDS-ACTION", "")},
+	}
+	c := NewClientWithBackend(mb)
+
+	invitations, err := c.ListInvitations(context.Background(), time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	agents := map[string]string{}
+	for _, inv := range invitations {
+		agents[inv.ID] = inv.ScheduleAgent
+	}
+	if agents["client"] != ScheduleAgentClient || agents["server"] != "" {
+		t.Errorf("schedule agents = %v, want CLIENT only for the client-scheduled invitation", agents)
+	}
+}
+
+func TestRespondToInvitation_ClientScheduled(t *testing.T) {
+	mb := newSchedulingBackend()
+	invitation := makeInvitation(t, "/home/work/pending.ics", "pending", "NEEDS-ACTION", "")
+	invitation.Data.Children[0].Props.Get(ical.PropOrganizer).Params.Set(paramScheduleAgent, ScheduleAgentClient)
+	mb.getResult = &invitation
+	c := NewClientWithBackend(mb)
+
+	if err := c.RespondToInvitation(context.Background(), "/home/work/pending.ics", InvitationResponse{Status: PartStatTentative}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mb.lastPutPath != "/home/work/pending.ics" {
+		t.Errorf("put %q, want the event", mb.lastPutPath)
+	}
+	if mb.outboxCal == nil || len(mb.outboxTo) != 1 || mb.outboxTo[0] != "mailto:boss@example.com" {
+		t.Fatalf("recipients = %v, want the organizer", mb.outboxTo)
+	}
+	if method := mb.outboxCal.Props.Get(ical.PropMethod); method == nil || method.Value != "REPLY" {
+		t.Errorf("METHOD = %v, want REPLY", method)
+	}
+}
+
+func TestRespondToInvitation_ClientScheduledWithoutOutbox(t *testing.T) {
+	mb := newSchedulingBackend()
+	mb.scheduling.outbox = ""
+	invitation := makeInvitation(t, "/home/work/pending.ics", "pending", "NEEDS-ACTION", "")
+	attendees := invitation.Data.Children[0].Props[ical.PropAttendee]
+	attendees[1].Params.Set(paramScheduleAgent, ScheduleAgentClient)
+	mb.getResult = &invitation
+	c := NewClientWithBackend(mb)
+
+	if err := c.RespondToInvitation(context.Background(), "/home/work/pending.ics", InvitationResponse{Status: PartStatAccepted}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(mb.putPaths) != 0 {
+		t.Error("nothing should be written when the reply cannot be sent")
+	}
+}
+
+func TestRespondToInvitation_NoScheduling(t *testing.T) {
+	mb := newSchedulingBackend()
+	invitation := makeInvitation(t, "/home/work/pending.ics", "pending", "NEEDS-ACTION", "")
+	invitation.Data.Children[0].Props.Get(ical.PropOrganizer).Params.Set(paramScheduleAgent, "none")
+	mb.getResult = &invitation
+	c := NewClientWithBackend(mb)
+
+	if err := c.RespondToInvitation(context.Background(), "/home/work/pending.ics", InvitationResponse{Status: PartStatAccepted}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mb.lastPutPath != "/home/work/pending.ics" {
+		t.Errorf("put %q, want the event", mb.lastPutPath)
+	}
+	if mb.outboxCal != nil {
+		t.Error("no reply should be sent with SCHEDULE-AGENT=NONE")
+	}
+}
+
+func TestValidateScheduleAgent(t *testing.T) {
+	for _, agent := range []string{"", ScheduleAgentServer, ScheduleAgentClient, ScheduleAgentNone, "client"} {
+		if err := ValidateScheduleAgent(agent); err != nil {
+			t.Errorf("%q: unexpected error %v", agent, err)
+		}
+	}
+	if err := ValidateScheduleAgent("EMAIL"); err == nil {
+		t.Error("expected an error for EMAIL")
+	}
+}
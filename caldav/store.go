@@ -0,0 +1,303 @@
+package caldav
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav/caldav"
+	bolt "go.etcd.io/bbolt"
+)
+
+// storeFile is the name of the database file in the cache directory.
+const storeFile = "calendar-cache.db"
+
+// Bucket and key names. Each account has a bucket holding its metadata keys
+// and an events bucket, which holds a bucket per calendar with the sync
+// state and an objects bucket of stored events by path.
+var (
+	accountsBucket = []byte("accounts")
+	eventsBucket   = []byte("events")
+	objectsBucket  = []byte("objects")
+
+	identityKey  = []byte("identity")
+	principalKey = []byte("principal")
+	homeSetKey   = []byte("homeSet")
+	calendarsKey = []byte("calendars")
+	stateKey     = []byte("state")
+)
+
+// Store keeps calendars, events and sync tokens on disk, so the event cache
+// survives restarts and reads can be answered while the server is
+// unreachable. It holds the data of several accounts in one bbolt database.
+// The data is not encrypted; the file is only readable by its owner.
+type Store struct {
+	db *bolt.DB
+}
+
+// OpenStore opens the store in dir, creating the directory and database if
+// needed. Only one process can have the store open at a time.
+func OpenStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	db, err := bolt.Open(filepath.Join(dir, storeFile), 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache database: %w", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(accountsBucket)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to initialize cache database: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Account returns the part of the store holding the data of the named
+// account. identity identifies the account on the server, such as its email
+// and server URL; data stored under another identity is discarded, so a
+// reconfigured account never sees the previous one's events.
+func (s *Store) Account(name, identity string) (*AccountStore, error) {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		accounts := tx.Bucket(accountsBucket)
+		if b := accounts.Bucket([]byte(name)); b != nil && string(b.Get(identityKey)) != identity {
+			if err := accounts.DeleteBucket([]byte(name)); err != nil {
+				return err
+			}
+		}
+		b, err := accounts.CreateBucketIfNotExists([]byte(name))
+		if err != nil {
+			return err
+		}
+		if _, err := b.CreateBucketIfNotExists(eventsBucket); err != nil {
+			return err
+		}
+		return b.Put(identityKey, []byte(identity))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache for account %s: %w", name, err)
+	}
+	return &AccountStore{db: s.db, name: []byte(name)}, nil
+}
+
+// AccountStore is the part of a Store holding one account's data.
+type AccountStore struct {
+	db   *bolt.DB
+	name []byte
+}
+
+// storedCalendars is the calendar list of an account as last listed.
+type storedCalendars struct {
+	Calendars []Calendar
+	Listed    time.Time
+}
+
+// storedState is the sync state of a calendar.
+type storedState struct {
+	Token  string
+	Synced time.Time
+}
+
+// storedEvent is an event as stored: its VEVENT components, the master
+// first, which are parsed again on load so that nothing is lost.
+type storedEvent struct {
+	ETag       string
+	Components []*ical.Component
+}
+
+func (s *AccountStore) view(fn func(account *bolt.Bucket) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		return fn(tx.Bucket(accountsBucket).Bucket(s.name))
+	})
+}
+
+func (s *AccountStore) update(fn func(account *bolt.Bucket) error) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return fn(tx.Bucket(accountsBucket).Bucket(s.name))
+	})
+}
+
+// HomeSet returns the stored principal and calendar home set, if any.
+func (s *AccountStore) HomeSet() (principal, homeSet string, err error) {
+	err = s.view(func(account *bolt.Bucket) error {
+		principal = string(account.Get(principalKey))
+		homeSet = string(account.Get(homeSetKey))
+		return nil
+	})
+	return principal, homeSet, err
+}
+
+// SaveHomeSet stores the account's principal and calendar home set.
+func (s *AccountStore) SaveHomeSet(principal, homeSet string) error {
+	return s.update(func(account *bolt.Bucket) error {
+		if err := account.Put(principalKey, []byte(principal)); err != nil {
+			return err
+		}
+		return account.Put(homeSetKey, []byte(homeSet))
+	})
+}
+
+// calendars returns the stored calendar list, or nil if there is none.
+func (s *AccountStore) calendars() (*storedCalendars, error) {
+	var stored *storedCalendars
+	err := s.view(func(account *bolt.Bucket) error {
+		data := account.Get(calendarsKey)
+		if data == nil {
+			return nil
+		}
+		stored = &storedCalendars{}
+		return json.Unmarshal(data, stored)
+	})
+	return stored, err
+}
+
+func (s *AccountStore) saveCalendars(stored *storedCalendars) error {
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return s.update(func(account *bolt.Bucket) error {
+		return account.Put(calendarsKey, data)
+	})
+}
+
+// loadCalendar returns the stored events of the calendar with the given
+// cache key by path, and its sync state. It returns nil events if the
+// calendar was never stored.
+func (s *AccountStore) loadCalendar(key string) (map[string]Event, storedState, error) {
+	var events map[string]Event
+	var state storedState
+	err := s.view(func(account *bolt.Bucket) error {
+		cal := account.Bucket(eventsBucket).Bucket([]byte(key))
+		if cal == nil {
+			return nil
+		}
+		if err := json.Unmarshal(cal.Get(stateKey), &state); err != nil {
+			return err
+		}
+		events = make(map[string]Event)
+		return cal.Bucket(objectsBucket).ForEach(func(k, v []byte) error {
+			var stored storedEvent
+			if err := json.Unmarshal(v, &stored); err != nil {
+				return err
+			}
+			event, err := stored.event(string(k))
+			if err != nil {
+				return err
+			}
+			events[event.Path] = *event
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, storedState{}, err
+	}
+	return events, state, nil
+}
+
+// saveSync applies the result of a sync from token to the stored calendar
+// with the given cache key, replacing all its events for a full sync.
+func (s *AccountStore) saveSync(key, token string, result *SyncResult, synced time.Time) error {
+	state := storedState{Token: result.Token, Synced: synced}
+	return s.update(func(account *bolt.Bucket) error {
+		calendars := account.Bucket(eventsBucket)
+		if result.Full && calendars.Bucket([]byte(key)) != nil {
+			if err := calendars.DeleteBucket([]byte(key)); err != nil {
+				return err
+			}
+		}
+		if !result.Full {
+			// Changes since another token than the stored one, for example
+			// after a failed save, leave the stored events incomplete
+			var stored storedState
+			if cal := calendars.Bucket([]byte(key)); cal != nil {
+				_ = json.Unmarshal(cal.Get(stateKey), &stored)
+			}
+			if stored.Token == "" || stored.Token != token {
+				state.Token = ""
+			}
+		}
+		cal, err := calendars.CreateBucketIfNotExists([]byte(key))
+		if err != nil {
+			return err
+		}
+		objects, err := cal.CreateBucketIfNotExists(objectsBucket)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range result.Deleted {
+			if err := objects.Delete([]byte(p)); err != nil {
+				return err
+			}
+		}
+		for _, event := range result.Changed {
+			stored, ok := newStoredEvent(event)
+			if !ok {
+				// The event cannot be restored, so the next run must sync
+				// the calendar from scratch
+				state.Token = ""
+				continue
+			}
+			data, err := json.Marshal(stored)
+			if err != nil {
+				return err
+			}
+			if err := objects.Put([]byte(event.Path), data); err != nil {
+				return err
+			}
+		}
+
+		data, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+		return cal.Put(stateKey, data)
+	})
+}
+
+// deleteCalendar removes the calendar with the given cache key and its
+// events.
+func (s *AccountStore) deleteCalendar(key string) error {
+	return s.update(func(account *bolt.Bucket) error {
+		calendars := account.Bucket(eventsBucket)
+		if calendars.Bucket([]byte(key)) == nil {
+			return nil
+		}
+		return calendars.DeleteBucket([]byte(key))
+	})
+}
+
+// newStoredEvent returns the stored form of event. It fails for events that
+// were not read from the server, which lack their components.
+func newStoredEvent(event Event) (storedEvent, bool) {
+	if event.Raw == nil {
+		return storedEvent{}, false
+	}
+	stored := storedEvent{ETag: event.ETag, Components: []*ical.Component{event.Raw}}
+	for _, o := range event.Overrides {
+		if o.Raw == nil {
+			return storedEvent{}, false
+		}
+		stored.Components = append(stored.Components, o.Raw)
+	}
+	return stored, true
+}
+
+// event parses the stored event at path.
+func (e storedEvent) event(path string) (*Event, error) {
+	cal := ical.NewCalendar()
+	cal.Children = e.Components
+	return parseCalendarObject(&caldav.CalendarObject{Path: path, ETag: e.ETag, Data: cal})
+}
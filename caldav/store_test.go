@@ -0,0 +1,178 @@
+package caldav
+
+import (
+	"testing"
+	"time"
+
+	extcaldav "github.com/emersion/go-webdav/caldav"
+)
+
+func openTestStore(t *testing.T, dir string) *Store {
+	t.Helper()
+	store, err := OpenStore(dir)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func openTestAccount(t *testing.T, store *Store, name string) *AccountStore {
+	t.Helper()
+	account, err := store.Account(name, name+"@icloud.com")
+	if err != nil {
+		t.Fatalf("failed to open account: %v", err)
+	}
+	return account
+}
+
+// seriesEvent returns a weekly series in New York time with a moved
+// occurrence and a property the Event fields do not map.
+func seriesEvent(t *testing.T) Event {
+	t.Helper()
+	event, err := parseCalendarObject(&extcaldav.CalendarObject{Path: "/cal/series.ics", ETag: `"etag-1"`, Data: decodeCalendar(t, `
+BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//test//EN
+BEGIN:VEVENT
+UID:series
+DTSTAMP:20250101T080000Z
+DTSTART;TZID=America/New_York:20250303T090000
+DTEND;TZID=America/New_York:20250303T093000
+RRULE:FREQ=WEEKLY;COUNT=4
+SUMMARY:Team sync
+X-APPLE-TRAVEL-ADVISORY-BEHAVIOR:AUTOMATIC
+END:VEVENT
+BEGIN:VEVENT
+UID:series
+DTSTAMP:20250101T080000Z
+RECURRENCE-ID;TZID=America/New_York:20250310T090000
+DTSTART;TZID=America/New_York:20250310T110000
+DTEND;TZID=America/New_York:20250310T113000
+SUMMARY:Team sync (moved)
+END:VEVENT
+END:VCALENDAR`)})
+	if err != nil {
+		t.Fatalf("invalid test event: %v", err)
+	}
+	return *event
+}
+
+func TestStore_SaveAndLoadCalendar(t *testing.T) {
+	dir := t.TempDir()
+	synced := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	obj := uidObject(t, "/cal/other.ics", "other")
+	other, _ := parseCalendarObject(&obj)
+
+	account := openTestAccount(t, openTestStore(t, dir), "work")
+	if err := account.saveSync("/cal/", "", &SyncResult{Token: "t1", Full: true, Changed: []Event{seriesEvent(t), *other}}, synced); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := account.saveSync("/cal/", "t1", &SyncResult{Token: "t2", Deleted: []string{"/cal/other.ics"}}, synced.Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events, state, err := account.loadCalendar("/cal/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state.Token != "t2" || !state.Synced.Equal(synced.Add(time.Hour)) {
+		t.Errorf("state = %+v, want token t2 synced an hour later", state)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+
+	event := events["/cal/series.ics"]
+	if event.ID != "series" || event.ETag != "etag-1" || event.Timezone != "America/New_York" {
+		t.Errorf("event = %+v", event)
+	}
+	if event.StartTime.Location().String() != "America/New_York" {
+		t.Errorf("start time zone = %s, want America/New_York", event.StartTime.Location())
+	}
+	if len(event.Overrides) != 1 || event.Overrides[0].Title != "Team sync (moved)" {
+		t.Errorf("overrides = %+v", event.Overrides)
+	}
+	if event.Raw == nil || event.Raw.Props.Get("X-APPLE-TRAVEL-ADVISORY-BEHAVIOR") == nil {
+		t.Error("expected the raw component to keep unmapped properties")
+	}
+
+	if events, _, err := account.loadCalendar("/other/"); err != nil || events != nil {
+		t.Errorf("unknown calendar = %v, %v, want nothing", events, err)
+	}
+}
+
+func TestStore_PersistsAcrossOpens(t *testing.T) {
+	dir := t.TempDir()
+	store := openTestStore(t, dir)
+	account := openTestAccount(t, store, "work")
+	if err := account.SaveHomeSet("/123/principal/", "/123/calendars/"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := account.saveCalendars(&storedCalendars{Calendars: []Calendar{{Path: "/123/calendars/work/", Name: "Work", SupportedComponents: []string{"VEVENT"}}}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	account = openTestAccount(t, openTestStore(t, dir), "work")
+	principal, homeSet, err := account.HomeSet()
+	if err != nil || principal != "/123/principal/" || homeSet != "/123/calendars/" {
+		t.Errorf("HomeSet() = %q, %q, %v", principal, homeSet, err)
+	}
+	list, err := account.calendars()
+	if err != nil || list == nil || len(list.Calendars) != 1 || list.Calendars[0].Name != "Work" {
+		t.Errorf("calendars() = %+v, %v", list, err)
+	}
+}
+
+func TestStore_AccountsAreSeparate(t *testing.T) {
+	store := openTestStore(t, t.TempDir())
+	work := openTestAccount(t, store, "work")
+	if err := work.SaveHomeSet("/1/principal/", "/1/calendars/"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, homeSet, _ := openTestAccount(t, store, "home").HomeSet(); homeSet != "" {
+		t.Errorf("home account sees home set %q of the work account", homeSet)
+	}
+
+	// The same account name for another login starts empty
+	work, err := store.Account("work", "someone-else@icloud.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, homeSet, _ := work.HomeSet(); homeSet != "" {
+		t.Errorf("reconfigured account sees home set %q", homeSet)
+	}
+}
+
+func TestStore_IncompleteSyncResetsToken(t *testing.T) {
+	account := openTestAccount(t, openTestStore(t, t.TempDir()), "work")
+	synced := time.Now()
+
+	// Changes since a token the store does not have
+	if err := account.saveSync("/cal/", "t1", &SyncResult{Token: "t2", Changed: []Event{seriesEvent(t)}}, synced); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, state, _ := account.loadCalendar("/cal/"); state.Token != "" {
+		t.Errorf("token = %q, want none after an incremental sync into an empty store", state.Token)
+	}
+
+	// Events without their components cannot be restored
+	if err := account.saveSync("/cal/", "", &SyncResult{Token: "t3", Full: true, Changed: []Event{{ID: "x", Path: "/cal/x.ics"}}}, synced); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	events, state, _ := account.loadCalendar("/cal/")
+	if state.Token != "" || len(events) != 0 {
+		t.Errorf("token = %q, events = %d, want a full sync next time", state.Token, len(events))
+	}
+
+	if err := account.deleteCalendar("/cal/"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if events, _, _ := account.loadCalendar("/cal/"); events != nil {
+		t.Error("expected the calendar to be gone")
+	}
+}
@@ -0,0 +1,216 @@
+package caldav
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/emersion/go-webdav/caldav"
+)
+
+// SyncResult holds the changes to a calendar's events since a sync token.
+type SyncResult struct {
+	// Token identifies the calendar's current state; pass it to the next
+	// SyncCalendar call. It is empty if the server offers no way to detect
+	// changes, in which case every sync is a full one.
+	Token string
+	// Full is set when Changed holds every event in the calendar rather than
+	// the changes since the token, and events missing from it are gone.
+	Full bool
+	// Changed holds the events added or changed since the token.
+	Changed []Event
+	// Deleted holds the paths of calendar objects removed since the token.
+	Deleted []string
+}
+
+// ctagTokenPrefix marks sync tokens that are really a ctag, for servers
+// without sync-collection support.
+const ctagTokenPrefix = "ctag:"
+
+// multiGetBatch is the number of events fetched per calendar-multiget
+// REPORT.
+const multiGetBatch = 100
+
+// maxSyncRounds bounds the sync-collection requests of one sync when the
+// server keeps truncating its answers.
+const maxSyncRounds = 20
+
+// SyncCalendar returns the changes to the events in the calendar at
+// calendarPath since token, or all of them for an empty token. It uses the
+// sync-collection REPORT (RFC 6578) and fetches the changed events with
+// calendar-multiget. Servers without sync-collection support fall back to
+// the calendar's getctag: if it is unchanged nothing is fetched, otherwise
+// the whole calendar is. A token the server no longer accepts also leads to
+// a full sync.
+func (c *Client) SyncCalendar(ctx context.Context, calendarPath, token string) (*SyncResult, error) {
+	if !strings.HasPrefix(token, ctagTokenPrefix) {
+		result, err := c.syncCollection(ctx, calendarPath, token)
+		if err == nil {
+			return result, nil
+		}
+		if !syncUnsupported(err) {
+			return nil, fmt.Errorf("failed to sync calendar: %w", err)
+		}
+		slog.Debug("sync-collection not supported, falling back to getctag", "path", calendarPath, "error", err)
+		token = ""
+	}
+
+	result, err := c.syncByCTag(ctx, calendarPath, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sync calendar: %w", err)
+	}
+	return result, nil
+}
+
+// syncCollection syncs the calendar with sync-collection REPORTs, following
+// truncated answers.
+func (c *Client) syncCollection(ctx context.Context, calendarPath, token string) (*SyncResult, error) {
+	full := token == ""
+	resp, err := c.backend.SyncCollection(ctx, calendarPath, token)
+	if !full && invalidSyncToken(err) {
+		slog.Debug("sync token no longer valid, syncing the whole calendar", "path", calendarPath)
+		full = true
+		resp, err = c.backend.SyncCollection(ctx, calendarPath, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Later rounds override earlier ones for members that changed again
+	state := make(map[string]bool) // path -> deleted
+	var order []string
+	for round := 1; ; round++ {
+		for _, p := range resp.changed {
+			if _, seen := state[p]; !seen {
+				order = append(order, p)
+			}
+			state[p] = false
+		}
+		for _, p := range resp.deleted {
+			if _, seen := state[p]; !seen {
+				order = append(order, p)
+			}
+			state[p] = true
+		}
+		if !resp.truncated || round == maxSyncRounds {
+			break
+		}
+		if resp, err = c.backend.SyncCollection(ctx, calendarPath, resp.token); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &SyncResult{Token: resp.token, Full: full}
+	var changed []string
+	for _, p := range order {
+		if state[p] {
+			result.Deleted = append(result.Deleted, p)
+		} else {
+			changed = append(changed, p)
+		}
+	}
+	if result.Changed, err = c.multiGetEvents(ctx, calendarPath, changed); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// syncByCTag compares the calendar's getctag with the one in token and
+// fetches the whole calendar if it changed.
+func (c *Client) syncByCTag(ctx context.Context, calendarPath, token string) (*SyncResult, error) {
+	ctag, err := c.backend.CalendarCTag(ctx, calendarPath)
+	if err != nil {
+		return nil, err
+	}
+	if ctag != "" && ctagTokenPrefix+ctag == token {
+		return &SyncResult{Token: token}, nil
+	}
+
+	query := &caldav.CalendarQuery{
+		CompRequest: caldav.CalendarCompRequest{
+			Name:     "VCALENDAR",
+			AllProps: true,
+			AllComps: true,
+		},
+		CompFilter: caldav.CompFilter{
+			Name:  "VCALENDAR",
+			Comps: []caldav.CompFilter{{Name: "VEVENT"}},
+		},
+	}
+	objects, err := c.backend.QueryCalendar(ctx, calendarPath, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SyncResult{Full: true, Changed: c.parseEvents(objects)}
+	if ctag != "" {
+		result.Token = ctagTokenPrefix + ctag
+	}
+	return result, nil
+}
+
+// multiGetEvents fetches the events at paths with calendar-multiget
+// REPORTs. Members that are not events, such as tasks, are left out.
+func (c *Client) multiGetEvents(ctx context.Context, calendarPath string, paths []string) ([]Event, error) {
+	var events []Event
+	for start := 0; start < len(paths); start += multiGetBatch {
+		end := min(start+multiGetBatch, len(paths))
+		objects, err := c.backend.MultiGetCalendar(ctx, calendarPath, &caldav.CalendarMultiGet{
+			Paths: paths[start:end],
+			CompRequest: caldav.CalendarCompRequest{
+				Name:     "VCALENDAR",
+				AllProps: true,
+				AllComps: true,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch changed events: %w", err)
+		}
+		events = append(events, c.parseEvents(objects)...)
+	}
+	return events, nil
+}
+
+// parseEvents parses the calendar objects holding events, skipping other
+// objects and those that fail to parse.
+func (c *Client) parseEvents(objects []caldav.CalendarObject) []Event {
+	events := make([]Event, 0, len(objects))
+	for i := range objects {
+		obj := &objects[i]
+		if obj.Data == nil || len(obj.Data.Events()) == 0 {
+			continue
+		}
+		event, err := parseCalendarObject(obj)
+		if err != nil {
+			slog.Warn("skipping unparseable calendar object", "path", obj.Path, "error", err)
+			continue
+		}
+		events = append(events, *event)
+	}
+	return events
+}
+
+// invalidSyncToken reports whether err is the server refusing a sync token
+// it no longer knows (RFC 6578 section 3.2, DAV:valid-sync-token).
+func invalidSyncToken(err error) bool {
+	status := httpStatus(err)
+	return (status == http.StatusForbidden || status == http.StatusConflict) &&
+		strings.Contains(err.Error(), "valid-sync-token")
+}
+
+// syncUnsupported reports whether err is the server refusing the
+// sync-collection REPORT itself: the method or report is not implemented, or
+// the calendar does not offer it (RFC 3253 DAV:supported-report). Other
+// failures, such as throttling or an outage, must not trigger the fallback,
+// which downloads the whole calendar and stops using sync-collection for it.
+func syncUnsupported(err error) bool {
+	switch httpStatus(err) {
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return true
+	case http.StatusForbidden, http.StatusConflict:
+		return strings.Contains(err.Error(), "supported-report")
+	}
+	return false
+}
@@ -0,0 +1,183 @@
+package caldav
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	extcaldav "github.com/emersion/go-webdav/caldav"
+)
+
+// taskObject returns a calendar object holding a VTODO.
+func taskObject(t *testing.T, path string) extcaldav.CalendarObject {
+	t.Helper()
+	return extcaldav.CalendarObject{Path: path, Data: decodeCalendar(t, `
+BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//test//EN
+BEGIN:VTODO
+UID:task-1
+DTSTAMP:20250101T080000Z
+SUMMARY:Buy milk
+END:VTODO
+END:VCALENDAR`)}
+}
+
+func TestSyncCalendar_Incremental(t *testing.T) {
+	mb := &mockBackend{
+		syncs: []*syncResponse{{
+			token:   "token-2",
+			changed: []string{"/cal/a.ics", "/cal/task.ics"},
+			deleted: []string{"/cal/b.ics"},
+		}},
+		multiGetResult: []extcaldav.CalendarObject{
+			uidObject(t, "/cal/a.ics", "a"),
+			taskObject(t, "/cal/task.ics"),
+		},
+	}
+	c := NewClientWithBackend(mb)
+
+	result, err := c.SyncCalendar(context.Background(), "/cal/", "token-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Token != "token-2" || result.Full {
+		t.Errorf("Token = %q, Full = %v, want token-2 and an incremental sync", result.Token, result.Full)
+	}
+	if len(result.Changed) != 1 || result.Changed[0].ID != "a" || result.Changed[0].Path != "/cal/a.ics" {
+		t.Errorf("Changed = %+v, want only the event a", result.Changed)
+	}
+	if !reflect.DeepEqual(result.Deleted, []string{"/cal/b.ics"}) {
+		t.Errorf("Deleted = %v", result.Deleted)
+	}
+	if !reflect.DeepEqual(mb.syncTokens, []string{"token-1"}) {
+		t.Errorf("sync tokens = %v", mb.syncTokens)
+	}
+}
+
+func TestSyncCalendar_InvalidTokenSyncsEverything(t *testing.T) {
+	mb := &mockBackend{
+		syncErrs: []error{&statusError{code: http.StatusForbidden, body: `<D:error xmlns:D="DAV:"><D:valid-sync-token/></D:error>`}},
+		syncs:    []*syncResponse{{token: "token-3", changed: []string{"/cal/a.ics"}}},
+		multiGetResult: []extcaldav.CalendarObject{
+			uidObject(t, "/cal/a.ics", "a"),
+		},
+	}
+	c := NewClientWithBackend(mb)
+
+	result, err := c.SyncCalendar(context.Background(), "/cal/", "expired")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Full || result.Token != "token-3" || len(result.Changed) != 1 {
+		t.Errorf("result = %+v, want a full sync", result)
+	}
+	if !reflect.DeepEqual(mb.syncTokens, []string{"expired", ""}) {
+		t.Errorf("sync tokens = %v, want a retry without token", mb.syncTokens)
+	}
+}
+
+func TestSyncCalendar_Truncated(t *testing.T) {
+	mb := &mockBackend{
+		syncs: []*syncResponse{
+			{token: "page-1", changed: []string{"/cal/a.ics", "/cal/b.ics"}, truncated: true},
+			{token: "page-2", deleted: []string{"/cal/b.ics"}},
+		},
+		multiGetResult: []extcaldav.CalendarObject{
+			uidObject(t, "/cal/a.ics", "a"),
+			uidObject(t, "/cal/b.ics", "b"),
+		},
+	}
+	c := NewClientWithBackend(mb)
+
+	result, err := c.SyncCalendar(context.Background(), "/cal/", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Token != "page-2" || !result.Full {
+		t.Errorf("Token = %q, Full = %v", result.Token, result.Full)
+	}
+	if len(result.Changed) != 1 || result.Changed[0].ID != "a" {
+		t.Errorf("Changed = %+v, want only a", result.Changed)
+	}
+	if !reflect.DeepEqual(result.Deleted, []string{"/cal/b.ics"}) {
+		t.Errorf("Deleted = %v, want b, deleted in the second round", result.Deleted)
+	}
+	if !reflect.DeepEqual(mb.multiGetPaths, [][]string{{"/cal/a.ics"}}) {
+		t.Errorf("multiget paths = %v, want only a", mb.multiGetPaths)
+	}
+}
+
+func TestSyncCalendar_MultiGetBatches(t *testing.T) {
+	var paths []string
+	for i := 0; i < multiGetBatch+1; i++ {
+		paths = append(paths, fmt.Sprintf("/cal/%d.ics", i))
+	}
+	mb := &mockBackend{syncs: []*syncResponse{{token: "t", changed: paths}}}
+	c := NewClientWithBackend(mb)
+
+	if _, err := c.SyncCalendar(context.Background(), "/cal/", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mb.multiGetPaths) != 2 || len(mb.multiGetPaths[0]) != multiGetBatch || len(mb.multiGetPaths[1]) != 1 {
+		t.Errorf("got %d multiget batches, want %d and 1 paths", len(mb.multiGetPaths), multiGetBatch)
+	}
+}
+
+func TestSyncCalendar_CTagFallback(t *testing.T) {
+	mb := &mockBackend{
+		syncErrs:    []error{&statusError{code: http.StatusNotImplemented}},
+		ctag:        "ctag-1",
+		queryResult: []extcaldav.CalendarObject{uidObject(t, "/cal/a.ics", "a")},
+	}
+	c := NewClientWithBackend(mb)
+
+	result, err := c.SyncCalendar(context.Background(), "/cal/", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Full || result.Token != "ctag:ctag-1" || len(result.Changed) != 1 {
+		t.Fatalf("result = %+v, want a full sync keyed by the ctag", result)
+	}
+
+	// An unchanged ctag fetches nothing, and sync-collection is not tried
+	// again
+	mb.lastQuery = nil
+	result, err = c.SyncCalendar(context.Background(), "/cal/", result.Token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Full || len(result.Changed) != 0 || result.Token != "ctag:ctag-1" {
+		t.Errorf("result = %+v, want no changes", result)
+	}
+	if mb.lastQuery != nil || len(mb.syncTokens) != 1 {
+		t.Errorf("query sent = %v, sync-collection requests = %d", mb.lastQuery != nil, len(mb.syncTokens))
+	}
+}
+
+func TestSyncCalendar_ThrottledDoesNotFallBack(t *testing.T) {
+	mb := &mockBackend{
+		syncErrs:    []error{&statusError{code: http.StatusServiceUnavailable}},
+		ctag:        "ctag-1",
+		queryResult: []extcaldav.CalendarObject{uidObject(t, "/cal/a.ics", "a")},
+	}
+	c := NewClientWithBackend(mb)
+
+	if _, err := c.SyncCalendar(context.Background(), "/cal/", "token-1"); httpStatus(err) != http.StatusServiceUnavailable {
+		t.Fatalf("expected the 503 to be returned, got %v", err)
+	}
+	if mb.lastQuery != nil {
+		t.Error("a throttled sync must not download the whole calendar")
+	}
+}
+
+func TestSyncCalendar_NotFound(t *testing.T) {
+	mb := &mockBackend{syncErrs: []error{&statusError{code: http.StatusNotFound}}}
+	c := NewClientWithBackend(mb)
+
+	if _, err := c.SyncCalendar(context.Background(), "/cal/gone/", ""); err == nil {
+		t.Fatal("expected error for a missing calendar")
+	}
+}
@@ -0,0 +1,368 @@
+package caldav
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav/caldav"
+)
+
+// Task statuses (RFC 5545 STATUS values for VTODO).
+const (
+	TaskNeedsAction = "NEEDS-ACTION"
+	TaskInProcess   = "IN-PROCESS"
+	TaskCompleted   = "COMPLETED"
+	TaskCancelled   = "CANCELLED"
+)
+
+// Task represents a to-do (VTODO), such as an iCloud reminder.
+type Task struct {
+	ID          string     `json:"id"`
+	Path        string     `json:"path"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Due         *time.Time `json:"due,omitempty"`
+	// AllDay tasks are due on a date rather than at a time. Due is then
+	// midnight UTC of that date.
+	AllDay bool `json:"allDay,omitempty"`
+	// Priority ranges from 1 (highest) to 9 (lowest); 0 is undefined.
+	Priority        int        `json:"priority,omitempty"`
+	Status          string     `json:"status,omitempty"`
+	Completed       *time.Time `json:"completed,omitempty"`
+	PercentComplete int        `json:"percentComplete,omitempty"`
+	// RelatedTo is the UID of the parent task, if any.
+	RelatedTo string `json:"relatedTo,omitempty"`
+	ETag      string `json:"etag,omitempty"`
+}
+
+// TaskUpdate represents fields to update on a task.
+// nil pointer = don't change, non-nil empty string = clear field.
+type TaskUpdate struct {
+	Title       *string
+	Description *string
+	// Due sets the due time; a zero time clears it. AllDay writes it as a
+	// date.
+	Due    *time.Time
+	AllDay bool
+	// Priority is 0 to 9, with 0 clearing it.
+	Priority *int
+	// Status changes the task's status. Setting TaskCompleted records the
+	// completion time and 100 percent complete; any other status clears the
+	// completion time.
+	Status          *string
+	PercentComplete *int
+	RelatedTo       *string
+	// ETag, if set, makes the update fail with a *ConflictError unless the
+	// task still has this ETag on the server.
+	ETag string
+}
+
+// ValidateTaskStatus checks that status is a VTODO status.
+func ValidateTaskStatus(status string) error {
+	switch status {
+	case TaskNeedsAction, TaskInProcess, TaskCompleted, TaskCancelled:
+		return nil
+	}
+	return fmt.Errorf("invalid task status %q: use %s, %s, %s or %s", status, TaskNeedsAction, TaskInProcess, TaskCompleted, TaskCancelled)
+}
+
+func validatePriority(priority int) error {
+	if priority < 0 || priority > 9 {
+		return fmt.Errorf("priority must be between 0 and 9, got %d", priority)
+	}
+	return nil
+}
+
+func validatePercentComplete(percent int) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("percent complete must be between 0 and 100, got %d", percent)
+	}
+	return nil
+}
+
+// SearchTasks lists the tasks in a calendar. Completed and cancelled tasks
+// are left out unless includeCompleted is set.
+func (c *Client) SearchTasks(ctx context.Context, calendarPath string, includeCompleted bool) ([]Task, error) {
+	query := &caldav.CalendarQuery{
+		CompRequest: caldav.CalendarCompRequest{
+			Name: "VCALENDAR",
+			Comps: []caldav.CalendarCompRequest{
+				{
+					Name:     "VTODO",
+					AllProps: true,
+				},
+			},
+		},
+		CompFilter: caldav.CompFilter{
+			Name: "VCALENDAR",
+			Comps: []caldav.CompFilter{
+				{
+					Name: "VTODO",
+				},
+			},
+		},
+	}
+
+	calendarObjects, err := c.backend.QueryCalendar(ctx, calendarPath, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tasks: %w", err)
+	}
+
+	tasks := make([]Task, 0, len(calendarObjects))
+	for _, obj := range calendarObjects {
+		task, err := parseTaskObject(&obj)
+		if err != nil {
+			slog.Warn("skipping unparseable task", "path", obj.Path, "error", err)
+			continue
+		}
+		if !includeCompleted && (task.Status == TaskCompleted || task.Status == TaskCancelled || task.Completed != nil) {
+			continue
+		}
+		tasks = append(tasks, *task)
+	}
+
+	return tasks, nil
+}
+
+// CreateTask creates a new task in the specified calendar
+func (c *Client) CreateTask(ctx context.Context, calendarPath string, task *Task) (string, error) {
+	status := task.Status
+	if status == "" {
+		status = TaskNeedsAction
+	}
+	if err := ValidateTaskStatus(status); err != nil {
+		return "", err
+	}
+	if err := validatePriority(task.Priority); err != nil {
+		return "", err
+	}
+	if err := validatePercentComplete(task.PercentComplete); err != nil {
+		return "", err
+	}
+
+	cal := newCalendar()
+	todo := ical.NewComponent(ical.CompToDo)
+
+	uid := task.ID
+	if uid == "" {
+		uid = NewEventID()
+	}
+	todo.Props.SetText(ical.PropUID, uid)
+	todo.Props.SetDateTime(ical.PropDateTimeStamp, time.Now().UTC())
+	todo.Props.SetText(ical.PropSummary, task.Title)
+
+	if task.Description != "" {
+		todo.Props.SetText(ical.PropDescription, task.Description)
+	}
+	if task.Due != nil {
+		setTaskDue(cal, todo, *task.Due, task.AllDay)
+	}
+	if task.Priority != 0 {
+		setInt(todo.Props, ical.PropPriority, task.Priority)
+	}
+	if task.PercentComplete != 0 {
+		setInt(todo.Props, ical.PropPercentComplete, task.PercentComplete)
+	}
+	if task.RelatedTo != "" {
+		todo.Props.SetText(ical.PropRelatedTo, task.RelatedTo)
+	}
+	setTaskStatus(todo, status)
+
+	cal.Children = append(cal.Children, todo)
+
+	taskPath := c.GetEventPath(calendarPath, uid)
+	_, err := c.backend.PutCalendarObject(ctx, taskPath, cal, precondition{ifNoneMatch: true})
+	if isPreconditionFailed(err) {
+		return "", fmt.Errorf("failed to create task: a task with UID %s already exists", uid)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to create task: %w", err)
+	}
+
+	return uid, nil
+}
+
+// UpdateTask updates an existing task using pointer fields.
+// nil pointer = don't change, non-nil empty string = clear the field.
+// Like UpdateEvent, the write is conditional on the task's ETag, and a task
+// not stored under its UID is looked up by UID.
+func (c *Client) UpdateTask(ctx context.Context, taskPath string, update *TaskUpdate) error {
+	if update.Status != nil {
+		if err := ValidateTaskStatus(*update.Status); err != nil {
+			return err
+		}
+	}
+	if update.Priority != nil {
+		if err := validatePriority(*update.Priority); err != nil {
+			return err
+		}
+	}
+	if update.PercentComplete != nil {
+		if err := validatePercentComplete(*update.PercentComplete); err != nil {
+			return err
+		}
+	}
+
+	obj, taskPath, ifMatch, err := c.getTaskForUpdate(ctx, taskPath, update.ETag)
+	if err != nil {
+		return err
+	}
+
+	todo := findComponent(obj.Data, ical.CompToDo)
+	if todo == nil {
+		return fmt.Errorf("no VTODO component found in calendar object")
+	}
+
+	setOrDeleteText(todo.Props, ical.PropSummary, update.Title)
+	setOrDeleteText(todo.Props, ical.PropDescription, update.Description)
+	setOrDeleteText(todo.Props, ical.PropRelatedTo, update.RelatedTo)
+
+	if update.Due != nil {
+		if update.Due.IsZero() {
+			todo.Props.Del(ical.PropDue)
+		} else {
+			setTaskDue(obj.Data, todo, *update.Due, update.AllDay)
+		}
+	}
+	if update.Priority != nil {
+		if *update.Priority == 0 {
+			todo.Props.Del(ical.PropPriority)
+		} else {
+			setInt(todo.Props, ical.PropPriority, *update.Priority)
+		}
+	}
+	if update.PercentComplete != nil {
+		setInt(todo.Props, ical.PropPercentComplete, *update.PercentComplete)
+	}
+	if update.Status != nil {
+		setTaskStatus(todo, *update.Status)
+	}
+
+	now := time.Now().UTC()
+	todo.Props.SetDateTime(ical.PropDateTimeStamp, now)
+	todo.Props.SetDateTime(ical.PropLastModified, now)
+
+	_, err = c.backend.PutCalendarObject(ctx, taskPath, obj.Data, precondition{ifMatch: ifMatch})
+	if isPreconditionFailed(err) {
+		return c.refetchConflict(ctx, taskPath)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+
+	return nil
+}
+
+// CompleteTask marks a task as completed now. A non-empty etag makes the
+// change conditional, as for UpdateTask.
+func (c *Client) CompleteTask(ctx context.Context, taskPath, etag string) error {
+	status := TaskCompleted
+	return c.UpdateTask(ctx, taskPath, &TaskUpdate{Status: &status, ETag: etag})
+}
+
+// setTaskDue writes the DUE of todo, as a date for all-day tasks and in the
+// zone of due otherwise.
+func setTaskDue(cal *ical.Calendar, todo *ical.Component, due time.Time, allDay bool) {
+	if allDay {
+		due = dateOf(due)
+	} else {
+		addTimezone(cal, due.Location(), due)
+	}
+	setTimeProp(todo.Props, ical.PropDue, due, allDay, false)
+}
+
+// setTaskStatus writes the STATUS of todo and keeps COMPLETED and
+// PERCENT-COMPLETE consistent with it.
+func setTaskStatus(todo *ical.Component, status string) {
+	todo.Props.SetText(ical.PropStatus, status)
+	if status != TaskCompleted {
+		todo.Props.Del(ical.PropCompleted)
+		return
+	}
+	if todo.Props.Get(ical.PropCompleted) == nil {
+		todo.Props.SetDateTime(ical.PropCompleted, time.Now().UTC())
+	}
+	setInt(todo.Props, ical.PropPercentComplete, 100)
+}
+
+func setInt(props ical.Props, name string, value int) {
+	props.Set(&ical.Prop{Name: name, Value: strconv.Itoa(value), Params: ical.Params{}})
+}
+
+// setOrDeleteText applies a TaskUpdate-style text field: nil leaves the
+// property alone and an empty string deletes it.
+func setOrDeleteText(props ical.Props, name string, value *string) {
+	switch {
+	case value == nil:
+	case *value == "":
+		props.Del(name)
+	default:
+		props.SetText(name, *value)
+	}
+}
+
+// findComponent returns the first child of cal named name, or nil.
+func findComponent(cal *ical.Calendar, name string) *ical.Component {
+	for _, child := range cal.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+// parseTaskObject converts a CalDAV calendar object to our Task struct
+func parseTaskObject(obj *caldav.CalendarObject) (*Task, error) {
+	todo := findComponent(obj.Data, ical.CompToDo)
+	if todo == nil {
+		return nil, fmt.Errorf("no VTODO component found")
+	}
+
+	task := &Task{
+		Path: obj.Path,
+		ETag: normalizeETag(obj.ETag),
+	}
+	if prop := todo.Props.Get(ical.PropUID); prop != nil {
+		task.ID = prop.Value
+	}
+	if prop := todo.Props.Get(ical.PropSummary); prop != nil {
+		task.Title = prop.Value
+	}
+	if prop := todo.Props.Get(ical.PropDescription); prop != nil {
+		task.Description = prop.Value
+	}
+	if prop := todo.Props.Get(ical.PropDue); prop != nil {
+		if due, _, err := parseTimeProp(prop); err == nil {
+			task.Due = &due
+			task.AllDay = isDateValue(prop)
+		}
+	}
+	if prop := todo.Props.Get(ical.PropPriority); prop != nil {
+		if priority, err := prop.Int(); err == nil {
+			task.Priority = priority
+		}
+	}
+	if prop := todo.Props.Get(ical.PropStatus); prop != nil {
+		task.Status = strings.ToUpper(prop.Value)
+	}
+	if prop := todo.Props.Get(ical.PropCompleted); prop != nil {
+		if completed, _, err := parseTimeProp(prop); err == nil {
+			task.Completed = &completed
+		}
+	}
+	if prop := todo.Props.Get(ical.PropPercentComplete); prop != nil {
+		if percent, err := prop.Int(); err == nil {
+			task.PercentComplete = percent
+		}
+	}
+	if prop := todo.Props.Get(ical.PropRelatedTo); prop != nil {
+		task.RelatedTo = prop.Value
+	}
+
+	return task, nil
+}
@@ -0,0 +1,273 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+	extcaldav "github.com/emersion/go-webdav/caldav"
+)
+
+func makeTaskObject(path, uid, title, status string) extcaldav.CalendarObject {
+	cal := ical.NewCalendar()
+	cal.Props.SetText(ical.PropVersion, "2.0")
+	cal.Props.SetText(ical.PropProductID, "-//test//test//EN")
+
+	todo := ical.NewComponent(ical.CompToDo)
+	todo.Props.SetText(ical.PropUID, uid)
+	todo.Props.SetText(ical.PropSummary, title)
+	todo.Props.SetDateTime(ical.PropDateTimeStamp, time.Now())
+	if status != "" {
+		todo.Props.SetText(ical.PropStatus, status)
+	}
+	cal.Children = append(cal.Children, todo)
+
+	return extcaldav.CalendarObject{Path: path, ETag: `"etag-1"`, Data: cal}
+}
+
+func TestSearchTasks_FiltersCompleted(t *testing.T) {
+	done := makeTaskObject("/cal/reminders/t2.ics", "t2", "Done", TaskCompleted)
+	mb := &mockBackend{
+		queryResult: []extcaldav.CalendarObject{
+			makeTaskObject("/cal/reminders/t1.ics", "t1", "Open", TaskNeedsAction),
+			done,
+			makeTaskObject("/cal/reminders/t3.ics", "t3", "Dropped", TaskCancelled),
+		},
+	}
+	c := NewClientWithBackend(mb)
+
+	tasks, err := c.SearchTasks(context.Background(), "/cal/reminders", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != "t1" {
+		t.Fatalf("tasks = %+v, want only t1", tasks)
+	}
+	if tasks[0].ETag != "etag-1" {
+		t.Errorf("ETag = %q, want etag-1", tasks[0].ETag)
+	}
+	if got := mb.lastQuery.CompFilter.Comps[0].Name; got != ical.CompToDo {
+		t.Errorf("query filter = %q, want VTODO", got)
+	}
+
+	tasks, err = c.SearchTasks(context.Background(), "/cal/reminders", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tasks) != 3 {
+		t.Errorf("expected 3 tasks with includeCompleted, got %d", len(tasks))
+	}
+}
+
+func TestSearchTasks_QueryError(t *testing.T) {
+	mb := &mockBackend{queryErr: fmt.Errorf("server error")}
+	c := NewClientWithBackend(mb)
+
+	if _, err := c.SearchTasks(context.Background(), "/cal/reminders", false); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestCreateTask_Success(t *testing.T) {
+	mb := &mockBackend{}
+	c := NewClientWithBackend(mb)
+
+	due := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	input := &Task{
+		Title:     "Pay rent",
+		Due:       &due,
+		AllDay:    true,
+		Priority:  1,
+		RelatedTo: "parent-uid",
+	}
+	uid, err := c.CreateTask(context.Background(), "/cal/reminders", input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if input.Status != "" {
+		t.Errorf("caller's task status = %q, want it left empty", input.Status)
+	}
+	if mb.lastPutPath != "/cal/reminders/"+uid+".ics" {
+		t.Errorf("put path = %q", mb.lastPutPath)
+	}
+	if !mb.lastPutCond.ifNoneMatch {
+		t.Error("expected If-None-Match on create")
+	}
+
+	task, err := parseTaskObject(&extcaldav.CalendarObject{Data: mb.lastPutCal})
+	if err != nil {
+		t.Fatalf("failed to parse written task: %v", err)
+	}
+	if task.Title != "Pay rent" || task.Status != TaskNeedsAction || task.Priority != 1 || task.RelatedTo != "parent-uid" {
+		t.Errorf("task = %+v", task)
+	}
+	if !task.AllDay || task.Due == nil || !task.Due.Equal(due) {
+		t.Errorf("due = %v (allDay %v), want %v as a date", task.Due, task.AllDay, due)
+	}
+}
+
+func TestCreateTask_InvalidPriority(t *testing.T) {
+	mb := &mockBackend{}
+	c := NewClientWithBackend(mb)
+
+	if _, err := c.CreateTask(context.Background(), "/cal/reminders", &Task{Title: "x", Priority: 10}); err == nil {
+		t.Fatal("expected error for priority 10")
+	}
+	if mb.lastPutCal != nil {
+		t.Error("nothing should be written for an invalid task")
+	}
+}
+
+func TestUpdateTask_ClearsAndSetsFields(t *testing.T) {
+	obj := makeTaskObject("/cal/reminders/t1.ics", "t1", "Old", TaskNeedsAction)
+	obj.Data.Children[0].Props.SetText(ical.PropDescription, "notes")
+	mb := &mockBackend{getResult: &obj}
+	c := NewClientWithBackend(mb)
+
+	title := "New"
+	empty := ""
+	priority := 5
+	err := c.UpdateTask(context.Background(), obj.Path, &TaskUpdate{
+		Title:       &title,
+		Description: &empty,
+		Priority:    &priority,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mb.lastPutCond.ifMatch != obj.ETag {
+		t.Errorf("If-Match = %q, want %q", mb.lastPutCond.ifMatch, obj.ETag)
+	}
+
+	task, err := parseTaskObject(&extcaldav.CalendarObject{Data: mb.lastPutCal})
+	if err != nil {
+		t.Fatalf("failed to parse written task: %v", err)
+	}
+	if task.Title != "New" || task.Description != "" || task.Priority != 5 {
+		t.Errorf("task = %+v", task)
+	}
+}
+
+func TestUpdateTask_Conflict(t *testing.T) {
+	obj := makeTaskObject("/cal/reminders/t1.ics", "t1", "Old", TaskNeedsAction)
+	mb := &mockBackend{getResult: &obj}
+	c := NewClientWithBackend(mb)
+
+	title := "New"
+	err := c.UpdateTask(context.Background(), obj.Path, &TaskUpdate{Title: &title, ETag: "stale"})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected *ConflictError, got %v", err)
+	}
+	if conflict.CurrentTask == nil || conflict.CurrentTask.ID != "t1" {
+		t.Errorf("CurrentTask = %+v, want t1", conflict.CurrentTask)
+	}
+	if mb.lastPutCal != nil {
+		t.Error("nothing should be written on conflict")
+	}
+}
+
+func TestUpdateTask_PreconditionFailed(t *testing.T) {
+	obj := makeTaskObject("/cal/reminders/t1.ics", "t1", "Old", TaskNeedsAction)
+	mb := &mockBackend{
+		getResult: &obj,
+		putErr:    &statusError{code: http.StatusPreconditionFailed},
+	}
+	c := NewClientWithBackend(mb)
+
+	title := "New"
+	err := c.UpdateTask(context.Background(), obj.Path, &TaskUpdate{Title: &title})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected *ConflictError, got %v", err)
+	}
+}
+
+func TestCompleteTask(t *testing.T) {
+	obj := makeTaskObject("/cal/reminders/t1.ics", "t1", "Open", TaskInProcess)
+	mb := &mockBackend{getResult: &obj}
+	c := NewClientWithBackend(mb)
+
+	if err := c.CompleteTask(context.Background(), obj.Path, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	task, err := parseTaskObject(&extcaldav.CalendarObject{Data: mb.lastPutCal})
+	if err != nil {
+		t.Fatalf("failed to parse written task: %v", err)
+	}
+	if task.Status != TaskCompleted || task.Completed == nil || task.PercentComplete != 100 {
+		t.Errorf("task = %+v, want completed with a completion time and 100%%", task)
+	}
+
+	// Reopening clears the completion time
+	status := TaskNeedsAction
+	obj.Data = mb.lastPutCal
+	if err := c.UpdateTask(context.Background(), obj.Path, &TaskUpdate{Status: &status}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	task, _ = parseTaskObject(&extcaldav.CalendarObject{Data: mb.lastPutCal})
+	if task.Status != TaskNeedsAction || task.Completed != nil {
+		t.Errorf("task = %+v, want reopened", task)
+	}
+}
+
+func TestCompleteTask_ResolvesPathByUID(t *testing.T) {
+	// Reminders created on an iPhone are not stored under their UID
+	obj := makeTaskObject("/cal/reminders/E0F1-2A3B.ics", "t1", "Open", TaskNeedsAction)
+	mb := &mockBackend{
+		getErr:      &statusError{code: http.StatusNotFound},
+		queryResult: []extcaldav.CalendarObject{obj},
+	}
+	c := NewClientWithBackend(mb)
+
+	if err := c.CompleteTask(context.Background(), "/cal/reminders/t1.ics", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mb.lastPutPath != obj.Path || mb.lastPutCond.ifMatch != obj.ETag {
+		t.Errorf("put %s with %+v, want a conditional write to the task's actual path", mb.lastPutPath, mb.lastPutCond)
+	}
+	if comps := mb.lastQuery.CompFilter.Comps; len(comps) != 1 || comps[0].Name != ical.CompToDo {
+		t.Errorf("query filter = %+v, want a VTODO comp-filter", mb.lastQuery.CompFilter)
+	}
+
+	// A task that cannot be found is reported as such
+	mb.queryResult = nil
+	if err := c.CompleteTask(context.Background(), "/cal/reminders/t2.ics", ""); !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("err = %v, want ErrEventNotFound", err)
+	}
+}
+
+func TestValidateTaskStatus(t *testing.T) {
+	for _, s := range []string{TaskNeedsAction, TaskInProcess, TaskCompleted, TaskCancelled} {
+		if err := ValidateTaskStatus(s); err != nil {
+			t.Errorf("ValidateTaskStatus(%q) = %v", s, err)
+		}
+	}
+	if err := ValidateTaskStatus("done"); err == nil {
+		t.Error("expected error for invalid status")
+	}
+}
+
+func TestListCalendars_SupportedComponents(t *testing.T) {
+	mb := &mockBackend{
+		principal: "/principals/user/",
+		homeSet:   "/calendars/user/",
+		calendars: []Calendar{
+			{Path: "/cal/reminders", Name: "Reminders", SupportedComponents: []string{"VTODO"}},
+		},
+	}
+	c := NewClientWithBackend(mb)
+
+	cals, err := c.ListCalendars(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cals[0].SupportedComponents) != 1 || cals[0].SupportedComponents[0] != "VTODO" {
+		t.Errorf("SupportedComponents = %v, want [VTODO]", cals[0].SupportedComponents)
+	}
+}
@@ -0,0 +1,169 @@
+package caldav
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+)
+
+// LoadTimezone resolves an IANA time zone name such as "America/New_York".
+// An empty name and "UTC" select UTC. "Local" is rejected because the
+// server's own zone means nothing to the calendar's other clients.
+func LoadTimezone(name string) (*time.Location, error) {
+	switch name {
+	case "", "UTC", "Z":
+		return time.UTC, nil
+	case "Local":
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return time.LoadLocation(name)
+}
+
+// isUTC reports whether loc should be written as a plain UTC time rather than
+// with a TZID. Fixed offsets parsed from RFC 3339 strings have no zone name
+// and are written as UTC too.
+func isUTC(loc *time.Location) bool {
+	switch loc.String() {
+	case "", "UTC", "Local":
+		return true
+	}
+	return false
+}
+
+// timezoneComponent returns the VTIMEZONE for tzid in cal, or nil.
+func timezoneComponent(cal *ical.Calendar, tzid string) *ical.Component {
+	for _, child := range cal.Children {
+		if child.Name != ical.CompTimezone {
+			continue
+		}
+		if prop := child.Props.Get(ical.PropTimezoneID); prop != nil && prop.Value == tzid {
+			return child
+		}
+	}
+	return nil
+}
+
+// addTimezone adds a VTIMEZONE describing loc to cal, unless cal already has
+// one for that TZID. t selects the year whose rules are described.
+func addTimezone(cal *ical.Calendar, loc *time.Location, t time.Time) {
+	if isUTC(loc) || timezoneComponent(cal, loc.String()) != nil {
+		return
+	}
+	// RFC 5545 expects VTIMEZONE before the components that reference it.
+	children := make([]*ical.Component, 0, len(cal.Children)+1)
+	children = append(children, newTimezone(loc, t))
+	cal.Children = append(children, cal.Children...)
+}
+
+// zoneTransition is a change of UTC offset in a time zone.
+type zoneTransition struct {
+	at       time.Time
+	from, to int // UTC offsets in seconds
+	name     string
+	dst      bool
+}
+
+// newTimezone builds a VTIMEZONE component for loc from the offset changes in
+// the year of t. Zones with a daylight saving pair get yearly rules, so the
+// component stays correct for recurring events in later years.
+func newTimezone(loc *time.Location, t time.Time) *ical.Component {
+	tz := ical.NewComponent(ical.CompTimezone)
+	tz.Props.SetText(ical.PropTimezoneID, loc.String())
+
+	year := t.In(loc).Year()
+	transitions := zoneTransitions(loc, year)
+	if len(transitions) == 0 {
+		name, offset := time.Date(year, 1, 1, 0, 0, 0, 0, loc).Zone()
+		epoch := time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+		tz.Children = append(tz.Children, zoneObservance(ical.CompTimezoneStandard, epoch, offset, offset, name, ""))
+		return tz
+	}
+
+	yearly := len(transitions) == 2 && transitions[0].dst != transitions[1].dst
+	for _, tr := range transitions {
+		compName := ical.CompTimezoneStandard
+		if tr.dst {
+			compName = ical.CompTimezoneDaylight
+		}
+		// Observance onsets are local times in the offset in effect before.
+		onset := tr.at.In(time.FixedZone("", tr.from))
+		rule := ""
+		if yearly {
+			rule = yearlyRule(onset)
+		}
+		tz.Children = append(tz.Children, zoneObservance(compName, wallClock(onset, time.UTC), tr.from, tr.to, tr.name, rule))
+	}
+	return tz
+}
+
+// zoneTransitions lists the offset changes of loc during year.
+func zoneTransitions(loc *time.Location, year int) []zoneTransition {
+	var transitions []zoneTransition
+	t := time.Date(year, 1, 1, 0, 0, 0, 0, loc)
+	end := time.Date(year+1, 1, 1, 0, 0, 0, 0, loc)
+	_, prev := t.Zone()
+	for t.Before(end) {
+		next := t.Add(24 * time.Hour)
+		if _, offset := next.Zone(); offset != prev {
+			// Narrow the change down to the second.
+			lo, hi := t, next
+			for hi.Sub(lo) > time.Second {
+				mid := lo.Add(hi.Sub(lo) / 2)
+				if _, o := mid.Zone(); o == prev {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			hi = hi.Truncate(time.Second)
+			name, to := hi.Zone()
+			transitions = append(transitions, zoneTransition{at: hi, from: prev, to: to, name: name, dst: hi.IsDST()})
+			prev = to
+		}
+		t = next
+	}
+	return transitions
+}
+
+// yearlyRule returns an RRULE that repeats onset on the same weekday of the
+// same week of its month every year, e.g. "the second Sunday of March".
+func yearlyRule(onset time.Time) string {
+	n := (onset.Day()-1)/7 + 1
+	daysInMonth := time.Date(onset.Year(), onset.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if onset.Day()+7 > daysInMonth {
+		n = -1
+	}
+	day := strings.ToUpper(onset.Weekday().String()[:2])
+	return fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYDAY=%d%s", int(onset.Month()), n, day)
+}
+
+func zoneObservance(name string, onset time.Time, from, to int, tzname, rule string) *ical.Component {
+	comp := ical.NewComponent(name)
+	setTimeProp(comp.Props, ical.PropDateTimeStart, onset, false, true)
+	comp.Props.Set(&ical.Prop{Name: ical.PropTimezoneOffsetFrom, Value: formatUTCOffset(from), Params: ical.Params{}})
+	comp.Props.Set(&ical.Prop{Name: ical.PropTimezoneOffsetTo, Value: formatUTCOffset(to), Params: ical.Params{}})
+	if tzname != "" {
+		comp.Props.SetText(ical.PropTimezoneName, tzname)
+	}
+	if rule != "" {
+		setRecurrenceRule(comp.Props, rule)
+	}
+	return comp
+}
+
+// formatUTCOffset formats an offset in seconds as an iCalendar UTC-OFFSET
+// value such as "-0500" or "+0530".
+func formatUTCOffset(seconds int) string {
+	sign := '+'
+	if seconds < 0 {
+		sign = '-'
+		seconds = -seconds
+	}
+	s := fmt.Sprintf("%c%02d%02d", sign, seconds/3600, seconds%3600/60)
+	if rem := seconds % 60; rem != 0 {
+		s += fmt.Sprintf("%02d", rem)
+	}
+	return s
+}
@@ -0,0 +1,143 @@
+package caldav
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	return loc
+}
+
+func TestLoadTimezone(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", "UTC", false},
+		{"UTC", "UTC", false},
+		{"America/New_York", "America/New_York", false},
+		{"Local", "", true},
+		{"Mars/Olympus_Mons", "", true},
+	}
+
+	for _, tt := range tests {
+		loc, err := LoadTimezone(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("LoadTimezone(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && loc.String() != tt.want {
+			t.Errorf("LoadTimezone(%q) = %q, want %q", tt.name, loc, tt.want)
+		}
+	}
+}
+
+func TestNewTimezone_DaylightSaving(t *testing.T) {
+	loc := mustLoadLocation(t, "America/New_York")
+	tz := newTimezone(loc, time.Date(2024, 6, 1, 9, 0, 0, 0, loc))
+
+	if tzid := tz.Props.Get(ical.PropTimezoneID).Value; tzid != "America/New_York" {
+		t.Errorf("TZID = %q", tzid)
+	}
+	if len(tz.Children) != 2 {
+		t.Fatalf("expected 2 observances, got %d", len(tz.Children))
+	}
+
+	tests := []struct {
+		comp, dtstart, from, to, rule string
+	}{
+		{ical.CompTimezoneDaylight, "20240310T020000", "-0500", "-0400", "FREQ=YEARLY;BYMONTH=3;BYDAY=2SU"},
+		{ical.CompTimezoneStandard, "20241103T020000", "-0400", "-0500", "FREQ=YEARLY;BYMONTH=11;BYDAY=1SU"},
+	}
+	for i, tt := range tests {
+		obs := tz.Children[i]
+		if obs.Name != tt.comp {
+			t.Errorf("observance %d = %s, want %s", i, obs.Name, tt.comp)
+		}
+		if v := obs.Props.Get(ical.PropDateTimeStart).Value; v != tt.dtstart {
+			t.Errorf("observance %d DTSTART = %q, want %q", i, v, tt.dtstart)
+		}
+		if v := obs.Props.Get(ical.PropTimezoneOffsetFrom).Value; v != tt.from {
+			t.Errorf("observance %d TZOFFSETFROM = %q, want %q", i, v, tt.from)
+		}
+		if v := obs.Props.Get(ical.PropTimezoneOffsetTo).Value; v != tt.to {
+			t.Errorf("observance %d TZOFFSETTO = %q, want %q", i, v, tt.to)
+		}
+		if v := obs.Props.Get(ical.PropRecurrenceRule).Value; v != tt.rule {
+			t.Errorf("observance %d RRULE = %q, want %q", i, v, tt.rule)
+		}
+	}
+}
+
+func TestNewTimezone_LastSundayRule(t *testing.T) {
+	loc := mustLoadLocation(t, "Europe/London")
+	tz := newTimezone(loc, time.Date(2024, 1, 1, 0, 0, 0, 0, loc))
+
+	for _, obs := range tz.Children {
+		if rule := obs.Props.Get(ical.PropRecurrenceRule).Value; !strings.HasSuffix(rule, "BYDAY=-1SU") {
+			t.Errorf("%s RRULE = %q, want last Sunday", obs.Name, rule)
+		}
+	}
+}
+
+func TestNewTimezone_NoDaylightSaving(t *testing.T) {
+	loc := mustLoadLocation(t, "Asia/Tokyo")
+	tz := newTimezone(loc, time.Date(2024, 1, 1, 0, 0, 0, 0, loc))
+
+	if len(tz.Children) != 1 || tz.Children[0].Name != ical.CompTimezoneStandard {
+		t.Fatalf("expected a single STANDARD observance, got %d children", len(tz.Children))
+	}
+	obs := tz.Children[0]
+	if from, to := obs.Props.Get(ical.PropTimezoneOffsetFrom).Value, obs.Props.Get(ical.PropTimezoneOffsetTo).Value; from != "+0900" || to != "+0900" {
+		t.Errorf("offsets = %s -> %s, want +0900 -> +0900", from, to)
+	}
+	if obs.Props.Get(ical.PropRecurrenceRule) != nil {
+		t.Error("unexpected RRULE for a zone without transitions")
+	}
+}
+
+func TestAddTimezone_Encodes(t *testing.T) {
+	loc := mustLoadLocation(t, "America/New_York")
+	cal := newTestCalendar()
+
+	addTimezone(cal, loc, time.Date(2024, 6, 1, 9, 0, 0, 0, loc))
+	addTimezone(cal, loc, time.Date(2024, 6, 1, 9, 0, 0, 0, loc))
+	addTimezone(cal, time.UTC, time.Now())
+
+	if len(cal.Children) != 2 || cal.Children[0].Name != ical.CompTimezone {
+		t.Fatalf("expected one VTIMEZONE before the VEVENT, got %d children", len(cal.Children))
+	}
+
+	var buf bytes.Buffer
+	if err := ical.NewEncoder(&buf).Encode(cal); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+}
+
+func TestFormatUTCOffset(t *testing.T) {
+	tests := []struct {
+		seconds int
+		want    string
+	}{
+		{0, "+0000"},
+		{-5 * 3600, "-0500"},
+		{5*3600 + 30*60, "+0530"},
+		{-(3600 + 15*60 + 12), "-011512"},
+	}
+	for _, tt := range tests {
+		if got := formatUTCOffset(tt.seconds); got != tt.want {
+			t.Errorf("formatUTCOffset(%d) = %q, want %q", tt.seconds, got, tt.want)
+		}
+	}
+}
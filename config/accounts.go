@@ -6,12 +6,13 @@ import (
 	"os"
 )
 
-// Account represents a single iCloud account configuration.
+// Account represents a single CalDAV account configuration.
 type Account struct {
 	Name       string `json:"name"`
 	Email      string `json:"email"`
 	Password   string `json:"password"`
 	CalendarID string `json:"calendarId,omitempty"`
+	ServerURL  string `json:"serverUrl,omitempty"` // empty = iCloud
 }
 
 // AccountsConfig holds multiple account configurations.
@@ -31,6 +32,7 @@ func LoadAccounts(cfg *Config) (map[string]Account, error) {
 				Email:      cfg.ICloudEmail,
 				Password:   cfg.ICloudPassword,
 				CalendarID: cfg.ICloudCalendarID,
+				ServerURL:  cfg.CalDAVServerURL,
 			},
 		}, nil
 	}
@@ -60,6 +62,9 @@ func LoadAccounts(cfg *Config) (map[string]Account, error) {
 		if a.Password == "" {
 			return nil, fmt.Errorf("account %q is missing 'password' field", a.Name)
 		}
+		if err := ValidateServerURL(a.ServerURL); err != nil {
+			return nil, fmt.Errorf("account %q has invalid 'serverUrl': %w", a.Name, err)
+		}
 		accounts[a.Name] = a
 	}
 
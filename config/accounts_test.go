@@ -161,3 +161,44 @@ func TestLoadAccounts_MissingPassword(t *testing.T) {
 		t.Fatal("expected error for missing password")
 	}
 }
+
+func TestLoadAccounts_ServerURL(t *testing.T) {
+	dir := t.TempDir()
+	accountsFile := filepath.Join(dir, "accounts.json")
+	content := `{"accounts": [
+		{"name": "icloud", "email": "me@icloud.com", "password": "pass1234"},
+		{"name": "fastmail", "email": "me@fastmail.com", "password": "pass1234", "serverUrl": "fastmail.com"}
+	]}`
+	if err := os.WriteFile(accountsFile, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	t.Setenv("ACCOUNTS_FILE", accountsFile)
+
+	accounts, err := LoadAccounts(&Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if accounts["icloud"].ServerURL != "" {
+		t.Errorf("icloud serverUrl = %q, want empty", accounts["icloud"].ServerURL)
+	}
+	if accounts["fastmail"].ServerURL != "fastmail.com" {
+		t.Errorf("fastmail serverUrl = %q, want fastmail.com", accounts["fastmail"].ServerURL)
+	}
+}
+
+func TestLoadAccounts_InvalidServerURL(t *testing.T) {
+	dir := t.TempDir()
+	accountsFile := filepath.Join(dir, "accounts.json")
+	content := `{"accounts": [{"name": "work", "email": "work@example.com", "password": "pass1234", "serverUrl": "ftp://example.com"}]}`
+	if err := os.WriteFile(accountsFile, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	t.Setenv("ACCOUNTS_FILE", accountsFile)
+
+	_, err := LoadAccounts(&Config{})
+	if err == nil {
+		t.Fatal("expected error for invalid serverUrl")
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"net/mail"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -16,6 +17,7 @@ type Config struct {
 	ICloudEmail      string
 	ICloudPassword   string
 	ICloudCalendarID string // Optional default calendar ID
+	CalDAVServerURL  string // Optional CalDAV server URL or domain (defaults to iCloud)
 	LogLevel         string
 	MaxConnsPerHost  int
 	ToolTimeout      time.Duration
@@ -24,6 +26,9 @@ type Config struct {
 	HealthPort       string
 	RateLimitRPS     float64
 	RateLimitBurst   int
+	CacheTTL         time.Duration
+	CacheDir         string
+	PollInterval     time.Duration
 	TLSCertFile      string
 	TLSKeyFile       string
 	TLSCAFile        string
@@ -40,6 +45,7 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 	calendarID := os.Getenv("ICLOUD_CALENDAR_ID")
+	serverURL := os.Getenv("CALDAV_SERVER_URL")
 	logLevel := os.Getenv("LOG_LEVEL")
 
 	// Validate required fields
@@ -87,10 +93,21 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	cacheTTL, err := getDurationEnv("CACHE_TTL", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	pollInterval, err := getDurationEnv("RESOURCE_POLL_INTERVAL", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
 		ICloudEmail:      email,
 		ICloudPassword:   password,
 		ICloudCalendarID: calendarID,
+		CalDAVServerURL:  serverURL,
 		LogLevel:         logLevel,
 		MaxConnsPerHost:  maxConns,
 		ToolTimeout:      toolTimeout,
@@ -99,6 +116,9 @@ func Load() (*Config, error) {
 		HealthPort:       healthPort,
 		RateLimitRPS:     rateLimitRPS,
 		RateLimitBurst:   rateLimitBurst,
+		CacheTTL:         cacheTTL,
+		CacheDir:         os.Getenv("CACHE_DIR"),
+		PollInterval:     pollInterval,
 		TLSCertFile:      os.Getenv("TLS_CERT_FILE"),
 		TLSKeyFile:       os.Getenv("TLS_KEY_FILE"),
 		TLSCAFile:        os.Getenv("TLS_CA_FILE"),
@@ -122,6 +142,9 @@ func (c *Config) Validate() error {
 	if c.ICloudCalendarID != "" && !strings.HasPrefix(c.ICloudCalendarID, "/") {
 		return fmt.Errorf("ICLOUD_CALENDAR_ID must start with '/'")
 	}
+	if err := ValidateServerURL(c.CalDAVServerURL); err != nil {
+		return fmt.Errorf("invalid CALDAV_SERVER_URL: %w", err)
+	}
 	if c.MaxConnsPerHost < 1 || c.MaxConnsPerHost > 100 {
 		return fmt.Errorf("MAX_CONNS_PER_HOST must be between 1 and 100")
 	}
@@ -134,6 +157,37 @@ func (c *Config) Validate() error {
 	if c.RetryBaseDelay < 100*time.Millisecond || c.RetryBaseDelay > 30*time.Second {
 		return fmt.Errorf("RETRY_BASE_DELAY must be between 100ms and 30s")
 	}
+	if c.CacheTTL < 0 || c.CacheTTL > time.Hour {
+		return fmt.Errorf("CACHE_TTL must be between 0s and 1h")
+	}
+	if c.PollInterval < 10*time.Second || c.PollInterval > time.Hour {
+		return fmt.Errorf("RESOURCE_POLL_INTERVAL must be between 10s and 1h")
+	}
+	return nil
+}
+
+// ValidateServerURL checks a CalDAV server setting. Empty values (iCloud), bare
+// domains for RFC 6764 discovery and http(s) URLs are accepted.
+func ValidateServerURL(server string) error {
+	if server == "" {
+		return nil
+	}
+	if !strings.Contains(server, "://") {
+		if strings.ContainsAny(server, "/?#@ ") {
+			return fmt.Errorf("%q is neither a URL nor a domain name", server)
+		}
+		return nil
+	}
+	u, err := url.Parse(server)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("scheme must be http or https, got %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("missing host in %q", server)
+	}
 	return nil
 }
 
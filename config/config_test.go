@@ -12,6 +12,7 @@ func setDefaults(t *testing.T) {
 	t.Setenv("ICLOUD_EMAIL", "user@example.com")
 	t.Setenv("ICLOUD_PASSWORD", "testpass1234")
 	t.Setenv("ICLOUD_CALENDAR_ID", "")
+	t.Setenv("CALDAV_SERVER_URL", "")
 	t.Setenv("LOG_LEVEL", "")
 	t.Setenv("MAX_CONNS_PER_HOST", "")
 	t.Setenv("TOOL_TIMEOUT", "")
@@ -19,6 +20,9 @@ func setDefaults(t *testing.T) {
 	t.Setenv("RETRY_BASE_DELAY", "")
 	t.Setenv("RATE_LIMIT_RPS", "")
 	t.Setenv("RATE_LIMIT_BURST", "")
+	t.Setenv("CACHE_TTL", "")
+	t.Setenv("CACHE_DIR", "")
+	t.Setenv("RESOURCE_POLL_INTERVAL", "")
 	t.Setenv("HEALTH_PORT", "")
 	t.Setenv("TLS_CERT_FILE", "")
 	t.Setenv("TLS_KEY_FILE", "")
@@ -69,6 +73,12 @@ func TestLoad_Defaults(t *testing.T) {
 	if cfg.RetryBaseDelay != 1*time.Second {
 		t.Errorf("RetryBaseDelay = %v, want 1s", cfg.RetryBaseDelay)
 	}
+	if cfg.CacheTTL != 0 {
+		t.Errorf("CacheTTL = %v, want 0s", cfg.CacheTTL)
+	}
+	if cfg.PollInterval != time.Minute {
+		t.Errorf("PollInterval = %v, want 1m", cfg.PollInterval)
+	}
 }
 
 func TestLoad_CustomValues(t *testing.T) {
@@ -79,6 +89,7 @@ func TestLoad_CustomValues(t *testing.T) {
 	t.Setenv("TOOL_TIMEOUT", "30s")
 	t.Setenv("MAX_RETRIES", "5")
 	t.Setenv("RETRY_BASE_DELAY", "2s")
+	t.Setenv("CACHE_DIR", "/var/cache/mcp-icloud-calendar")
 
 	cfg, err := Load()
 	if err != nil {
@@ -100,6 +111,9 @@ func TestLoad_CustomValues(t *testing.T) {
 	if cfg.MaxRetries != 5 {
 		t.Errorf("MaxRetries = %d, want 5", cfg.MaxRetries)
 	}
+	if cfg.CacheDir != "/var/cache/mcp-icloud-calendar" {
+		t.Errorf("CacheDir = %q, want /var/cache/mcp-icloud-calendar", cfg.CacheDir)
+	}
 }
 
 func TestLoad_InvalidValues(t *testing.T) {
@@ -351,3 +365,78 @@ func TestLoad_DefaultRateLimitValues(t *testing.T) {
 		t.Errorf("RateLimitBurst = %d, want 20", cfg.RateLimitBurst)
 	}
 }
+
+func TestLoad_CalDAVServerURL(t *testing.T) {
+	setDefaults(t)
+	t.Setenv("CALDAV_SERVER_URL", "https://caldav.fastmail.com/dav/")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.CalDAVServerURL != "https://caldav.fastmail.com/dav/" {
+		t.Errorf("CalDAVServerURL = %q", cfg.CalDAVServerURL)
+	}
+}
+
+func TestValidateServerURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  string
+		wantErr bool
+	}{
+		{"empty uses iCloud", "", false},
+		{"https URL", "https://nextcloud.example.com/remote.php/dav", false},
+		{"http URL for local servers", "http://localhost:5232/", false},
+		{"bare domain", "fastmail.com", false},
+		{"unsupported scheme", "ftp://example.com", true},
+		{"missing host", "https://", true},
+		{"domain with path", "example.com/dav", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateServerURL(tt.server)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateServerURL(%q) error = %v, wantErr %v", tt.server, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad_InvalidCalDAVServerURL(t *testing.T) {
+	setDefaults(t)
+	t.Setenv("CALDAV_SERVER_URL", "ftp://example.com")
+
+	_, err := Load()
+	if err == nil {
+		t.Fatal("expected error for invalid CALDAV_SERVER_URL")
+	}
+}
+
+func TestValidate_CacheTTLTooHigh(t *testing.T) {
+	setDefaults(t)
+	t.Setenv("CACHE_TTL", "2h")
+	_, err := Load()
+	if err == nil {
+		t.Fatal("expected error for CacheTTL above 1h")
+	}
+}
+
+func TestLoad_InvalidCacheTTL(t *testing.T) {
+	setDefaults(t)
+	t.Setenv("CACHE_TTL", "soon")
+	_, err := Load()
+	if err == nil {
+		t.Fatal("expected error for invalid CACHE_TTL")
+	}
+}
+
+func TestValidate_PollIntervalTooLow(t *testing.T) {
+	setDefaults(t)
+	t.Setenv("RESOURCE_POLL_INTERVAL", "1s")
+	_, err := Load()
+	if err == nil {
+		t.Fatal("expected error for PollInterval below 10s")
+	}
+}
@@ -10,6 +10,7 @@ require (
 	github.com/mark3labs/mcp-go v0.43.2
 	github.com/prometheus/client_golang v1.23.2
 	github.com/teambition/rrule-go v1.8.2
+	go.etcd.io/bbolt v1.4.3
 	golang.org/x/time v0.14.0
 )
 
@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	_ "time/tzdata" // IANA zones for event timezones on hosts without zoneinfo
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -18,6 +19,7 @@ import (
 	"github.com/rgabriel/mcp-icloud-calendar/logging"
 	"github.com/rgabriel/mcp-icloud-calendar/metrics"
 	mw "github.com/rgabriel/mcp-icloud-calendar/middleware"
+	"github.com/rgabriel/mcp-icloud-calendar/resources"
 	"github.com/rgabriel/mcp-icloud-calendar/tools"
 )
 
@@ -40,34 +42,72 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Create a CalendarService client per account, each with rate limiter + retry
+	// Open the on-disk cache, if configured
+	var store *caldav.Store
+	if cfg.CacheDir != "" {
+		store, err = caldav.OpenStore(cfg.CacheDir)
+		if err != nil {
+			slog.Error("failed to open cache", "dir", cfg.CacheDir, "error", err)
+			os.Exit(1)
+		}
+		defer func() { _ = store.Close() }()
+	}
+
+	// Create a CalendarService client per account, each with rate limiter + retry + cache
 	clients := make(map[string]caldav.CalendarService, len(accounts))
 	defaultCalendars := make(map[string]string, len(accounts))
 
 	for name, acct := range accounts {
-		caldavClient, err := caldav.NewClient(acct.Email, acct.Password, caldav.ClientOptions{
+		opts := caldav.ClientOptions{
+			ServerURL:       acct.ServerURL,
 			MaxConnsPerHost: cfg.MaxConnsPerHost,
 			TLSCertFile:     cfg.TLSCertFile,
 			TLSKeyFile:      cfg.TLSKeyFile,
 			TLSCAFile:       cfg.TLSCAFile,
-		})
+		}
+
+		// A home set stored by an earlier run skips discovery, so the
+		// server also starts while the CalDAV server is unreachable
+		var accountStore *caldav.AccountStore
+		if store != nil {
+			accountStore, err = store.Account(name, acct.Email+" "+acct.ServerURL)
+			if err == nil {
+				opts.Principal, opts.CalendarHomeSet, err = accountStore.HomeSet()
+			}
+			if err != nil {
+				slog.Error("failed to read cache", "account", name, "error", err)
+				os.Exit(1)
+			}
+		}
+
+		caldavClient, err := caldav.NewClient(acct.Email, acct.Password, opts)
 		if err != nil {
 			slog.Error("failed to create CalDAV client", "account", name, "error", err)
 			os.Exit(1)
 		}
 
 		// Validate connection
-		ctx := context.Background()
-		_, err = caldavClient.DiscoverCalendarHomeSet(ctx)
-		if err != nil {
-			slog.Error("failed to connect to iCloud CalDAV (check credentials)", "account", name, "error", err)
-			os.Exit(1)
+		if opts.CalendarHomeSet == "" {
+			homeSet, err := caldavClient.DiscoverCalendarHomeSet(context.Background())
+			if err != nil {
+				slog.Error("failed to connect to CalDAV server (check credentials and server URL)", "account", name, "error", err)
+				os.Exit(1)
+			}
+			if accountStore != nil {
+				if err := accountStore.SaveHomeSet(caldavClient.Principal(), homeSet); err != nil {
+					slog.Warn("failed to store calendar home set", "account", name, "error", err)
+				}
+			}
 		}
 
-		// Wrap: real -> rateLimited -> retry
-		clients[name] = caldav.NewRetryClient(
-			caldav.NewRateLimitedClient(caldavClient, cfg.RateLimitRPS, cfg.RateLimitBurst),
-			cfg.MaxRetries, cfg.RetryBaseDelay,
+		// Wrap: real -> rateLimited -> retry -> cache
+		clients[name] = caldav.NewCachingClient(
+			caldav.NewRetryClient(
+				caldav.NewRateLimitedClient(caldavClient, cfg.RateLimitRPS, cfg.RateLimitBurst),
+				cfg.MaxRetries, cfg.RetryBaseDelay,
+			),
+			cfg.CacheTTL,
+			accountStore,
 		)
 		defaultCalendars[name] = acct.CalendarID
 
@@ -91,7 +131,7 @@ func main() {
 		toolName := req.Params.Name
 		// Only audit mutating operations
 		switch toolName {
-		case "create_event", "update_event", "delete_event":
+		case "create_event", "update_event", "delete_event", "move_event", "create_calendar", "update_calendar", "delete_calendar", "create_task", "update_task", "complete_task", "respond_to_invitation":
 		default:
 			return
 		}
@@ -106,6 +146,10 @@ func main() {
 			"account", args["account"],
 			"calendarId", args["calendarId"],
 			"eventId", args["eventId"],
+			"taskId", args["taskId"],
+			"targetAccount", args["targetAccount"],
+			"targetCalendarId", args["targetCalendarId"],
+			"path", args["path"],
 			"status", status,
 		)
 	})
@@ -115,6 +159,7 @@ func main() {
 		"iCloud Calendar Server",
 		version,
 		server.WithToolCapabilities(false),
+		server.WithResourceCapabilities(true, false),
 		server.WithRecovery(),
 		server.WithHooks(auditHook),
 		server.WithToolHandlerMiddleware(mw.RequestIDMiddleware()),
@@ -124,7 +169,7 @@ func main() {
 
 	// Register search_events tool
 	searchEventsTool := mcp.NewTool("search_events",
-		mcp.WithDescription("Search for calendar events within a date range. Returns paginated results with event id, title, description, location, startTime, endTime, recurrence, timezone, and attendees. Use list_calendars first to discover valid calendarId values."),
+		mcp.WithDescription("Search for calendar events within a date range, optionally filtered by text in the title, description, location, attendees or categories. Returns paginated results with event id, title, description, location, startTime, endTime, recurrence (RRULE, recurrenceDates, exceptionDates), timezone, attendees, alarms, and etag. All-day events are flagged with allDay and use midnight UTC dates with an exclusive endTime; floating events (no time zone) are flagged with floating. With expandRecurrence, each occurrence carries a recurrenceId and an occurrenceId for editing or deleting it alone. Use list_calendars first to discover valid calendarId values."),
 		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithIdempotentHintAnnotation(true),
@@ -151,13 +196,52 @@ func main() {
 			mcp.DefaultNumber(0),
 			mcp.Min(0),
 		),
+		mcp.WithString("title",
+			mcp.Description("Only return events whose title contains this text, ignoring case (e.g. 'dentist')."),
+		),
+		mcp.WithString("description",
+			mcp.Description("Only return events whose description contains this text, ignoring case."),
+		),
+		mcp.WithString("location",
+			mcp.Description("Only return events whose location contains this text, ignoring case."),
+		),
+		mcp.WithString("attendee",
+			mcp.Description("Only return events with an attendee whose email address contains this text, ignoring case."),
+		),
+		mcp.WithString("category",
+			mcp.Description("Only return events with a category containing this text, ignoring case."),
+		),
 		mcp.WithBoolean("expandRecurrence",
-			mcp.Description("When true, recurring events are expanded into individual occurrences within the startTime/endTime range. Requires both startTime and endTime to be set."),
+			mcp.Description("When true, recurring events are expanded into individual occurrences within the startTime/endTime range, by the server where it supports it. Requires both startTime and endTime to be set."),
 			mcp.DefaultBool(false),
 		),
+		mcp.WithString("timezone",
+			mcp.Description("IANA time zone (e.g., 'America/New_York') to report event times in. startTime/endTime filters without an offset are read in this zone too. Defaults to each event's own zone."),
+		),
 	)
 	s.AddTool(searchEventsTool, tools.SearchEventsHandler(accountClients))
 
+	// Register get_event tool
+	getEventTool := mcp.NewTool("get_event",
+		mcp.WithDescription("Fetch a single event by its ID or path, without searching the whole calendar. Returns the parsed event (in the search_events format, with overrides of single occurrences), its path and etag, and the raw iCalendar data in ical. Use it to read an event back after create_event, or to get its current etag before an update."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("account",
+			mcp.Description("Account name for multi-account setups. Omit to use the default account."),
+		),
+		mcp.WithString("eventId",
+			mcp.Description("Event ID (UID), as returned by create_event or search_events. An occurrenceId returns the whole series. Events stored under another name are found by UID."),
+		),
+		mcp.WithString("calendarId",
+			mcp.Description("Calendar path containing the event. Uses the server's default calendar if omitted."),
+		),
+		mcp.WithString("path",
+			mcp.Description("Path of the event from a search_events result. Takes precedence over eventId and calendarId."),
+		),
+	)
+	s.AddTool(getEventTool, tools.GetEventHandler(accountClients))
+
 	// Register create_event tool
 	createEventTool := mcp.NewTool("create_event",
 		mcp.WithDescription("Create a new calendar event on iCloud. Returns the created event's unique ID on success. Use list_calendars first to discover valid calendarId values."),
@@ -174,11 +258,31 @@ func main() {
 		),
 		mcp.WithString("startTime",
 			mcp.Required(),
-			mcp.Description("Event start time in RFC 3339 format (e.g., '2025-03-15T14:30:00Z'). Must be before endTime."),
+			mcp.Description("Event start time in RFC 3339 format (e.g., '2025-03-15T14:30:00Z'). Must be before endTime. A plain date (e.g., '2025-03-15') creates an all-day event."),
 		),
 		mcp.WithString("endTime",
-			mcp.Required(),
-			mcp.Description("Event end time in RFC 3339 format (e.g., '2025-03-15T16:30:00Z'). Must be after startTime."),
+			mcp.Description("Event end time in RFC 3339 format (e.g., '2025-03-15T16:30:00Z'). Must be after startTime. Required for timed events. For all-day events this is the exclusive end date (e.g., '2025-03-17' for 15-16 March); omit it or set it equal to startTime for a single day."),
+		),
+		mcp.WithBoolean("allDay",
+			mcp.Description("When true, creates an all-day event using only the dates of startTime and endTime."),
+		),
+		mcp.WithBoolean("floating",
+			mcp.Description("When true, creates a floating event that happens at the same wall-clock time in every time zone (e.g., '2025-03-15T07:00:00'). Any offset in startTime/endTime is ignored."),
+		),
+		mcp.WithString("recurrence",
+			mcp.Description("RFC 5545 recurrence rule to create a recurring series as a single event (e.g., 'FREQ=WEEKLY;BYDAY=MO;COUNT=52' or 'FREQ=MONTHLY;BYMONTHDAY=1;UNTIL=20251231T000000Z'). startTime/endTime define the first occurrence."),
+		),
+		mcp.WithString("recurrenceDates",
+			mcp.Description("JSON array of extra occurrence start times (RDATE), in the same format as startTime. Example: [\"2025-03-20T09:00:00Z\"]"),
+		),
+		mcp.WithString("exceptionDates",
+			mcp.Description("JSON array of occurrence start times to skip (EXDATE), in the same format as startTime. Example: [\"2025-04-14T09:00:00Z\"]"),
+		),
+		mcp.WithString("alarms",
+			mcp.Description("JSON array of alarm objects. Each requires 'action' (DISPLAY, AUDIO or EMAIL) and 'trigger': a duration relative to the start such as '-PT15M' or '-P1D', or an absolute RFC 3339 time. Optional: 'relatedEnd' (true to count from the end), 'description', and for EMAIL 'summary' and 'attendees' (required list of email addresses). Example: [{\"action\":\"DISPLAY\",\"trigger\":\"-PT15M\"}]"),
+		),
+		mcp.WithString("timezone",
+			mcp.Description("IANA time zone of the event (e.g., 'America/New_York'). Times are stored in this zone so recurring events keep their local time across daylight saving changes, and startTime/endTime may omit the offset (e.g., '2025-03-15T09:00:00'). Defaults to UTC."),
 		),
 		mcp.WithString("description",
 			mcp.Description("Detailed event description or notes."),
@@ -190,7 +294,38 @@ func main() {
 			mcp.Description("Calendar path from list_calendars to create the event in. Uses the server's default calendar if omitted."),
 		),
 		mcp.WithString("attendees",
-			mcp.Description("JSON array of attendee objects. Each object requires 'email' and optionally 'name', 'role' (CHAIR, REQ-PARTICIPANT, OPT-PARTICIPANT), and 'status' (NEEDS-ACTION, ACCEPTED, DECLINED, TENTATIVE). Example: [{\"email\":\"alice@example.com\",\"name\":\"Alice\"}]"),
+			mcp.Description("JSON array of attendee objects. Each object requires 'email' and optionally 'name', 'role' (CHAIR, REQ-PARTICIPANT, OPT-PARTICIPANT), and 'status' (NEEDS-ACTION, ACCEPTED, DECLINED, TENTATIVE), 'rsvp' (true or false), 'type' (INDIVIDUAL, GROUP, RESOURCE, ROOM), 'scheduleAgent' (SERVER, the default: the server sends the invitation; CLIENT: this client sends it; NONE: nobody does). Example: [{\"email\":\"alice@example.com\",\"name\":\"Alice\"}]"),
+		),
+		mcp.WithString("status",
+			mcp.Description("Event status: TENTATIVE, CONFIRMED or CANCELLED. Omit to leave it unset."),
+			mcp.Enum(caldav.EventTentative, caldav.EventConfirmed, caldav.EventCancelled),
+		),
+		mcp.WithBoolean("transparent",
+			mcp.Description("Set to true for events that should not block time in free/busy lookups (TRANSP:TRANSPARENT), such as reminders or optional sessions. Defaults to false."),
+		),
+		mcp.WithString("categories",
+			mcp.Description("JSON array of category names (e.g., [\"Work\",\"Travel\"])."),
+		),
+		mcp.WithString("url",
+			mcp.Description("Absolute URL of a page about the event, such as an agenda or a video call link."),
+		),
+		mcp.WithString("class",
+			mcp.Description("Access classification: PUBLIC, PRIVATE or CONFIDENTIAL."),
+			mcp.Enum(caldav.ClassPublic, caldav.ClassPrivate, caldav.ClassConfidential),
+		),
+		mcp.WithNumber("priority",
+			mcp.Description("Priority from 1 (highest) to 9 (lowest). 0 or omitted means no priority."),
+		),
+		mcp.WithString("organizer",
+			mcp.Description("Email address of the organizer, for events with attendees. Defaults to the account's own address."),
+		),
+		mcp.WithString("checkConflicts",
+			mcp.Description("Whether to look for existing events that overlap the new one (including occurrences of recurring events, and the first 90 days of a recurring new event) before creating it: 'off' (the default) skips the check, 'warn' creates the event and lists the overlapping events in 'conflicts', 'reject' refuses to create it if anything overlaps and lists the overlapping events."),
+			mcp.Enum("off", "warn", "reject"),
+		),
+		mcp.WithString("conflictScope",
+			mcp.Description("Where to look for overlapping events: 'calendar' (the default) for the target calendar only, or 'account' for every event calendar of the account."),
+			mcp.Enum("calendar", "account"),
 		),
 	)
 	s.AddTool(createEventTool, tools.CreateEventHandler(accountClients))
@@ -205,12 +340,14 @@ func main() {
 			mcp.Description("Account name for multi-account setups. Omit to use the default account."),
 		),
 		mcp.WithString("eventId",
-			mcp.Required(),
-			mcp.Description("Unique event ID (UID) from a previous search_events result."),
+			mcp.Description("Unique event ID (UID) from a previous search_events result, or the occurrenceId of an expanded occurrence to update just that occurrence. Required unless path is given."),
 		),
 		mcp.WithString("calendarId",
 			mcp.Description("Calendar path containing the event. Uses the server's default calendar if omitted."),
 		),
+		mcp.WithString("path",
+			mcp.Description("Path of the event from a search_events result. Takes precedence over eventId and calendarId; use it for events whose path does not end in their UID."),
+		),
 		mcp.WithString("title",
 			mcp.Description("Updated event title. Omit to keep the current title. Set to empty string to clear."),
 		),
@@ -221,17 +358,83 @@ func main() {
 			mcp.Description("Updated event location. Omit to keep the current location. Set to empty string to clear."),
 		),
 		mcp.WithString("startTime",
-			mcp.Description("Updated start time in RFC 3339 format (e.g., '2025-03-15T14:30:00Z'). Omit to keep the current start time."),
+			mcp.Description("Updated start time in RFC 3339 format (e.g., '2025-03-15T14:30:00Z'), or a date (e.g., '2025-03-15') for all-day events. Omit to keep the current start time."),
 		),
 		mcp.WithString("endTime",
-			mcp.Description("Updated end time in RFC 3339 format (e.g., '2025-03-15T16:30:00Z'). Omit to keep the current end time. Must be after startTime if both are provided."),
+			mcp.Description("Updated end time in RFC 3339 format (e.g., '2025-03-15T16:30:00Z'). Omit to keep the current end time. Must be after startTime if both are provided. For all-day events this is the exclusive end date."),
+		),
+		mcp.WithBoolean("allDay",
+			mcp.Description("Set to true to turn the event into an all-day event, or false to turn an all-day event into a timed one (requires startTime and endTime). Omit to keep the current kind."),
+		),
+		mcp.WithString("recurrence",
+			mcp.Description("Updated RFC 5545 recurrence rule for the whole series (e.g., 'FREQ=WEEKLY;BYDAY=MO,WE'). Set to empty string to make the event non-recurring. Omit to keep the current rule."),
+		),
+		mcp.WithString("recurrenceDates",
+			mcp.Description("JSON array replacing the series' extra occurrence start times (RDATE). Set to '[]' to clear. Omit to keep the current list."),
+		),
+		mcp.WithString("exceptionDates",
+			mcp.Description("JSON array replacing the occurrence start times skipped in the series (EXDATE). Set to '[]' to clear. Omit to keep the current list."),
+		),
+		mcp.WithString("alarms",
+			mcp.Description("JSON array of alarm objects replacing the event's alarms, in the same format as create_event. Alarms passed back as search_events reported them are kept unchanged, even with other actions. Set to '[]' to remove all alarms. Omit to keep the current alarms."),
+		),
+		mcp.WithString("attendees",
+			mcp.Description("JSON array of attendee objects replacing the attendee list, in the same format as create_event. Attendees already invited keep their other parameters (such as rsvp, type or delegatedFrom). Set to '[]' to remove all attendees. Omit to keep the current list."),
+		),
+		mcp.WithString("addAttendees",
+			mcp.Description("JSON array of attendee objects to invite. An attendee who is already invited is updated instead, e.g. [{\"email\":\"bob@example.com\",\"role\":\"OPT-PARTICIPANT\"}] changes Bob's role and keeps the rest."),
+		),
+		mcp.WithString("removeAttendees",
+			mcp.Description("JSON array of email addresses of attendees to remove (e.g., [\"bob@example.com\"])."),
+		),
+		mcp.WithString("status",
+			mcp.Description("New status: TENTATIVE, CONFIRMED or CANCELLED. Set to empty string to clear. Changing the status, like changing the times or recurrence, increases the event's sequence number."),
+		),
+		mcp.WithBoolean("transparent",
+			mcp.Description("Set to true so the event no longer blocks time in free/busy lookups, or false so it does. Omit to keep the current setting."),
+		),
+		mcp.WithString("categories",
+			mcp.Description("JSON array replacing the event's categories. Set to '[]' to clear. Omit to keep the current categories."),
+		),
+		mcp.WithString("url",
+			mcp.Description("New absolute URL of a page about the event. Set to empty string to clear."),
+		),
+		mcp.WithString("class",
+			mcp.Description("New access classification: PUBLIC, PRIVATE or CONFIDENTIAL. Set to empty string to clear."),
+		),
+		mcp.WithNumber("priority",
+			mcp.Description("New priority from 1 (highest) to 9 (lowest), or 0 to clear it."),
+		),
+		mcp.WithString("organizer",
+			mcp.Description("Email address of the new organizer, for events with attendees. Set to an empty string to make the account the organizer again."),
+		),
+		mcp.WithString("timezone",
+			mcp.Description("IANA time zone to move the event to (e.g., 'Europe/Berlin'). startTime/endTime may then omit the offset. Omit to keep the event's current zone."),
+		),
+		mcp.WithString("recurrenceId",
+			mcp.Description("Original start time of one occurrence of a recurring event, as reported in recurrenceId by search_events with expandRecurrence. Omit to update the whole series."),
+		),
+		mcp.WithString("scope",
+			mcp.Description("Which occurrences to update: 'this' (the default with recurrenceId) for just the given occurrence, 'thisAndFollowing' for it and all later ones, or 'all' for the whole series."),
+			mcp.Enum("this", "thisAndFollowing", "all"),
+		),
+		mcp.WithString("etag",
+			mcp.Description("ETag from the search_events result this update is based on. If the event has changed on the server since, the update is refused and the current version is returned so you can re-apply your change."),
+		),
+		mcp.WithString("checkConflicts",
+			mcp.Description("Whether to look for other events that overlap the new times before moving the event: 'off' (the default) skips the check, 'warn' updates the event and lists the overlapping events in 'conflicts', 'reject' refuses the update if anything overlaps. Times not given are taken from the event."),
+			mcp.Enum("off", "warn", "reject"),
+		),
+		mcp.WithString("conflictScope",
+			mcp.Description("Where to look for overlapping events: 'calendar' (the default) for the event's calendar only, or 'account' for every event calendar of the account."),
+			mcp.Enum("calendar", "account"),
 		),
 	)
 	s.AddTool(updateEventTool, tools.UpdateEventHandler(accountClients))
 
 	// Register delete_event tool
 	deleteEventTool := mcp.NewTool("delete_event",
-		mcp.WithDescription("Permanently delete a calendar event. This action cannot be undone. Use search_events first to find the event's id and calendarId."),
+		mcp.WithDescription("Permanently delete a calendar event, or selected occurrences of a recurring one. This action cannot be undone. Use search_events first to find the event's id and calendarId."),
 		mcp.WithReadOnlyHintAnnotation(false),
 		mcp.WithDestructiveHintAnnotation(true),
 		mcp.WithIdempotentHintAnnotation(true),
@@ -239,28 +442,441 @@ func main() {
 			mcp.Description("Account name for multi-account setups. Omit to use the default account."),
 		),
 		mcp.WithString("eventId",
-			mcp.Required(),
-			mcp.Description("Unique event ID (UID) from a previous search_events result."),
+			mcp.Description("Unique event ID (UID) from a previous search_events result, or the occurrenceId of an expanded occurrence to delete just that occurrence. Required unless path is given."),
 		),
 		mcp.WithString("calendarId",
-			mcp.Required(),
-			mcp.Description("Calendar path containing the event. Required for delete operations to ensure the correct calendar is targeted."),
+			mcp.Description("Calendar path containing the event. Always pass it with eventId to ensure the correct calendar is targeted."),
+		),
+		mcp.WithString("path",
+			mcp.Description("Path of the event from a search_events result. Takes precedence over eventId and calendarId; use it for events whose path does not end in their UID."),
+		),
+		mcp.WithString("recurrenceId",
+			mcp.Description("Original start time of one occurrence of a recurring event, as reported in recurrenceId by search_events with expandRecurrence. Omit to delete the whole series."),
+		),
+		mcp.WithString("scope",
+			mcp.Description("Which occurrences to delete: 'this' (the default with recurrenceId) for just the given occurrence, 'thisAndFollowing' for it and all later ones, or 'all' for the whole series."),
+			mcp.Enum("this", "thisAndFollowing", "all"),
+		),
+		mcp.WithString("etag",
+			mcp.Description("ETag from the search_events result. If the event has changed on the server since, the delete is refused and the current version is returned."),
 		),
 	)
 	s.AddTool(deleteEventTool, tools.DeleteEventHandler(accountClients))
 
+	// Register move_event tool
+	moveEventTool := mcp.NewTool("move_event",
+		mcp.WithDescription("Move or copy an event to another calendar, in the same or another account. The event keeps its UID, attendees, alarms, overrides of single occurrences and every other property. Use search_events first to find the event's id and calendarId, and list_calendars for the target calendar."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("account",
+			mcp.Description("Account name of the event for multi-account setups. Omit to use the default account."),
+		),
+		mcp.WithString("eventId",
+			mcp.Description("Unique event ID (UID) from a previous search_events result. An occurrenceId moves the whole series. Required unless path is given."),
+		),
+		mcp.WithString("calendarId",
+			mcp.Description("Calendar path containing the event. Defaults to the account's default calendar."),
+		),
+		mcp.WithString("targetCalendarId",
+			mcp.Required(),
+			mcp.Description("Calendar path to move the event to, from list_calendars (of targetAccount, if given)."),
+		),
+		mcp.WithString("targetAccount",
+			mcp.Description("Account to move the event to. Omit to stay in the same account."),
+		),
+		mcp.WithBoolean("copy",
+			mcp.Description("Copy the event instead of moving it, keeping the original. The copy keeps the UID."),
+		),
+		mcp.WithString("etag",
+			mcp.Description("ETag from the search_events result. If the event has changed on the server since, the move is refused and the current version is returned."),
+		),
+		mcp.WithString("path",
+			mcp.Description("Path of the event from a search_events result. Takes precedence over eventId and calendarId; use it for events whose path does not end in their UID."),
+		),
+	)
+	s.AddTool(moveEventTool, tools.MoveEventHandler(accountClients))
+
 	// Register list_calendars tool
 	listCalendarsTool := mcp.NewTool("list_calendars",
-		mcp.WithDescription("List all available iCloud calendars for the account. Returns each calendar's path (use as calendarId in other tools), display name, description, and color. Call this first to discover valid calendarId values before using search_events, create_event, update_event, or delete_event."),
+		mcp.WithDescription("List all available iCloud calendars for the account. Returns each calendar's path (use as calendarId in other tools), display name, description, color, order, the component types it supports (VEVENT for events, VTODO for tasks), whether it is read-only or shared by another user, its owner and its ctag. Call this first to discover valid calendarId values before using search_events, create_event, update_event, or delete_event."),
 		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithIdempotentHintAnnotation(true),
 		mcp.WithString("account",
 			mcp.Description("Account name for multi-account setups. Omit to use the default account."),
 		),
+		mcp.WithString("component",
+			mcp.Description("Only list calendars that hold this component type: VEVENT for event calendars, VTODO for reminder lists."),
+			mcp.Enum("VEVENT", "VTODO"),
+		),
+		mcp.WithBoolean("writable",
+			mcp.Description("When true, only list calendars the user can add events to, leaving out subscribed and read-only shared calendars."),
+		),
 	)
 	s.AddTool(listCalendarsTool, tools.ListCalendarsHandler(accountClients))
 
+	// Register create_calendar tool
+	createCalendarTool := mcp.NewTool("create_calendar",
+		mcp.WithDescription("Create a new calendar in the account, for example one per project. Returns the new calendar's path, to use as calendarId in other tools."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("account",
+			mcp.Description("Account name for multi-account setups. Omit to use the default account."),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Display name of the calendar (e.g., 'Project X')."),
+			mcp.MinLength(1),
+		),
+		mcp.WithString("description",
+			mcp.Description("Description of the calendar."),
+		),
+		mcp.WithString("color",
+			mcp.Description("Color as #RRGGBB or #RRGGBBAA (e.g., '#FF2968')."),
+		),
+		mcp.WithString("components",
+			mcp.Description("JSON array of the component types the calendar holds: VEVENT for events, VTODO for tasks. Defaults to [\"VEVENT\"]; iCloud keeps events and reminders in separate calendars."),
+		),
+	)
+	s.AddTool(createCalendarTool, tools.CreateCalendarHandler(accountClients))
+
+	// Register update_calendar tool
+	updateCalendarTool := mcp.NewTool("update_calendar",
+		mcp.WithDescription("Rename, recolor, describe or reorder a calendar. Only the given fields change. Use list_calendars first to find the calendarId."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("account",
+			mcp.Description("Account name for multi-account setups. Omit to use the default account."),
+		),
+		mcp.WithString("calendarId",
+			mcp.Required(),
+			mcp.Description("Calendar path from list_calendars."),
+		),
+		mcp.WithString("name",
+			mcp.Description("New display name."),
+		),
+		mcp.WithString("description",
+			mcp.Description("New description. Pass an empty string to remove it."),
+		),
+		mcp.WithString("color",
+			mcp.Description("New color as #RRGGBB or #RRGGBBAA. Pass an empty string to remove it."),
+		),
+		mcp.WithNumber("order",
+			mcp.Description("Position of the calendar in Apple Calendar's list; lower numbers come first."),
+			mcp.Min(0),
+		),
+	)
+	s.AddTool(updateCalendarTool, tools.UpdateCalendarHandler(accountClients))
+
+	// Register delete_calendar tool
+	deleteCalendarTool := mcp.NewTool("delete_calendar",
+		mcp.WithDescription("Permanently delete a calendar together with every event and task in it. This action cannot be undone. Use list_calendars first to find the calendarId."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("account",
+			mcp.Description("Account name for multi-account setups. Omit to use the default account."),
+		),
+		mcp.WithString("calendarId",
+			mcp.Required(),
+			mcp.Description("Calendar path from list_calendars. Never defaults to the configured calendar."),
+		),
+	)
+	s.AddTool(deleteCalendarTool, tools.DeleteCalendarHandler(accountClients))
+
+	// Register get_free_busy tool
+	freeBusyTool := mcp.NewTool("get_free_busy",
+		mcp.WithDescription("Get the busy time intervals in a date range without reading any event details. Busy intervals from all event calendars of the account are merged; transparent (free) and cancelled events are ignored. Use this instead of search_events to find free slots."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("account",
+			mcp.Description("Account name for multi-account setups. Omit to use the default account."),
+		),
+		mcp.WithString("accounts",
+			mcp.Description("JSON array of account names to merge busy time across (e.g., [\"work\", \"personal\"]). Cannot be combined with account, allAccounts or calendarIds."),
+		),
+		mcp.WithBoolean("allAccounts",
+			mcp.Description("When true, merges busy time across every configured account. Cannot be combined with account, accounts or calendarIds."),
+		),
+		mcp.WithString("startTime",
+			mcp.Required(),
+			mcp.Description("Start of the range in RFC 3339 format (e.g., '2025-03-15T09:00:00Z')."),
+		),
+		mcp.WithString("endTime",
+			mcp.Required(),
+			mcp.Description("End of the range in RFC 3339 format (e.g., '2025-03-15T17:00:00Z'). Must be after startTime."),
+		),
+		mcp.WithString("calendarIds",
+			mcp.Description("JSON array of calendar paths from list_calendars to include. Defaults to every calendar of the account that holds events. Example: [\"/calendars/user/work/\"]"),
+		),
+		mcp.WithString("timezone",
+			mcp.Description("IANA time zone (e.g., 'America/New_York') to report busy times in. startTime/endTime without an offset are read in this zone, and all-day events block the whole day in it. Defaults to the offset of startTime."),
+		),
+	)
+	s.AddTool(freeBusyTool, tools.FreeBusyHandler(accountClients))
+
+	// Register find_available_slots tool
+	findSlotsTool := mcp.NewTool("find_available_slots",
+		mcp.WithDescription("Propose meeting times: finds free slots of a given length within working hours across one or more calendars and accounts, and returns them ranked best first. Slots that sit right next to existing meetings or the edge of the working day rank above ones that would fragment free time."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("account",
+			mcp.Description("Account name for multi-account setups. Omit to use the default account."),
+		),
+		mcp.WithString("accounts",
+			mcp.Description("JSON array of account names whose busy time to consider (e.g., [\"work\", \"personal\"]). Cannot be combined with account, allAccounts or calendarIds."),
+		),
+		mcp.WithBoolean("allAccounts",
+			mcp.Description("When true, considers busy time from every configured account. Cannot be combined with account, accounts or calendarIds."),
+		),
+		mcp.WithString("calendarIds",
+			mcp.Description("JSON array of calendar paths from list_calendars whose events block time. Defaults to every calendar of the account that holds events."),
+		),
+		mcp.WithNumber("durationMinutes",
+			mcp.Required(),
+			mcp.Description("Length of the meeting in minutes."),
+			mcp.Min(1),
+			mcp.Max(1440),
+		),
+		mcp.WithString("startTime",
+			mcp.Required(),
+			mcp.Description("Start of the search window, in RFC 3339 format or as a local time in timezone (e.g., '2025-03-17T00:00:00')."),
+		),
+		mcp.WithString("endTime",
+			mcp.Required(),
+			mcp.Description("End of the search window, in the same format as startTime. At most 90 days after startTime."),
+		),
+		mcp.WithString("timezone",
+			mcp.Required(),
+			mcp.Description("IANA time zone (e.g., 'America/New_York') in which working hours apply and slots are reported."),
+		),
+		mcp.WithString("workingHoursStart",
+			mcp.Description("Start of the working day as HH:MM."),
+			mcp.DefaultString("09:00"),
+		),
+		mcp.WithString("workingHoursEnd",
+			mcp.Description("End of the working day as HH:MM."),
+			mcp.DefaultString("17:00"),
+		),
+		mcp.WithString("workingDays",
+			mcp.Description("Comma-separated weekdays on which meetings may be proposed, as iCalendar codes (SU, MO, TU, WE, TH, FR, SA)."),
+			mcp.DefaultString("MO,TU,WE,TH,FR"),
+		),
+		mcp.WithNumber("bufferMinutes",
+			mcp.Description("Minutes to keep free before and after existing events."),
+			mcp.DefaultNumber(0),
+			mcp.Min(0),
+		),
+		mcp.WithNumber("stepMinutes",
+			mcp.Description("Proposed slots start on multiples of this many minutes after the hour."),
+			mcp.DefaultNumber(30),
+			mcp.Min(5),
+			mcp.Max(60),
+		),
+		mcp.WithNumber("maxResults",
+			mcp.Description("Maximum number of slots to return."),
+			mcp.DefaultNumber(10),
+			mcp.Min(1),
+			mcp.Max(100),
+		),
+	)
+	s.AddTool(findSlotsTool, tools.FindAvailableSlotsHandler(accountClients))
+
+	// Register list_tasks tool
+	listTasksTool := mcp.NewTool("list_tasks",
+		mcp.WithDescription("List the tasks (reminders) in a calendar. Returns each task's id, title, due date, priority, status and etag. Use list_calendars to find calendars that support VTODO; iCloud keeps reminders in their own calendars."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("account",
+			mcp.Description("Account name for multi-account setups. Omit to use the default account."),
+		),
+		mcp.WithString("calendarId",
+			mcp.Description("Calendar path from list_calendars to list tasks from. Uses the default calendar if omitted."),
+		),
+		mcp.WithBoolean("includeCompleted",
+			mcp.Description("When true, also returns completed and cancelled tasks."),
+		),
+	)
+	s.AddTool(listTasksTool, tools.ListTasksHandler(accountClients))
+
+	// Register create_task tool
+	createTaskTool := mcp.NewTool("create_task",
+		mcp.WithDescription("Create a new task (reminder). Returns the created task's unique ID on success."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("account",
+			mcp.Description("Account name for multi-account setups. Omit to use the default account."),
+		),
+		mcp.WithString("title",
+			mcp.Required(),
+			mcp.Description("Task title."),
+			mcp.MinLength(1),
+		),
+		mcp.WithString("description",
+			mcp.Description("Detailed task description or notes."),
+		),
+		mcp.WithString("due",
+			mcp.Description("Due time in RFC 3339 format (e.g., '2025-03-15T17:00:00Z'). A plain date (e.g., '2025-03-15') makes the task due on that day."),
+		),
+		mcp.WithNumber("priority",
+			mcp.Description("Priority from 1 (highest) to 9 (lowest). 0 or omitted means no priority."),
+			mcp.Min(0),
+			mcp.Max(9),
+		),
+		mcp.WithString("relatedTo",
+			mcp.Description("ID of the parent task, to create a subtask."),
+		),
+		mcp.WithString("calendarId",
+			mcp.Description("Calendar path from list_calendars to create the task in. Must support VTODO. Uses the default calendar if omitted."),
+		),
+	)
+	s.AddTool(createTaskTool, tools.CreateTaskHandler(accountClients))
+
+	// Register update_task tool
+	updateTaskTool := mcp.NewTool("update_task",
+		mcp.WithDescription("Update specific fields of an existing task. Only include the fields you want to change. Omitted fields remain unchanged. Set a text field or due to an empty string to clear it. Use list_tasks first to find the task's id."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("account",
+			mcp.Description("Account name for multi-account setups. Omit to use the default account."),
+		),
+		mcp.WithString("taskId",
+			mcp.Description("Unique task ID from a previous list_tasks result. Required unless path is given."),
+		),
+		mcp.WithString("calendarId",
+			mcp.Description("Calendar path containing the task. Uses the default calendar if omitted."),
+		),
+		mcp.WithString("path",
+			mcp.Description("Path of the task from a list_tasks result. Takes precedence over taskId and calendarId; use it for tasks whose path does not end in their ID."),
+		),
+		mcp.WithString("title",
+			mcp.Description("New task title."),
+		),
+		mcp.WithString("description",
+			mcp.Description("New task description."),
+		),
+		mcp.WithString("due",
+			mcp.Description("New due time in RFC 3339 format, or a plain date for a task due on that day."),
+		),
+		mcp.WithNumber("priority",
+			mcp.Description("New priority from 1 (highest) to 9 (lowest), or 0 to clear it."),
+			mcp.Min(0),
+			mcp.Max(9),
+		),
+		mcp.WithString("status",
+			mcp.Description("New status. COMPLETED records the completion time; any other status reopens the task."),
+			mcp.Enum(caldav.TaskNeedsAction, caldav.TaskInProcess, caldav.TaskCompleted, caldav.TaskCancelled),
+		),
+		mcp.WithNumber("percentComplete",
+			mcp.Description("How far along the task is, from 0 to 100."),
+			mcp.Min(0),
+			mcp.Max(100),
+		),
+		mcp.WithString("relatedTo",
+			mcp.Description("ID of the new parent task."),
+		),
+		mcp.WithString("etag",
+			mcp.Description("ETag from the list_tasks result. If the task has changed on the server since, the update is refused and the current version is returned."),
+		),
+	)
+	s.AddTool(updateTaskTool, tools.UpdateTaskHandler(accountClients))
+
+	// Register complete_task tool
+	completeTaskTool := mcp.NewTool("complete_task",
+		mcp.WithDescription("Mark a task as completed now. Use list_tasks first to find the task's id."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("account",
+			mcp.Description("Account name for multi-account setups. Omit to use the default account."),
+		),
+		mcp.WithString("taskId",
+			mcp.Description("Unique task ID from a previous list_tasks result. Required unless path is given."),
+		),
+		mcp.WithString("calendarId",
+			mcp.Description("Calendar path containing the task. Uses the default calendar if omitted."),
+		),
+		mcp.WithString("path",
+			mcp.Description("Path of the task from a list_tasks result. Takes precedence over taskId and calendarId; use it for tasks whose path does not end in their ID."),
+		),
+		mcp.WithString("etag",
+			mcp.Description("ETag from the list_tasks result. If the task has changed on the server since, the change is refused and the current version is returned."),
+		),
+	)
+	s.AddTool(completeTaskTool, tools.CompleteTaskHandler(accountClients))
+
+	// Register list_invitations tool
+	listInvitationsTool := mcp.NewTool("list_invitations",
+		mcp.WithDescription("List meeting invitations the user has not answered yet: events in the user's calendars that list the user as an attendee with status NEEDS-ACTION, and invitations waiting in the CalDAV scheduling inbox. Answer them with respond_to_invitation."),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("account",
+			mcp.Description("Account name for multi-account setups. Omit to use the default account."),
+		),
+		mcp.WithString("startTime",
+			mcp.Description("Only list invited events ending after this time (RFC 3339). Defaults to now."),
+		),
+		mcp.WithString("endTime",
+			mcp.Description("Only list invited events starting before this time (RFC 3339). Defaults to 90 days after startTime. Invitations in the scheduling inbox are always listed."),
+		),
+	)
+	s.AddTool(listInvitationsTool, tools.ListInvitationsHandler(accountClients))
+
+	// Register respond_to_invitation tool
+	respondToInvitationTool := mcp.NewTool("respond_to_invitation",
+		mcp.WithDescription("Accept, decline or tentatively accept a meeting invitation. Sets the user's participation status in the event; the server then notifies the organizer, unless the invitation's scheduleAgent is CLIENT, in which case the reply is sent through the scheduling outbox, or NONE, in which case no reply is sent. Use list_invitations first to find the invitation's path."),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("account",
+			mcp.Description("Account name for multi-account setups. Omit to use the default account."),
+		),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Path of the invitation from a previous list_invitations result."),
+		),
+		mcp.WithString("response",
+			mcp.Required(),
+			mcp.Description("The answer to send to the organizer."),
+			mcp.Enum(caldav.PartStatAccepted, caldav.PartStatDeclined, caldav.PartStatTentative),
+		),
+		mcp.WithString("comment",
+			mcp.Description("Optional note to the organizer, such as a reason for declining."),
+		),
+		mcp.WithString("etag",
+			mcp.Description("ETag from the list_invitations result. If the invitation has changed on the server since (for example because the organizer moved it), the response is refused and the current version is returned."),
+		),
+	)
+	s.AddTool(respondToInvitationTool, tools.RespondToInvitationHandler(accountClients))
+
+	// Register calendar and event resources
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate(resources.CalendarTemplate, "Calendar",
+			mcp.WithTemplateDescription("A calendar and its events in the next 30 days. The calendar path is the calendarId from list_calendars without its leading slash."),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		resources.CalendarHandler(accountClients),
+	)
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate(resources.EventTemplate, "Event",
+			mcp.WithTemplateDescription("An event by UID, as JSON and as iCalendar data."),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		resources.EventHandler(accountClients),
+	)
+	for _, resource := range resources.CalendarResources(context.Background(), accountClients) {
+		s.AddResource(resource, resources.CalendarHandler(accountClients))
+	}
+
 	// Start health server if configured
 	var healthServer *health.Server
 	var httpServer *http.Server
@@ -305,8 +921,15 @@ func main() {
 		cancel()
 	}()
 
+	// Poll subscribed resources for changes made on other devices
+	watcher := resources.NewWatcher(accountClients, cfg.PollInterval, func(uri string) {
+		s.SendNotificationToAllClients(string(mcp.MethodNotificationResourceUpdated), map[string]any{"uri": uri})
+	})
+	go watcher.Run(ctx)
+
+	stdin, stdout := resources.FilterStdio(ctx, os.Stdin, os.Stdout, watcher)
 	stdioServer := server.NewStdioServer(s)
-	err = stdioServer.Listen(ctx, os.Stdin, os.Stdout)
+	err = stdioServer.Listen(ctx, stdin, stdout)
 	cancel()
 	if err != nil {
 		slog.Error("server error", "error", err)
@@ -0,0 +1,227 @@
+package resources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+	"github.com/rgabriel/mcp-icloud-calendar/tools"
+)
+
+// upcomingWindow is how far ahead a calendar resource lists events.
+const upcomingWindow = 30 * 24 * time.Hour
+
+// CalendarHandler creates a handler for reading calendar resources: the
+// calendar's properties and its events in the next 30 days, with recurring
+// events expanded into occurrences.
+func CalendarHandler(accounts *tools.AccountClients) func(context.Context, mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		ref, err := parseURI(req.Params.URI)
+		if err != nil {
+			return nil, err
+		}
+		if ref.calendarPath == "" {
+			return nil, fmt.Errorf("%w %q: not a calendar URI", errInvalidURI, req.Params.URI)
+		}
+		client, cal, stale, err := lookupCalendar(ctx, accounts, ref)
+		if err != nil {
+			return nil, err
+		}
+
+		start := time.Now()
+		end := start.Add(upcomingWindow)
+		events, err := client.SearchEvents(ctx, cal.Path, &start, &end, caldav.SearchOptions{Expand: true})
+		if err != nil && !errors.As(err, &stale) {
+			return nil, fmt.Errorf("failed to search events: %w", err)
+		}
+		if events == nil {
+			events = []caldav.Event{}
+		}
+
+		response := map[string]interface{}{
+			"account":  ref.account,
+			"calendar": cal,
+			"from":     start,
+			"to":       end,
+			"count":    len(events),
+			"events":   events,
+		}
+		if stale != nil {
+			response["stale"] = true
+			response["syncedAt"] = stale.Since
+		}
+		return jsonContents(req.Params.URI, response)
+	}
+}
+
+// EventHandler creates a handler for reading event resources: the event as
+// JSON, and as iCalendar data when it can be encoded.
+func EventHandler(accounts *tools.AccountClients) func(context.Context, mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		ref, err := parseURI(req.Params.URI)
+		if err != nil {
+			return nil, err
+		}
+		if ref.uid == "" {
+			return nil, fmt.Errorf("%w %q: not an event URI", errInvalidURI, req.Params.URI)
+		}
+		client, defaultCalendar, err := resolve(accounts, ref.account)
+		if err != nil {
+			return nil, err
+		}
+
+		event, obj, calendarPath, err := findEvent(ctx, client, defaultCalendar, ref)
+		if err != nil {
+			return nil, err
+		}
+
+		contents, err := jsonContents(req.Params.URI, map[string]interface{}{
+			"account":    ref.account,
+			"calendarId": calendarPath,
+			"event":      event,
+			"path":       event.Path,
+			"etag":       event.ETag,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if obj != nil && obj.Data != nil {
+			var buf bytes.Buffer
+			if err := ical.NewEncoder(&buf).Encode(obj.Data); err != nil {
+				slog.Warn("failed to encode iCalendar data", "path", event.Path, "error", err)
+			} else {
+				contents = append(contents, mcp.TextResourceContents{
+					URI:      req.Params.URI,
+					MIMEType: "text/calendar",
+					Text:     buf.String(),
+				})
+			}
+		}
+		return contents, nil
+	}
+}
+
+// CalendarResources returns a resource for every calendar of every account.
+// Accounts whose calendars cannot be listed are left out.
+func CalendarResources(ctx context.Context, accounts *tools.AccountClients) []mcp.Resource {
+	names := accounts.AccountNames()
+	sort.Strings(names)
+
+	var resources []mcp.Resource
+	for _, name := range names {
+		client, _, err := accounts.Resolve(name)
+		if err != nil {
+			continue
+		}
+		calendars, err := client.ListCalendars(ctx)
+		if err != nil && !errors.As(err, new(*caldav.StaleError)) {
+			slog.Warn("failed to list calendars for resources", "account", name, "error", err)
+			continue
+		}
+		for _, cal := range calendars {
+			description := cal.Description
+			if description == "" {
+				description = fmt.Sprintf("Calendar %s of account %s and its events in the next 30 days", cal.Name, name)
+			}
+			resources = append(resources, mcp.NewResource(CalendarURI(name, cal.Path), cal.Name,
+				mcp.WithResourceDescription(description),
+				mcp.WithMIMEType("application/json"),
+			))
+		}
+	}
+	return resources
+}
+
+// resolve returns the client and default calendar of the named account.
+func resolve(accounts *tools.AccountClients, account string) (caldav.CalendarService, string, error) {
+	client, defaultCalendar, err := accounts.Resolve(account)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", server.ErrResourceNotFound, err)
+	}
+	return client, defaultCalendar, nil
+}
+
+// lookupCalendar returns the client of the account in ref and its calendar
+// at the path in ref. A calendar list answered from the cache while the
+// server is unreachable is returned with a StaleError.
+func lookupCalendar(ctx context.Context, accounts *tools.AccountClients, ref resourceRef) (caldav.CalendarService, caldav.Calendar, *caldav.StaleError, error) {
+	client, _, err := resolve(accounts, ref.account)
+	if err != nil {
+		return nil, caldav.Calendar{}, nil, err
+	}
+	var stale *caldav.StaleError
+	calendars, err := client.ListCalendars(ctx)
+	if err != nil && !errors.As(err, &stale) {
+		return nil, caldav.Calendar{}, nil, fmt.Errorf("failed to list calendars: %w", err)
+	}
+	cal, ok := findCalendar(calendars, ref.calendarPath)
+	if !ok {
+		return nil, caldav.Calendar{}, nil, fmt.Errorf("%w: calendar %s in account %s", server.ErrResourceNotFound, ref.calendarPath, ref.account)
+	}
+	return client, cal, stale, nil
+}
+
+// findCalendar returns the calendar at calendarPath, ignoring a trailing
+// slash.
+func findCalendar(calendars []caldav.Calendar, calendarPath string) (caldav.Calendar, bool) {
+	for _, cal := range calendars {
+		if samePath(cal.Path, calendarPath) {
+			return cal, true
+		}
+	}
+	return caldav.Calendar{}, false
+}
+
+// findEvent looks up the event with the UID of ref in the account's
+// calendars that hold events, starting with the default calendar. It also
+// returns the path of the calendar holding it.
+func findEvent(ctx context.Context, client caldav.CalendarService, defaultCalendar string, ref resourceRef) (*caldav.Event, *caldav.EventObject, string, error) {
+	calendars, err := client.ListCalendars(ctx)
+	if err != nil && !errors.As(err, new(*caldav.StaleError)) {
+		return nil, nil, "", fmt.Errorf("failed to list calendars: %w", err)
+	}
+
+	var paths []string
+	if defaultCalendar != "" {
+		paths = append(paths, defaultCalendar)
+	}
+	for _, cal := range calendars {
+		if cal.Supports(ical.CompEvent) && !samePath(cal.Path, defaultCalendar) {
+			paths = append(paths, cal.Path)
+		}
+	}
+
+	for _, p := range paths {
+		event, obj, err := client.GetEvent(ctx, client.GetEventPath(p, ref.uid))
+		if errors.Is(err, caldav.ErrEventNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, "", err
+		}
+		return event, obj, p, nil
+	}
+	return nil, nil, "", fmt.Errorf("%w: no event with UID %s in account %s: %w", server.ErrResourceNotFound, ref.uid, ref.account, caldav.ErrEventNotFound)
+}
+
+// jsonContents returns v as the JSON contents of the resource at uri.
+func jsonContents(uri string, v interface{}) ([]mcp.ResourceContents, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format resource: %w", err)
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      uri,
+		MIMEType: "application/json",
+		Text:     string(data),
+	}}, nil
+}
@@ -0,0 +1,181 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+	"github.com/rgabriel/mcp-icloud-calendar/tools"
+)
+
+// testAccounts creates an AccountClients with a single "default" account.
+func testAccounts(mock caldav.CalendarService, defaultCalendar string) *tools.AccountClients {
+	return tools.NewAccountClients(
+		map[string]caldav.CalendarService{"default": mock},
+		map[string]string{"default": defaultCalendar},
+	)
+}
+
+// testServer creates an MCP server with the resource templates registered.
+func testServer(accounts *tools.AccountClients) *server.MCPServer {
+	s := server.NewMCPServer("test", "1.0", server.WithResourceCapabilities(true, false))
+	s.AddResourceTemplate(mcp.NewResourceTemplate(CalendarTemplate, "Calendar"), CalendarHandler(accounts))
+	s.AddResourceTemplate(mcp.NewResourceTemplate(EventTemplate, "Event"), EventHandler(accounts))
+	return s
+}
+
+// readResource reads uri through the server and returns the contents, or
+// the error response.
+func readResource(t *testing.T, s *server.MCPServer, uri string) ([]mcp.TextResourceContents, *mcp.JSONRPCError) {
+	t.Helper()
+	msg := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":%q}}`, uri)
+	switch resp := s.HandleMessage(context.Background(), []byte(msg)).(type) {
+	case mcp.JSONRPCResponse:
+		result := resp.Result.(mcp.ReadResourceResult)
+		contents := make([]mcp.TextResourceContents, 0, len(result.Contents))
+		for _, c := range result.Contents {
+			contents = append(contents, c.(mcp.TextResourceContents))
+		}
+		return contents, nil
+	case mcp.JSONRPCError:
+		return nil, &resp
+	default:
+		t.Fatalf("unexpected response %T", resp)
+		return nil, nil
+	}
+}
+
+func TestCalendarHandler_ListsUpcomingEvents(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	mock := &caldav.MockClient{
+		Calendars: []caldav.Calendar{{Path: "/123/calendars/home/", Name: "Home"}},
+		Events: []caldav.Event{
+			{ID: "soon", Title: "Soon", StartTime: start, EndTime: start.Add(time.Hour)},
+		},
+	}
+	s := testServer(testAccounts(mock, ""))
+
+	uri := CalendarURI("default", "/123/calendars/home/")
+	contents, rpcErr := readResource(t, s, uri)
+	if rpcErr != nil {
+		t.Fatalf("unexpected error: %+v", rpcErr.Error)
+	}
+	if len(contents) != 1 || contents[0].URI != uri || contents[0].MIMEType != "application/json" {
+		t.Fatalf("unexpected contents %+v", contents)
+	}
+	if !mock.LastSearchOpts.Expand {
+		t.Error("expected recurring events to be expanded")
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(contents[0].Text), &response); err != nil {
+		t.Fatalf("failed to parse contents: %v", err)
+	}
+	if response["count"].(float64) != 1 {
+		t.Fatalf("expected 1 upcoming event, got %v", response["count"])
+	}
+	from, _ := time.Parse(time.RFC3339, response["from"].(string))
+	to, _ := time.Parse(time.RFC3339, response["to"].(string))
+	if to.Sub(from) != upcomingWindow {
+		t.Errorf("expected a %v window, got %v to %v", upcomingWindow, from, to)
+	}
+	event := response["events"].([]interface{})[0].(map[string]interface{})
+	if event["id"] != "soon" {
+		t.Errorf("expected event soon, got %v", event["id"])
+	}
+	if cal := response["calendar"].(map[string]interface{}); cal["Name"] != "Home" {
+		t.Errorf("expected calendar Home, got %v", cal["Name"])
+	}
+}
+
+func TestCalendarHandler_UnknownCalendar(t *testing.T) {
+	mock := &caldav.MockClient{Calendars: []caldav.Calendar{{Path: "/cal/home/", Name: "Home"}}}
+	s := testServer(testAccounts(mock, ""))
+
+	if _, rpcErr := readResource(t, s, "calendar://default/cal/work/"); rpcErr == nil {
+		t.Fatal("expected an error for an unknown calendar")
+	}
+	if _, rpcErr := readResource(t, s, "calendar://other/cal/home/"); rpcErr == nil {
+		t.Fatal("expected an error for an unknown account")
+	}
+}
+
+func TestEventHandler_ReturnsJSONAndICalendar(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	cal := ical.NewCalendar()
+	cal.Props.SetText(ical.PropVersion, "2.0")
+	cal.Props.SetText(ical.PropProductID, "-//test//EN")
+	vevent := ical.NewEvent()
+	vevent.Props.SetText(ical.PropUID, "abc@example.com")
+	vevent.Props.SetDateTime(ical.PropDateTimeStamp, start)
+	vevent.Props.SetDateTime(ical.PropDateTimeStart, start)
+	vevent.Props.SetText(ical.PropSummary, "Standup")
+	cal.Children = append(cal.Children, vevent.Component)
+
+	mock := &caldav.MockClient{
+		Calendars: []caldav.Calendar{{Path: "/cal/home/", Name: "Home"}},
+		Events: []caldav.Event{
+			{ID: "abc@example.com", Path: "/cal/home/abc@example.com.ics", ETag: `"1"`, Title: "Standup", StartTime: start, EndTime: start.Add(time.Hour)},
+		},
+		Object: &caldav.EventObject{Path: "/cal/home/abc@example.com.ics", ETag: `"1"`, Data: cal},
+	}
+	s := testServer(testAccounts(mock, "/cal/home/"))
+
+	uri := EventURI("default", "abc@example.com")
+	contents, rpcErr := readResource(t, s, uri)
+	if rpcErr != nil {
+		t.Fatalf("unexpected error: %+v", rpcErr.Error)
+	}
+	if len(contents) != 2 {
+		t.Fatalf("expected JSON and iCalendar contents, got %d", len(contents))
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(contents[0].Text), &response); err != nil {
+		t.Fatalf("failed to parse contents: %v", err)
+	}
+	if response["calendarId"] != "/cal/home/" || response["etag"] != `"1"` {
+		t.Errorf("unexpected response %v", response)
+	}
+	if contents[1].MIMEType != "text/calendar" || !strings.Contains(contents[1].Text, "SUMMARY:Standup") {
+		t.Errorf("unexpected iCalendar contents %+v", contents[1])
+	}
+}
+
+func TestEventHandler_NotFound(t *testing.T) {
+	mock := &caldav.MockClient{Calendars: []caldav.Calendar{{Path: "/cal/home/", Name: "Home"}}}
+	s := testServer(testAccounts(mock, "/cal/home/"))
+
+	if _, rpcErr := readResource(t, s, EventURI("default", "missing")); rpcErr == nil {
+		t.Fatal("expected an error for a missing event")
+	}
+}
+
+func TestCalendarResources(t *testing.T) {
+	accounts := tools.NewAccountClients(
+		map[string]caldav.CalendarService{
+			"work":     &caldav.MockClient{Calendars: []caldav.Calendar{{Path: "/w/cal/", Name: "Work", Description: "Office"}}},
+			"personal": &caldav.MockClient{Calendars: []caldav.Calendar{{Path: "/p/cal/", Name: "Home"}}},
+			"broken":   &caldav.MockClient{ListCalendarsErr: fmt.Errorf("connection refused")},
+		},
+		map[string]string{},
+	)
+
+	resources := CalendarResources(context.Background(), accounts)
+	if len(resources) != 2 {
+		t.Fatalf("expected 2 resources, got %d", len(resources))
+	}
+	if resources[0].URI != "calendar://personal/p/cal/" || resources[0].Name != "Home" {
+		t.Errorf("unexpected resource %+v", resources[0])
+	}
+	if resources[1].URI != "calendar://work/w/cal/" || resources[1].Description != "Office" {
+		t.Errorf("unexpected resource %+v", resources[1])
+	}
+}
@@ -0,0 +1,133 @@
+package resources
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// FilterStdio answers resources/subscribe and resources/unsubscribe requests
+// read from in with the watcher, since the MCP server does not handle them,
+// and passes every other message on. It returns the reader and writer to
+// serve the MCP server on; messages written to the writer and the answers to
+// subscription requests are written to out without interleaving.
+func FilterStdio(ctx context.Context, in io.Reader, out io.Writer, watcher *Watcher) (io.Reader, io.Writer) {
+	pr, pw := io.Pipe()
+	w := &lockedWriter{w: out}
+	go func() {
+		reader := bufio.NewReader(in)
+		for {
+			line, err := reader.ReadString('\n')
+			if len(line) > 0 && !handleSubscription(ctx, line, w, watcher) {
+				if _, werr := io.WriteString(pw, line); werr != nil {
+					return
+				}
+			}
+			if err != nil {
+				if err == io.EOF {
+					_ = pw.Close()
+				} else {
+					_ = pw.CloseWithError(err)
+				}
+				return
+			}
+		}
+	}()
+	return pr, w
+}
+
+// lockedWriter serializes writes, each of which is a whole message.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// Subscription methods, which mcp-go has no constants for.
+const (
+	methodSubscribe   = "resources/subscribe"
+	methodUnsubscribe = "resources/unsubscribe"
+)
+
+// subscriptionRequest is a resources/subscribe or resources/unsubscribe
+// request.
+type subscriptionRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params struct {
+		URI string `json:"uri"`
+	} `json:"params"`
+}
+
+// handleSubscription answers line if it is a subscription request, in the
+// background since subscribing reads the calendar from the server. It
+// reports whether it did.
+func handleSubscription(ctx context.Context, line string, out io.Writer, watcher *Watcher) bool {
+	var req subscriptionRequest
+	if err := json.Unmarshal([]byte(line), &req); err != nil || len(req.ID) == 0 {
+		return false
+	}
+	switch req.Method {
+	case methodSubscribe:
+		go func() {
+			err := watcher.Subscribe(ctx, req.Params.URI)
+			if err != nil {
+				slog.Warn("resource subscription failed", "uri", req.Params.URI, "error", err)
+			} else {
+				slog.Debug("resource subscribed", "uri", req.Params.URI)
+			}
+			writeReply(out, req.ID, err)
+		}()
+	case methodUnsubscribe:
+		writeReply(out, req.ID, watcher.Unsubscribe(req.Params.URI))
+	default:
+		return false
+	}
+	return true
+}
+
+// writeReply writes the JSON-RPC response to the request with the given id:
+// an empty result, or the error.
+func writeReply(out io.Writer, id json.RawMessage, err error) {
+	reply := map[string]interface{}{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      id,
+	}
+	if err == nil {
+		reply["result"] = struct{}{}
+	} else {
+		code := mcp.INTERNAL_ERROR
+		switch {
+		case errors.Is(err, errInvalidURI):
+			code = mcp.INVALID_PARAMS
+		case errors.Is(err, server.ErrResourceNotFound):
+			code = mcp.RESOURCE_NOT_FOUND
+		}
+		reply["error"] = map[string]interface{}{
+			"code":    code,
+			"message": err.Error(),
+		}
+	}
+
+	data, err := json.Marshal(reply)
+	if err != nil {
+		slog.Error("failed to encode subscription response", "error", err)
+		return
+	}
+	if _, err := fmt.Fprintf(out, "%s\n", data); err != nil {
+		slog.Error("failed to write subscription response", "error", err)
+	}
+}
@@ -0,0 +1,74 @@
+package resources
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+func TestFilterStdio(t *testing.T) {
+	mock := &caldav.MockClient{Calendars: []caldav.Calendar{{Path: "/cal/home/", Name: "Home"}}}
+	w, _ := testWatcher(mock)
+
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`,
+		`{"jsonrpc":"2.0","id":"s1","method":"resources/subscribe","params":{"uri":"calendar://default/cal/home/"}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"resources/subscribe","params":{"uri":"calendar://default/cal/work/"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"resources/subscribe","params":{"uri":"mailto:someone"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`not json`,
+		"",
+	}, "\n")
+	pr, pw := io.Pipe()
+	in, out := FilterStdio(context.Background(), strings.NewReader(input), pw, w)
+
+	// Messages for the MCP server pass through unchanged
+	go func() {
+		passed, err := io.ReadAll(in)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		want := `{"jsonrpc":"2.0","id":1,"method":"resources/list"}` + "\n" +
+			`{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n" +
+			"not json\n"
+		if string(passed) != want {
+			t.Errorf("expected passed messages %q, got %q", want, passed)
+		}
+		// The MCP server writes through the returned writer
+		_, _ = io.WriteString(out, `{"jsonrpc":"2.0","id":1,"result":{}}`+"\n")
+	}()
+
+	replies := make(map[string]map[string]interface{})
+	scanner := bufio.NewScanner(pr)
+	for len(replies) < 4 && scanner.Scan() {
+		var reply map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &reply); err != nil {
+			t.Fatalf("invalid reply %q: %v", scanner.Text(), err)
+		}
+		id, _ := json.Marshal(reply["id"])
+		replies[string(id)] = reply
+	}
+
+	if reply := replies[`"s1"`]; reply == nil || reply["result"] == nil {
+		t.Errorf("expected subscription to succeed, got %v", reply)
+	}
+	if reply := replies["1"]; reply == nil || reply["result"] == nil {
+		t.Errorf("expected the server's reply to be written, got %v", reply)
+	}
+	for id, code := range map[string]int{"2": mcp.RESOURCE_NOT_FOUND, "3": mcp.INVALID_PARAMS} {
+		reply := replies[id]
+		if reply == nil || reply["error"] == nil {
+			t.Errorf("expected an error for request %s, got %v", id, reply)
+			continue
+		}
+		if got := reply["error"].(map[string]interface{})["code"].(float64); int(got) != code {
+			t.Errorf("expected code %d for request %s, got %v", code, id, got)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+	"github.com/rgabriel/mcp-icloud-calendar/tools"
+)
+
+// Watcher tracks the resources clients subscribed to and polls their
+// calendars for changes made elsewhere, such as on another device. It uses
+// the calendars' sync tokens, so an unchanged calendar costs one request per
+// poll.
+type Watcher struct {
+	accounts *tools.AccountClients
+	interval time.Duration
+	notify   func(uri string)
+
+	mu        sync.Mutex
+	calendars map[calendarKey]*watchedCalendar
+}
+
+// calendarKey identifies a watched calendar.
+type calendarKey struct {
+	account string
+	path    string
+}
+
+// watchedCalendar is the last known state of a calendar with subscriptions
+// to it or its events.
+type watchedCalendar struct {
+	token string
+	// objects holds the ETag and UID of each event by path
+	objects map[string]watchedObject
+	// uri is the URI subscribed to for the calendar itself, if any; events
+	// holds the URIs subscribed to for events by UID
+	uri    string
+	events map[string]string
+}
+
+type watchedObject struct {
+	etag string
+	uid  string
+}
+
+// NewWatcher creates a Watcher that polls every interval and calls notify
+// with the URI of each subscribed resource that changed.
+func NewWatcher(accounts *tools.AccountClients, interval time.Duration, notify func(uri string)) *Watcher {
+	return &Watcher{
+		accounts:  accounts,
+		interval:  interval,
+		notify:    notify,
+		calendars: make(map[calendarKey]*watchedCalendar),
+	}
+}
+
+// Subscribe starts watching the calendar or event at uri. It fails if the
+// URI is invalid or the resource does not exist.
+func (w *Watcher) Subscribe(ctx context.Context, uri string) error {
+	ref, err := parseURI(uri)
+	if err != nil {
+		return err
+	}
+
+	var client caldav.CalendarService
+	var calendarPath string
+	if ref.uid != "" {
+		var defaultCalendar string
+		if client, defaultCalendar, err = resolve(w.accounts, ref.account); err != nil {
+			return err
+		}
+		if _, _, calendarPath, err = findEvent(ctx, client, defaultCalendar, ref); err != nil {
+			return err
+		}
+	} else {
+		var cal caldav.Calendar
+		if client, cal, _, err = lookupCalendar(ctx, w.accounts, ref); err != nil {
+			return err
+		}
+		calendarPath = cal.Path
+	}
+
+	key := calendarKey{account: ref.account, path: calendarPath}
+	w.mu.Lock()
+	watched, ok := w.calendars[key]
+	w.mu.Unlock()
+	if !ok {
+		// Changes are reported relative to the calendar's state now
+		result, err := client.SyncCalendar(ctx, calendarPath, "")
+		if err != nil {
+			return fmt.Errorf("failed to read calendar state: %w", err)
+		}
+		watched = &watchedCalendar{events: make(map[string]string)}
+		watched.apply(result)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if existing, ok := w.calendars[key]; ok {
+		watched = existing
+	} else {
+		w.calendars[key] = watched
+	}
+	if ref.uid != "" {
+		watched.events[ref.uid] = uri
+	} else {
+		watched.uri = uri
+	}
+	return nil
+}
+
+// Unsubscribe stops watching the resource at uri. Unknown URIs are ignored.
+func (w *Watcher) Unsubscribe(uri string) error {
+	ref, err := parseURI(uri)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for key, watched := range w.calendars {
+		if key.account != ref.account {
+			continue
+		}
+		if ref.uid != "" {
+			delete(watched.events, ref.uid)
+		} else if samePath(key.path, ref.calendarPath) {
+			watched.uri = ""
+		}
+		if watched.uri == "" && len(watched.events) == 0 {
+			delete(w.calendars, key)
+		}
+	}
+	return nil
+}
+
+// Run polls the watched calendars until ctx is done.
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.poll(ctx)
+		}
+	}
+}
+
+// poll syncs each watched calendar and notifies the subscribers of the
+// resources that changed. Calendars that fail to sync keep their state and
+// are tried again on the next poll.
+func (w *Watcher) poll(ctx context.Context) {
+	w.mu.Lock()
+	tokens := make(map[calendarKey]string, len(w.calendars))
+	for key, watched := range w.calendars {
+		tokens[key] = watched.token
+	}
+	w.mu.Unlock()
+
+	for key, token := range tokens {
+		if ctx.Err() != nil {
+			return
+		}
+		client, _, err := w.accounts.Resolve(key.account)
+		if err != nil {
+			continue
+		}
+		result, err := client.SyncCalendar(ctx, key.path, token)
+		if err != nil {
+			slog.Warn("failed to poll calendar for changes", "account", key.account, "path", key.path, "error", err)
+			continue
+		}
+
+		w.mu.Lock()
+		watched, ok := w.calendars[key]
+		var uris []string
+		if ok && watched.token == token {
+			uris = watched.changedURIs(watched.apply(result))
+		}
+		w.mu.Unlock()
+
+		for _, uri := range uris {
+			w.notify(uri)
+		}
+	}
+}
+
+// apply updates the calendar's state with a sync result and returns the
+// UIDs of the events that were added, changed or removed.
+func (c *watchedCalendar) apply(result *caldav.SyncResult) []string {
+	changed := make(map[string]bool)
+	var seen map[string]bool
+	if result.Full {
+		seen = make(map[string]bool, len(result.Changed))
+	}
+	if c.objects == nil {
+		c.objects = make(map[string]watchedObject, len(result.Changed))
+	}
+
+	for _, event := range result.Changed {
+		// A full sync lists every event, so only those whose ETag differs
+		// changed
+		if old, ok := c.objects[event.Path]; !result.Full || !ok || old.etag != event.ETag {
+			changed[event.ID] = true
+		}
+		c.objects[event.Path] = watchedObject{etag: event.ETag, uid: event.ID}
+		if seen != nil {
+			seen[event.Path] = true
+		}
+	}
+	for _, p := range result.Deleted {
+		if old, ok := c.objects[p]; ok {
+			changed[old.uid] = true
+			delete(c.objects, p)
+		}
+	}
+	if seen != nil {
+		for p, old := range c.objects {
+			if !seen[p] {
+				changed[old.uid] = true
+				delete(c.objects, p)
+			}
+		}
+	}
+	c.token = result.Token
+
+	uids := make([]string, 0, len(changed))
+	for uid := range changed {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+	return uids
+}
+
+// changedURIs returns the URIs of the subscribed resources affected by
+// changes to the events with the given UIDs.
+func (c *watchedCalendar) changedURIs(uids []string) []string {
+	if len(uids) == 0 {
+		return nil
+	}
+	var uris []string
+	if c.uri != "" {
+		uris = append(uris, c.uri)
+	}
+	for _, uid := range uids {
+		if uri, ok := c.events[uid]; ok {
+			uris = append(uris, uri)
+		}
+	}
+	return uris
+}
+
+// samePath reports whether two calendar paths are equal, ignoring a trailing
+// slash.
+func samePath(a, b string) bool {
+	return strings.TrimSuffix(a, "/") == strings.TrimSuffix(b, "/")
+}
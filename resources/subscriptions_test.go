@@ -0,0 +1,179 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+// testWatcher creates a Watcher over mock that records notifications.
+func testWatcher(mock *caldav.MockClient) (*Watcher, *[]string) {
+	var notified []string
+	w := NewWatcher(testAccounts(mock, "/cal/home/"), time.Minute, func(uri string) {
+		notified = append(notified, uri)
+	})
+	return w, &notified
+}
+
+func watchedEvent(id, etag string) caldav.Event {
+	return caldav.Event{ID: id, Path: "/cal/home/" + id + ".ics", ETag: etag}
+}
+
+func TestWatcher_NotifiesCalendarAndEventSubscribers(t *testing.T) {
+	mock := &caldav.MockClient{
+		Calendars: []caldav.Calendar{{Path: "/cal/home/", Name: "Home"}},
+		Events:    []caldav.Event{watchedEvent("a", `"1"`), watchedEvent("b", `"1"`)},
+		Syncs: []*caldav.SyncResult{
+			{Token: "t1", Full: true, Changed: []caldav.Event{watchedEvent("a", `"1"`), watchedEvent("b", `"1"`)}},
+			{Token: "t2", Changed: []caldav.Event{watchedEvent("a", `"2"`)}},
+			{Token: "t3", Deleted: []string{"/cal/home/b.ics"}},
+			{Token: "t3"},
+		},
+	}
+	w, notified := testWatcher(mock)
+	ctx := context.Background()
+
+	calendarURI := "calendar://default/cal/home/"
+	if err := w.Subscribe(ctx, calendarURI); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Subscribe(ctx, EventURI("default", "b")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.SyncCallCount != 1 {
+		t.Fatalf("expected one baseline sync for the calendar, got %d", mock.SyncCallCount)
+	}
+	if len(*notified) != 0 {
+		t.Fatalf("expected no notifications on subscribe, got %v", *notified)
+	}
+
+	// a changed: only the calendar subscriber is told
+	w.poll(ctx)
+	if mock.LastSyncToken != "t1" {
+		t.Errorf("expected sync from t1, got %q", mock.LastSyncToken)
+	}
+	if want := []string{calendarURI}; !reflect.DeepEqual(*notified, want) {
+		t.Fatalf("expected %v, got %v", want, *notified)
+	}
+
+	// b deleted: both are told
+	*notified = nil
+	w.poll(ctx)
+	if want := []string{calendarURI, "event://default/b"}; !reflect.DeepEqual(*notified, want) {
+		t.Fatalf("expected %v, got %v", want, *notified)
+	}
+
+	// Nothing changed
+	*notified = nil
+	w.poll(ctx)
+	if len(*notified) != 0 {
+		t.Fatalf("expected no notifications, got %v", *notified)
+	}
+}
+
+func TestWatcher_FullSyncComparesETags(t *testing.T) {
+	mock := &caldav.MockClient{
+		Calendars: []caldav.Calendar{{Path: "/cal/home/", Name: "Home"}},
+		Events:    []caldav.Event{watchedEvent("a", `"1"`), watchedEvent("b", `"1"`)},
+		Syncs: []*caldav.SyncResult{
+			{Full: true, Changed: []caldav.Event{watchedEvent("a", `"1"`), watchedEvent("b", `"1"`)}},
+			{Full: true, Changed: []caldav.Event{watchedEvent("a", `"1"`), watchedEvent("b", `"1"`)}},
+			{Full: true, Changed: []caldav.Event{watchedEvent("a", `"2"`)}},
+		},
+	}
+	w, notified := testWatcher(mock)
+	ctx := context.Background()
+
+	for _, uid := range []string{"a", "b"} {
+		if err := w.Subscribe(ctx, EventURI("default", uid)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// A server without sync tokens sends everything every time
+	w.poll(ctx)
+	if len(*notified) != 0 {
+		t.Fatalf("expected no notifications for an unchanged calendar, got %v", *notified)
+	}
+
+	w.poll(ctx)
+	if want := []string{"event://default/a", "event://default/b"}; !reflect.DeepEqual(*notified, want) {
+		t.Fatalf("expected %v, got %v", want, *notified)
+	}
+}
+
+func TestWatcher_Unsubscribe(t *testing.T) {
+	mock := &caldav.MockClient{
+		Calendars: []caldav.Calendar{{Path: "/cal/home/", Name: "Home"}},
+		Syncs: []*caldav.SyncResult{
+			{Token: "t1", Full: true},
+			{Token: "t2", Changed: []caldav.Event{watchedEvent("a", `"1"`)}},
+		},
+	}
+	w, notified := testWatcher(mock)
+	ctx := context.Background()
+
+	if err := w.Subscribe(ctx, "calendar://default/cal/home"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Unsubscribe("calendar://default/cal/home/"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	w.poll(ctx)
+	if mock.SyncCallCount != 1 || len(*notified) != 0 {
+		t.Fatalf("expected no polling after unsubscribing, got %d syncs and %v", mock.SyncCallCount, *notified)
+	}
+}
+
+func TestWatcher_PollErrorKeepsState(t *testing.T) {
+	mock := &caldav.MockClient{
+		Calendars: []caldav.Calendar{{Path: "/cal/home/", Name: "Home"}},
+		Syncs:     []*caldav.SyncResult{{Token: "t1", Full: true}},
+	}
+	w, notified := testWatcher(mock)
+	ctx := context.Background()
+
+	if err := w.Subscribe(ctx, "calendar://default/cal/home/"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mock.SyncErr = errors.New("connection refused")
+	w.poll(ctx)
+	if len(*notified) != 0 {
+		t.Fatalf("expected no notifications, got %v", *notified)
+	}
+
+	mock.SyncErr = nil
+	mock.Syncs = []*caldav.SyncResult{{Token: "t2", Changed: []caldav.Event{watchedEvent("a", `"1"`)}}}
+	w.poll(ctx)
+	if mock.LastSyncToken != "t1" {
+		t.Errorf("expected sync from t1 after the failure, got %q", mock.LastSyncToken)
+	}
+	if len(*notified) != 1 {
+		t.Fatalf("expected one notification, got %v", *notified)
+	}
+}
+
+func TestWatcher_SubscribeErrors(t *testing.T) {
+	mock := &caldav.MockClient{Calendars: []caldav.Calendar{{Path: "/cal/home/", Name: "Home"}}}
+	w, _ := testWatcher(mock)
+	ctx := context.Background()
+
+	if err := w.Subscribe(ctx, "https://example.com/"); !errors.Is(err, errInvalidURI) {
+		t.Errorf("expected errInvalidURI, got %v", err)
+	}
+	if err := w.Subscribe(ctx, "calendar://default/cal/work/"); !errors.Is(err, server.ErrResourceNotFound) {
+		t.Errorf("expected ErrResourceNotFound for an unknown calendar, got %v", err)
+	}
+	if err := w.Subscribe(ctx, "calendar://other/cal/home/"); !errors.Is(err, server.ErrResourceNotFound) {
+		t.Errorf("expected ErrResourceNotFound for an unknown account, got %v", err)
+	}
+	if err := w.Subscribe(ctx, EventURI("default", "missing")); !errors.Is(err, server.ErrResourceNotFound) {
+		t.Errorf("expected ErrResourceNotFound for a missing event, got %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+// Package resources exposes calendars and events as MCP resources, and
+// notifies subscribed clients when they change on the server.
+package resources
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// URI schemes and templates of the resources. The templates use reserved
+// expansion for the calendar path, which contains slashes, and for the UID,
+// which often contains an @.
+const (
+	calendarScheme = "calendar://"
+	eventScheme    = "event://"
+
+	CalendarTemplate = "calendar://{account}/{+calendarPath}"
+	EventTemplate    = "event://{account}/{+uid}"
+)
+
+// errInvalidURI is returned for URIs that are not calendar or event URIs.
+var errInvalidURI = errors.New("invalid resource URI")
+
+// CalendarURI returns the URI of the calendar at calendarPath in the named
+// account, such as calendar://personal/123/calendars/home/.
+func CalendarURI(account, calendarPath string) string {
+	p := (&url.URL{Path: strings.TrimPrefix(calendarPath, "/")}).EscapedPath()
+	return calendarScheme + url.PathEscape(account) + "/" + p
+}
+
+// EventURI returns the URI of the event with the given UID in the named
+// account, such as event://personal/abc123@example.com.
+func EventURI(account, uid string) string {
+	return eventScheme + url.PathEscape(account) + "/" + url.PathEscape(uid)
+}
+
+// resourceRef is a parsed resource URI.
+type resourceRef struct {
+	account string
+	// calendarPath is set for calendar URIs and uid for event URIs.
+	calendarPath string
+	uid          string
+}
+
+// parseURI parses a calendar or event URI.
+func parseURI(uri string) (resourceRef, error) {
+	var rest, what string
+	isCalendar := strings.HasPrefix(uri, calendarScheme)
+	switch {
+	case isCalendar:
+		rest, what = strings.TrimPrefix(uri, calendarScheme), "calendar path"
+	case strings.HasPrefix(uri, eventScheme):
+		rest, what = strings.TrimPrefix(uri, eventScheme), "UID"
+	default:
+		return resourceRef{}, fmt.Errorf("%w %q: use calendar://{account}/{calendarPath} or event://{account}/{uid}", errInvalidURI, uri)
+	}
+
+	account, name, ok := strings.Cut(rest, "/")
+	if !ok || account == "" || name == "" {
+		return resourceRef{}, fmt.Errorf("%w %q: missing account or %s", errInvalidURI, uri, what)
+	}
+	account, err := url.PathUnescape(account)
+	if err != nil {
+		return resourceRef{}, fmt.Errorf("%w %q: %v", errInvalidURI, uri, err)
+	}
+	name, err = url.PathUnescape(name)
+	if err != nil {
+		return resourceRef{}, fmt.Errorf("%w %q: %v", errInvalidURI, uri, err)
+	}
+
+	if isCalendar {
+		return resourceRef{account: account, calendarPath: "/" + name}, nil
+	}
+	return resourceRef{account: account, uid: name}, nil
+}
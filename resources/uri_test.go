@@ -0,0 +1,50 @@
+package resources
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCalendarURI_RoundTrip(t *testing.T) {
+	uri := CalendarURI("work mail", "/123/calendars/home/")
+	if uri != "calendar://work%20mail/123/calendars/home/" {
+		t.Fatalf("unexpected URI %q", uri)
+	}
+	ref, err := parseURI(uri)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ref.account != "work mail" || ref.calendarPath != "/123/calendars/home/" || ref.uid != "" {
+		t.Errorf("unexpected ref %+v", ref)
+	}
+}
+
+func TestEventURI_RoundTrip(t *testing.T) {
+	uri := EventURI("default", "abc/123@example.com")
+	if uri != "event://default/abc%2F123@example.com" {
+		t.Fatalf("unexpected URI %q", uri)
+	}
+	ref, err := parseURI(uri)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ref.account != "default" || ref.uid != "abc/123@example.com" || ref.calendarPath != "" {
+		t.Errorf("unexpected ref %+v", ref)
+	}
+}
+
+func TestParseURI_Invalid(t *testing.T) {
+	for _, uri := range []string{
+		"",
+		"https://example.com/cal/",
+		"calendar://default",
+		"calendar://default/",
+		"calendar:///cal/home/",
+		"event://default/",
+		"event://default/%zz",
+	} {
+		if _, err := parseURI(uri); !errors.Is(err, errInvalidURI) {
+			t.Errorf("parseURI(%q): expected errInvalidURI, got %v", uri, err)
+		}
+	}
+}
@@ -1,9 +1,14 @@
 package tools
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"path"
 	"strings"
+	"time"
 
+	"github.com/emersion/go-ical"
 	"github.com/rgabriel/mcp-icloud-calendar/caldav"
 )
 
@@ -48,3 +53,101 @@ func (a *AccountClients) AccountNames() []string {
 	}
 	return names
 }
+
+// FreeBusy returns the busy periods of the named accounts between start and
+// end, merged across all their calendars. calendarIDs limits the lookup to
+// those calendars; if it is empty, every calendar of each account that holds
+// events is included.
+func (a *AccountClients) FreeBusy(ctx context.Context, accountNames, calendarIDs []string, start, end time.Time) ([]caldav.BusyPeriod, error) {
+	var periods []caldav.BusyPeriod
+	for _, name := range accountNames {
+		client, _, err := a.Resolve(name)
+		if err != nil {
+			return nil, err
+		}
+
+		ids := calendarIDs
+		if len(ids) == 0 {
+			calendars, err := client.ListCalendars(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list calendars: %w", err)
+			}
+			for _, cal := range calendars {
+				if cal.Supports(ical.CompEvent) {
+					ids = append(ids, cal.Path)
+				}
+			}
+		}
+
+		for _, id := range ids {
+			busy, err := client.FreeBusy(ctx, id, start, end)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get free/busy for %s: %w", id, err)
+			}
+			periods = append(periods, busy...)
+		}
+	}
+	return caldav.MergeBusyPeriods(periods, start, end), nil
+}
+
+// MoveEvent moves the event at eventPath of account from into the calendar
+// at calendarPath of account to, and returns its new path. With copyEvent
+// the original is kept. Within one account the server moves or copies the
+// resource itself. Between accounts the calendar object is read, written
+// unchanged to the target and then deleted from the source; if the delete
+// fails, the new copy is removed again so that the event does not end up in
+// both places. A non-empty etag makes a move conditional on the event being
+// unchanged.
+func (a *AccountClients) MoveEvent(ctx context.Context, from, to, eventPath, calendarPath, etag string, copyEvent bool) (string, error) {
+	if from == "" {
+		from = "default"
+	}
+	if to == "" {
+		to = from
+	}
+	src, _, err := a.Resolve(from)
+	if err != nil {
+		return "", err
+	}
+	if to == from {
+		if copyEvent {
+			return src.CopyEvent(ctx, eventPath, calendarPath)
+		}
+		return src.MoveEvent(ctx, eventPath, calendarPath, etag)
+	}
+	dst, _, err := a.Resolve(to)
+	if err != nil {
+		return "", err
+	}
+
+	obj, err := src.GetEventObject(ctx, eventPath)
+	if err != nil {
+		return "", err
+	}
+	if obj.Path != "" {
+		// The event may be stored under a name other than its UID
+		eventPath = obj.Path
+	}
+	newPath := dst.GetEventPath(calendarPath, path.Base(eventPath))
+	if err := dst.PutEventObject(ctx, newPath, obj.Data); err != nil {
+		return "", err
+	}
+	if copyEvent {
+		return newPath, nil
+	}
+
+	// Only delete the version that was copied, if its ETag can say so
+	if etag == "" && !strings.HasPrefix(obj.ETag, "W/") {
+		etag = obj.ETag
+	}
+	// The event lives on in the target account, so neither delete may send
+	// cancellations to its attendees
+	if err := src.DeleteEventObject(ctx, eventPath, etag); err != nil {
+		if rerr := dst.DeleteEventObject(ctx, newPath, ""); rerr != nil {
+			slog.Warn("failed to remove moved event after error", "account", to, "path", newPath, "error", rerr)
+			return "", fmt.Errorf("%w (the copy in %s could not be removed: %v)", err, calendarPath, rerr)
+		}
+		return "", err
+	}
+	return newPath, nil
+}
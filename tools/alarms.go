@@ -0,0 +1,32 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+// parseAlarmList parses the alarms argument, a JSON array of alarm objects.
+// An empty string yields an empty list. The alarms are not validated, since
+// an update may pass back alarms read from the server with actions that
+// cannot be created; see validateNewAlarms.
+func parseAlarmList(value string) ([]caldav.Alarm, error) {
+	alarms := []caldav.Alarm{}
+	if value != "" {
+		if err := json.Unmarshal([]byte(value), &alarms); err != nil {
+			return nil, fmt.Errorf("expected a JSON array of alarms: %w", err)
+		}
+	}
+	return alarms, nil
+}
+
+// validateNewAlarms checks alarms that are all to be created.
+func validateNewAlarms(alarms []caldav.Alarm) error {
+	for _, alarm := range alarms {
+		if err := caldav.ValidateAlarm(alarm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// CompleteTaskHandler creates a handler for marking tasks as completed
+func CompleteTaskHandler(accounts *AccountClients) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		accountName, _ := args["account"].(string)
+		client, defaultCalendar, err := accounts.Resolve(accountName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// Identify the task, by taskId or by path
+		taskID, calendarID, taskPath, err := parseTaskRef(args, defaultCalendar)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if taskPath == "" {
+			taskPath = client.GetEventPath(calendarID, taskID)
+		}
+
+		etag, _ := args["etag"].(string)
+
+		err = client.CompleteTask(ctx, taskPath, etag)
+		if result := conflictResult(err); result != nil {
+			return result, nil
+		}
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to complete task: %v", err)), nil
+		}
+
+		// Format response
+		response := map[string]interface{}{
+			"success": true,
+			"taskId":  taskID,
+			"message": "Task completed successfully",
+		}
+
+		jsonData, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonData)), nil
+	}
+}
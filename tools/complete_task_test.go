@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+func newCompleteTaskRequest(args map[string]interface{}) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "complete_task",
+			Arguments: args,
+		},
+	}
+}
+
+func TestCompleteTaskHandler_HappyPath(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := CompleteTaskHandler(testAccounts(mock, "/cal/reminders"))
+
+	result, err := handler(context.Background(), newCompleteTaskRequest(map[string]interface{}{
+		"taskId": "t1",
+		"etag":   "etag-1",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+	if mock.CompleteCallCount != 1 {
+		t.Errorf("CompleteTask called %d times, want 1", mock.CompleteCallCount)
+	}
+	if mock.LastTaskPath != "/cal/reminders/t1.ics" || mock.LastTaskETag != "etag-1" {
+		t.Errorf("path = %q, etag = %q", mock.LastTaskPath, mock.LastTaskETag)
+	}
+}
+
+func TestCompleteTaskHandler_ByPath(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := CompleteTaskHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newCompleteTaskRequest(map[string]interface{}{
+		"path": "/cal/reminders/E0F1-2A3B.ics",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+	if mock.LastTaskPath != "/cal/reminders/E0F1-2A3B.ics" {
+		t.Errorf("path = %q, want the given path", mock.LastTaskPath)
+	}
+}
+
+func TestCompleteTaskHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		mock *caldav.MockClient
+		args map[string]interface{}
+	}{
+		{"missing taskId", &caldav.MockClient{}, map[string]interface{}{}},
+		{"invalid taskId", &caldav.MockClient{}, map[string]interface{}{"taskId": "../t1"}},
+		{"caldav error", &caldav.MockClient{TaskErr: fmt.Errorf("not found")}, map[string]interface{}{"taskId": "t1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := CompleteTaskHandler(testAccounts(tt.mock, "/cal/reminders"))
+			result, err := handler(context.Background(), newCompleteTaskRequest(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Fatal("expected error result")
+			}
+		})
+	}
+}
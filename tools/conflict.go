@@ -0,0 +1,47 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+// conflictResult converts a *caldav.ConflictError into a tool error that
+// includes the server's current version of the event or task, so the agent
+// can merge its change and retry with the new etag. It returns nil for other
+// errors.
+func conflictResult(err error) *mcp.CallToolResult {
+	var conflict *caldav.ConflictError
+	if !errors.As(err, &conflict) {
+		return nil
+	}
+
+	kind := "event"
+	if conflict.CurrentTask != nil {
+		kind = "task"
+	}
+	response := map[string]interface{}{
+		"success":  false,
+		"conflict": true,
+		"message":  fmt.Sprintf("The %s was modified on the server since it was read. Re-apply your change to the current version and retry with currentEtag.", kind),
+	}
+	switch {
+	case conflict.Current != nil:
+		response["currentEtag"] = conflict.Current.ETag
+		response["currentEvent"] = conflict.Current
+	case conflict.CurrentTask != nil:
+		response["currentEtag"] = conflict.CurrentTask.ETag
+		response["currentTask"] = conflict.CurrentTask
+	default:
+		response["message"] = "The event was modified or deleted on the server since it was read."
+	}
+
+	jsonData, jsonErr := json.MarshalIndent(response, "", "  ")
+	if jsonErr != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("conflict: %v", err))
+	}
+	return mcp.NewToolResultError(string(jsonData))
+}
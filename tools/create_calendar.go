@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/emersion/go-ical"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+// CreateCalendarHandler creates a handler for creating calendars
+func CreateCalendarHandler(accounts *AccountClients) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		accountName, _ := args["account"].(string)
+		client, _, err := accounts.Resolve(accountName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// Extract required parameters
+		name, ok := args["name"].(string)
+		if !ok || strings.TrimSpace(name) == "" {
+			return mcp.NewToolResultError("name is required"), nil
+		}
+
+		calendar := &caldav.Calendar{Name: name}
+		calendar.Description, _ = args["description"].(string)
+
+		if color, _ := args["color"].(string); color != "" {
+			if err := caldav.ValidateCalendarColor(color); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			calendar.Color = color
+		}
+
+		// iCloud keeps events and reminders in separate calendars, so a new
+		// calendar holds events unless asked otherwise
+		calendar.SupportedComponents = []string{ical.CompEvent}
+		if s, _ := args["components"].(string); s != "" {
+			var components []string
+			if err := json.Unmarshal([]byte(s), &components); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid components: expected a JSON array of strings: %v", err)), nil
+			}
+			if len(components) == 0 {
+				return mcp.NewToolResultError("invalid components: at least one component type is required"), nil
+			}
+			for i, comp := range components {
+				components[i] = strings.ToUpper(comp)
+				if err := caldav.ValidateCalendarComponent(components[i]); err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+			}
+			calendar.SupportedComponents = components
+		}
+
+		calendarPath, err := client.CreateCalendar(ctx, calendar)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// Format response
+		response := map[string]interface{}{
+			"success":    true,
+			"calendarId": calendarPath,
+			"name":       calendar.Name,
+			"components": calendar.SupportedComponents,
+			"message":    "Calendar created successfully",
+		}
+
+		jsonData, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonData)), nil
+	}
+}
@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+func newCreateCalendarRequest(args map[string]interface{}) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "create_calendar",
+			Arguments: args,
+		},
+	}
+}
+
+func TestCreateCalendarHandler_HappyPath(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := CreateCalendarHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newCreateCalendarRequest(map[string]interface{}{
+		"name":        "Project X",
+		"description": "Engagement with X",
+		"color":       "#FF2968",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+
+	created := mock.LastCreateCalendar
+	if created.Name != "Project X" || created.Description != "Engagement with X" || created.Color != "#FF2968" {
+		t.Errorf("calendar = %+v", created)
+	}
+	if len(created.SupportedComponents) != 1 || created.SupportedComponents[0] != "VEVENT" {
+		t.Errorf("components = %v, want the VEVENT default", created.SupportedComponents)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response["calendarId"] != "/calendars/user/new-calendar/" {
+		t.Errorf("calendarId = %v", response["calendarId"])
+	}
+}
+
+func TestCreateCalendarHandler_Components(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := CreateCalendarHandler(testAccounts(mock, ""))
+
+	result, err := handler(context.Background(), newCreateCalendarRequest(map[string]interface{}{
+		"name":       "Chores",
+		"components": `["vtodo"]`,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+	if got := mock.LastCreateCalendar.SupportedComponents; len(got) != 1 || got[0] != "VTODO" {
+		t.Errorf("components = %v, want [VTODO]", got)
+	}
+}
+
+func TestCreateCalendarHandler_InvalidArgs(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"missing name":     {},
+		"blank name":       {"name": "  "},
+		"bad color":        {"name": "Work", "color": "blue"},
+		"bad components":   {"name": "Work", "components": "VEVENT"},
+		"empty components": {"name": "Work", "components": "[]"},
+		"unknown comp":     {"name": "Work", "components": `["VJOURNAL"]`},
+	}
+	for name, args := range tests {
+		t.Run(name, func(t *testing.T) {
+			mock := &caldav.MockClient{}
+			handler := CreateCalendarHandler(testAccounts(mock, ""))
+			result, err := handler(context.Background(), newCreateCalendarRequest(args))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Fatal("expected error result")
+			}
+			if mock.CreateCalendarCount != 0 {
+				t.Error("calendar created despite invalid arguments")
+			}
+		})
+	}
+}
+
+func TestCreateCalendarHandler_CalDAVError(t *testing.T) {
+	mock := &caldav.MockClient{CalendarErr: fmt.Errorf("failed to create calendar: 403 Forbidden")}
+	handler := CreateCalendarHandler(testAccounts(mock, ""))
+
+	result, err := handler(context.Background(), newCreateCalendarRequest(map[string]interface{}{"name": "Work"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected error result")
+	}
+}
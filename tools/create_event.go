@@ -29,21 +29,48 @@ func CreateEventHandler(accounts *AccountClients) func(context.Context, mcp.Call
 
 		startTimeStr, ok := args["startTime"].(string)
 		if !ok || startTimeStr == "" {
-			return mcp.NewToolResultError("startTime is required (ISO 8601 format like '2024-01-15T14:30:00Z')"), nil
+			return mcp.NewToolResultError("startTime is required (ISO 8601 format like '2024-01-15T14:30:00Z', or a date like '2024-01-15' for all-day events)"), nil
 		}
 
-		endTimeStr, ok := args["endTime"].(string)
-		if !ok || endTimeStr == "" {
-			return mcp.NewToolResultError("endTime is required (ISO 8601 format like '2024-01-15T14:30:00Z')"), nil
+		// A plain date as startTime implies an all-day event
+		allDay, _ := args["allDay"].(bool)
+		if isDateOnly(startTimeStr) {
+			allDay = true
+		}
+		floating, _ := args["floating"].(bool)
+		if allDay && floating {
+			return mcp.NewToolResultError("allDay and floating cannot both be set"), nil
+		}
+
+		// Times without an offset are read in the event's time zone
+		timezone, _ := args["timezone"].(string)
+		var loc *time.Location
+		if timezone != "" {
+			if floating {
+				return mcp.NewToolResultError("floating events cannot have a timezone"), nil
+			}
+			loc, err = caldav.LoadTimezone(timezone)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid timezone: %v (use an IANA name like 'America/New_York')", err)), nil
+			}
+		}
+
+		endTimeStr, _ := args["endTime"].(string)
+		if endTimeStr == "" {
+			if !allDay {
+				return mcp.NewToolResultError("endTime is required (ISO 8601 format like '2024-01-15T14:30:00Z')"), nil
+			}
+			// All-day events default to a single day
+			endTimeStr = startTimeStr
 		}
 
 		// Parse times
-		startTime, err := time.Parse(time.RFC3339, startTimeStr)
+		startTime, err := parseEventTime(startTimeStr, allDay, floating, loc)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("invalid startTime format: %v", err)), nil
 		}
 
-		endTime, err := time.Parse(time.RFC3339, endTimeStr)
+		endTime, err := parseEventTime(endTimeStr, allDay, floating, loc)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("invalid endTime format: %v", err)), nil
 		}
@@ -78,14 +105,97 @@ func CreateEventHandler(accounts *AccountClients) func(context.Context, mcp.Call
 			}
 		}
 
+		// Parse optional alarms
+		alarmsStr, _ := args["alarms"].(string)
+		alarms, err := parseAlarmList(alarmsStr)
+		if err == nil {
+			err = validateNewAlarms(alarms)
+		}
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid alarms: %v", err)), nil
+		}
+
+		// Parse optional recurrence
+		recurrence, _ := args["recurrence"].(string)
+		if recurrence != "" {
+			if err := caldav.ValidateRecurrenceRule(recurrence); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		recurrenceDatesStr, _ := args["recurrenceDates"].(string)
+		recurrenceDates, err := parseEventTimeList(recurrenceDatesStr, allDay, floating, loc)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid recurrenceDates: %v", err)), nil
+		}
+
+		exceptionDatesStr, _ := args["exceptionDates"].(string)
+		exceptionDates, err := parseEventTimeList(exceptionDatesStr, allDay, floating, loc)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid exceptionDates: %v", err)), nil
+		}
+
+		var metadata caldav.EventUpdate
+		if err := parseEventMetadata(args, &metadata); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		check, err := parseOverlapCheck(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
 		// Create event
 		event := &caldav.Event{
-			Title:       title,
-			Description: description,
-			Location:    location,
-			StartTime:   startTime,
-			EndTime:     endTime,
-			Attendees:   attendees,
+			Title:           title,
+			Description:     description,
+			Location:        location,
+			StartTime:       startTime,
+			EndTime:         endTime,
+			Attendees:       attendees,
+			Alarms:          alarms,
+			Recurrence:      recurrence,
+			RecurrenceDates: recurrenceDates,
+			ExceptionDates:  exceptionDates,
+			Timezone:        timezone,
+			AllDay:          allDay,
+			Floating:        floating,
+		}
+		if metadata.Status != nil {
+			event.Status = *metadata.Status
+		}
+		if metadata.Transparent != nil {
+			event.Transparent = *metadata.Transparent
+		}
+		if metadata.Categories != nil {
+			event.Categories = *metadata.Categories
+		}
+		if metadata.URL != nil {
+			event.URL = *metadata.URL
+		}
+		if metadata.Organizer != nil {
+			event.Organizer = *metadata.Organizer
+		}
+		if metadata.Class != nil {
+			event.Class = *metadata.Class
+		}
+		if metadata.Priority != nil {
+			event.Priority = *metadata.Priority
+		}
+
+		// Look for overlapping events before writing
+		var overlaps []overlap
+		if check.enabled() {
+			if loc == nil {
+				loc = startTime.Location()
+			}
+			overlaps, err = findOverlaps(ctx, client, check, calendarID, *event, caldav.ScopeAll, loc)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to check for conflicts: %v", err)), nil
+			}
+			if len(overlaps) > 0 && check.mode == conflictsReject {
+				return overlapResult(overlaps), nil
+			}
 		}
 
 		eventID, err := client.CreateEvent(ctx, calendarID, event)
@@ -99,6 +209,12 @@ func CreateEventHandler(accounts *AccountClients) func(context.Context, mcp.Call
 			"eventId": eventID,
 			"message": fmt.Sprintf("Event '%s' created successfully", title),
 		}
+		if check.enabled() {
+			response["conflicts"] = overlaps
+			if len(overlaps) > 0 {
+				response["message"] = fmt.Sprintf("Event '%s' created successfully, but it overlaps %d existing event(s)", title, len(overlaps))
+			}
+		}
 
 		jsonData, err := json.MarshalIndent(response, "", "  ")
 		if err != nil {
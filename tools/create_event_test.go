@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/rgabriel/mcp-icloud-calendar/caldav"
@@ -274,3 +275,407 @@ func TestCreateEventHandler_UnknownAccount(t *testing.T) {
 		t.Fatal("expected error for unknown account")
 	}
 }
+
+func TestCreateEventHandler_AllDayFromDate(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := CreateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newCreateRequest(map[string]interface{}{
+		"title":     "Vacation",
+		"startTime": "2024-07-01",
+		"endTime":   "2024-07-08",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+
+	event := mock.LastCreateEvent
+	if !event.AllDay {
+		t.Error("expected a plain date to create an all-day event")
+	}
+	if want := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC); !event.StartTime.Equal(want) {
+		t.Errorf("StartTime = %v, want %v", event.StartTime, want)
+	}
+	if want := time.Date(2024, 7, 8, 0, 0, 0, 0, time.UTC); !event.EndTime.Equal(want) {
+		t.Errorf("EndTime = %v, want %v", event.EndTime, want)
+	}
+}
+
+func TestCreateEventHandler_AllDayWithoutEndTime(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := CreateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newCreateRequest(map[string]interface{}{
+		"title":     "Birthday",
+		"startTime": "2024-05-04T09:00:00-07:00",
+		"allDay":    true,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+
+	// The date is taken as written, not shifted to UTC.
+	event := mock.LastCreateEvent
+	want := time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC)
+	if !event.AllDay || !event.StartTime.Equal(want) || !event.EndTime.Equal(want) {
+		t.Errorf("got AllDay=%v start=%v end=%v, want single all-day event on %v",
+			event.AllDay, event.StartTime, event.EndTime, want)
+	}
+}
+
+func TestCreateEventHandler_Floating(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := CreateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newCreateRequest(map[string]interface{}{
+		"title":     "Wake up",
+		"startTime": "2024-03-01T07:00:00",
+		"endTime":   "2024-03-01T07:15:00+02:00",
+		"floating":  true,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+
+	event := mock.LastCreateEvent
+	if !event.Floating {
+		t.Error("expected floating event")
+	}
+	if want := time.Date(2024, 3, 1, 7, 15, 0, 0, time.UTC); !event.EndTime.Equal(want) {
+		t.Errorf("EndTime = %v, want wall clock %v", event.EndTime, want)
+	}
+}
+
+func TestCreateEventHandler_AllDayAndFloating(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := CreateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newCreateRequest(map[string]interface{}{
+		"title":     "Bad",
+		"startTime": "2024-03-01",
+		"floating":  true,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected error when both allDay and floating are set")
+	}
+}
+
+func TestCreateEventHandler_Timezone(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := CreateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newCreateRequest(map[string]interface{}{
+		"title":     "Standup",
+		"startTime": "2024-03-11T09:00:00",
+		"endTime":   "2024-03-11T09:15:00",
+		"timezone":  "America/New_York",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+
+	event := mock.LastCreateEvent
+	if event.Timezone != "America/New_York" {
+		t.Errorf("Timezone = %q", event.Timezone)
+	}
+	// 11 March is after the DST change, so 9am is UTC-4.
+	if want := time.Date(2024, 3, 11, 13, 0, 0, 0, time.UTC); !event.StartTime.Equal(want) {
+		t.Errorf("StartTime = %v, want %v", event.StartTime, want)
+	}
+}
+
+func TestCreateEventHandler_InvalidTimezone(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := CreateEventHandler(testAccounts(mock, "/cal/default"))
+
+	for _, args := range []map[string]interface{}{
+		{"title": "X", "startTime": "2024-03-11T09:00:00Z", "endTime": "2024-03-11T10:00:00Z", "timezone": "Not/AZone"},
+		{"title": "X", "startTime": "2024-03-11T09:00:00Z", "endTime": "2024-03-11T10:00:00Z", "timezone": "Europe/Paris", "floating": true},
+	} {
+		result, err := handler(context.Background(), newCreateRequest(args))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.IsError {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+	if mock.CreateCallCount != 0 {
+		t.Error("CreateEvent should not have been called")
+	}
+}
+
+func TestCreateEventHandler_Recurring(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := CreateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newCreateRequest(map[string]interface{}{
+		"title":          "Weekly 1:1",
+		"startTime":      "2024-01-01T09:00:00Z",
+		"endTime":        "2024-01-01T09:30:00Z",
+		"recurrence":     "FREQ=WEEKLY;COUNT=52",
+		"exceptionDates": `["2024-01-15T09:00:00Z"]`,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	if mock.CreateCallCount != 1 {
+		t.Errorf("expected a single master event, got %d creates", mock.CreateCallCount)
+	}
+
+	event := mock.LastCreateEvent
+	if event.Recurrence != "FREQ=WEEKLY;COUNT=52" {
+		t.Errorf("Recurrence = %q", event.Recurrence)
+	}
+	if len(event.ExceptionDates) != 1 || !event.ExceptionDates[0].Equal(time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("ExceptionDates = %v", event.ExceptionDates)
+	}
+}
+
+func TestCreateEventHandler_InvalidRecurrence(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := CreateEventHandler(testAccounts(mock, "/cal/default"))
+
+	for _, args := range []map[string]interface{}{
+		{"title": "X", "startTime": "2024-01-01T09:00:00Z", "endTime": "2024-01-01T10:00:00Z", "recurrence": "EVERY MONDAY"},
+		{"title": "X", "startTime": "2024-01-01T09:00:00Z", "endTime": "2024-01-01T10:00:00Z", "recurrenceDates": "2024-01-03"},
+		{"title": "X", "startTime": "2024-01-01T09:00:00Z", "endTime": "2024-01-01T10:00:00Z", "exceptionDates": `["next week"]`},
+	} {
+		result, err := handler(context.Background(), newCreateRequest(args))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.IsError {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+	if mock.CreateCallCount != 0 {
+		t.Error("CreateEvent should not have been called")
+	}
+}
+
+func TestCreateEventHandler_WithAlarms(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := CreateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newCreateRequest(map[string]interface{}{
+		"title":     "Meeting",
+		"startTime": "2024-01-15T14:30:00Z",
+		"endTime":   "2024-01-15T16:30:00Z",
+		"alarms":    `[{"action":"DISPLAY","trigger":"-PT15M"},{"action":"EMAIL","trigger":"-P1D","attendees":["alice@example.com"]}]`,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+
+	alarms := mock.LastCreateEvent.Alarms
+	if len(alarms) != 2 || alarms[0].Trigger != "-PT15M" || alarms[1].Action != caldav.AlarmEmail {
+		t.Errorf("alarms = %+v", alarms)
+	}
+}
+
+func TestCreateEventHandler_InvalidAlarms(t *testing.T) {
+	tests := []struct {
+		name   string
+		alarms string
+	}{
+		{"not JSON", "15 minutes before"},
+		{"bad trigger", `[{"action":"DISPLAY","trigger":"15m"}]`},
+		{"email without attendees", `[{"action":"EMAIL","trigger":"-PT15M"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &caldav.MockClient{}
+			handler := CreateEventHandler(testAccounts(mock, "/cal/default"))
+
+			result, err := handler(context.Background(), newCreateRequest(map[string]interface{}{
+				"title":     "Meeting",
+				"startTime": "2024-01-15T14:30:00Z",
+				"endTime":   "2024-01-15T16:30:00Z",
+				"alarms":    tt.alarms,
+			}))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Fatal("expected error for invalid alarms")
+			}
+			if mock.CreateCallCount != 0 {
+				t.Error("CreateEvent should not have been called")
+			}
+		})
+	}
+}
+
+func TestCreateEventHandler_CheckConflictsWarn(t *testing.T) {
+	mock := &caldav.MockClient{
+		Events: []caldav.Event{
+			{ID: "standup", Title: "Standup",
+				StartTime: time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 10, 9, 30, 0, 0, time.UTC)},
+		},
+	}
+	handler := CreateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newCreateRequest(map[string]interface{}{
+		"title":          "Planning",
+		"startTime":      "2025-03-10T09:15:00Z",
+		"endTime":        "2025-03-10T10:00:00Z",
+		"checkConflicts": "warn",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	if mock.CreateCallCount != 1 {
+		t.Error("event should be created despite the overlap")
+	}
+
+	var response struct {
+		Conflicts []overlap `json:"conflicts"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(response.Conflicts) != 1 || response.Conflicts[0].Title != "Standup" {
+		t.Errorf("conflicts = %+v, want the standup", response.Conflicts)
+	}
+}
+
+func TestCreateEventHandler_CheckConflictsReject(t *testing.T) {
+	mock := &caldav.MockClient{
+		Events: []caldav.Event{
+			{ID: "standup", Title: "Standup",
+				StartTime: time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 10, 9, 30, 0, 0, time.UTC)},
+		},
+	}
+	handler := CreateEventHandler(testAccounts(mock, "/cal/default"))
+
+	args := map[string]interface{}{
+		"title":          "Planning",
+		"startTime":      "2025-03-10T09:15:00Z",
+		"endTime":        "2025-03-10T10:00:00Z",
+		"checkConflicts": "reject",
+	}
+	result, err := handler(context.Background(), newCreateRequest(args))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected the overlap to be refused")
+	}
+	if mock.CreateCallCount != 0 {
+		t.Error("event should not have been created")
+	}
+
+	// Without an overlap the event is created
+	args["startTime"] = "2025-03-10T09:30:00Z"
+	result, _ = handler(context.Background(), newCreateRequest(args))
+	if result.IsError || mock.CreateCallCount != 1 {
+		t.Errorf("expected the event to be created, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+}
+
+func TestCreateEventHandler_CheckConflictsSearchError(t *testing.T) {
+	mock := &caldav.MockClient{SearchEventsErr: fmt.Errorf("connection refused")}
+	handler := CreateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newCreateRequest(map[string]interface{}{
+		"title":          "Planning",
+		"startTime":      "2025-03-10T09:15:00Z",
+		"endTime":        "2025-03-10T10:00:00Z",
+		"checkConflicts": "warn",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError || mock.CreateCallCount != 0 {
+		t.Error("expected an error result without creating the event")
+	}
+}
+
+func TestCreateEventHandler_Metadata(t *testing.T) {
+	mock := &caldav.MockClient{CreatedEventID: "new-uid-123"}
+	handler := CreateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newCreateRequest(map[string]interface{}{
+		"title":       "Offsite",
+		"startTime":   "2024-01-15T09:00:00Z",
+		"endTime":     "2024-01-15T17:00:00Z",
+		"status":      "tentative",
+		"transparent": true,
+		"categories":  `["Work","Travel"]`,
+		"url":         "https://example.com/offsite",
+		"class":       "confidential",
+		"priority":    float64(1),
+		"organizer":   "mailto:boss@example.com",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	event := mock.LastCreateEvent
+	if event.Status != caldav.EventTentative || !event.Transparent || event.Class != caldav.ClassConfidential {
+		t.Errorf("Status = %q, Transparent = %v, Class = %q", event.Status, event.Transparent, event.Class)
+	}
+	if len(event.Categories) != 2 || event.Categories[1] != "Travel" {
+		t.Errorf("Categories = %v", event.Categories)
+	}
+	if event.URL != "https://example.com/offsite" || event.Priority != 1 {
+		t.Errorf("URL = %q, Priority = %d", event.URL, event.Priority)
+	}
+	if event.Organizer != "boss@example.com" {
+		t.Errorf("Organizer = %q, want boss@example.com", event.Organizer)
+	}
+}
+
+func TestCreateEventHandler_InvalidMetadata(t *testing.T) {
+	for _, args := range []map[string]interface{}{
+		{"status": "MAYBE"},
+		{"class": "SECRET"},
+		{"url": "not a url"},
+		{"priority": float64(-1)},
+		{"organizer": "boss"},
+	} {
+		mock := &caldav.MockClient{}
+		handler := CreateEventHandler(testAccounts(mock, "/cal/default"))
+		args["title"] = "Meeting"
+		args["startTime"] = "2024-01-15T14:30:00Z"
+		args["endTime"] = "2024-01-15T15:30:00Z"
+		result, err := handler(context.Background(), newCreateRequest(args))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.IsError {
+			t.Errorf("expected error result for %v", args)
+		}
+		if mock.LastCreateEvent != nil {
+			t.Errorf("CreateEvent should not be called for %v", args)
+		}
+	}
+}
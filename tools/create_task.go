@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+// CreateTaskHandler creates a handler for creating tasks (VTODOs)
+func CreateTaskHandler(accounts *AccountClients) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		accountName, _ := args["account"].(string)
+		client, defaultCalendar, err := accounts.Resolve(accountName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// Extract required parameters
+		title, ok := args["title"].(string)
+		if !ok || title == "" {
+			return mcp.NewToolResultError("title is required"), nil
+		}
+
+		calendarID, _ := args["calendarId"].(string)
+		if calendarID == "" {
+			calendarID = defaultCalendar
+		}
+
+		if calendarID == "" {
+			return mcp.NewToolResultError("calendarId is required (no default calendar configured)"), nil
+		}
+
+		if err := caldav.ValidateCalendarPath(calendarID); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid calendarId: %v", err)), nil
+		}
+
+		task := &caldav.Task{Title: title}
+		task.Description, _ = args["description"].(string)
+		task.RelatedTo, _ = args["relatedTo"].(string)
+
+		// A plain date makes the task due on that day
+		if dueStr, ok := args["due"].(string); ok && dueStr != "" {
+			task.AllDay = isDateOnly(dueStr)
+			due, err := parseEventTime(dueStr, task.AllDay, false, nil)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid due format: %v", err)), nil
+			}
+			task.Due = &due
+		}
+
+		if v, ok := args["priority"].(float64); ok {
+			task.Priority = int(v)
+		}
+
+		uid, err := client.CreateTask(ctx, calendarID, task)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to create task: %v", err)), nil
+		}
+
+		// Format response
+		response := map[string]interface{}{
+			"success": true,
+			"taskId":  uid,
+			"message": "Task created successfully",
+		}
+
+		jsonData, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonData)), nil
+	}
+}
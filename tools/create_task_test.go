@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+func newCreateTaskRequest(args map[string]interface{}) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "create_task",
+			Arguments: args,
+		},
+	}
+}
+
+func TestCreateTaskHandler_HappyPath(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := CreateTaskHandler(testAccounts(mock, "/cal/reminders"))
+
+	result, err := handler(context.Background(), newCreateTaskRequest(map[string]interface{}{
+		"title":     "Pay rent",
+		"due":       "2025-03-14T17:00:00Z",
+		"priority":  float64(1),
+		"relatedTo": "parent-uid",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+
+	task := mock.LastCreateTask
+	if task == nil {
+		t.Fatal("CreateTask was not called")
+	}
+	if task.Title != "Pay rent" || task.Priority != 1 || task.RelatedTo != "parent-uid" {
+		t.Errorf("task = %+v", task)
+	}
+	want := time.Date(2025, 3, 14, 17, 0, 0, 0, time.UTC)
+	if task.Due == nil || !task.Due.Equal(want) || task.AllDay {
+		t.Errorf("due = %v (allDay %v), want %v", task.Due, task.AllDay, want)
+	}
+}
+
+func TestCreateTaskHandler_DateOnlyDue(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := CreateTaskHandler(testAccounts(mock, "/cal/reminders"))
+
+	result, err := handler(context.Background(), newCreateTaskRequest(map[string]interface{}{
+		"title": "Renew passport",
+		"due":   "2025-06-01",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+	if !mock.LastCreateTask.AllDay {
+		t.Error("expected a date-only due to make the task all-day")
+	}
+}
+
+func TestCreateTaskHandler_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+	}{
+		{"missing title", map[string]interface{}{}},
+		{"invalid due", map[string]interface{}{"title": "x", "due": "tomorrow"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &caldav.MockClient{}
+			handler := CreateTaskHandler(testAccounts(mock, "/cal/reminders"))
+			result, err := handler(context.Background(), newCreateTaskRequest(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Fatal("expected error result")
+			}
+			if mock.LastCreateTask != nil {
+				t.Error("CreateTask should not be called")
+			}
+		})
+	}
+}
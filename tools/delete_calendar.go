@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+// DeleteCalendarHandler creates a handler for deleting calendars
+func DeleteCalendarHandler(accounts *AccountClients) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		accountName, _ := args["account"].(string)
+		client, _, err := accounts.Resolve(accountName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// The calendar is never taken from the default: deleting the wrong
+		// one loses every event in it
+		calendarID, ok := args["calendarId"].(string)
+		if !ok || calendarID == "" {
+			return mcp.NewToolResultError("calendarId is required"), nil
+		}
+
+		if err := caldav.ValidateCalendarPath(calendarID); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid calendarId: %v", err)), nil
+		}
+
+		if err := client.DeleteCalendar(ctx, calendarID); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// Format response
+		response := map[string]interface{}{
+			"success":    true,
+			"calendarId": calendarID,
+			"message":    "Calendar deleted successfully",
+		}
+
+		jsonData, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonData)), nil
+	}
+}
@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+func newDeleteCalendarRequest(args map[string]interface{}) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "delete_calendar",
+			Arguments: args,
+		},
+	}
+}
+
+func TestDeleteCalendarHandler_HappyPath(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := DeleteCalendarHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newDeleteCalendarRequest(map[string]interface{}{
+		"calendarId": "/cal/project-x/",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+	if mock.LastCalendarPath != "/cal/project-x/" {
+		t.Errorf("deleted %q, want /cal/project-x/", mock.LastCalendarPath)
+	}
+}
+
+func TestDeleteCalendarHandler_NoDefaultCalendar(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := DeleteCalendarHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newDeleteCalendarRequest(map[string]interface{}{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected error result")
+	}
+	if mock.DeleteCalendarCount != 0 {
+		t.Error("the default calendar was deleted")
+	}
+}
+
+func TestDeleteCalendarHandler_CalDAVError(t *testing.T) {
+	mock := &caldav.MockClient{CalendarErr: fmt.Errorf("failed to delete calendar: 403 Forbidden")}
+	handler := DeleteCalendarHandler(testAccounts(mock, ""))
+
+	result, err := handler(context.Background(), newDeleteCalendarRequest(map[string]interface{}{
+		"calendarId": "/cal/work/",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected error result")
+	}
+}
@@ -20,34 +20,38 @@ func DeleteEventHandler(accounts *AccountClients) func(context.Context, mcp.Call
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		// Extract required parameters
-		eventID, ok := args["eventId"].(string)
-		if !ok || eventID == "" {
-			return mcp.NewToolResultError("eventId is required"), nil
+		// Identify the event, by eventId or by path
+		eventID, calendarID, eventPath, err := parseEventRef(args, defaultCalendar)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		if err := caldav.ValidateEventID(eventID); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid eventId: %v", err)), nil
-		}
+		etag, _ := args["etag"].(string)
 
-		calendarID, _ := args["calendarId"].(string)
-		if calendarID == "" {
-			calendarID = defaultCalendar
+		eventID, scope, recurrenceID, err := parseOccurrence(args, eventID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		if calendarID == "" {
-			return mcp.NewToolResultError("calendarId is required (no default calendar configured)"), nil
+		// Build event path
+		if eventPath == "" {
+			eventPath = client.GetEventPath(calendarID, eventID)
 		}
 
-		if err := caldav.ValidateCalendarPath(calendarID); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid calendarId: %v", err)), nil
+		// Delete the event, or some of its occurrences
+		message := "Event deleted successfully"
+		if scope == caldav.ScopeAll {
+			err = client.DeleteEvent(ctx, eventPath, etag)
+		} else {
+			err = client.DeleteOccurrence(ctx, eventPath, *recurrenceID, scope, etag)
+			message = "Occurrence deleted successfully"
+			if scope == caldav.ScopeThisAndFollowing {
+				message = "Occurrence and following occurrences deleted successfully"
+			}
+		}
+		if result := conflictResult(err); result != nil {
+			return result, nil
 		}
-
-		// Build event path
-		eventPath := client.GetEventPath(calendarID, eventID)
-
-		// Delete event
-		err = client.DeleteEvent(ctx, eventPath)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("failed to delete event: %v", err)), nil
 		}
@@ -56,7 +60,7 @@ func DeleteEventHandler(accounts *AccountClients) func(context.Context, mcp.Call
 		response := map[string]interface{}{
 			"success": true,
 			"eventId": eventID,
-			"message": "Event deleted successfully",
+			"message": message,
 		}
 
 		jsonData, err := json.MarshalIndent(response, "", "  ")
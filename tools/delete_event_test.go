@@ -3,7 +3,9 @@ package tools
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/rgabriel/mcp-icloud-calendar/caldav"
@@ -101,3 +103,101 @@ func TestDeleteEventHandler_InvalidEventID(t *testing.T) {
 		t.Fatal("expected error for path traversal")
 	}
 }
+
+func TestDeleteEventHandler_ConflictOnStaleETag(t *testing.T) {
+	mock := &caldav.MockClient{
+		DeleteEventErr: &caldav.ConflictError{Path: "/cal/default/event-123.ics"},
+	}
+	handler := DeleteEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newDeleteRequest(map[string]interface{}{
+		"eventId":    "event-123",
+		"calendarId": "/cal/default",
+		"etag":       "etag-1",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected error result")
+	}
+	if mock.LastDeleteETag != "etag-1" {
+		t.Errorf("etag = %q, want etag-1", mock.LastDeleteETag)
+	}
+	if !strings.Contains(result.Content[0].(mcp.TextContent).Text, `"conflict": true`) {
+		t.Errorf("expected conflict response, got %s", result.Content[0].(mcp.TextContent).Text)
+	}
+}
+
+func TestDeleteEventHandler_ThisAndFollowing(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := DeleteEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newDeleteRequest(map[string]interface{}{
+		"eventId":      "event-123",
+		"calendarId":   "/cal/default",
+		"recurrenceId": "2025-03-10",
+		"scope":        "thisAndFollowing",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	if mock.LastDeleteScope != caldav.ScopeThisAndFollowing {
+		t.Errorf("scope = %q, want thisAndFollowing", mock.LastDeleteScope)
+	}
+	if want := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC); !mock.LastDeleteRecurrenceID.Equal(want) {
+		t.Errorf("recurrenceId = %v, want %v", mock.LastDeleteRecurrenceID, want)
+	}
+	if mock.LastDeletePath != "/cal/default/event-123.ics" {
+		t.Errorf("delete path = %q", mock.LastDeletePath)
+	}
+}
+
+func TestDeleteEventHandler_WholeSeriesByDefault(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := DeleteEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newDeleteRequest(map[string]interface{}{
+		"eventId":    "event-123",
+		"calendarId": "/cal/default",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatal("expected success")
+	}
+	if mock.LastDeleteScope != "" {
+		t.Errorf("expected DeleteEvent, got DeleteOccurrence with scope %q", mock.LastDeleteScope)
+	}
+}
+
+func TestDeleteEventHandler_Path(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := DeleteEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newDeleteRequest(map[string]interface{}{
+		"path": "/cal/work/AB12-CD34.ics",
+		"etag": "etag-1",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	if mock.LastDeletePath != "/cal/work/AB12-CD34.ics" || mock.LastDeleteETag != "etag-1" {
+		t.Errorf("deleted %q with etag %q", mock.LastDeletePath, mock.LastDeleteETag)
+	}
+
+	result, err = handler(context.Background(), newDeleteRequest(map[string]interface{}{"path": "/cal/../secret.ics"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "invalid path") {
+		t.Errorf("expected invalid path error, got: %v", result.Content)
+	}
+}
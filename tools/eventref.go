@@ -0,0 +1,60 @@
+package tools
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+// parseEventRef reads the eventId, calendarId and path arguments that name
+// the event a tool acts on. path is an event's path from search_events and
+// takes precedence: it is returned as it is, with its collection as the
+// calendar ID. Otherwise eventId is required, and the caller builds the path
+// from it once any occurrence ID has been taken apart. eventID is returned
+// as given and may be empty when path is.
+func parseEventRef(args map[string]interface{}, defaultCalendar string) (eventID, calendarID, eventPath string, err error) {
+	return parseObjectRef(args, "eventId", "the event's path from search_events", defaultCalendar)
+}
+
+// parseTaskRef is parseEventRef for tasks: it reads the taskId, calendarId
+// and path arguments, path being a task's path from list_tasks.
+func parseTaskRef(args map[string]interface{}, defaultCalendar string) (taskID, calendarID, taskPath string, err error) {
+	return parseObjectRef(args, "taskId", "the task's path from list_tasks", defaultCalendar)
+}
+
+// parseObjectRef implements parseEventRef and parseTaskRef. idArg names the
+// ID argument and pathHint where a path comes from.
+func parseObjectRef(args map[string]interface{}, idArg, pathHint, defaultCalendar string) (id, calendarID, objectPath string, err error) {
+	id, _ = args[idArg].(string)
+	if id != "" {
+		if err := caldav.ValidateEventID(id); err != nil {
+			return "", "", "", fmt.Errorf("invalid %s: %v", idArg, err)
+		}
+	}
+
+	objectPath, _ = args["path"].(string)
+	if objectPath != "" {
+		if err := caldav.ValidateCalendarPath(objectPath); err != nil {
+			return "", "", "", fmt.Errorf("invalid path: %v", err)
+		}
+		return id, path.Dir(objectPath) + "/", objectPath, nil
+	}
+	if id == "" {
+		return "", "", "", fmt.Errorf("%s is required (or %s)", idArg, pathHint)
+	}
+
+	calendarID, _ = args["calendarId"].(string)
+	if calendarID == "" {
+		calendarID = defaultCalendar
+	}
+
+	if calendarID == "" {
+		return "", "", "", fmt.Errorf("calendarId is required (no default calendar configured)")
+	}
+
+	if err := caldav.ValidateCalendarPath(calendarID); err != nil {
+		return "", "", "", fmt.Errorf("invalid calendarId: %v", err)
+	}
+	return id, calendarID, "", nil
+}
@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const dateOnlyFormat = "2006-01-02"
+
+// isDateOnly reports whether value is a plain date such as "2025-03-15".
+func isDateOnly(value string) bool {
+	_, err := time.Parse(dateOnlyFormat, value)
+	return err == nil
+}
+
+// parseEventTime parses a startTime/endTime argument. Timed events take an
+// RFC 3339 timestamp, or a local time without offset (e.g.
+// '2025-03-15T09:00:00') when loc is given. All-day events also accept a
+// plain date (YYYY-MM-DD) and keep only the date of a timestamp. Floating
+// events accept a local time too and keep only its wall clock.
+func parseEventTime(value string, allDay, floating bool, loc *time.Location) (time.Time, error) {
+	if allDay {
+		if d, err := time.Parse(dateOnlyFormat, value); err == nil {
+			return d, nil
+		}
+	}
+	if floating {
+		loc = time.UTC
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil && loc != nil {
+		t, err = time.ParseInLocation("2006-01-02T15:04:05", value, loc)
+	}
+	if err != nil {
+		if allDay {
+			return time.Time{}, fmt.Errorf("use a date like '2025-03-15' or RFC 3339 format: %w", err)
+		}
+		return time.Time{}, err
+	}
+	if allDay {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	if floating {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC), nil
+	}
+	return t, nil
+}
+
+// parseEventTimeList parses a JSON array of times, such as the recurrenceDates
+// and exceptionDates arguments, with the same rules as parseEventTime. An empty
+// string yields an empty list.
+func parseEventTimeList(value string, allDay, floating bool, loc *time.Location) ([]time.Time, error) {
+	var values []string
+	if value != "" {
+		if err := json.Unmarshal([]byte(value), &values); err != nil {
+			return nil, fmt.Errorf("expected a JSON array of times: %w", err)
+		}
+	}
+	times := make([]time.Time, 0, len(values))
+	for _, v := range values {
+		t, err := parseEventTime(v, allDay, floating, loc)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", v, err)
+		}
+		times = append(times, t)
+	}
+	return times, nil
+}
@@ -0,0 +1,138 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+// maxSlotSearchDays bounds the search window of find_available_slots.
+const maxSlotSearchDays = 90
+
+// FindAvailableSlotsHandler creates a handler for proposing meeting times
+func FindAvailableSlotsHandler(accounts *AccountClients) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		// Working hours and slot alignment are defined in this zone
+		timezone, _ := args["timezone"].(string)
+		if timezone == "" {
+			return mcp.NewToolResultError("timezone is required (an IANA name like 'America/New_York')"), nil
+		}
+		loc, err := caldav.LoadTimezone(timezone)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid timezone: %v (use an IANA name like 'America/New_York')", err)), nil
+		}
+
+		// Lengths are whole minutes; anything shorter would give empty slots
+		durationMinutes, _ := args["durationMinutes"].(float64)
+		if math.Round(durationMinutes) < 1 {
+			return mcp.NewToolResultError("durationMinutes is required and must be at least 1"), nil
+		}
+		duration := time.Duration(math.Round(durationMinutes)) * time.Minute
+
+		// Extract the search window
+		startStr, ok := args["startTime"].(string)
+		if !ok || startStr == "" {
+			return mcp.NewToolResultError("startTime is required (ISO 8601 format like '2024-01-15T00:00:00')"), nil
+		}
+		endStr, ok := args["endTime"].(string)
+		if !ok || endStr == "" {
+			return mcp.NewToolResultError("endTime is required (ISO 8601 format like '2024-01-20T00:00:00')"), nil
+		}
+		startTime, err := parseEventTime(startStr, false, false, loc)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid startTime format: %v", err)), nil
+		}
+		endTime, err := parseEventTime(endStr, false, false, loc)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid endTime format: %v", err)), nil
+		}
+		if !endTime.After(startTime) {
+			return mcp.NewToolResultError("endTime must be after startTime"), nil
+		}
+		if endTime.Sub(startTime) > maxSlotSearchDays*24*time.Hour {
+			return mcp.NewToolResultError(fmt.Sprintf("the search window may be at most %d days", maxSlotSearchDays)), nil
+		}
+		startTime, endTime = startTime.In(loc), endTime.In(loc)
+
+		hoursStart, _ := args["workingHoursStart"].(string)
+		if hoursStart == "" {
+			hoursStart = "09:00"
+		}
+		hoursEnd, _ := args["workingHoursEnd"].(string)
+		if hoursEnd == "" {
+			hoursEnd = "17:00"
+		}
+		workingDays, _ := args["workingDays"].(string)
+		if workingDays == "" {
+			workingDays = "MO,TU,WE,TH,FR"
+		}
+		hours, err := parseWorkingHours(hoursStart, hoursEnd, workingDays)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		var buffer time.Duration
+		if v, ok := args["bufferMinutes"].(float64); ok && v > 0 {
+			buffer = time.Duration(v) * time.Minute
+		}
+		step := 30 * time.Minute
+		if v, ok := args["stepMinutes"].(float64); ok {
+			if math.Round(v) < 1 {
+				return mcp.NewToolResultError("stepMinutes must be at least 1"), nil
+			}
+			step = time.Duration(math.Round(v)) * time.Minute
+		}
+		maxResults := 10
+		if v, ok := args["maxResults"].(float64); ok && v > 0 {
+			maxResults = int(v)
+		}
+
+		accountNames, calendarIDs, err := parseCalendarSelection(args, accounts)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		busy, err := accounts.FreeBusy(ctx, accountNames, calendarIDs, startTime, endTime)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get free/busy: %v", err)), nil
+		}
+
+		free := freeIntervals(busy, startTime, endTime, hours, buffer, loc)
+		slots := candidateSlots(free, duration, step, loc)
+		rankSlots(slots)
+		if len(slots) > maxResults {
+			slots = slots[:maxResults]
+		}
+
+		ranked := make([]map[string]interface{}, len(slots))
+		for i, s := range slots {
+			ranked[i] = map[string]interface{}{
+				"rank":  i + 1,
+				"start": s.start,
+				"end":   s.end,
+			}
+		}
+
+		// Format response
+		response := map[string]interface{}{
+			"durationMinutes": int(durationMinutes),
+			"timezone":        timezone,
+			"count":           len(ranked),
+			"slots":           ranked,
+		}
+
+		jsonData, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonData)), nil
+	}
+}
@@ -0,0 +1,119 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+func newFindSlotsRequest(args map[string]interface{}) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "find_available_slots",
+			Arguments: args,
+		},
+	}
+}
+
+func TestFindAvailableSlotsHandler_HappyPath(t *testing.T) {
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	mock := &caldav.MockClient{
+		Calendars: []caldav.Calendar{{Path: "/cal/work"}},
+		// Monday 10 March: busy 9:00-12:00 and 13:00-17:00 local time
+		Busy: []caldav.BusyPeriod{
+			{Start: time.Date(2025, 3, 10, 9, 0, 0, 0, loc), End: time.Date(2025, 3, 10, 12, 0, 0, 0, loc)},
+			{Start: time.Date(2025, 3, 10, 13, 0, 0, 0, loc), End: time.Date(2025, 3, 10, 17, 0, 0, 0, loc)},
+		},
+	}
+	handler := FindAvailableSlotsHandler(testAccounts(mock, ""))
+
+	result, err := handler(context.Background(), newFindSlotsRequest(map[string]interface{}{
+		"durationMinutes": float64(30),
+		"startTime":       "2025-03-10T00:00:00",
+		"endTime":         "2025-03-11T00:00:00",
+		"timezone":        "Europe/Berlin",
+		"bufferMinutes":   float64(15),
+		"stepMinutes":     float64(15),
+		"maxResults":      float64(2),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+
+	var response struct {
+		Count int `json:"count"`
+		Slots []struct {
+			Rank  int       `json:"rank"`
+			Start time.Time `json:"start"`
+			End   time.Time `json:"end"`
+		} `json:"slots"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	// Only the lunch gap is free; with 15-minute buffers it is 12:15-12:45
+	if response.Count != 1 || len(response.Slots) != 1 {
+		t.Fatalf("slots = %+v, want exactly one", response.Slots)
+	}
+	got := response.Slots[0]
+	want := time.Date(2025, 3, 10, 12, 15, 0, 0, loc)
+	if got.Rank != 1 || !got.Start.Equal(want) || !got.End.Equal(want.Add(30*time.Minute)) {
+		t.Errorf("slot = %+v, want 12:15-12:45 Berlin time", got)
+	}
+}
+
+func TestFindAvailableSlotsHandler_InvalidInput(t *testing.T) {
+	base := func() map[string]interface{} {
+		return map[string]interface{}{
+			"durationMinutes": float64(30),
+			"startTime":       "2025-03-10T00:00:00Z",
+			"endTime":         "2025-03-11T00:00:00Z",
+			"timezone":        "UTC",
+		}
+	}
+	tests := []struct {
+		name   string
+		modify func(map[string]interface{})
+	}{
+		{"missing timezone", func(a map[string]interface{}) { delete(a, "timezone") }},
+		{"unknown timezone", func(a map[string]interface{}) { a["timezone"] = "Mars/Olympus" }},
+		{"missing duration", func(a map[string]interface{}) { delete(a, "durationMinutes") }},
+		{"sub-minute duration", func(a map[string]interface{}) { a["durationMinutes"] = 0.4 }},
+		{"sub-minute step", func(a map[string]interface{}) { a["stepMinutes"] = 0.4 }},
+		{"zero step", func(a map[string]interface{}) { a["stepMinutes"] = float64(0) }},
+		{"missing startTime", func(a map[string]interface{}) { delete(a, "startTime") }},
+		{"end before start", func(a map[string]interface{}) { a["endTime"] = "2025-03-09T00:00:00Z" }},
+		{"window too long", func(a map[string]interface{}) { a["endTime"] = "2025-09-01T00:00:00Z" }},
+		{"bad working hours", func(a map[string]interface{}) { a["workingHoursStart"] = "nine" }},
+		{"bad working days", func(a map[string]interface{}) { a["workingDays"] = "weekdays" }},
+		{"bad calendarIds", func(a map[string]interface{}) { a["calendarIds"] = "/cal/work" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &caldav.MockClient{}
+			handler := FindAvailableSlotsHandler(testAccounts(mock, ""))
+			args := base()
+			tt.modify(args)
+
+			result, err := handler(context.Background(), newFindSlotsRequest(args))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Fatal("expected error result")
+			}
+			if len(mock.FreeBusyPaths) != 0 {
+				t.Error("FreeBusy should not have been called")
+			}
+		})
+	}
+}
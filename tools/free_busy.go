@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+// FreeBusyHandler creates a handler for looking up busy times without
+// exposing event details
+func FreeBusyHandler(accounts *AccountClients) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		// Optional zone for the results; times without an offset are read in it
+		var loc *time.Location
+		if timezone, ok := args["timezone"].(string); ok && timezone != "" {
+			var err error
+			loc, err = caldav.LoadTimezone(timezone)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid timezone: %v (use an IANA name like 'America/New_York')", err)), nil
+			}
+		}
+
+		// Extract required parameters
+		startStr, ok := args["startTime"].(string)
+		if !ok || startStr == "" {
+			return mcp.NewToolResultError("startTime is required (ISO 8601 format like '2024-01-15T09:00:00Z')"), nil
+		}
+		endStr, ok := args["endTime"].(string)
+		if !ok || endStr == "" {
+			return mcp.NewToolResultError("endTime is required (ISO 8601 format like '2024-01-15T17:00:00Z')"), nil
+		}
+
+		startTime, err := parseEventTime(startStr, false, false, loc)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid startTime format: %v", err)), nil
+		}
+		endTime, err := parseEventTime(endStr, false, false, loc)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid endTime format: %v", err)), nil
+		}
+		if !endTime.After(startTime) {
+			return mcp.NewToolResultError("endTime must be after startTime"), nil
+		}
+		// All-day and floating events are placed in the zone of startTime
+		if loc != nil {
+			startTime, endTime = startTime.In(loc), endTime.In(loc)
+		}
+
+		accountNames, calendarIDs, err := parseCalendarSelection(args, accounts)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		busy, err := accounts.FreeBusy(ctx, accountNames, calendarIDs, startTime, endTime)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to get free/busy: %v", err)), nil
+		}
+
+		if loc != nil {
+			for i := range busy {
+				busy[i].Start, busy[i].End = busy[i].Start.In(loc), busy[i].End.In(loc)
+			}
+		}
+
+		// Format response
+		response := map[string]interface{}{
+			"startTime": startTime,
+			"endTime":   endTime,
+			"count":     len(busy),
+			"busy":      busy,
+		}
+
+		jsonData, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonData)), nil
+	}
+}
+
+// parseCalendarSelection reads the account, accounts, allAccounts and
+// calendarIds arguments that select the calendars to look up busy time in.
+// An empty list of calendar IDs means all event calendars of the accounts.
+func parseCalendarSelection(args map[string]interface{}, accounts *AccountClients) ([]string, []string, error) {
+	accountName, _ := args["account"].(string)
+	allAccounts, _ := args["allAccounts"].(bool)
+
+	var accountNames []string
+	if s, ok := args["accounts"].(string); ok && s != "" {
+		if err := json.Unmarshal([]byte(s), &accountNames); err != nil {
+			return nil, nil, fmt.Errorf("invalid accounts: expected a JSON array of account names: %v", err)
+		}
+		if len(accountNames) == 0 {
+			return nil, nil, fmt.Errorf("accounts must name at least one account")
+		}
+		for _, name := range accountNames {
+			if _, _, err := accounts.Resolve(name); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	var calendarIDs []string
+	if s, ok := args["calendarIds"].(string); ok && s != "" {
+		if err := json.Unmarshal([]byte(s), &calendarIDs); err != nil {
+			return nil, nil, fmt.Errorf("invalid calendarIds: expected a JSON array of calendar paths: %v", err)
+		}
+		for _, id := range calendarIDs {
+			if err := caldav.ValidateCalendarPath(id); err != nil {
+				return nil, nil, fmt.Errorf("invalid calendarId %q: %v", id, err)
+			}
+		}
+	}
+
+	switch {
+	case allAccounts:
+		if accountName != "" || accountNames != nil || len(calendarIDs) > 0 {
+			return nil, nil, fmt.Errorf("allAccounts cannot be combined with account, accounts or calendarIds")
+		}
+		return accounts.AccountNames(), nil, nil
+	case accountNames != nil:
+		if accountName != "" || len(calendarIDs) > 0 {
+			return nil, nil, fmt.Errorf("accounts cannot be combined with account or calendarIds")
+		}
+		return accountNames, nil, nil
+	default:
+		return []string{accountName}, calendarIDs, nil
+	}
+}
@@ -0,0 +1,229 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+func newFreeBusyRequest(args map[string]interface{}) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "get_free_busy",
+			Arguments: args,
+		},
+	}
+}
+
+func busyAt(startHour, endHour int) caldav.BusyPeriod {
+	return caldav.BusyPeriod{
+		Start: time.Date(2025, 3, 10, startHour, 0, 0, 0, time.UTC),
+		End:   time.Date(2025, 3, 10, endHour, 0, 0, 0, time.UTC),
+	}
+}
+
+func parseBusy(t *testing.T, result *mcp.CallToolResult) []caldav.BusyPeriod {
+	t.Helper()
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	var response struct {
+		Count int                 `json:"count"`
+		Busy  []caldav.BusyPeriod `json:"busy"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response.Count != len(response.Busy) {
+		t.Errorf("count = %d, but %d periods", response.Count, len(response.Busy))
+	}
+	return response.Busy
+}
+
+func TestFreeBusyHandler_EventCalendarsOfAccount(t *testing.T) {
+	mock := &caldav.MockClient{
+		Calendars: []caldav.Calendar{
+			{Path: "/cal/work", SupportedComponents: []string{"VEVENT"}},
+			{Path: "/cal/reminders", SupportedComponents: []string{"VTODO"}},
+			{Path: "/cal/old"},
+		},
+		Busy: []caldav.BusyPeriod{busyAt(9, 10)},
+	}
+	handler := FreeBusyHandler(testAccounts(mock, "/cal/work"))
+
+	result, err := handler(context.Background(), newFreeBusyRequest(map[string]interface{}{
+		"startTime": "2025-03-10T00:00:00Z",
+		"endTime":   "2025-03-11T00:00:00Z",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	busy := parseBusy(t, result)
+
+	if len(mock.FreeBusyPaths) != 2 || mock.FreeBusyPaths[0] != "/cal/work" || mock.FreeBusyPaths[1] != "/cal/old" {
+		t.Errorf("queried %v, want the event calendars", mock.FreeBusyPaths)
+	}
+	if len(busy) != 1 || !busy[0].Start.Equal(busyAt(9, 10).Start) {
+		t.Errorf("busy = %v, want one merged period", busy)
+	}
+}
+
+func TestFreeBusyHandler_AllAccounts(t *testing.T) {
+	personal := &caldav.MockClient{
+		Calendars: []caldav.Calendar{{Path: "/cal/home"}},
+		Busy:      []caldav.BusyPeriod{busyAt(9, 11)},
+	}
+	work := &caldav.MockClient{
+		Calendars: []caldav.Calendar{{Path: "/cal/work"}},
+		Busy:      []caldav.BusyPeriod{busyAt(10, 12), busyAt(14, 15)},
+	}
+	handler := FreeBusyHandler(testMultiAccounts(
+		map[string]caldav.CalendarService{"personal": personal, "work": work},
+		map[string]string{},
+	))
+
+	result, err := handler(context.Background(), newFreeBusyRequest(map[string]interface{}{
+		"startTime":   "2025-03-10T00:00:00Z",
+		"endTime":     "2025-03-11T00:00:00Z",
+		"allAccounts": true,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	busy := parseBusy(t, result)
+
+	want := []caldav.BusyPeriod{busyAt(9, 12), busyAt(14, 15)}
+	if len(busy) != len(want) {
+		t.Fatalf("busy = %v, want %v", busy, want)
+	}
+	for i := range want {
+		if !busy[i].Start.Equal(want[i].Start) || !busy[i].End.Equal(want[i].End) {
+			t.Errorf("busy[%d] = %v, want %v", i, busy[i], want[i])
+		}
+	}
+}
+
+func TestFreeBusyHandler_Accounts(t *testing.T) {
+	personal := &caldav.MockClient{
+		Calendars: []caldav.Calendar{{Path: "/cal/home"}},
+		Busy:      []caldav.BusyPeriod{busyAt(9, 11)},
+	}
+	work := &caldav.MockClient{
+		Calendars: []caldav.Calendar{{Path: "/cal/work"}},
+		Busy:      []caldav.BusyPeriod{busyAt(10, 12)},
+	}
+	other := &caldav.MockClient{
+		Calendars: []caldav.Calendar{{Path: "/cal/other"}},
+		Busy:      []caldav.BusyPeriod{busyAt(14, 15)},
+	}
+	handler := FreeBusyHandler(testMultiAccounts(
+		map[string]caldav.CalendarService{"personal": personal, "work": work, "other": other},
+		map[string]string{},
+	))
+
+	result, err := handler(context.Background(), newFreeBusyRequest(map[string]interface{}{
+		"startTime": "2025-03-10T00:00:00Z",
+		"endTime":   "2025-03-11T00:00:00Z",
+		"accounts":  `["personal", "work"]`,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	busy := parseBusy(t, result)
+
+	if len(busy) != 1 || !busy[0].Start.Equal(busyAt(9, 12).Start) || !busy[0].End.Equal(busyAt(9, 12).End) {
+		t.Errorf("busy = %v, want %v", busy, busyAt(9, 12))
+	}
+	if len(other.FreeBusyPaths) != 0 {
+		t.Errorf("queried %v in an account that was not selected", other.FreeBusyPaths)
+	}
+}
+
+func TestFreeBusyHandler_CalendarIDsAndTimezone(t *testing.T) {
+	mock := &caldav.MockClient{Busy: []caldav.BusyPeriod{busyAt(14, 15)}}
+	handler := FreeBusyHandler(testAccounts(mock, ""))
+
+	result, err := handler(context.Background(), newFreeBusyRequest(map[string]interface{}{
+		"startTime":   "2025-03-10T08:00:00",
+		"endTime":     "2025-03-10T18:00:00",
+		"timezone":    "America/New_York",
+		"calendarIds": `["/cal/a", "/cal/b"]`,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	if len(mock.FreeBusyPaths) != 2 {
+		t.Errorf("queried %v, want /cal/a and /cal/b", mock.FreeBusyPaths)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	period := response["busy"].([]interface{})[0].(map[string]interface{})
+	if period["start"] != "2025-03-10T10:00:00-04:00" {
+		t.Errorf("start = %v, want it in New York time", period["start"])
+	}
+}
+
+func TestFreeBusyHandler_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+	}{
+		{"missing startTime", map[string]interface{}{"endTime": "2025-03-11T00:00:00Z"}},
+		{"missing endTime", map[string]interface{}{"startTime": "2025-03-10T00:00:00Z"}},
+		{"end before start", map[string]interface{}{"startTime": "2025-03-11T00:00:00Z", "endTime": "2025-03-10T00:00:00Z"}},
+		{"invalid calendarIds", map[string]interface{}{"startTime": "2025-03-10T00:00:00Z", "endTime": "2025-03-11T00:00:00Z", "calendarIds": "/cal/a"}},
+		{"unsafe calendarId", map[string]interface{}{"startTime": "2025-03-10T00:00:00Z", "endTime": "2025-03-11T00:00:00Z", "calendarIds": `["../etc"]`}},
+		{"allAccounts with account", map[string]interface{}{"startTime": "2025-03-10T00:00:00Z", "endTime": "2025-03-11T00:00:00Z", "allAccounts": true, "account": "default"}},
+		{"unknown account", map[string]interface{}{"startTime": "2025-03-10T00:00:00Z", "endTime": "2025-03-11T00:00:00Z", "account": "nope"}},
+		{"invalid accounts", map[string]interface{}{"startTime": "2025-03-10T00:00:00Z", "endTime": "2025-03-11T00:00:00Z", "accounts": "default"}},
+		{"empty accounts", map[string]interface{}{"startTime": "2025-03-10T00:00:00Z", "endTime": "2025-03-11T00:00:00Z", "accounts": "[]"}},
+		{"unknown account in accounts", map[string]interface{}{"startTime": "2025-03-10T00:00:00Z", "endTime": "2025-03-11T00:00:00Z", "accounts": `["default", "nope"]`}},
+		{"accounts with account", map[string]interface{}{"startTime": "2025-03-10T00:00:00Z", "endTime": "2025-03-11T00:00:00Z", "accounts": `["default"]`, "account": "default"}},
+		{"accounts with allAccounts", map[string]interface{}{"startTime": "2025-03-10T00:00:00Z", "endTime": "2025-03-11T00:00:00Z", "accounts": `["default"]`, "allAccounts": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &caldav.MockClient{}
+			handler := FreeBusyHandler(testAccounts(mock, ""))
+			result, err := handler(context.Background(), newFreeBusyRequest(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Fatal("expected error result")
+			}
+			if len(mock.FreeBusyPaths) != 0 {
+				t.Error("FreeBusy should not have been called")
+			}
+		})
+	}
+}
+
+func TestFreeBusyHandler_CalDAVError(t *testing.T) {
+	mock := &caldav.MockClient{FreeBusyErr: fmt.Errorf("server error")}
+	handler := FreeBusyHandler(testAccounts(mock, ""))
+
+	result, err := handler(context.Background(), newFreeBusyRequest(map[string]interface{}{
+		"startTime":   "2025-03-10T00:00:00Z",
+		"endTime":     "2025-03-11T00:00:00Z",
+		"calendarIds": `["/cal/a"]`,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected error result")
+	}
+}
@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/emersion/go-ical"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+// GetEventHandler creates a handler for fetching a single event
+func GetEventHandler(accounts *AccountClients) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		accountName, _ := args["account"].(string)
+		client, defaultCalendar, err := accounts.Resolve(accountName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// Identify the event, by eventId or by path
+		eventID, calendarID, eventPath, err := parseEventRef(args, defaultCalendar)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if eventPath == "" {
+			// An occurrence is returned as part of its series
+			if uid, _, ok := caldav.ParseOccurrenceID(eventID); ok {
+				eventID = uid
+			}
+			eventPath = client.GetEventPath(calendarID, eventID)
+		}
+
+		event, obj, err := client.GetEvent(ctx, eventPath)
+		if errors.Is(err, caldav.ErrEventNotFound) {
+			return mcp.NewToolResultError(fmt.Sprintf("%v (check the calendarId, or use search_events to find the event's path)", err)), nil
+		}
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		response := map[string]interface{}{
+			"event": event,
+			"path":  event.Path,
+			"etag":  event.ETag,
+		}
+		// The encoder refuses data that breaks RFC 5545, such as an event
+		// without DTSTAMP; the parsed event is still worth returning
+		if obj != nil && obj.Data != nil {
+			var buf bytes.Buffer
+			if err := ical.NewEncoder(&buf).Encode(obj.Data); err != nil {
+				slog.Warn("failed to encode iCalendar data", "path", eventPath, "error", err)
+			} else {
+				response["ical"] = buf.String()
+			}
+		}
+
+		jsonData, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonData)), nil
+	}
+}
@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/emersion/go-ical"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+func newGetRequest(args map[string]interface{}) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "get_event",
+			Arguments: args,
+		},
+	}
+}
+
+func TestGetEventHandler_ByID(t *testing.T) {
+	event := ical.NewEvent()
+	event.Props.SetText(ical.PropUID, "event-123")
+	event.Props.SetText(ical.PropDateTimeStamp, "20250101T080000Z")
+	event.Props.SetText(ical.PropSummary, "Dentist")
+	cal := ical.NewCalendar()
+	cal.Props.SetText(ical.PropVersion, "2.0")
+	cal.Props.SetText(ical.PropProductID, "-//test//EN")
+	cal.Children = append(cal.Children, event.Component)
+
+	mock := &caldav.MockClient{
+		Events: []caldav.Event{{ID: "event-123", Path: "/cal/default/event-123.ics", Title: "Dentist", ETag: "etag-1"}},
+		Object: &caldav.EventObject{Path: "/cal/default/event-123.ics", ETag: "etag-1", Data: cal},
+	}
+	handler := GetEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newGetRequest(map[string]interface{}{"eventId": "event-123"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	if mock.LastGetPath != "/cal/default/event-123.ics" {
+		t.Errorf("path = %q", mock.LastGetPath)
+	}
+
+	var response struct {
+		Event caldav.Event `json:"event"`
+		Path  string       `json:"path"`
+		ETag  string       `json:"etag"`
+		ICal  string       `json:"ical"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response.Event.Title != "Dentist" || response.ETag != "etag-1" || response.Path != "/cal/default/event-123.ics" {
+		t.Errorf("response = %+v", response)
+	}
+	if !strings.Contains(response.ICal, "SUMMARY:Dentist") {
+		t.Errorf("ical = %q, want the raw calendar data", response.ICal)
+	}
+}
+
+func TestGetEventHandler_ByPath(t *testing.T) {
+	mock := &caldav.MockClient{
+		Events: []caldav.Event{{ID: "event-123", Path: "/cal/work/AB12-CD34.ics", Title: "Dentist"}},
+	}
+	handler := GetEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newGetRequest(map[string]interface{}{
+		"eventId": "ignored",
+		"path":    "/cal/work/AB12-CD34.ics",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	if mock.LastGetPath != "/cal/work/AB12-CD34.ics" {
+		t.Errorf("path = %q, want the explicit path", mock.LastGetPath)
+	}
+}
+
+func TestGetEventHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"missing id", map[string]interface{}{}, "eventId is required"},
+		{"invalid id", map[string]interface{}{"eventId": "../secret"}, "invalid eventId"},
+		{"invalid path", map[string]interface{}{"path": "/cal/../x.ics"}, "invalid path"},
+		{"not found", map[string]interface{}{"eventId": "missing"}, "search_events"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := GetEventHandler(testAccounts(&caldav.MockClient{}, "/cal/default"))
+			result, err := handler(context.Background(), newGetRequest(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Fatal("expected error result")
+			}
+			if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, tt.want) {
+				t.Errorf("error = %q, want it to contain %q", text, tt.want)
+			}
+		})
+	}
+}
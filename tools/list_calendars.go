@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
 )
 
 // ListCalendarsHandler creates a handler for listing available calendars
@@ -19,18 +21,42 @@ func ListCalendarsHandler(accounts *AccountClients) func(context.Context, mcp.Ca
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		// List calendars
-		calendars, err := client.ListCalendars(ctx)
+		component, _ := args["component"].(string)
+		component = strings.ToUpper(component)
+		if component != "" {
+			if err := caldav.ValidateCalendarComponent(component); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+		writable, _ := args["writable"].(bool)
+
+		// List calendars; the last list is returned while the server is
+		// unreachable
+		all, err := client.ListCalendars(ctx)
+		stale, err := staleResults(err)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("failed to list calendars: %v", err)), nil
 		}
 
+		calendars := make([]caldav.Calendar, 0, len(all))
+		for _, cal := range all {
+			if component != "" && !cal.Supports(component) {
+				continue
+			}
+			if writable && cal.ReadOnly {
+				continue
+			}
+			calendars = append(calendars, cal)
+		}
+
 		// Format response
 		response := map[string]interface{}{
 			"count":     len(calendars),
 			"calendars": calendars,
 		}
 
+		markStale(response, stale)
+
 		jsonData, err := json.MarshalIndent(response, "", "  ")
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
@@ -114,3 +114,97 @@ func TestListCalendarsHandler_MultiAccount(t *testing.T) {
 		t.Errorf("personal count = %v, want 2", response["count"])
 	}
 }
+
+func TestListCalendarsHandler_Filters(t *testing.T) {
+	mock := &caldav.MockClient{
+		Calendars: []caldav.Calendar{
+			{Path: "/cal/work/", Name: "Work", SupportedComponents: []string{"VEVENT"}},
+			{Path: "/cal/reminders/", Name: "Reminders", SupportedComponents: []string{"VTODO"}},
+			{Path: "/cal/holidays/", Name: "Holidays", SupportedComponents: []string{"VEVENT"}, ReadOnly: true},
+			{Path: "/cal/legacy/", Name: "Legacy"},
+		},
+	}
+	handler := ListCalendarsHandler(testAccounts(mock, ""))
+
+	tests := []struct {
+		args map[string]interface{}
+		want []string
+	}{
+		{map[string]interface{}{}, []string{"Work", "Reminders", "Holidays", "Legacy"}},
+		{map[string]interface{}{"component": "VEVENT"}, []string{"Work", "Holidays", "Legacy"}},
+		{map[string]interface{}{"component": "vtodo"}, []string{"Reminders", "Legacy"}},
+		{map[string]interface{}{"component": "VEVENT", "writable": true}, []string{"Work", "Legacy"}},
+	}
+	for _, tt := range tests {
+		req := mcp.CallToolRequest{
+			Params: mcp.CallToolParams{Name: "list_calendars", Arguments: tt.args},
+		}
+		result, err := handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.IsError {
+			t.Fatalf("%v: expected success, got error: %v", tt.args, result.Content)
+		}
+
+		var response struct {
+			Calendars []caldav.Calendar `json:"calendars"`
+		}
+		if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+		var names []string
+		for _, cal := range response.Calendars {
+			names = append(names, cal.Name)
+		}
+		if fmt.Sprint(names) != fmt.Sprint(tt.want) {
+			t.Errorf("%v: calendars = %v, want %v", tt.args, names, tt.want)
+		}
+	}
+}
+
+func TestListCalendarsHandler_InvalidComponent(t *testing.T) {
+	handler := ListCalendarsHandler(testAccounts(&caldav.MockClient{}, ""))
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: "list_calendars", Arguments: map[string]interface{}{"component": "VJOURNAL"}},
+	}
+
+	result, err := handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected error result")
+	}
+}
+
+func TestListCalendarsHandler_StaleWhenUnreachable(t *testing.T) {
+	mock := &caldav.MockClient{
+		Calendars: []caldav.Calendar{{Path: "/cal/work/", Name: "Work"}},
+	}
+	handler := ListCalendarsHandler(testAccounts(caldav.NewCachingClient(mock, 0, nil), ""))
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: "list_calendars"},
+	}
+
+	if result, _ := handler(context.Background(), req); result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+
+	mock.ListCalendarsErr = fmt.Errorf("connection refused")
+	result, err := handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected the last list, got error: %v", result.Content)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response["stale"] != true || response["count"].(float64) != 1 {
+		t.Errorf("response = %v, want the cached calendar flagged as stale", response)
+	}
+}
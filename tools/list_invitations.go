@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultInvitationDays is how far ahead list_invitations looks when no
+// endTime is given.
+const defaultInvitationDays = 90
+
+// ListInvitationsHandler creates a handler for listing unanswered invitations
+func ListInvitationsHandler(accounts *AccountClients) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		accountName, _ := args["account"].(string)
+		client, _, err := accounts.Resolve(accountName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// The range defaults to the next 90 days
+		startTime := time.Now()
+		if s, ok := args["startTime"].(string); ok && s != "" {
+			startTime, err = time.Parse(time.RFC3339, s)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid startTime format: %v", err)), nil
+			}
+		}
+		endTime := startTime.AddDate(0, 0, defaultInvitationDays)
+		if s, ok := args["endTime"].(string); ok && s != "" {
+			endTime, err = time.Parse(time.RFC3339, s)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid endTime format: %v", err)), nil
+			}
+		}
+		if !endTime.After(startTime) {
+			return mcp.NewToolResultError("endTime must be after startTime"), nil
+		}
+
+		invitations, err := client.ListInvitations(ctx, startTime, endTime)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to list invitations: %v", err)), nil
+		}
+
+		// Format response
+		response := map[string]interface{}{
+			"count":       len(invitations),
+			"invitations": invitations,
+		}
+
+		jsonData, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonData)), nil
+	}
+}
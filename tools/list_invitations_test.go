@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+func newListInvitationsRequest(args map[string]interface{}) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "list_invitations",
+			Arguments: args,
+		},
+	}
+}
+
+func TestListInvitationsHandler_HappyPath(t *testing.T) {
+	mock := &caldav.MockClient{
+		Invitations: []caldav.Invitation{
+			{
+				Event: caldav.Event{
+					ID:        "review",
+					Path:      "/cal/work/review.ics",
+					Title:     "Quarterly review",
+					StartTime: time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC),
+					EndTime:   time.Date(2025, 3, 10, 16, 0, 0, 0, time.UTC),
+					Organizer: "boss@example.com",
+				},
+				CalendarPath: "/cal/work",
+			},
+		},
+	}
+	handler := ListInvitationsHandler(testAccounts(mock, ""))
+
+	result, err := handler(context.Background(), newListInvitationsRequest(map[string]interface{}{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+
+	var response struct {
+		Count       int                      `json:"count"`
+		Invitations []map[string]interface{} `json:"invitations"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response.Count != 1 {
+		t.Fatalf("count = %d, want 1", response.Count)
+	}
+	inv := response.Invitations[0]
+	if inv["path"] != "/cal/work/review.ics" || inv["organizer"] != "boss@example.com" || inv["calendarPath"] != "/cal/work" {
+		t.Errorf("invitation = %v", inv)
+	}
+}
+
+func TestListInvitationsHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		mock *caldav.MockClient
+		args map[string]interface{}
+	}{
+		{"invalid startTime", &caldav.MockClient{}, map[string]interface{}{"startTime": "tomorrow"}},
+		{"end before start", &caldav.MockClient{}, map[string]interface{}{"startTime": "2025-03-10T00:00:00Z", "endTime": "2025-03-01T00:00:00Z"}},
+		{"caldav error", &caldav.MockClient{InvitationErr: fmt.Errorf("connection refused")}, map[string]interface{}{}},
+		{"unknown account", &caldav.MockClient{}, map[string]interface{}{"account": "other"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := ListInvitationsHandler(testAccounts(tt.mock, ""))
+			result, err := handler(context.Background(), newListInvitationsRequest(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Fatal("expected error result")
+			}
+		})
+	}
+}
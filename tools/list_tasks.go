@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+// ListTasksHandler creates a handler for listing tasks (VTODOs)
+func ListTasksHandler(accounts *AccountClients) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		accountName, _ := args["account"].(string)
+		client, defaultCalendar, err := accounts.Resolve(accountName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		calendarID, _ := args["calendarId"].(string)
+		if calendarID == "" {
+			calendarID = defaultCalendar
+		}
+
+		if calendarID == "" {
+			return mcp.NewToolResultError("calendarId is required (no default calendar configured)"), nil
+		}
+
+		if err := caldav.ValidateCalendarPath(calendarID); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid calendarId: %v", err)), nil
+		}
+
+		includeCompleted, _ := args["includeCompleted"].(bool)
+
+		tasks, err := client.SearchTasks(ctx, calendarID, includeCompleted)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to list tasks: %v", err)), nil
+		}
+
+		// Format response
+		response := map[string]interface{}{
+			"count": len(tasks),
+			"tasks": tasks,
+		}
+
+		jsonData, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonData)), nil
+	}
+}
@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+func newListTasksRequest(args map[string]interface{}) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "list_tasks",
+			Arguments: args,
+		},
+	}
+}
+
+func TestListTasksHandler_HappyPath(t *testing.T) {
+	mock := &caldav.MockClient{
+		Tasks: []caldav.Task{
+			{ID: "t1", Title: "Buy milk", Status: caldav.TaskNeedsAction},
+			{ID: "t2", Title: "Call bank", Status: caldav.TaskInProcess},
+		},
+	}
+	handler := ListTasksHandler(testAccounts(mock, "/cal/reminders"))
+
+	result, err := handler(context.Background(), newListTasksRequest(map[string]interface{}{
+		"includeCompleted": true,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+	if !mock.LastIncludeCompleted {
+		t.Error("includeCompleted was not passed through")
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response["count"].(float64) != 2 {
+		t.Errorf("count = %v, want 2", response["count"])
+	}
+}
+
+func TestListTasksHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name            string
+		mock            *caldav.MockClient
+		defaultCalendar string
+		args            map[string]interface{}
+	}{
+		{"no calendar", &caldav.MockClient{}, "", map[string]interface{}{}},
+		{"invalid calendar", &caldav.MockClient{}, "", map[string]interface{}{"calendarId": "../etc"}},
+		{"caldav error", &caldav.MockClient{TaskErr: fmt.Errorf("server error")}, "/cal/reminders", map[string]interface{}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := ListTasksHandler(testAccounts(tt.mock, tt.defaultCalendar))
+			result, err := handler(context.Background(), newListTasksRequest(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Fatal("expected error result")
+			}
+		})
+	}
+}
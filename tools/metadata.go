@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+// parseEventMetadata reads the status, transparent, categories, url, class,
+// priority and organizer arguments into the matching fields of update.
+// Arguments that are not given leave their field nil.
+func parseEventMetadata(args map[string]interface{}, update *caldav.EventUpdate) error {
+	if s, ok := args["status"].(string); ok {
+		s = strings.ToUpper(s)
+		if s != "" {
+			if err := caldav.ValidateEventStatus(s); err != nil {
+				return err
+			}
+		}
+		update.Status = &s
+	}
+
+	if v, ok := args["transparent"].(bool); ok {
+		update.Transparent = &v
+	}
+
+	if s, ok := args["categories"].(string); ok {
+		categories := []string{}
+		if s != "" {
+			if err := json.Unmarshal([]byte(s), &categories); err != nil {
+				return fmt.Errorf("invalid categories: expected a JSON array of strings: %v", err)
+			}
+		}
+		update.Categories = &categories
+	}
+
+	if s, ok := args["url"].(string); ok {
+		if s != "" {
+			if err := caldav.ValidateEventURL(s); err != nil {
+				return err
+			}
+		}
+		update.URL = &s
+	}
+
+	if s, ok := args["class"].(string); ok {
+		s = strings.ToUpper(s)
+		if s != "" {
+			if err := caldav.ValidateClass(s); err != nil {
+				return err
+			}
+		}
+		update.Class = &s
+	}
+
+	if v, ok := args["priority"].(float64); ok {
+		priority := int(v)
+		if priority < 0 || priority > 9 {
+			return fmt.Errorf("priority must be between 0 and 9, got %d", priority)
+		}
+		update.Priority = &priority
+	}
+
+	if s, ok := args["organizer"].(string); ok {
+		s = strings.TrimPrefix(strings.TrimSpace(s), "mailto:")
+		if s != "" {
+			if err := caldav.ValidateOrganizer(s); err != nil {
+				return err
+			}
+		}
+		update.Organizer = &s
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+// MoveEventHandler creates a handler for moving or copying events between
+// calendars and accounts
+func MoveEventHandler(accounts *AccountClients) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		accountName, _ := args["account"].(string)
+		client, defaultCalendar, err := accounts.Resolve(accountName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		targetAccount, _ := args["targetAccount"].(string)
+		if targetAccount != "" {
+			if _, _, err := accounts.Resolve(targetAccount); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid targetAccount: %v", err)), nil
+			}
+		}
+
+		// Identify the event, by eventId or by path
+		eventID, calendarID, eventPath, err := parseEventRef(args, defaultCalendar)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// An occurrence stands for its whole series
+		if uid, _, ok := caldav.ParseOccurrenceID(eventID); ok {
+			eventID = uid
+		}
+
+		targetCalendarID, _ := args["targetCalendarId"].(string)
+		if targetCalendarID == "" {
+			return mcp.NewToolResultError("targetCalendarId is required"), nil
+		}
+
+		if err := caldav.ValidateCalendarPath(targetCalendarID); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid targetCalendarId: %v", err)), nil
+		}
+
+		etag, _ := args["etag"].(string)
+		copyEvent, _ := args["copy"].(bool)
+
+		if eventPath == "" {
+			eventPath = client.GetEventPath(calendarID, eventID)
+		} else if eventID == "" {
+			// The response names the event by UID, which the path may not
+			event, _, err := client.GetEvent(ctx, eventPath)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			eventID = event.ID
+		}
+
+		newPath, err := accounts.MoveEvent(ctx, accountName, targetAccount, eventPath, targetCalendarID, etag, copyEvent)
+		if result := conflictResult(err); result != nil {
+			return result, nil
+		}
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		message := "Event moved successfully"
+		if copyEvent {
+			message = "Event copied successfully"
+		}
+
+		// Format response
+		response := map[string]interface{}{
+			"success":    true,
+			"eventId":    eventID,
+			"calendarId": targetCalendarID,
+			"path":       newPath,
+			"message":    message,
+		}
+		if targetAccount != "" {
+			response["account"] = targetAccount
+		}
+
+		jsonData, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonData)), nil
+	}
+}
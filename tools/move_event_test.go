@@ -0,0 +1,258 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/emersion/go-ical"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+func newMoveRequest(args map[string]interface{}) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "move_event",
+			Arguments: args,
+		},
+	}
+}
+
+func TestMoveEventHandler_SameAccount(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := MoveEventHandler(testAccounts(mock, "/cal/work"))
+
+	result, err := handler(context.Background(), newMoveRequest(map[string]interface{}{
+		"eventId":          "event-123",
+		"targetCalendarId": "/cal/home/",
+		"etag":             `"etag-1"`,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	if mock.MoveCallCount != 1 || mock.LastMovePath != "/cal/work/event-123.ics" || mock.LastMoveCalendar != "/cal/home/" {
+		t.Errorf("MoveEvent(%q, %q) called %d times", mock.LastMovePath, mock.LastMoveCalendar, mock.MoveCallCount)
+	}
+	if mock.LastMoveETag != `"etag-1"` {
+		t.Errorf("etag = %q", mock.LastMoveETag)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response["eventId"] != "event-123" || response["calendarId"] != "/cal/home/" {
+		t.Errorf("response = %v", response)
+	}
+}
+
+func TestMoveEventHandler_ByPath(t *testing.T) {
+	// Another client stored the event under a name other than its UID
+	mock := &caldav.MockClient{
+		Events: []caldav.Event{{ID: "event-123@example.com", Path: "/cal/work/AB12-CD34.ics"}},
+	}
+	handler := MoveEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newMoveRequest(map[string]interface{}{
+		"path":             "/cal/work/AB12-CD34.ics",
+		"targetCalendarId": "/cal/home/",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	if mock.LastMovePath != "/cal/work/AB12-CD34.ics" {
+		t.Errorf("moved %q, want the given path", mock.LastMovePath)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response["eventId"] != "event-123@example.com" || response["path"] != "/cal/home/AB12-CD34.ics" {
+		t.Errorf("response = %v, want the UID and the new path", response)
+	}
+}
+
+func TestMoveEventHandler_Copy(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := MoveEventHandler(testAccounts(mock, "/cal/work"))
+
+	result, err := handler(context.Background(), newMoveRequest(map[string]interface{}{
+		"eventId":          "event-123",
+		"targetCalendarId": "/cal/home/",
+		"copy":             true,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	if mock.CopyCallCount != 1 || mock.MoveCallCount != 0 {
+		t.Errorf("CopyEvent called %d times, MoveEvent %d times", mock.CopyCallCount, mock.MoveCallCount)
+	}
+}
+
+// movedObject returns the calendar object read from the source account in
+// the cross-account tests.
+func movedObject() *caldav.EventObject {
+	event := ical.NewEvent()
+	event.Props.SetText(ical.PropUID, "event-123")
+	event.Props.SetText("X-APPLE-TRAVEL-ADVISORY-BEHAVIOR", "AUTOMATIC")
+	cal := ical.NewCalendar()
+	cal.Children = append(cal.Children, event.Component)
+	return &caldav.EventObject{Path: "/work/cal/event-123.ics", ETag: "etag-1", Data: cal}
+}
+
+func TestMoveEventHandler_BetweenAccounts(t *testing.T) {
+	src := &caldav.MockClient{Object: movedObject()}
+	dst := &caldav.MockClient{}
+	accounts := testMultiAccounts(
+		map[string]caldav.CalendarService{"work": src, "personal": dst},
+		map[string]string{"work": "/work/cal", "personal": "/personal/cal"},
+	)
+	handler := MoveEventHandler(accounts)
+
+	result, err := handler(context.Background(), newMoveRequest(map[string]interface{}{
+		"account":          "work",
+		"eventId":          "event-123",
+		"targetAccount":    "personal",
+		"targetCalendarId": "/personal/cal/",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	if dst.LastPutObjectPath != "/personal/cal/event-123.ics" || dst.LastPutObject != src.Object.Data {
+		t.Errorf("wrote %q, want the original data at /personal/cal/event-123.ics", dst.LastPutObjectPath)
+	}
+	if src.LastDeletePath != "/work/cal/event-123.ics" || src.LastDeleteETag != "etag-1" {
+		t.Errorf("deleted %q with etag %q, want the copied version", src.LastDeletePath, src.LastDeleteETag)
+	}
+	if src.DeleteObjectCount != 1 {
+		t.Error("the source must be deleted without sending cancellations")
+	}
+	if src.MoveCallCount != 0 || dst.DeleteCallCount != 0 {
+		t.Error("a move between accounts must not use MOVE or roll back")
+	}
+}
+
+func TestMoveEventHandler_BetweenAccountsWeakETag(t *testing.T) {
+	object := movedObject()
+	object.ETag = "W/etag-1"
+	src := &caldav.MockClient{Object: object}
+	dst := &caldav.MockClient{}
+	accounts := testMultiAccounts(
+		map[string]caldav.CalendarService{"work": src, "personal": dst},
+		map[string]string{"work": "/work/cal", "personal": "/personal/cal"},
+	)
+	handler := MoveEventHandler(accounts)
+
+	result, err := handler(context.Background(), newMoveRequest(map[string]interface{}{
+		"account":          "work",
+		"eventId":          "event-123",
+		"targetAccount":    "personal",
+		"targetCalendarId": "/personal/cal/",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	if src.LastDeletePath != "/work/cal/event-123.ics" || src.LastDeleteETag != "" {
+		t.Errorf("deleted %q with etag %q, want an unconditional delete", src.LastDeletePath, src.LastDeleteETag)
+	}
+}
+
+func TestMoveEventHandler_BetweenAccountsRollsBack(t *testing.T) {
+	src := &caldav.MockClient{Object: movedObject(), DeleteEventErr: errors.New("500 Internal Server Error")}
+	dst := &caldav.MockClient{}
+	accounts := testMultiAccounts(
+		map[string]caldav.CalendarService{"work": src, "personal": dst},
+		map[string]string{"work": "/work/cal", "personal": "/personal/cal"},
+	)
+	handler := MoveEventHandler(accounts)
+
+	result, err := handler(context.Background(), newMoveRequest(map[string]interface{}{
+		"account":          "work",
+		"eventId":          "event-123",
+		"targetAccount":    "personal",
+		"targetCalendarId": "/personal/cal/",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected error result")
+	}
+	if dst.DeleteObjectCount != 1 || dst.LastDeletePath != "/personal/cal/event-123.ics" {
+		t.Errorf("copy not removed: %d deletes of %q", dst.DeleteObjectCount, dst.LastDeletePath)
+	}
+}
+
+func TestMoveEventHandler_CopyBetweenAccountsKeepsOriginal(t *testing.T) {
+	src := &caldav.MockClient{Object: movedObject()}
+	dst := &caldav.MockClient{}
+	accounts := testMultiAccounts(
+		map[string]caldav.CalendarService{"work": src, "personal": dst},
+		map[string]string{"work": "/work/cal", "personal": "/personal/cal"},
+	)
+	handler := MoveEventHandler(accounts)
+
+	result, err := handler(context.Background(), newMoveRequest(map[string]interface{}{
+		"account":          "work",
+		"eventId":          "event-123",
+		"targetAccount":    "personal",
+		"targetCalendarId": "/personal/cal/",
+		"copy":             true,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	if dst.LastPutObject == nil || src.DeleteCallCount != 0 {
+		t.Errorf("put = %v, source deletes = %d", dst.LastPutObject != nil, src.DeleteCallCount)
+	}
+}
+
+func TestMoveEventHandler_InvalidArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"missing eventId", map[string]interface{}{"targetCalendarId": "/cal/home/"}, "eventId is required"},
+		{"missing target", map[string]interface{}{"eventId": "event-123"}, "targetCalendarId is required"},
+		{"unknown target account", map[string]interface{}{"eventId": "event-123", "targetCalendarId": "/cal/home/", "targetAccount": "nope"}, "invalid targetAccount"},
+		{"same calendar", map[string]interface{}{"eventId": "event-123", "targetCalendarId": "/cal/work"}, "already in"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := MoveEventHandler(testAccounts(&caldav.MockClient{}, "/cal/work"))
+			result, err := handler(context.Background(), newMoveRequest(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Fatal("expected error result")
+			}
+			if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, tt.want) {
+				t.Errorf("error = %q, want it to contain %q", text, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package tools
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+// parseOccurrence reads the arguments that select occurrences of a recurring
+// event and returns the event's UID, the scope and the original start of the
+// occurrence. eventID may be an occurrenceId from search_events, which names
+// an occurrence just like recurrenceId does. recurrenceId is the original
+// start of an occurrence as reported by search_events, or a date for all-day
+// events. The scope defaults to "this" when an occurrence is named and "all"
+// otherwise.
+func parseOccurrence(args map[string]interface{}, eventID string) (string, string, *time.Time, error) {
+	scope, _ := args["scope"].(string)
+	if err := caldav.ValidateScope(scope); err != nil {
+		return "", "", nil, err
+	}
+
+	var recurrenceID *time.Time
+	if uid, rid, ok := caldav.ParseOccurrenceID(eventID); ok {
+		eventID = uid
+		recurrenceID = &rid
+	}
+	if s, _ := args["recurrenceId"].(string); s != "" {
+		t, err := parseEventTime(s, isDateOnly(s), false, nil)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("invalid recurrenceId format: %v", err)
+		}
+		recurrenceID = &t
+	}
+
+	switch {
+	case scope == "" && recurrenceID != nil:
+		scope = caldav.ScopeThis
+	case scope == "":
+		scope = caldav.ScopeAll
+	case scope != caldav.ScopeAll && recurrenceID == nil:
+		return "", "", nil, fmt.Errorf("recurrenceId is required for scope %q", scope)
+	}
+	return eventID, scope, recurrenceID, nil
+}
@@ -0,0 +1,198 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+// Values of the checkConflicts argument.
+const (
+	conflictsOff    = "off"
+	conflictsWarn   = "warn"
+	conflictsReject = "reject"
+)
+
+// Values of the conflictScope argument.
+const (
+	conflictScopeCalendar = "calendar"
+	conflictScopeAccount  = "account"
+)
+
+// overlapHorizon limits how far ahead the occurrences of a recurring event
+// are checked for overlaps.
+const overlapHorizon = 90 * 24 * time.Hour
+
+// overlapCheck is the parsed checkConflicts and conflictScope arguments.
+type overlapCheck struct {
+	mode  string
+	scope string
+}
+
+// parseOverlapCheck reads the checkConflicts and conflictScope arguments.
+func parseOverlapCheck(args map[string]interface{}) (overlapCheck, error) {
+	check := overlapCheck{mode: conflictsOff, scope: conflictScopeCalendar}
+	if mode, _ := args["checkConflicts"].(string); mode != "" {
+		check.mode = mode
+	}
+	if scope, _ := args["conflictScope"].(string); scope != "" {
+		check.scope = scope
+	}
+	switch check.mode {
+	case conflictsOff, conflictsWarn, conflictsReject:
+	default:
+		return check, fmt.Errorf("invalid checkConflicts %q: use %q, %q or %q", check.mode, conflictsOff, conflictsWarn, conflictsReject)
+	}
+	switch check.scope {
+	case conflictScopeCalendar, conflictScopeAccount:
+	default:
+		return check, fmt.Errorf("invalid conflictScope %q: use %q or %q", check.scope, conflictScopeCalendar, conflictScopeAccount)
+	}
+	return check, nil
+}
+
+// enabled reports whether overlapping events should be looked up.
+func (c overlapCheck) enabled() bool {
+	return c.mode != conflictsOff
+}
+
+// overlap is an existing event that overlaps the event being written.
+type overlap struct {
+	CalendarID   string    `json:"calendarId"`
+	EventID      string    `json:"eventId"`
+	OccurrenceID string    `json:"occurrenceId,omitempty"`
+	Title        string    `json:"title"`
+	StartTime    time.Time `json:"startTime"`
+	EndTime      time.Time `json:"endTime"`
+	AllDay       bool      `json:"allDay,omitempty"`
+}
+
+// findOverlaps returns the events that block time during proposed, in
+// calendarID or, with the account scope, in every event calendar of the
+// account. A recurring proposed event is checked over overlapHorizon from
+// its first occurrence. Existing events that the write replaces, as told by
+// replaces, are ignored, as are transparent and cancelled ones. Dates and
+// floating times are placed in loc.
+func findOverlaps(ctx context.Context, client caldav.CalendarService, check overlapCheck, calendarID string, proposed caldav.Event, scope string, loc *time.Location) ([]overlap, error) {
+	occurrences := []caldav.Event{proposed}
+	if proposed.Recurrence != "" || len(proposed.RecurrenceDates) > 0 {
+		var err error
+		occurrences, err = caldav.ExpandRecurrence(proposed, proposed.StartTime, proposed.StartTime.Add(overlapHorizon))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var periods []caldav.BusyPeriod
+	var first, last time.Time
+	for _, o := range occurrences {
+		period, ok := o.BusyPeriod(loc)
+		if !ok || !period.End.After(period.Start) {
+			continue
+		}
+		if len(periods) == 0 || period.Start.Before(first) {
+			first = period.Start
+		}
+		if len(periods) == 0 || period.End.After(last) {
+			last = period.End
+		}
+		periods = append(periods, period)
+	}
+	if len(periods) == 0 {
+		return []overlap{}, nil
+	}
+
+	calendarIDs := []string{calendarID}
+	if check.scope == conflictScopeAccount {
+		calendars, err := client.ListCalendars(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list calendars: %w", err)
+		}
+		calendarIDs = nil
+		for _, cal := range calendars {
+			if cal.Supports(ical.CompEvent) {
+				calendarIDs = append(calendarIDs, cal.Path)
+			}
+		}
+	}
+
+	// Widen the search by a day so that all-day and floating events, whose
+	// instants depend on loc, are not missed.
+	searchStart := first.Add(-24 * time.Hour)
+	searchEnd := last.Add(24 * time.Hour)
+
+	overlaps := []overlap{}
+	for _, id := range calendarIDs {
+		events, err := client.SearchEvents(ctx, id, &searchStart, &searchEnd, caldav.SearchOptions{Expand: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search %s: %w", id, err)
+		}
+		for _, e := range events {
+			if replaces(proposed, scope, e) {
+				continue
+			}
+			busy, ok := e.BusyPeriod(loc)
+			if !ok || !overlapsAny(busy, periods) {
+				continue
+			}
+			overlaps = append(overlaps, overlap{
+				CalendarID:   id,
+				EventID:      e.ID,
+				OccurrenceID: e.OccurrenceID,
+				Title:        e.Title,
+				StartTime:    e.StartTime,
+				EndTime:      e.EndTime,
+				AllDay:       e.AllDay,
+			})
+		}
+	}
+	return overlaps, nil
+}
+
+// replaces reports whether writing proposed with scope replaces the existing
+// event or occurrence e, which then cannot overlap it. With ScopeAll that is
+// every event with the UID or path of proposed; with ScopeThis only the
+// occurrence at proposed.RecurrenceID, and with ScopeThisAndFollowing that
+// one and all later ones. An event with the same UID but no recurrence ID,
+// such as an unexpanded series, cannot be told apart and is replaced too.
+func replaces(proposed caldav.Event, scope string, e caldav.Event) bool {
+	same := e.ID == proposed.ID && proposed.ID != "" || e.Path == proposed.Path && proposed.Path != ""
+	if !same || scope == caldav.ScopeAll || proposed.RecurrenceID == nil || e.RecurrenceID == nil {
+		return same
+	}
+	rid := *proposed.RecurrenceID
+	if scope == caldav.ScopeThisAndFollowing {
+		return !e.RecurrenceID.Before(rid)
+	}
+	return e.RecurrenceID.Equal(rid)
+}
+
+// overlapsAny reports whether busy shares any time with one of periods.
+func overlapsAny(busy caldav.BusyPeriod, periods []caldav.BusyPeriod) bool {
+	for _, p := range periods {
+		if busy.Start.Before(p.End) && p.Start.Before(busy.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// overlapResult is the tool error returned when the checkConflicts mode is
+// reject and the event would overlap others.
+func overlapResult(overlaps []overlap) *mcp.CallToolResult {
+	response := map[string]interface{}{
+		"success":   false,
+		"conflicts": overlaps,
+		"message":   fmt.Sprintf("The event was not saved because it overlaps %d existing event(s). Pick another time, or retry with checkConflicts set to %q to save it anyway.", len(overlaps), conflictsOff),
+	}
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("the event overlaps %d existing event(s)", len(overlaps)))
+	}
+	return mcp.NewToolResultError(string(jsonData))
+}
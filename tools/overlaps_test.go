@@ -0,0 +1,161 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+func TestParseOverlapCheck(t *testing.T) {
+	check, err := parseOverlapCheck(map[string]interface{}{})
+	if err != nil || check.enabled() || check.scope != conflictScopeCalendar {
+		t.Errorf("defaults = %+v, %v", check, err)
+	}
+
+	check, err = parseOverlapCheck(map[string]interface{}{"checkConflicts": "reject", "conflictScope": "account"})
+	if err != nil || check.mode != conflictsReject || check.scope != conflictScopeAccount {
+		t.Errorf("check = %+v, %v", check, err)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"checkConflicts": "block"},
+		{"checkConflicts": "warn", "conflictScope": "everywhere"},
+	} {
+		if _, err := parseOverlapCheck(args); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
+
+func TestFindOverlaps(t *testing.T) {
+	mock := &caldav.MockClient{
+		Events: []caldav.Event{
+			{ID: "standup", Title: "Standup", Recurrence: "FREQ=DAILY",
+				StartTime: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 1, 9, 15, 0, 0, time.UTC)},
+			{ID: "lunch", Title: "Lunch",
+				StartTime: time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 10, 13, 0, 0, 0, time.UTC)},
+			{ID: "focus", Title: "Focus time", Transparent: true,
+				StartTime: time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)},
+			{ID: "self", Title: "The event itself",
+				StartTime: time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC)},
+		},
+	}
+	check := overlapCheck{mode: conflictsWarn, scope: conflictScopeCalendar}
+	proposed := caldav.Event{
+		ID:        "self",
+		StartTime: time.Date(2025, 3, 10, 9, 10, 0, 0, time.UTC),
+		EndTime:   time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC),
+	}
+
+	overlaps, err := findOverlaps(context.Background(), mock, check, "/cal/work", proposed, caldav.ScopeAll, time.UTC)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The standup occurrence overlaps; lunch only touches the end; focus
+	// time is transparent; "self" is the event being moved.
+	if len(overlaps) != 1 || overlaps[0].EventID != "standup" || overlaps[0].CalendarID != "/cal/work" {
+		t.Fatalf("overlaps = %+v, want only the 10 March standup", overlaps)
+	}
+	if !overlaps[0].StartTime.Equal(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("StartTime = %v, want the 10 March occurrence", overlaps[0].StartTime)
+	}
+	if !mock.LastSearchOpts.Expand {
+		t.Error("existing recurring events should be expanded")
+	}
+}
+
+func TestFindOverlaps_RecurringProposal(t *testing.T) {
+	mock := &caldav.MockClient{
+		Events: []caldav.Event{
+			{ID: "review", Title: "Review",
+				StartTime: time.Date(2025, 3, 24, 14, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 24, 15, 0, 0, 0, time.UTC)},
+		},
+	}
+	proposed := caldav.Event{
+		StartTime:  time.Date(2025, 3, 3, 14, 30, 0, 0, time.UTC),
+		EndTime:    time.Date(2025, 3, 3, 15, 30, 0, 0, time.UTC),
+		Recurrence: "FREQ=WEEKLY",
+	}
+
+	overlaps, err := findOverlaps(context.Background(), mock, overlapCheck{mode: conflictsWarn, scope: conflictScopeCalendar}, "/cal/work", proposed, caldav.ScopeAll, time.UTC)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(overlaps) != 1 || overlaps[0].EventID != "review" {
+		t.Errorf("overlaps = %+v, want the review on the fourth Monday", overlaps)
+	}
+}
+
+func TestFindOverlaps_AllDayInZone(t *testing.T) {
+	loc, _ := time.LoadLocation("America/Los_Angeles")
+	mock := &caldav.MockClient{
+		Events: []caldav.Event{
+			{ID: "offsite", Title: "Offsite", AllDay: true,
+				StartTime: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	check := overlapCheck{mode: conflictsWarn, scope: conflictScopeCalendar}
+
+	// 17:00 on 10 March in Los Angeles is already 11 March in UTC, but
+	// still during the all-day event in the user's zone.
+	proposed := caldav.Event{
+		StartTime: time.Date(2025, 3, 10, 17, 0, 0, 0, loc),
+		EndTime:   time.Date(2025, 3, 10, 18, 0, 0, 0, loc),
+	}
+	overlaps, err := findOverlaps(context.Background(), mock, check, "/cal/work", proposed, caldav.ScopeAll, loc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(overlaps) != 1 {
+		t.Errorf("overlaps = %+v, want the all-day offsite", overlaps)
+	}
+}
+
+func TestFindOverlaps_AccountScope(t *testing.T) {
+	mock := &caldav.MockClient{
+		Calendars: []caldav.Calendar{
+			{Path: "/cal/work", SupportedComponents: []string{"VEVENT"}},
+			{Path: "/cal/reminders", SupportedComponents: []string{"VTODO"}},
+			{Path: "/cal/home"},
+		},
+	}
+	proposed := caldav.Event{
+		StartTime: time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC),
+	}
+
+	if _, err := findOverlaps(context.Background(), mock, overlapCheck{mode: conflictsWarn, scope: conflictScopeAccount}, "/cal/work", proposed, caldav.ScopeAll, time.UTC); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.SearchCallCount != 2 {
+		t.Errorf("searched %d calendars, want the 2 event calendars", mock.SearchCallCount)
+	}
+}
+
+func TestReplaces(t *testing.T) {
+	at := func(day int) *time.Time {
+		t := time.Date(2025, 3, day, 7, 0, 0, 0, time.UTC)
+		return &t
+	}
+	proposed := caldav.Event{ID: "standup", RecurrenceID: at(11)}
+	tests := []struct {
+		scope string
+		e     caldav.Event
+		want  bool
+	}{
+		{caldav.ScopeAll, caldav.Event{ID: "standup", RecurrenceID: at(10)}, true},
+		{caldav.ScopeAll, caldav.Event{ID: "review"}, false},
+		{caldav.ScopeThis, caldav.Event{ID: "standup", RecurrenceID: at(11)}, true},
+		{caldav.ScopeThis, caldav.Event{ID: "standup", RecurrenceID: at(12)}, false},
+		{caldav.ScopeThisAndFollowing, caldav.Event{ID: "standup", RecurrenceID: at(10)}, false},
+		{caldav.ScopeThisAndFollowing, caldav.Event{ID: "standup", RecurrenceID: at(12)}, true},
+		{caldav.ScopeThis, caldav.Event{ID: "standup"}, true},
+	}
+	for _, tt := range tests {
+		if got := replaces(proposed, tt.scope, tt.e); got != tt.want {
+			t.Errorf("replaces(%s, %v) = %v, want %v", tt.scope, tt.e.RecurrenceID, got, tt.want)
+		}
+	}
+}
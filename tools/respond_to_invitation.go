@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+// RespondToInvitationHandler creates a handler for accepting, declining or
+// tentatively accepting invitations
+func RespondToInvitationHandler(accounts *AccountClients) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		accountName, _ := args["account"].(string)
+		client, _, err := accounts.Resolve(accountName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// Extract required parameters
+		path, ok := args["path"].(string)
+		if !ok || path == "" {
+			return mcp.NewToolResultError("path is required (use the path of an invitation from list_invitations)"), nil
+		}
+
+		if err := caldav.ValidateCalendarPath(path); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid path: %v", err)), nil
+		}
+
+		status, _ := args["response"].(string)
+		status = strings.ToUpper(status)
+		if err := caldav.ValidateInvitationResponse(status); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		response := caldav.InvitationResponse{Status: status}
+		response.Comment, _ = args["comment"].(string)
+		response.ETag, _ = args["etag"].(string)
+
+		err = client.RespondToInvitation(ctx, path, response)
+		if result := conflictResult(err); result != nil {
+			return result, nil
+		}
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to respond to invitation: %v", err)), nil
+		}
+
+		// Format response
+		result := map[string]interface{}{
+			"success":  true,
+			"path":     path,
+			"response": status,
+			"message":  fmt.Sprintf("Invitation answered with %s", status),
+		}
+
+		jsonData, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonData)), nil
+	}
+}
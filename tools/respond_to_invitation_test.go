@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+func newRespondRequest(args map[string]interface{}) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "respond_to_invitation",
+			Arguments: args,
+		},
+	}
+}
+
+func TestRespondToInvitationHandler_HappyPath(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := RespondToInvitationHandler(testAccounts(mock, ""))
+
+	result, err := handler(context.Background(), newRespondRequest(map[string]interface{}{
+		"path":     "/cal/work/review.ics",
+		"response": "tentative",
+		"comment":  "May be a few minutes late",
+		"etag":     "etag-1",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	if mock.LastInvitationPath != "/cal/work/review.ics" {
+		t.Errorf("path = %q", mock.LastInvitationPath)
+	}
+	want := caldav.InvitationResponse{Status: caldav.PartStatTentative, Comment: "May be a few minutes late", ETag: "etag-1"}
+	if mock.LastResponse == nil || *mock.LastResponse != want {
+		t.Errorf("response = %+v, want %+v", mock.LastResponse, want)
+	}
+}
+
+func TestRespondToInvitationHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		mock *caldav.MockClient
+		args map[string]interface{}
+	}{
+		{"missing path", &caldav.MockClient{}, map[string]interface{}{"response": "ACCEPTED"}},
+		{"invalid path", &caldav.MockClient{}, map[string]interface{}{"path": "/cal/../x.ics", "response": "ACCEPTED"}},
+		{"missing response", &caldav.MockClient{}, map[string]interface{}{"path": "/cal/work/review.ics"}},
+		{"invalid response", &caldav.MockClient{}, map[string]interface{}{"path": "/cal/work/review.ics", "response": "MAYBE"}},
+		{"caldav error", &caldav.MockClient{InvitationErr: fmt.Errorf("you are not an attendee of this event")}, map[string]interface{}{"path": "/cal/work/review.ics", "response": "DECLINED"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := RespondToInvitationHandler(testAccounts(tt.mock, ""))
+			result, err := handler(context.Background(), newRespondRequest(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Fatal("expected error result")
+			}
+		})
+	}
+}
+
+func TestRespondToInvitationHandler_Conflict(t *testing.T) {
+	mock := &caldav.MockClient{InvitationErr: &caldav.ConflictError{Path: "/cal/work/review.ics", Current: &caldav.Event{ID: "review", ETag: "etag-2"}}}
+	handler := RespondToInvitationHandler(testAccounts(mock, ""))
+
+	result, err := handler(context.Background(), newRespondRequest(map[string]interface{}{
+		"path":     "/cal/work/review.ics",
+		"response": "ACCEPTED",
+		"etag":     "etag-1",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected error result")
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "etag-2") {
+		t.Errorf("expected the current etag in the conflict result, got: %s", text)
+	}
+}
@@ -35,11 +35,20 @@ func SearchEventsHandler(accounts *AccountClients) func(context.Context, mcp.Cal
 			return mcp.NewToolResultError(fmt.Sprintf("invalid calendarId: %v", err)), nil
 		}
 
+		// Optional zone for the results; filters without an offset are read in it
+		var loc *time.Location
+		if timezone, ok := args["timezone"].(string); ok && timezone != "" {
+			loc, err = caldav.LoadTimezone(timezone)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid timezone: %v (use an IANA name like 'America/New_York')", err)), nil
+			}
+		}
+
 		// Parse optional time filters
 		var startTime, endTime *time.Time
 
 		if startStr, ok := args["startTime"].(string); ok && startStr != "" {
-			t, err := time.Parse(time.RFC3339, startStr)
+			t, err := parseEventTime(startStr, false, false, loc)
 			if err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("invalid startTime format: %v (use ISO 8601 format like '2024-01-15T14:30:00Z')", err)), nil
 			}
@@ -47,7 +56,7 @@ func SearchEventsHandler(accounts *AccountClients) func(context.Context, mcp.Cal
 		}
 
 		if endStr, ok := args["endTime"].(string); ok && endStr != "" {
-			t, err := time.Parse(time.RFC3339, endStr)
+			t, err := parseEventTime(endStr, false, false, loc)
 			if err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("invalid endTime format: %v (use ISO 8601 format like '2024-01-15T14:30:00Z')", err)), nil
 			}
@@ -64,29 +73,37 @@ func SearchEventsHandler(accounts *AccountClients) func(context.Context, mcp.Cal
 			offset = int(v)
 		}
 
+		// Recurring events can only be expanded within a range
 		expandRecurrence, _ := args["expandRecurrence"].(bool)
-
-		// Search events
-		events, err := client.SearchEvents(ctx, calendarID, startTime, endTime)
+		opts := caldav.SearchOptions{Expand: expandRecurrence && startTime != nil && endTime != nil}
+
+		// Text filters, matched case-insensitively as substrings
+		opts.Filter.Title, _ = args["title"].(string)
+		opts.Filter.Description, _ = args["description"].(string)
+		opts.Filter.Location, _ = args["location"].(string)
+		opts.Filter.Attendee, _ = args["attendee"].(string)
+		opts.Filter.Category, _ = args["category"].(string)
+
+		// Search events; cached results are returned while the server is
+		// unreachable
+		events, err := client.SearchEvents(ctx, calendarID, startTime, endTime, opts)
+		stale, err := staleResults(err)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("failed to search events: %v", err)), nil
 		}
 
-		// Expand recurrences if requested
-		if expandRecurrence && startTime != nil && endTime != nil {
-			var expanded []caldav.Event
-			for _, e := range events {
-				if e.Recurrence != "" {
-					occurrences, err := caldav.ExpandRecurrence(e, *startTime, *endTime)
-					if err != nil {
-						return mcp.NewToolResultError(fmt.Sprintf("failed to expand recurrence for event %s: %v", e.ID, err)), nil
-					}
-					expanded = append(expanded, occurrences...)
-				} else {
-					expanded = append(expanded, e)
+		// Report timed events in the requested zone. All-day and floating
+		// events have no zone and are left as they are.
+		if loc != nil {
+			zoned := make([]caldav.Event, len(events))
+			for i, e := range events {
+				if !e.AllDay && !e.Floating {
+					e.StartTime = e.StartTime.In(loc)
+					e.EndTime = e.EndTime.In(loc)
 				}
+				zoned[i] = e
 			}
-			events = expanded
+			events = zoned
 		}
 
 		// Apply pagination
@@ -109,6 +126,8 @@ func SearchEventsHandler(accounts *AccountClients) func(context.Context, mcp.Cal
 			"events": paginatedEvents,
 		}
 
+		markStale(response, stale)
+
 		jsonData, err := json.MarshalIndent(response, "", "  ")
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
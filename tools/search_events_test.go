@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -176,6 +177,52 @@ func TestSearchEventsHandler_Pagination(t *testing.T) {
 	})
 }
 
+func TestSearchEventsHandler_TextFilters(t *testing.T) {
+	mock := &caldav.MockClient{
+		Events: []caldav.Event{
+			{ID: "e1", Title: "Dentist", Location: "Main Street", Categories: []string{"Health"}},
+			{ID: "e2", Title: "Team meeting", Location: "Office"},
+			{ID: "e3", Title: "Dentist follow-up", Location: "Elm Street"},
+		},
+	}
+	handler := SearchEventsHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newSearchRequest(map[string]interface{}{
+		"title":    "dentist",
+		"location": "main",
+		"category": "health",
+		"attendee": "bob@example.com",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+
+	want := caldav.EventFilter{Title: "dentist", Location: "main", Category: "health", Attendee: "bob@example.com"}
+	if mock.LastSearchOpts.Filter != want {
+		t.Errorf("filter = %+v, want %+v", mock.LastSearchOpts.Filter, want)
+	}
+
+	// Without the attendee filter, only e1 matches; pagination counts the
+	// matching events
+	result, err = handler(context.Background(), newSearchRequest(map[string]interface{}{
+		"title":    "dentist",
+		"location": "main",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response["total"].(float64) != 1 {
+		t.Errorf("total = %v, want 1", response["total"])
+	}
+}
+
 func TestSearchEventsHandler_WithValidDates(t *testing.T) {
 	mock := &caldav.MockClient{
 		Events: []caldav.Event{
@@ -249,6 +296,9 @@ func TestSearchEventsHandler_ExpandRecurrence(t *testing.T) {
 	if response["total"].(float64) != 3 {
 		t.Errorf("total = %v, want 3 (expanded occurrences)", response["total"])
 	}
+	if !mock.LastSearchOpts.Expand {
+		t.Error("expected the client to be asked to expand")
+	}
 }
 
 func TestSearchEventsHandler_ExpandRecurrence_NoTimeRange(t *testing.T) {
@@ -285,6 +335,9 @@ func TestSearchEventsHandler_ExpandRecurrence_NoTimeRange(t *testing.T) {
 	if response["total"].(float64) != 1 {
 		t.Errorf("total = %v, want 1 (not expanded)", response["total"])
 	}
+	if mock.LastSearchOpts.Expand {
+		t.Error("expansion needs a range and should not be requested")
+	}
 }
 
 func TestSearchEventsHandler_ExpandRecurrence_NonRecurring(t *testing.T) {
@@ -367,3 +420,91 @@ func TestSearchEventsHandler_MultiAccount(t *testing.T) {
 		t.Fatal("expected error for unknown account")
 	}
 }
+
+func TestSearchEventsHandler_Timezone(t *testing.T) {
+	start := time.Date(2024, 7, 1, 13, 0, 0, 0, time.UTC)
+	day := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	mock := &caldav.MockClient{
+		Events: []caldav.Event{
+			{ID: "timed", StartTime: start, EndTime: start.Add(time.Hour)},
+			{ID: "allday", StartTime: day, EndTime: day.AddDate(0, 0, 1), AllDay: true},
+		},
+	}
+
+	handler := SearchEventsHandler(testAccounts(mock, "/cal/default"))
+	result, err := handler(context.Background(), newSearchRequest(map[string]interface{}{
+		"timezone": "America/New_York",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+
+	var response struct {
+		Events []struct {
+			ID        string `json:"id"`
+			StartTime string `json:"startTime"`
+		} `json:"events"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if got := response.Events[0].StartTime; got != "2024-07-01T09:00:00-04:00" {
+		t.Errorf("timed startTime = %s, want 2024-07-01T09:00:00-04:00", got)
+	}
+	if got := response.Events[1].StartTime; got != "2024-07-01T00:00:00Z" {
+		t.Errorf("all-day startTime = %s, want the date unchanged", got)
+	}
+	if !mock.Events[0].StartTime.Equal(start) || mock.Events[0].StartTime.Location() != time.UTC {
+		t.Error("search results from the client must not be modified")
+	}
+}
+
+func TestSearchEventsHandler_InvalidTimezone(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := SearchEventsHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newSearchRequest(map[string]interface{}{
+		"timezone": "Not/AZone",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected error for unknown timezone")
+	}
+}
+
+func TestSearchEventsHandler_StaleWhenUnreachable(t *testing.T) {
+	mock := &caldav.MockClient{
+		Events: []caldav.Event{{ID: "e1", Path: "/cal/default/e1.ics", Title: "Meeting"}},
+	}
+	handler := SearchEventsHandler(testAccounts(caldav.NewCachingClient(mock, 0, nil), "/cal/default"))
+
+	result, _ := handler(context.Background(), newSearchRequest(map[string]interface{}{}))
+	if result.IsError || strings.Contains(result.Content[0].(mcp.TextContent).Text, `"stale"`) {
+		t.Fatalf("expected fresh results, got %v", result.Content)
+	}
+
+	mock.SyncErr = fmt.Errorf("dial tcp: i/o timeout")
+	result, err := handler(context.Background(), newSearchRequest(map[string]interface{}{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected cached results, got error: %v", result.Content)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response["stale"] != true || response["syncedAt"] == nil {
+		t.Errorf("response = %v, want stale results with syncedAt", response)
+	}
+	if response["count"].(float64) != 1 {
+		t.Errorf("count = %v, want 1", response["count"])
+	}
+}
@@ -0,0 +1,176 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+// weekdayCodes maps iCalendar weekday codes, as used in BYDAY, to weekdays.
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// workingHours is the daily window in which meetings may be proposed.
+type workingHours struct {
+	start, end time.Duration // offsets from midnight
+	days       map[time.Weekday]bool
+}
+
+// parseWorkingHours parses "HH:MM" start and end times and a comma-separated
+// list of weekday codes such as "MO,TU,WE,TH,FR".
+func parseWorkingHours(start, end, days string) (workingHours, error) {
+	var hours workingHours
+	var err error
+	if hours.start, err = parseClock(start); err != nil {
+		return hours, fmt.Errorf("invalid workingHoursStart: %w", err)
+	}
+	if hours.end, err = parseClock(end); err != nil {
+		return hours, fmt.Errorf("invalid workingHoursEnd: %w", err)
+	}
+	if hours.end <= hours.start {
+		return hours, fmt.Errorf("workingHoursEnd must be after workingHoursStart")
+	}
+
+	hours.days = make(map[time.Weekday]bool)
+	for _, code := range strings.Split(days, ",") {
+		day, ok := weekdayCodes[strings.ToUpper(strings.TrimSpace(code))]
+		if !ok {
+			return hours, fmt.Errorf("invalid workingDays %q: use weekday codes like MO,TU,WE,TH,FR", days)
+		}
+		hours.days[day] = true
+	}
+	return hours, nil
+}
+
+// parseClock parses a time of day such as "09:30". "24:00" is the end of
+// the day.
+func parseClock(value string) (time.Duration, error) {
+	if value == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("use HH:MM like '09:00'")
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// interval is a span of free time.
+type interval struct {
+	start, end time.Time
+}
+
+// freeIntervals returns the free time between start and end that falls in
+// working hours in loc, keeping buffer clear before and after every busy
+// period.
+func freeIntervals(busy []caldav.BusyPeriod, start, end time.Time, hours workingHours, buffer time.Duration, loc *time.Location) []interval {
+	padded := make([]caldav.BusyPeriod, len(busy))
+	for i, b := range busy {
+		padded[i] = caldav.BusyPeriod{Start: b.Start.Add(-buffer), End: b.End.Add(buffer)}
+	}
+	padded = caldav.MergeBusyPeriods(padded, start, end)
+
+	var free []interval
+	first := start.In(loc)
+	for day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc); day.Before(end); day = day.AddDate(0, 0, 1) {
+		if !hours.days[day.Weekday()] {
+			continue
+		}
+		// Offsets are added to the wall clock so working hours stay put
+		// across daylight saving changes.
+		from := wallTime(day, hours.start, loc)
+		to := wallTime(day, hours.end, loc)
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+
+		for _, b := range padded {
+			if !b.End.After(from) || !b.Start.Before(to) {
+				continue
+			}
+			if b.Start.After(from) {
+				free = append(free, interval{from, b.Start})
+			}
+			from = b.End
+		}
+		if to.After(from) {
+			free = append(free, interval{from, to})
+		}
+	}
+	return free
+}
+
+// wallTime returns the time offset after midnight of day on the wall clock.
+func wallTime(day time.Time, offset time.Duration, loc *time.Location) time.Time {
+	h, m := int(offset/time.Hour), int(offset%time.Hour/time.Minute)
+	return time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, loc)
+}
+
+// slot is a candidate meeting time.
+type slot struct {
+	start, end time.Time
+	score      int
+}
+
+// candidateSlots proposes meetings of the given duration in each free
+// interval, starting on multiples of step after the hour in loc.
+func candidateSlots(free []interval, duration, step time.Duration, loc *time.Location) []slot {
+	var slots []slot
+	for _, f := range free {
+		for start := alignUp(f.start, step, loc); !start.Add(duration).After(f.end); start = start.Add(step) {
+			s := slot{start: start, end: start.Add(duration)}
+			s.score = gapScore(start.Sub(f.start), duration) + gapScore(f.end.Sub(s.end), duration)
+			slots = append(slots, s)
+		}
+	}
+	return slots
+}
+
+// alignUp rounds t up to the next multiple of step after the hour in loc.
+func alignUp(t time.Time, step time.Duration, loc *time.Location) time.Time {
+	local := t.In(loc)
+	hour := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), 0, 0, 0, loc)
+	offset := t.Sub(hour)
+	if rem := offset % step; rem != 0 {
+		offset += step - rem
+	}
+	return hour.Add(offset)
+}
+
+// gapScore rates the free time a slot leaves on one side within its free
+// interval: none is best, since the meeting sits right next to other
+// commitments or the edge of the working day; a gap too short for another
+// meeting of the same length is wasted and worst.
+func gapScore(gap, duration time.Duration) int {
+	switch {
+	case gap == 0:
+		return 1
+	case gap < duration:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// rankSlots orders slots best first: by score, then earliest first.
+func rankSlots(slots []slot) {
+	sort.SliceStable(slots, func(i, j int) bool {
+		if slots[i].score != slots[j].score {
+			return slots[i].score > slots[j].score
+		}
+		return slots[i].start.Before(slots[j].start)
+	})
+}
@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+func TestParseWorkingHours(t *testing.T) {
+	hours, err := parseWorkingHours("08:30", "24:00", "mo, tu,FR")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hours.start != 8*time.Hour+30*time.Minute || hours.end != 24*time.Hour {
+		t.Errorf("hours = %v-%v", hours.start, hours.end)
+	}
+	if !hours.days[time.Monday] || !hours.days[time.Friday] || hours.days[time.Wednesday] {
+		t.Errorf("days = %v", hours.days)
+	}
+
+	for _, tt := range [][3]string{
+		{"9am", "17:00", "MO"},
+		{"09:00", "08:00", "MO"},
+		{"09:00", "17:00", "MONDAY"},
+	} {
+		if _, err := parseWorkingHours(tt[0], tt[1], tt[2]); err == nil {
+			t.Errorf("parseWorkingHours(%q, %q, %q): expected error", tt[0], tt[1], tt[2])
+		}
+	}
+}
+
+func TestFreeIntervals(t *testing.T) {
+	loc, _ := time.LoadLocation("America/New_York")
+	at := func(day, hour, min int) time.Time { return time.Date(2025, 3, day, hour, min, 0, 0, loc) }
+	hours, _ := parseWorkingHours("09:00", "17:00", "MO,TU,WE,TH,FR")
+
+	// Friday 7 March to Monday 10 March, across the DST change on the 9th
+	busy := []caldav.BusyPeriod{
+		{Start: at(7, 10, 0), End: at(7, 11, 0)},
+		{Start: at(7, 16, 30), End: at(7, 18, 0)},
+	}
+	free := freeIntervals(busy, at(7, 0, 0), at(11, 0, 0), hours, 15*time.Minute, loc)
+
+	want := []interval{
+		{at(7, 9, 0), at(7, 9, 45)},
+		{at(7, 11, 15), at(7, 16, 15)},
+		{at(10, 9, 0), at(10, 17, 0)},
+	}
+	if len(free) != len(want) {
+		t.Fatalf("free = %v, want %v", free, want)
+	}
+	for i := range want {
+		if !free[i].start.Equal(want[i].start) || !free[i].end.Equal(want[i].end) {
+			t.Errorf("free[%d] = %v-%v, want %v-%v", i, free[i].start, free[i].end, want[i].start, want[i].end)
+		}
+	}
+}
+
+func TestFreeIntervals_ClippedToWindow(t *testing.T) {
+	at := func(hour, min int) time.Time { return time.Date(2025, 3, 10, hour, min, 0, 0, time.UTC) }
+	hours, _ := parseWorkingHours("09:00", "17:00", "MO")
+
+	free := freeIntervals(nil, at(13, 0), at(15, 0), hours, 0, time.UTC)
+	if len(free) != 1 || !free[0].start.Equal(at(13, 0)) || !free[0].end.Equal(at(15, 0)) {
+		t.Errorf("free = %v, want 13:00-15:00", free)
+	}
+}
+
+func TestCandidateSlotsAndRanking(t *testing.T) {
+	at := func(hour, min int) time.Time { return time.Date(2025, 3, 10, hour, min, 0, 0, time.UTC) }
+	free := []interval{
+		{at(9, 10), at(11, 0)},  // starts off the half hour
+		{at(13, 0), at(14, 0)},  // exactly one meeting long
+		{at(15, 0), at(15, 45)}, // too short
+	}
+
+	slots := candidateSlots(free, time.Hour, 30*time.Minute, time.UTC)
+	starts := make([]time.Time, len(slots))
+	for i, s := range slots {
+		starts[i] = s.start
+	}
+	wantStarts := []time.Time{at(9, 30), at(10, 0), at(13, 0)}
+	if len(starts) != len(wantStarts) {
+		t.Fatalf("starts = %v, want %v", starts, wantStarts)
+	}
+	for i := range wantStarts {
+		if !starts[i].Equal(wantStarts[i]) {
+			t.Errorf("starts[%d] = %v, want %v", i, starts[i], wantStarts[i])
+		}
+	}
+
+	rankSlots(slots)
+	// 13:00 fills its gap exactly; 10:00 ends at the edge of its interval;
+	// 9:30 leaves 20 wasted minutes before and 30 after.
+	wantRanked := []time.Time{at(13, 0), at(10, 0), at(9, 30)}
+	for i := range wantRanked {
+		if !slots[i].start.Equal(wantRanked[i]) {
+			t.Errorf("rank %d = %v, want %v", i+1, slots[i].start, wantRanked[i])
+		}
+	}
+}
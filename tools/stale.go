@@ -0,0 +1,29 @@
+package tools
+
+import (
+	"errors"
+
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+// staleResults separates a *caldav.StaleError, returned along with results
+// from the cache while the server is unreachable, from real failures. It
+// returns the stale error, or nil and err for other errors.
+func staleResults(err error) (*caldav.StaleError, error) {
+	var stale *caldav.StaleError
+	if errors.As(err, &stale) {
+		return stale, nil
+	}
+	return nil, err
+}
+
+// markStale flags a response built from cached results, with the time they
+// were last synced with the server.
+func markStale(response map[string]interface{}, stale *caldav.StaleError) {
+	if stale == nil {
+		return
+	}
+	response["stale"] = true
+	response["syncedAt"] = stale.Since
+	response["message"] = "The server could not be reached; these results are from the local cache and may be out of date."
+}
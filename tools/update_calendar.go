@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+// UpdateCalendarHandler creates a handler for renaming, recoloring and
+// reordering calendars
+func UpdateCalendarHandler(accounts *AccountClients) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		accountName, _ := args["account"].(string)
+		client, _, err := accounts.Resolve(accountName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// Extract required parameters
+		calendarID, ok := args["calendarId"].(string)
+		if !ok || calendarID == "" {
+			return mcp.NewToolResultError("calendarId is required"), nil
+		}
+
+		if err := caldav.ValidateCalendarPath(calendarID); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid calendarId: %v", err)), nil
+		}
+
+		// Build the update from the arguments given
+		update := &caldav.CalendarUpdate{}
+		if s, ok := args["name"].(string); ok {
+			if strings.TrimSpace(s) == "" {
+				return mcp.NewToolResultError("name cannot be empty"), nil
+			}
+			update.Name = &s
+		}
+		if s, ok := args["description"].(string); ok {
+			update.Description = &s
+		}
+		if s, ok := args["color"].(string); ok {
+			if s != "" {
+				if err := caldav.ValidateCalendarColor(s); err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+			}
+			update.Color = &s
+		}
+		if v, ok := args["order"].(float64); ok {
+			order := int(v)
+			update.Order = &order
+		}
+
+		if update.Name == nil && update.Description == nil && update.Color == nil && update.Order == nil {
+			return mcp.NewToolResultError("nothing to update: pass name, description, color or order"), nil
+		}
+
+		if err := client.UpdateCalendar(ctx, calendarID, update); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// Format response
+		response := map[string]interface{}{
+			"success":    true,
+			"calendarId": calendarID,
+			"message":    "Calendar updated successfully",
+		}
+
+		jsonData, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonData)), nil
+	}
+}
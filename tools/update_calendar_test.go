@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+func newUpdateCalendarRequest(args map[string]interface{}) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "update_calendar",
+			Arguments: args,
+		},
+	}
+}
+
+func TestUpdateCalendarHandler_HappyPath(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := UpdateCalendarHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newUpdateCalendarRequest(map[string]interface{}{
+		"calendarId":  "/cal/work/",
+		"name":        "Work (old)",
+		"description": "",
+		"order":       float64(4),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+
+	if mock.LastCalendarPath != "/cal/work/" {
+		t.Errorf("path = %q, want /cal/work/", mock.LastCalendarPath)
+	}
+	update := mock.LastCalendarUpdate
+	if update.Name == nil || *update.Name != "Work (old)" {
+		t.Errorf("Name = %v", update.Name)
+	}
+	if update.Description == nil || *update.Description != "" {
+		t.Errorf("Description = %v, want it removed", update.Description)
+	}
+	if update.Order == nil || *update.Order != 4 {
+		t.Errorf("Order = %v, want 4", update.Order)
+	}
+	if update.Color != nil {
+		t.Errorf("Color = %v, want it unchanged", *update.Color)
+	}
+}
+
+func TestUpdateCalendarHandler_InvalidArgs(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"missing calendarId": {"name": "Work"},
+		"traversal":          {"calendarId": "/cal/../x/", "name": "Work"},
+		"nothing to update":  {"calendarId": "/cal/work/"},
+		"empty name":         {"calendarId": "/cal/work/", "name": ""},
+		"bad color":          {"calendarId": "/cal/work/", "color": "#12"},
+	}
+	for name, args := range tests {
+		t.Run(name, func(t *testing.T) {
+			mock := &caldav.MockClient{}
+			handler := UpdateCalendarHandler(testAccounts(mock, "/cal/default"))
+			result, err := handler(context.Background(), newUpdateCalendarRequest(args))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Fatal("expected error result")
+			}
+			if mock.LastCalendarUpdate != nil {
+				t.Error("calendar updated despite invalid arguments")
+			}
+		})
+	}
+}
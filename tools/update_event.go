@@ -21,34 +21,26 @@ func UpdateEventHandler(accounts *AccountClients) func(context.Context, mcp.Call
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		// Extract required parameters
-		eventID, ok := args["eventId"].(string)
-		if !ok || eventID == "" {
-			return mcp.NewToolResultError("eventId is required"), nil
-		}
-
-		if err := caldav.ValidateEventID(eventID); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid eventId: %v", err)), nil
-		}
-
-		calendarID, _ := args["calendarId"].(string)
-		if calendarID == "" {
-			calendarID = defaultCalendar
+		// Identify the event, by eventId or by path
+		eventID, calendarID, eventPath, err := parseEventRef(args, defaultCalendar)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		if calendarID == "" {
-			return mcp.NewToolResultError("calendarId is required (no default calendar configured)"), nil
-		}
+		// Build update with pointer fields
+		update := &caldav.EventUpdate{}
+		update.ETag, _ = args["etag"].(string)
 
-		if err := caldav.ValidateCalendarPath(calendarID); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid calendarId: %v", err)), nil
+		// Occurrences of a recurring event to update
+		eventID, update.Scope, update.RecurrenceID, err = parseOccurrence(args, eventID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		// Build event path
-		eventPath := client.GetEventPath(calendarID, eventID)
-
-		// Build update with pointer fields
-		update := &caldav.EventUpdate{}
+		if eventPath == "" {
+			eventPath = client.GetEventPath(calendarID, eventID)
+		}
 
 		if title, exists := args["title"]; exists {
 			if s, ok := title.(string); ok {
@@ -68,29 +60,121 @@ func UpdateEventHandler(accounts *AccountClients) func(context.Context, mcp.Call
 			}
 		}
 
-		if startTimeStr, ok := args["startTime"].(string); ok && startTimeStr != "" {
-			startTime, err := time.Parse(time.RFC3339, startTimeStr)
+		// A plain date as startTime or endTime implies an all-day event
+		startTimeStr, _ := args["startTime"].(string)
+		endTimeStr, _ := args["endTime"].(string)
+		if v, ok := args["allDay"].(bool); ok {
+			update.AllDay = &v
+		} else if isDateOnly(startTimeStr) || isDateOnly(endTimeStr) {
+			allDay := true
+			update.AllDay = &allDay
+		}
+		allDay := update.AllDay != nil && *update.AllDay
+
+		// Times without an offset are read in the requested time zone
+		var loc *time.Location
+		if timezone, ok := args["timezone"].(string); ok {
+			loc, err = caldav.LoadTimezone(timezone)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid timezone: %v (use an IANA name like 'America/New_York')", err)), nil
+			}
+			update.Timezone = &timezone
+		}
+
+		if startTimeStr != "" {
+			startTime, err := parseEventTime(startTimeStr, allDay, false, loc)
 			if err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("invalid startTime format: %v", err)), nil
 			}
 			update.StartTime = &startTime
 		}
 
-		if endTimeStr, ok := args["endTime"].(string); ok && endTimeStr != "" {
-			endTime, err := time.Parse(time.RFC3339, endTimeStr)
+		if endTimeStr != "" {
+			endTime, err := parseEventTime(endTimeStr, allDay, false, loc)
 			if err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("invalid endTime format: %v", err)), nil
 			}
 			update.EndTime = &endTime
 		}
 
+		if recurrence, ok := args["recurrence"].(string); ok {
+			if recurrence != "" {
+				if err := caldav.ValidateRecurrenceRule(recurrence); err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+			}
+			update.Recurrence = &recurrence
+		}
+
+		if s, ok := args["recurrenceDates"].(string); ok {
+			dates, err := parseEventTimeList(s, allDay, false, loc)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid recurrenceDates: %v", err)), nil
+			}
+			update.RecurrenceDates = &dates
+		}
+
+		if s, ok := args["exceptionDates"].(string); ok {
+			dates, err := parseEventTimeList(s, allDay, false, loc)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid exceptionDates: %v", err)), nil
+			}
+			update.ExceptionDates = &dates
+		}
+
+		if s, ok := args["alarms"].(string); ok {
+			alarms, err := parseAlarmList(s)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid alarms: %v", err)), nil
+			}
+			update.Alarms = &alarms
+		}
+
+		if err := parseEventMetadata(args, update); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		attendees, err := parseAttendeeChanges(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		update.Attendees = attendees
+
 		// Validate time order if both provided
 		if update.StartTime != nil && update.EndTime != nil && update.EndTime.Before(*update.StartTime) {
 			return mcp.NewToolResultError("endTime must be after startTime"), nil
 		}
 
+		check, err := parseOverlapCheck(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// Look for events overlapping the new times before writing. Times
+		// not given here are taken from the event.
+		var overlaps []overlap
+		if check.enabled() {
+			proposed, err := proposedEvent(ctx, client, eventPath, update)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to check for conflicts: %v", err)), nil
+			}
+			if loc == nil {
+				loc = proposed.StartTime.Location()
+			}
+			overlaps, err = findOverlaps(ctx, client, check, calendarID, proposed, update.Scope, loc)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to check for conflicts: %v", err)), nil
+			}
+			if len(overlaps) > 0 && check.mode == conflictsReject {
+				return overlapResult(overlaps), nil
+			}
+		}
+
 		// Update event
-		err = client.UpdateEvent(ctx, eventPath, update)
+		newSeriesID, err := client.UpdateEvent(ctx, eventPath, update)
+		if result := conflictResult(err); result != nil {
+			return result, nil
+		}
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("failed to update event: %v", err)), nil
 		}
@@ -101,6 +185,18 @@ func UpdateEventHandler(accounts *AccountClients) func(context.Context, mcp.Call
 			"eventId": eventID,
 			"message": "Event updated successfully",
 		}
+		if newSeriesID != "" {
+			response["newEventId"] = newSeriesID
+			response["message"] = "Occurrence and following occurrences moved to a new event and updated successfully"
+		} else if update.Scope == caldav.ScopeThis {
+			response["message"] = "Occurrence updated successfully"
+		}
+		if check.enabled() {
+			response["conflicts"] = overlaps
+			if len(overlaps) > 0 {
+				response["message"] = fmt.Sprintf("%s, but it overlaps %d existing event(s)", response["message"], len(overlaps))
+			}
+		}
 
 		jsonData, err := json.MarshalIndent(response, "", "  ")
 		if err != nil {
@@ -110,3 +206,103 @@ func UpdateEventHandler(accounts *AccountClients) func(context.Context, mcp.Call
 		return mcp.NewToolResultText(string(jsonData)), nil
 	}
 }
+
+// proposedEvent returns the times of the event at eventPath as update will
+// leave them, for the overlap check: the event's start, end, recurrence and
+// all-day and floating flags, replaced by those given in update. A single
+// occurrence has the times of its override, if it has one; otherwise, like
+// the start of a split-off series, it starts at its recurrence ID and lasts
+// as long as the series' first occurrence.
+func proposedEvent(ctx context.Context, client caldav.CalendarService, eventPath string, update *caldav.EventUpdate) (caldav.Event, error) {
+	current, _, err := client.GetEvent(ctx, eventPath)
+	if err != nil {
+		return caldav.Event{}, err
+	}
+	proposed := caldav.Event{
+		ID:        current.ID,
+		Path:      current.Path,
+		StartTime: current.StartTime,
+		EndTime:   current.EndTime,
+		AllDay:    current.AllDay,
+		Floating:  current.Floating,
+	}
+	if update.Scope != caldav.ScopeThis {
+		proposed.Recurrence = current.Recurrence
+		proposed.RecurrenceDates = current.RecurrenceDates
+		proposed.ExceptionDates = current.ExceptionDates
+	}
+	if rid := update.RecurrenceID; update.Scope != caldav.ScopeAll && rid != nil {
+		// Only the occurrences from rid on are rewritten; the rest of the
+		// series can still overlap them
+		proposed.RecurrenceID = rid
+		proposed.StartTime = *rid
+		proposed.EndTime = rid.Add(current.EndTime.Sub(current.StartTime))
+		for _, o := range current.Overrides {
+			if update.Scope == caldav.ScopeThis && o.RecurrenceID != nil && o.RecurrenceID.Equal(*rid) {
+				proposed.StartTime, proposed.EndTime = o.StartTime, o.EndTime
+				proposed.AllDay, proposed.Floating = o.AllDay, o.Floating
+			}
+		}
+	}
+
+	// Converting between all-day and timed events drops the floating flag;
+	// moving a floating event to a zone keeps its wall-clock times, which
+	// the check already places in the requested zone.
+	if update.AllDay != nil && *update.AllDay != proposed.AllDay {
+		proposed.AllDay = *update.AllDay
+		proposed.Floating = false
+	}
+	duration := proposed.EndTime.Sub(proposed.StartTime)
+	if update.StartTime != nil {
+		proposed.StartTime = *update.StartTime
+	}
+	if update.EndTime != nil {
+		proposed.EndTime = *update.EndTime
+	} else if !proposed.EndTime.After(proposed.StartTime) {
+		// An event moved past its end keeps its length
+		proposed.EndTime = proposed.StartTime.Add(duration)
+	}
+	if update.Recurrence != nil {
+		proposed.Recurrence = *update.Recurrence
+	}
+	if update.RecurrenceDates != nil {
+		proposed.RecurrenceDates = *update.RecurrenceDates
+	}
+	if update.ExceptionDates != nil {
+		proposed.ExceptionDates = *update.ExceptionDates
+	}
+	return proposed, nil
+}
+
+// parseAttendeeChanges reads the attendees, addAttendees and removeAttendees
+// arguments. It returns nil if none of them is given.
+func parseAttendeeChanges(args map[string]interface{}) (*caldav.AttendeeChanges, error) {
+	var changes caldav.AttendeeChanges
+	given := false
+	if s, ok := args["attendees"].(string); ok {
+		replace := []caldav.Attendee{}
+		if s != "" {
+			if err := json.Unmarshal([]byte(s), &replace); err != nil {
+				return nil, fmt.Errorf("invalid attendees JSON: %v", err)
+			}
+		}
+		changes.Replace = &replace
+		given = true
+	}
+	if s, ok := args["addAttendees"].(string); ok && s != "" {
+		if err := json.Unmarshal([]byte(s), &changes.Add); err != nil {
+			return nil, fmt.Errorf("invalid addAttendees JSON: %v", err)
+		}
+		given = true
+	}
+	if s, ok := args["removeAttendees"].(string); ok && s != "" {
+		if err := json.Unmarshal([]byte(s), &changes.Remove); err != nil {
+			return nil, fmt.Errorf("invalid removeAttendees JSON (expected an array of email addresses): %v", err)
+		}
+		given = true
+	}
+	if !given {
+		return nil, nil
+	}
+	return &changes, nil
+}
@@ -2,8 +2,11 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/rgabriel/mcp-icloud-calendar/caldav"
@@ -261,3 +264,652 @@ func TestUpdateEventHandler_UnknownAccount(t *testing.T) {
 		t.Fatal("expected error for unknown account")
 	}
 }
+
+func TestUpdateEventHandler_PassesETag(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := UpdateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{
+		"eventId": "event-123",
+		"title":   "New Title",
+		"etag":    "etag-1",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatal("expected success")
+	}
+	if mock.LastUpdateEvent.ETag != "etag-1" {
+		t.Errorf("ETag = %q, want etag-1", mock.LastUpdateEvent.ETag)
+	}
+}
+
+func TestUpdateEventHandler_Conflict(t *testing.T) {
+	mock := &caldav.MockClient{
+		UpdateEventErr: &caldav.ConflictError{
+			Path:    "/cal/default/event-123.ics",
+			Current: &caldav.Event{ID: "event-123", Title: "Edited on iPhone", ETag: "etag-2"},
+		},
+	}
+	handler := UpdateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{
+		"eventId": "event-123",
+		"title":   "New Title",
+		"etag":    "etag-1",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected error result")
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("failed to parse conflict response: %v", err)
+	}
+	if response["conflict"] != true {
+		t.Errorf("conflict = %v, want true", response["conflict"])
+	}
+	if response["currentEtag"] != "etag-2" {
+		t.Errorf("currentEtag = %v, want etag-2", response["currentEtag"])
+	}
+	current, ok := response["currentEvent"].(map[string]interface{})
+	if !ok || current["title"] != "Edited on iPhone" {
+		t.Errorf("currentEvent = %v", response["currentEvent"])
+	}
+}
+
+func TestUpdateEventHandler_AllDayDates(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := UpdateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{
+		"eventId":   "event-123",
+		"startTime": "2024-02-01",
+		"endTime":   "2024-02-03",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+
+	update := mock.LastUpdateEvent
+	if update.AllDay == nil || !*update.AllDay {
+		t.Error("expected plain dates to mark the event all-day")
+	}
+	if want := time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC); update.EndTime == nil || !update.EndTime.Equal(want) {
+		t.Errorf("EndTime = %v, want %v", update.EndTime, want)
+	}
+}
+
+func TestUpdateEventHandler_AllDayFlagOnly(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := UpdateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{
+		"eventId": "event-123",
+		"allDay":  false,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	if mock.LastUpdateEvent.AllDay == nil || *mock.LastUpdateEvent.AllDay {
+		t.Error("expected AllDay=false to be passed through")
+	}
+}
+
+func TestUpdateEventHandler_Timezone(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := UpdateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{
+		"eventId":   "event-123",
+		"startTime": "2024-03-11T09:00:00",
+		"timezone":  "Europe/Berlin",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+
+	update := mock.LastUpdateEvent
+	if update.Timezone == nil || *update.Timezone != "Europe/Berlin" {
+		t.Errorf("Timezone = %v, want Europe/Berlin", update.Timezone)
+	}
+	if want := time.Date(2024, 3, 11, 8, 0, 0, 0, time.UTC); update.StartTime == nil || !update.StartTime.Equal(want) {
+		t.Errorf("StartTime = %v, want %v", update.StartTime, want)
+	}
+}
+
+func TestUpdateEventHandler_Recurrence(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := UpdateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{
+		"eventId":         "event-123",
+		"recurrence":      "",
+		"recurrenceDates": "[]",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+
+	update := mock.LastUpdateEvent
+	if update.Recurrence == nil || *update.Recurrence != "" {
+		t.Error("expected empty recurrence to clear the rule")
+	}
+	if update.RecurrenceDates == nil || len(*update.RecurrenceDates) != 0 {
+		t.Error("expected empty recurrenceDates to clear the list")
+	}
+	if update.ExceptionDates != nil {
+		t.Error("omitted exceptionDates should be left unchanged")
+	}
+}
+
+func TestUpdateEventHandler_InvalidRecurrence(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := UpdateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{
+		"eventId":    "event-123",
+		"recurrence": "FREQ=FORTNIGHTLY",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected error for invalid recurrence")
+	}
+	if mock.LastUpdateEvent != nil {
+		t.Error("UpdateEvent should not have been called")
+	}
+}
+
+func TestUpdateEventHandler_Occurrence(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := UpdateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{
+		"eventId":      "event-123",
+		"title":        "Moved",
+		"recurrenceId": "2025-03-10T09:00:00Z",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+
+	update := mock.LastUpdateEvent
+	if update.Scope != caldav.ScopeThis {
+		t.Errorf("scope = %q, want this by default with a recurrenceId", update.Scope)
+	}
+	want := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	if update.RecurrenceID == nil || !update.RecurrenceID.Equal(want) {
+		t.Errorf("recurrenceId = %v, want %v", update.RecurrenceID, want)
+	}
+}
+
+func TestUpdateEventHandler_ThisAndFollowingReportsNewEvent(t *testing.T) {
+	mock := &caldav.MockClient{NewSeriesID: "event-456"}
+	handler := UpdateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{
+		"eventId":      "event-123",
+		"title":        "Moved",
+		"recurrenceId": "2025-03-10T09:00:00Z",
+		"scope":        "thisAndFollowing",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response["eventId"] != "event-123" || response["newEventId"] != "event-456" {
+		t.Errorf("eventId = %v, newEventId = %v; want event-123 and event-456", response["eventId"], response["newEventId"])
+	}
+}
+
+func TestUpdateEventHandler_ScopeRequiresRecurrenceID(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := UpdateEventHandler(testAccounts(mock, "/cal/default"))
+
+	for _, scope := range []string{"thisAndFollowing", "sometimes"} {
+		result, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{
+			"eventId": "event-123",
+			"title":   "Moved",
+			"scope":   scope,
+		}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.IsError {
+			t.Errorf("scope %q: expected error", scope)
+		}
+	}
+	if mock.LastUpdateEvent != nil {
+		t.Error("UpdateEvent should not have been called")
+	}
+}
+
+func TestUpdateEventHandler_OccurrenceID(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := UpdateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{
+		"eventId": "event-123#20250310T090000Z",
+		"title":   "Moved",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+
+	if mock.LastUpdatePath != "/cal/default/event-123.ics" {
+		t.Errorf("update path = %q, want the series' path", mock.LastUpdatePath)
+	}
+	update := mock.LastUpdateEvent
+	want := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	if update.Scope != caldav.ScopeThis || update.RecurrenceID == nil || !update.RecurrenceID.Equal(want) {
+		t.Errorf("scope = %q, recurrenceId = %v; want this occurrence at %v", update.Scope, update.RecurrenceID, want)
+	}
+}
+
+func TestUpdateEventHandler_Alarms(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := UpdateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{
+		"eventId": "event-123",
+		"alarms":  `[{"action":"DISPLAY","trigger":"-PT30M"}]`,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	if alarms := mock.LastUpdateEvent.Alarms; alarms == nil || len(*alarms) != 1 || (*alarms)[0].Trigger != "-PT30M" {
+		t.Errorf("Alarms = %v", alarms)
+	}
+
+	// Alarms read from the server are passed on whatever their action, for
+	// the client to keep
+	if _, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{
+		"eventId": "event-123",
+		"alarms":  `[{"action":"PROCEDURE","trigger":"-PT30M"}]`,
+	})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if alarms := mock.LastUpdateEvent.Alarms; alarms == nil || len(*alarms) != 1 || (*alarms)[0].Action != "PROCEDURE" {
+		t.Errorf("Alarms = %v, want the PROCEDURE alarm passed on", alarms)
+	}
+
+	// Omitted alarms are left unchanged; '[]' removes them
+	if _, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{"eventId": "event-123"})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.LastUpdateEvent.Alarms != nil {
+		t.Error("omitted alarms should be left unchanged")
+	}
+	if _, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{"eventId": "event-123", "alarms": "[]"})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if alarms := mock.LastUpdateEvent.Alarms; alarms == nil || len(*alarms) != 0 {
+		t.Errorf("Alarms = %v, want an empty list", alarms)
+	}
+}
+
+func TestUpdateEventHandler_CheckConflicts(t *testing.T) {
+	mock := &caldav.MockClient{
+		Events: []caldav.Event{
+			{ID: "event-123", Title: "Planning",
+				StartTime: time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC)},
+			{ID: "review", Title: "Review",
+				StartTime: time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)},
+		},
+	}
+	handler := UpdateEventHandler(testAccounts(mock, "/cal/default"))
+
+	// Moving onto the review is refused
+	result, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{
+		"eventId":        "event-123",
+		"startTime":      "2025-03-10T14:30:00Z",
+		"endTime":        "2025-03-10T15:30:00Z",
+		"checkConflicts": "reject",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected the overlap to be refused")
+	}
+	if mock.LastUpdateEvent != nil {
+		t.Error("event should not have been updated")
+	}
+
+	// Moving within its own slot only overlaps the event itself
+	result, err = handler(context.Background(), newUpdateRequest(map[string]interface{}{
+		"eventId":        "event-123",
+		"startTime":      "2025-03-10T09:30:00Z",
+		"endTime":        "2025-03-10T10:30:00Z",
+		"checkConflicts": "reject",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	if mock.LastUpdateEvent == nil {
+		t.Error("event should have been updated")
+	}
+}
+
+func TestUpdateEventHandler_CheckConflictsWithinSeries(t *testing.T) {
+	mock := &caldav.MockClient{
+		Events: []caldav.Event{
+			{ID: "standup", Title: "Standup", Recurrence: "FREQ=DAILY;COUNT=5",
+				StartTime: time.Date(2025, 3, 10, 7, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 10, 7, 15, 0, 0, time.UTC)},
+		},
+	}
+	handler := UpdateEventHandler(testAccounts(mock, "/cal/default"))
+
+	// Moving one occurrence onto the next day's collides with that one
+	result, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{
+		"eventId":        "standup",
+		"recurrenceId":   "2025-03-11T07:00:00Z",
+		"startTime":      "2025-03-12T07:05:00Z",
+		"endTime":        "2025-03-12T07:20:00Z",
+		"checkConflicts": "reject",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected the collision with the next occurrence to be refused")
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "standup#20250312T070000Z") {
+		t.Errorf("expected the 12 March occurrence in the conflicts, got %s", text)
+	}
+
+	// Moving it within its own slot only overlaps the occurrence itself
+	result, err = handler(context.Background(), newUpdateRequest(map[string]interface{}{
+		"eventId":        "standup",
+		"recurrenceId":   "2025-03-11T07:00:00Z",
+		"startTime":      "2025-03-11T07:05:00Z",
+		"endTime":        "2025-03-11T07:20:00Z",
+		"checkConflicts": "reject",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+
+	// Moving the series onto itself is never a conflict
+	result, err = handler(context.Background(), newUpdateRequest(map[string]interface{}{
+		"eventId":        "standup",
+		"startTime":      "2025-03-10T07:05:00Z",
+		"endTime":        "2025-03-10T07:20:00Z",
+		"checkConflicts": "reject",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+}
+
+func TestUpdateEventHandler_CheckConflictsFillsMissingTimes(t *testing.T) {
+	standupMoved := time.Date(2025, 3, 11, 7, 0, 0, 0, time.UTC)
+	mock := &caldav.MockClient{
+		Events: []caldav.Event{
+			{ID: "event-123", Title: "Planning",
+				StartTime: time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC)},
+			{ID: "review", Title: "Review",
+				StartTime: time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)},
+			{ID: "floating", Title: "Gym", Floating: true,
+				StartTime: time.Date(2025, 3, 11, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 11, 10, 0, 0, 0, time.UTC)},
+			{ID: "call", Title: "Call",
+				StartTime: time.Date(2025, 3, 11, 8, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 11, 9, 0, 0, 0, time.UTC)},
+			{ID: "standup", Title: "Standup", Recurrence: "FREQ=DAILY;COUNT=5",
+				StartTime: time.Date(2025, 3, 10, 7, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 10, 7, 15, 0, 0, time.UTC),
+				Overrides: []caldav.Event{{ID: "standup", RecurrenceID: &standupMoved,
+					StartTime: time.Date(2025, 3, 11, 12, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 11, 13, 0, 0, 0, time.UTC)}}},
+			{ID: "lunch", Title: "Lunch",
+				StartTime: time.Date(2025, 3, 11, 12, 30, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 11, 13, 0, 0, 0, time.UTC)},
+		},
+	}
+	handler := UpdateEventHandler(testAccounts(mock, "/cal/default"))
+
+	for _, tt := range []struct {
+		name string
+		args map[string]interface{}
+	}{
+		// The event keeps its start and now ends during the review
+		{"end only", map[string]interface{}{"eventId": "event-123", "endTime": "2025-03-10T14:30:00Z"}},
+		// The event keeps its length and starts during the review
+		{"start only", map[string]interface{}{"eventId": "event-123", "startTime": "2025-03-10T14:30:00Z"}},
+		// The floating event starts at 9:00 in Berlin, during the call
+		{"floating", map[string]interface{}{"eventId": "floating", "endTime": "2025-03-11T10:30:00", "timezone": "Europe/Berlin"}},
+		// The occurrence moved to noon keeps its override's end and now runs into lunch
+		{"occurrence", map[string]interface{}{"eventId": "standup", "recurrenceId": "2025-03-11T07:00:00Z", "startTime": "2025-03-11T12:15:00Z"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.LastUpdateEvent = nil
+			tt.args["checkConflicts"] = "reject"
+			result, err := handler(context.Background(), newUpdateRequest(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Fatal("expected the overlap to be refused")
+			}
+			if mock.LastUpdateEvent != nil {
+				t.Error("event should not have been updated")
+			}
+		})
+	}
+}
+
+func TestUpdateEventHandler_Attendees(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := UpdateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{
+		"eventId":         "event-123",
+		"addAttendees":    `[{"email":"carol@example.com","role":"OPT-PARTICIPANT"}]`,
+		"removeAttendees": `["bob@example.com"]`,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	changes := mock.LastUpdateEvent.Attendees
+	if changes == nil {
+		t.Fatal("expected attendee changes")
+	}
+	if changes.Replace != nil {
+		t.Error("attendee list should not be replaced")
+	}
+	if len(changes.Add) != 1 || changes.Add[0].Email != "carol@example.com" || changes.Add[0].Role != "OPT-PARTICIPANT" {
+		t.Errorf("Add = %+v", changes.Add)
+	}
+	if len(changes.Remove) != 1 || changes.Remove[0] != "bob@example.com" {
+		t.Errorf("Remove = %v", changes.Remove)
+	}
+
+	// Omitted attendees are left unchanged; '[]' removes them all
+	if _, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{"eventId": "event-123"})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.LastUpdateEvent.Attendees != nil {
+		t.Error("omitted attendees should be left unchanged")
+	}
+	if _, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{"eventId": "event-123", "attendees": "[]"})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if changes := mock.LastUpdateEvent.Attendees; changes == nil || changes.Replace == nil || len(*changes.Replace) != 0 {
+		t.Errorf("Attendees = %+v, want an empty replacement list", changes)
+	}
+}
+
+func TestUpdateEventHandler_InvalidAttendees(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := UpdateEventHandler(testAccounts(mock, "/cal/default"))
+
+	for _, arg := range []string{"attendees", "addAttendees", "removeAttendees"} {
+		result, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{
+			"eventId": "event-123",
+			arg:       "not json",
+		}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.IsError {
+			t.Errorf("%s: expected error result", arg)
+		}
+	}
+	if mock.LastUpdateEvent != nil {
+		t.Error("UpdateEvent should not be called")
+	}
+}
+
+func TestUpdateEventHandler_Metadata(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := UpdateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{
+		"eventId":     "event-123",
+		"status":      "cancelled",
+		"transparent": true,
+		"categories":  `["Work","Travel"]`,
+		"url":         "",
+		"class":       "private",
+		"priority":    float64(3),
+		"organizer":   "mailto:boss@example.com",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	u := mock.LastUpdateEvent
+	if u.Status == nil || *u.Status != caldav.EventCancelled {
+		t.Errorf("Status = %v", u.Status)
+	}
+	if u.Transparent == nil || !*u.Transparent {
+		t.Errorf("Transparent = %v", u.Transparent)
+	}
+	if u.Categories == nil || len(*u.Categories) != 2 {
+		t.Errorf("Categories = %v", u.Categories)
+	}
+	if u.URL == nil || *u.URL != "" {
+		t.Errorf("URL = %v, want it cleared", u.URL)
+	}
+	if u.Class == nil || *u.Class != caldav.ClassPrivate {
+		t.Errorf("Class = %v", u.Class)
+	}
+	if u.Priority == nil || *u.Priority != 3 {
+		t.Errorf("Priority = %v", u.Priority)
+	}
+	if u.Organizer == nil || *u.Organizer != "boss@example.com" {
+		t.Errorf("Organizer = %v, want boss@example.com", u.Organizer)
+	}
+
+	// Omitted metadata is left unchanged
+	if _, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{"eventId": "event-123"})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	u = mock.LastUpdateEvent
+	if u.Status != nil || u.Transparent != nil || u.Categories != nil || u.URL != nil || u.Class != nil || u.Priority != nil || u.Organizer != nil {
+		t.Errorf("omitted metadata should be left unchanged: %+v", u)
+	}
+}
+
+func TestUpdateEventHandler_InvalidMetadata(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+	}{
+		{"status", map[string]interface{}{"status": "DONE"}},
+		{"categories", map[string]interface{}{"categories": "Work"}},
+		{"url", map[string]interface{}{"url": "example.com"}},
+		{"class", map[string]interface{}{"class": "SECRET"}},
+		{"priority", map[string]interface{}{"priority": float64(12)}},
+		{"organizer", map[string]interface{}{"organizer": "Boss <boss@example.com>"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &caldav.MockClient{}
+			handler := UpdateEventHandler(testAccounts(mock, "/cal/default"))
+			tt.args["eventId"] = "event-123"
+			result, err := handler(context.Background(), newUpdateRequest(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.IsError {
+				t.Error("expected error result")
+			}
+			if mock.LastUpdateEvent != nil {
+				t.Error("UpdateEvent should not be called")
+			}
+		})
+	}
+}
+
+func TestUpdateEventHandler_Path(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := UpdateEventHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newUpdateRequest(map[string]interface{}{
+		"path":  "/cal/work/AB12-CD34.ics",
+		"title": "Dentist",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", result.Content[0].(mcp.TextContent).Text)
+	}
+	if mock.LastUpdatePath != "/cal/work/AB12-CD34.ics" {
+		t.Errorf("path = %q, want the given path", mock.LastUpdatePath)
+	}
+
+	// Without eventId or path there is nothing to update
+	result, err = handler(context.Background(), newUpdateRequest(map[string]interface{}{"title": "Dentist"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected error result without eventId or path")
+	}
+}
@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+// UpdateTaskHandler creates a handler for updating tasks (VTODOs)
+func UpdateTaskHandler(accounts *AccountClients) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+
+		accountName, _ := args["account"].(string)
+		client, defaultCalendar, err := accounts.Resolve(accountName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// Identify the task, by taskId or by path
+		taskID, calendarID, taskPath, err := parseTaskRef(args, defaultCalendar)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if taskPath == "" {
+			taskPath = client.GetEventPath(calendarID, taskID)
+		}
+
+		// Build update with pointer fields
+		update := &caldav.TaskUpdate{}
+		update.ETag, _ = args["etag"].(string)
+
+		if s, ok := args["title"].(string); ok {
+			update.Title = &s
+		}
+		if s, ok := args["description"].(string); ok {
+			update.Description = &s
+		}
+		if s, ok := args["relatedTo"].(string); ok {
+			update.RelatedTo = &s
+		}
+
+		// An empty due clears it; a plain date makes the task due on that day
+		if dueStr, ok := args["due"].(string); ok {
+			var due time.Time
+			if dueStr != "" {
+				update.AllDay = isDateOnly(dueStr)
+				due, err = parseEventTime(dueStr, update.AllDay, false, nil)
+				if err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("invalid due format: %v", err)), nil
+				}
+			}
+			update.Due = &due
+		}
+
+		if v, ok := args["priority"].(float64); ok {
+			priority := int(v)
+			update.Priority = &priority
+		}
+		if v, ok := args["percentComplete"].(float64); ok {
+			percent := int(v)
+			update.PercentComplete = &percent
+		}
+		if s, ok := args["status"].(string); ok {
+			if err := caldav.ValidateTaskStatus(s); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			update.Status = &s
+		}
+
+		err = client.UpdateTask(ctx, taskPath, update)
+		if result := conflictResult(err); result != nil {
+			return result, nil
+		}
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to update task: %v", err)), nil
+		}
+
+		// Format response
+		response := map[string]interface{}{
+			"success": true,
+			"taskId":  taskID,
+			"message": "Task updated successfully",
+		}
+
+		jsonData, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to format response: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonData)), nil
+	}
+}
@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rgabriel/mcp-icloud-calendar/caldav"
+)
+
+func newUpdateTaskRequest(args map[string]interface{}) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "update_task",
+			Arguments: args,
+		},
+	}
+}
+
+func TestUpdateTaskHandler_HappyPath(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := UpdateTaskHandler(testAccounts(mock, "/cal/reminders"))
+
+	result, err := handler(context.Background(), newUpdateTaskRequest(map[string]interface{}{
+		"taskId":          "t1",
+		"title":           "New title",
+		"due":             "",
+		"status":          caldav.TaskInProcess,
+		"percentComplete": float64(50),
+		"etag":            "etag-1",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+
+	if mock.LastTaskPath != "/cal/reminders/t1.ics" {
+		t.Errorf("path = %q", mock.LastTaskPath)
+	}
+	update := mock.LastTaskUpdate
+	if update.Title == nil || *update.Title != "New title" {
+		t.Errorf("Title = %v", update.Title)
+	}
+	if update.Description != nil || update.Priority != nil {
+		t.Error("fields not given should be left unchanged")
+	}
+	if update.Due == nil || !update.Due.IsZero() {
+		t.Errorf("Due = %v, want zero to clear it", update.Due)
+	}
+	if update.Status == nil || *update.Status != caldav.TaskInProcess {
+		t.Errorf("Status = %v", update.Status)
+	}
+	if update.PercentComplete == nil || *update.PercentComplete != 50 {
+		t.Errorf("PercentComplete = %v", update.PercentComplete)
+	}
+	if update.ETag != "etag-1" {
+		t.Errorf("ETag = %q", update.ETag)
+	}
+}
+
+func TestUpdateTaskHandler_ByPath(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := UpdateTaskHandler(testAccounts(mock, "/cal/default"))
+
+	result, err := handler(context.Background(), newUpdateTaskRequest(map[string]interface{}{
+		"path":  "/cal/reminders/E0F1-2A3B.ics",
+		"title": "Buy milk",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+	if mock.LastTaskPath != "/cal/reminders/E0F1-2A3B.ics" {
+		t.Errorf("path = %q, want the given path", mock.LastTaskPath)
+	}
+
+	// Without a path, taskId is required
+	result, _ = handler(context.Background(), newUpdateTaskRequest(map[string]interface{}{"title": "Buy milk"}))
+	if !result.IsError {
+		t.Error("expected an error without taskId or path")
+	}
+}
+
+func TestUpdateTaskHandler_InvalidStatus(t *testing.T) {
+	mock := &caldav.MockClient{}
+	handler := UpdateTaskHandler(testAccounts(mock, "/cal/reminders"))
+
+	result, err := handler(context.Background(), newUpdateTaskRequest(map[string]interface{}{
+		"taskId": "t1",
+		"status": "done",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected error for invalid status")
+	}
+	if mock.LastTaskUpdate != nil {
+		t.Error("UpdateTask should not be called")
+	}
+}
+
+func TestUpdateTaskHandler_Conflict(t *testing.T) {
+	mock := &caldav.MockClient{
+		TaskErr: &caldav.ConflictError{
+			Path:        "/cal/reminders/t1.ics",
+			CurrentTask: &caldav.Task{ID: "t1", Title: "Theirs", ETag: "etag-2"},
+		},
+	}
+	handler := UpdateTaskHandler(testAccounts(mock, "/cal/reminders"))
+
+	result, err := handler(context.Background(), newUpdateTaskRequest(map[string]interface{}{
+		"taskId": "t1",
+		"title":  "Mine",
+		"etag":   "etag-1",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected error result")
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("failed to parse conflict response: %v", err)
+	}
+	if response["currentEtag"] != "etag-2" {
+		t.Errorf("currentEtag = %v, want etag-2", response["currentEtag"])
+	}
+	if response["currentTask"] == nil {
+		t.Error("expected currentTask in conflict response")
+	}
+	if msg, _ := response["message"].(string); !strings.HasPrefix(msg, "The task was modified") {
+		t.Errorf("message = %q, want it to be about the task", msg)
+	}
+}